@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 	"slices"
 
 	"github.com/aperturerobotics/cli"
@@ -15,6 +18,8 @@ var (
 	cliCompilerConfig     compiler.Config
 	cliCompilerPkg        cli.StringSlice
 	cliCompilerBuildFlags cli.StringSlice
+	cliCompilerOverrides  cli.StringSlice
+	cliCompilerFormat     string
 )
 
 // CompileCommands are commands related to compiling code.
@@ -27,6 +32,7 @@ var CompileCommands = []*cli.Command{{
 		logger := logrus.New()
 		logger.SetLevel(logrus.DebugLevel)
 		le := logrus.NewEntry(logger)
+		cliCompilerConfig.OverrideDirs = slices.Clone(cliCompilerOverrides.Value())
 		cliCompiler, err = compiler.NewCompiler(&cliCompilerConfig, le, nil)
 		return
 	},
@@ -59,6 +65,13 @@ var CompileCommands = []*cli.Command{{
 			Destination: &cliCompilerBuildFlags,
 			EnvVars:     []string{"GOSCRIPT_BUILD_FLAGS"},
 		},
+		&cli.StringSliceFlag{
+			Name:        "override-dir",
+			Aliases:     []string{"overrides"},
+			Usage:       "directory of handwritten TypeScript packages replacing Go packages, laid out like gs/ (repeatable, takes precedence over the built-in packages)",
+			Destination: &cliCompilerOverrides,
+			EnvVars:     []string{"GOSCRIPT_OVERRIDE_DIRS"},
+		},
 		&cli.BoolFlag{
 			Name:        "disable-emit-builtin",
 			Usage:       "disable emitting built-in packages that have handwritten equivalents",
@@ -74,6 +87,90 @@ var CompileCommands = []*cli.Command{{
 			Value:       false,
 			EnvVars:     []string{"GOSCRIPT_ALL_DEPENDENCIES"},
 		},
+		&cli.BoolFlag{
+			Name:        "int64-bigint",
+			Usage:       "represent int64, uint64 and uintptr as bigint with exact 64-bit semantics",
+			Destination: &cliCompilerConfig.Int64AsBigInt,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_INT64_BIGINT"},
+		},
+		&cli.BoolFlag{
+			Name:        "strict-integers",
+			Usage:       "wrap fixed-width integer arithmetic on overflow like Go (slower)",
+			Destination: &cliCompilerConfig.StrictIntegers,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_STRICT_INTEGERS"},
+		},
+		&cli.BoolFlag{
+			Name:        "preemptive",
+			Usage:       "make loops in async functions yield to other goroutines when their time slice is used up",
+			Destination: &cliCompilerConfig.Preemptive,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_PREEMPTIVE"},
+		},
+		&cli.BoolFlag{
+			Name:        "source-map",
+			Usage:       "write a source map next to each generated file, mapping it back to the Go sources",
+			Destination: &cliCompilerConfig.SourceMap,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SOURCE_MAP"},
+		},
+		&cli.BoolFlag{
+			Name:        "source-map-sources",
+			Usage:       "embed the Go sources in the source maps",
+			Destination: &cliCompilerConfig.SourceMapSourcesContent,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SOURCE_MAP_SOURCES"},
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "the format of the diagnostics: text, or json to print them as a JSON array on stdout",
+			Destination: &cliCompilerFormat,
+			Value:       "text",
+			EnvVars:     []string{"GOSCRIPT_FORMAT"},
+		},
+		&cli.BoolFlag{
+			Name:        "incremental",
+			Usage:       "write analysis metadata next to each compiled package and only compile the packages which changed since the last compile",
+			Destination: &cliCompilerConfig.Incremental,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_INCREMENTAL"},
+		},
+		&cli.BoolFlag{
+			Name:        "facade",
+			Usage:       "write a facade.ts next to each compiled package wrapping its exported functions with plain TypeScript values",
+			Destination: &cliCompilerConfig.Facade,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_FACADE"},
+		},
+		&cli.BoolFlag{
+			Name:        "schemas",
+			Usage:       "write a schema.ts next to each compiled package with JSON Schema and zod schemas of its exported structs",
+			Destination: &cliCompilerConfig.Schemas,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SCHEMAS"},
+		},
+		&cli.BoolFlag{
+			Name:        "enum-unions",
+			Usage:       "write named integer and string types only holding the values of their constants as literal union types",
+			Destination: &cliCompilerConfig.EnumUnions,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_ENUM_UNIONS"},
+		},
+		&cli.BoolFlag{
+			Name:        "sealed-unions",
+			Usage:       "write interfaces with an unexported method implemented only by structs of their package as discriminated unions of the structs",
+			Destination: &cliCompilerConfig.SealedUnions,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SEALED_UNIONS"},
+		},
+		&cli.BoolFlag{
+			Name:        "json-methods",
+			Usage:       "write toJSON, toPlain and fromJSON methods converting struct classes to and from their encoding/json encoding",
+			Destination: &cliCompilerConfig.JSONMethods,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_JSON_METHODS"},
+		},
 	},
 }}
 
@@ -87,6 +184,38 @@ func compilePackage(c *cli.Context) error {
 	// build flags
 	cliCompilerConfig.BuildFlags = slices.Clone(cliCompilerBuildFlags.Value())
 
-	_, err := cliCompiler.CompilePackages(context.Background(), pkgs...)
-	return err
+	if cliCompilerFormat != "text" && cliCompilerFormat != "json" {
+		return errors.Errorf("unknown format %q: expected text or json", cliCompilerFormat)
+	}
+
+	result, err := cliCompiler.CompilePackages(context.Background(), pkgs...)
+	var diagErr *compiler.DiagnosticsError
+	if err != nil && !errors.As(err, &diagErr) {
+		return err
+	}
+	if err := printDiagnostics(result.Diagnostics, cliCompilerFormat); err != nil {
+		return err
+	}
+	if diagErr != nil {
+		return errors.Errorf("compile failed with %d error(s)", len(diagErr.Diagnostics))
+	}
+	return nil
+}
+
+// printDiagnostics prints the diagnostics in the format: the text format
+// prints them to stderr like the go command, the json format prints them as
+// a JSON array to stdout.
+func printDiagnostics(diags []compiler.Diagnostic, format string) error {
+	if format == "json" {
+		if diags == nil {
+			diags = []compiler.Diagnostic{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diags)
+	}
+	for _, diag := range diags {
+		fmt.Fprintln(os.Stderr, diag.String())
+	}
+	return nil
 }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aperturerobotics/cli"
+	"github.com/aperturerobotics/goscript/compiler"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	cliDiffTestConfig     compiler.Config
+	cliDiffTestBuildFlags cli.StringSlice
+	cliDiffTestOverrides  cli.StringSlice
+	cliDiffTestArgs       cli.StringSlice
+	cliDiffTestRuntime    string
+	cliDiffTestRuns       int
+	cliDiffTestSeed       uint64
+	cliDiffTestTimeout    time.Duration
+)
+
+// DiffTestCommands are commands comparing compiled code with Go.
+var DiffTestCommands = []*cli.Command{{
+	Name:      "difftest",
+	Category:  "test",
+	Usage:     "run main packages with Go and compiled to TypeScript and compare their output",
+	ArgsUsage: "[packages]",
+	Action:    diffTestPackages,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "the output path for the binaries and typescript (default: a temporary directory)",
+			Destination: &cliDiffTestConfig.OutputPath,
+			Value:       "",
+			EnvVars:     []string{"GOSCRIPT_OUTPUT"},
+		},
+		&cli.StringFlag{
+			Name:        "dir",
+			Usage:       "the working directory to use for the compiler (default: current directory)",
+			Destination: &cliDiffTestConfig.Dir,
+			Value:       "",
+			EnvVars:     []string{"GOSCRIPT_DIR"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "build-flags",
+			Aliases:     []string{"b", "buildflags", "build-flag", "buildflag"},
+			Usage:       "Go build flags (tags) to use during analysis and the native build",
+			Destination: &cliDiffTestBuildFlags,
+			EnvVars:     []string{"GOSCRIPT_BUILD_FLAGS"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "override-dir",
+			Aliases:     []string{"overrides"},
+			Usage:       "directory of handwritten TypeScript packages replacing Go packages, laid out like gs/ (repeatable, takes precedence over the built-in packages)",
+			Destination: &cliDiffTestOverrides,
+			EnvVars:     []string{"GOSCRIPT_OVERRIDE_DIRS"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "arg",
+			Usage:       "an argument passed to the programs on every run",
+			Destination: &cliDiffTestArgs,
+		},
+		&cli.StringFlag{
+			Name:        "runtime",
+			Usage:       "the JavaScript runtime running the typescript: bun or node (with tsx)",
+			Destination: &cliDiffTestRuntime,
+			Value:       "bun",
+			EnvVars:     []string{"GOSCRIPT_TEST_RUNTIME"},
+		},
+		&cli.IntFlag{
+			Name:        "runs",
+			Usage:       "run the programs this many times with random arguments generated from the seed",
+			Destination: &cliDiffTestRuns,
+		},
+		&cli.Uint64Flag{
+			Name:        "seed",
+			Usage:       "the seed of the generated arguments",
+			Destination: &cliDiffTestSeed,
+		},
+		&cli.DurationFlag{
+			Name:        "timeout",
+			Usage:       "the time limit of each run of a program",
+			Destination: &cliDiffTestTimeout,
+			Value:       time.Minute,
+		},
+		&cli.BoolFlag{
+			Name:        "int64-bigint",
+			Usage:       "represent int64, uint64 and uintptr as bigint with exact 64-bit semantics",
+			Destination: &cliDiffTestConfig.Int64AsBigInt,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_INT64_BIGINT"},
+		},
+		&cli.BoolFlag{
+			Name:        "strict-integers",
+			Usage:       "wrap fixed-width integer arithmetic on overflow like Go (slower)",
+			Destination: &cliDiffTestConfig.StrictIntegers,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_STRICT_INTEGERS"},
+		},
+		&cli.BoolFlag{
+			Name:        "preemptive",
+			Usage:       "make loops in async functions yield to other goroutines when their time slice is used up",
+			Destination: &cliDiffTestConfig.Preemptive,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_PREEMPTIVE"},
+		},
+	},
+}}
+
+// diffTestPackages compares the native and compiled runs of the packages,
+// printing a summary per package.
+func diffTestPackages(c *cli.Context) error {
+	ctx := context.Background()
+	patterns := c.Args().Slice()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	conf := cliDiffTestConfig
+	conf.BuildFlags = slices.Clone(cliDiffTestBuildFlags.Value())
+	conf.OverrideDirs = slices.Clone(cliDiffTestOverrides.Value())
+	if conf.OutputPath == "" {
+		tmpDir, err := os.MkdirTemp("", "goscript-difftest-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+		conf.OutputPath = tmpDir
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	comp, err := compiler.NewCompiler(&conf, logrus.NewEntry(logger), nil)
+	if err != nil {
+		return err
+	}
+	results, err := comp.DiffTest(ctx, &compiler.DiffTestConfig{
+		Runtime: cliDiffTestRuntime,
+		Args:    cliDiffTestArgs.Value(),
+		Runs:    cliDiffTestRuns,
+		Seed:    cliDiffTestSeed,
+		Timeout: cliDiffTestTimeout,
+	}, patterns...)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, res := range results {
+		if res.Diff == nil {
+			runs := "1 run"
+			if res.Runs != 1 {
+				runs = fmt.Sprintf("%d runs", res.Runs)
+			}
+			fmt.Printf("ok  \t%s\t%s\n", res.PkgPath, runs)
+			continue
+		}
+		failed = true
+		fmt.Printf("FAIL\t%s\n\t%s\n", res.PkgPath, res.Diff)
+		if len(res.Args) != 0 {
+			fmt.Printf("\targs: %q\n", res.Args)
+		}
+		if cliDiffTestRuns != 0 {
+			fmt.Printf("\tseed: %d, run: %d\n", cliDiffTestSeed, res.Runs-1)
+		}
+	}
+	if failed {
+		return errors.New("FAIL")
+	}
+	return nil
+}
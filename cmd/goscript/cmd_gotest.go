@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aperturerobotics/cli"
+	"github.com/aperturerobotics/goscript/compiler"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	cliTestConfig     compiler.Config
+	cliTestBuildFlags cli.StringSlice
+	cliTestOverrides  cli.StringSlice
+	cliTestRuntime    string
+	cliTestVerbose    bool
+	cliTestRun        string
+	cliTestSkip       string
+	cliTestBench      string
+	cliTestBenchtime  string
+	cliTestShort      bool
+	cliTestFailfast   bool
+)
+
+// TestCommands are commands related to testing code.
+var TestCommands = []*cli.Command{{
+	Name:      "test",
+	Category:  "test",
+	Usage:     "compile the tests of Go packages to TypeScript and run them",
+	ArgsUsage: "[packages]",
+	Action:    testPackages,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "the output typescript path to use (default: a temporary directory)",
+			Destination: &cliTestConfig.OutputPath,
+			Value:       "",
+			EnvVars:     []string{"GOSCRIPT_OUTPUT"},
+		},
+		&cli.StringFlag{
+			Name:        "dir",
+			Usage:       "the working directory to use for the compiler (default: current directory)",
+			Destination: &cliTestConfig.Dir,
+			Value:       "",
+			EnvVars:     []string{"GOSCRIPT_DIR"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "build-flags",
+			Aliases:     []string{"b", "buildflags", "build-flag", "buildflag"},
+			Usage:       "Go build flags (tags) to use during analysis",
+			Destination: &cliTestBuildFlags,
+			EnvVars:     []string{"GOSCRIPT_BUILD_FLAGS"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "override-dir",
+			Aliases:     []string{"overrides"},
+			Usage:       "directory of handwritten TypeScript packages replacing Go packages, laid out like gs/ (repeatable, takes precedence over the built-in packages)",
+			Destination: &cliTestOverrides,
+			EnvVars:     []string{"GOSCRIPT_OVERRIDE_DIRS"},
+		},
+		&cli.StringFlag{
+			Name:        "runtime",
+			Usage:       "the JavaScript runtime running the tests: bun or node (with tsx)",
+			Destination: &cliTestRuntime,
+			Value:       "bun",
+			EnvVars:     []string{"GOSCRIPT_TEST_RUNTIME"},
+		},
+		&cli.BoolFlag{
+			Name:        "v",
+			Usage:       "print the output of all tests as they run",
+			Destination: &cliTestVerbose,
+		},
+		&cli.StringFlag{
+			Name:        "run",
+			Usage:       "run only the tests matching the regular expression",
+			Destination: &cliTestRun,
+		},
+		&cli.StringFlag{
+			Name:        "skip",
+			Usage:       "skip the tests matching the regular expression",
+			Destination: &cliTestSkip,
+		},
+		&cli.StringFlag{
+			Name:        "bench",
+			Usage:       "run the benchmarks matching the regular expression",
+			Destination: &cliTestBench,
+		},
+		&cli.StringFlag{
+			Name:        "benchtime",
+			Usage:       "run each benchmark for the duration, or N times with Nx",
+			Destination: &cliTestBenchtime,
+		},
+		&cli.BoolFlag{
+			Name:        "short",
+			Usage:       "tell long-running tests to shorten their run time",
+			Destination: &cliTestShort,
+		},
+		&cli.BoolFlag{
+			Name:        "failfast",
+			Usage:       "do not start new tests after the first test failure",
+			Destination: &cliTestFailfast,
+		},
+		&cli.BoolFlag{
+			Name:        "int64-bigint",
+			Usage:       "represent int64, uint64 and uintptr as bigint with exact 64-bit semantics",
+			Destination: &cliTestConfig.Int64AsBigInt,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_INT64_BIGINT"},
+		},
+		&cli.BoolFlag{
+			Name:        "strict-integers",
+			Usage:       "wrap fixed-width integer arithmetic on overflow like Go (slower)",
+			Destination: &cliTestConfig.StrictIntegers,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_STRICT_INTEGERS"},
+		},
+		&cli.BoolFlag{
+			Name:        "preemptive",
+			Usage:       "make loops in async functions yield to other goroutines when their time slice is used up",
+			Destination: &cliTestConfig.Preemptive,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_PREEMPTIVE"},
+		},
+		&cli.BoolFlag{
+			Name:        "source-map",
+			Usage:       "write source maps, mapping stack traces back to the Go sources",
+			Destination: &cliTestConfig.SourceMap,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SOURCE_MAP"},
+		},
+	},
+}}
+
+// testPackages compiles and runs the tests of the packages, printing a
+// summary line per package like go test.
+func testPackages(c *cli.Context) error {
+	ctx := context.Background()
+	patterns := c.Args().Slice()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	conf := cliTestConfig
+	conf.BuildFlags = slices.Clone(cliTestBuildFlags.Value())
+	conf.OverrideDirs = slices.Clone(cliTestOverrides.Value())
+	if conf.OutputPath == "" {
+		tmpDir, err := os.MkdirTemp("", "goscript-test-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+		conf.OutputPath = tmpDir
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	comp, err := compiler.NewCompiler(&conf, logrus.NewEntry(logger), nil)
+	if err != nil {
+		return err
+	}
+	testPkgs, err := comp.CompileTests(ctx, patterns...)
+	if err != nil {
+		return err
+	}
+
+	args := testRunArgs()
+	failed := false
+	for _, tp := range testPkgs {
+		if tp.EntryPath == "" {
+			fmt.Printf("?   \t%s\t[no test files]\n", tp.PkgPath)
+			continue
+		}
+
+		// Like go test, only show the output of passing packages when asked.
+		showOutput := cliTestVerbose || cliTestBench != ""
+		var out bytes.Buffer
+		runConf := &compiler.TestRunConfig{Runtime: cliTestRuntime, Args: args}
+		if showOutput {
+			runConf.Stdout, runConf.Stderr = os.Stdout, os.Stderr
+		} else {
+			runConf.Stdout, runConf.Stderr = &out, &out
+		}
+
+		start := time.Now()
+		passed, err := compiler.RunTests(ctx, tp, runConf)
+		if err != nil {
+			return err
+		}
+		elapsed := time.Since(start).Seconds()
+		if passed {
+			fmt.Printf("ok  \t%s\t%.3fs\n", tp.PkgPath, elapsed)
+			continue
+		}
+		failed = true
+		_, _ = os.Stdout.Write(out.Bytes())
+		fmt.Printf("FAIL\t%s\t%.3fs\n", tp.PkgPath, elapsed)
+	}
+	if failed {
+		return errors.New("FAIL")
+	}
+	return nil
+}
+
+// testRunArgs returns the flags of the test runner.
+func testRunArgs() []string {
+	var args []string
+	if cliTestVerbose {
+		args = append(args, "-test.v=true")
+	}
+	if cliTestShort {
+		args = append(args, "-test.short=true")
+	}
+	if cliTestFailfast {
+		args = append(args, "-test.failfast=true")
+	}
+	for _, f := range []struct{ name, value string }{
+		{"run", cliTestRun},
+		{"skip", cliTestSkip},
+		{"bench", cliTestBench},
+		{"benchtime", cliTestBenchtime},
+	} {
+		if f.value != "" {
+			args = append(args, "-test."+f.name+"="+f.value)
+		}
+	}
+	return args
+}
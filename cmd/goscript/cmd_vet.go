@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aperturerobotics/cli"
+	"github.com/aperturerobotics/goscript/compiler"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	cliVetConfig     compiler.Config
+	cliVetBuildFlags cli.StringSlice
+	cliVetOverrides  cli.StringSlice
+	cliVetFormat     string
+)
+
+// VetCommands are commands related to checking code.
+var VetCommands = []*cli.Command{{
+	Name:      "vet",
+	Category:  "compile",
+	Usage:     "report Go code which goscript does not translate faithfully",
+	ArgsUsage: "[packages]",
+	Action:    vetPackages,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "dir",
+			Usage:       "the working directory to use for the compiler (default: current directory)",
+			Destination: &cliVetConfig.Dir,
+			Value:       "",
+			EnvVars:     []string{"GOSCRIPT_DIR"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "build-flags",
+			Aliases:     []string{"b", "buildflags", "build-flag", "buildflag"},
+			Usage:       "Go build flags (tags) to use during analysis",
+			Destination: &cliVetBuildFlags,
+			EnvVars:     []string{"GOSCRIPT_BUILD_FLAGS"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "override-dir",
+			Aliases:     []string{"overrides"},
+			Usage:       "directory of handwritten TypeScript packages replacing Go packages, laid out like gs/ (repeatable, takes precedence over the built-in packages)",
+			Destination: &cliVetOverrides,
+			EnvVars:     []string{"GOSCRIPT_OVERRIDE_DIRS"},
+		},
+		&cli.BoolFlag{
+			Name:        "all-dependencies",
+			Usage:       "check all dependencies compiled with the requested packages",
+			Aliases:     []string{"all-deps", "deps"},
+			Destination: &cliVetConfig.AllDependencies,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_ALL_DEPENDENCIES"},
+		},
+		&cli.BoolFlag{
+			Name:        "int64-bigint",
+			Usage:       "represent int64, uint64 and uintptr as bigint with exact 64-bit semantics",
+			Destination: &cliVetConfig.Int64AsBigInt,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_INT64_BIGINT"},
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "the format of the diagnostics: text, or json to print them as a JSON array on stdout",
+			Destination: &cliVetFormat,
+			Value:       "text",
+			EnvVars:     []string{"GOSCRIPT_FORMAT"},
+		},
+	},
+}}
+
+// vetPackages reports the portability issues of the packages.
+func vetPackages(c *cli.Context) error {
+	patterns := c.Args().Slice()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	if cliVetFormat != "text" && cliVetFormat != "json" {
+		return errors.Errorf("unknown format %q: expected text or json", cliVetFormat)
+	}
+
+	conf := cliVetConfig
+	conf.BuildFlags = slices.Clone(cliVetBuildFlags.Value())
+	conf.OverrideDirs = slices.Clone(cliVetOverrides.Value())
+	// Nothing is written, but the compiler requires an output path.
+	conf.OutputPath = "output"
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+	comp, err := compiler.NewCompiler(&conf, logrus.NewEntry(logger), nil)
+	if err != nil {
+		return err
+	}
+
+	diags, err := comp.Vet(context.Background(), patterns...)
+	if err != nil {
+		return err
+	}
+	if err := printDiagnostics(diags, cliVetFormat); err != nil {
+		return err
+	}
+	if len(diags) != 0 {
+		return errors.Errorf("vet found %d problem(s)", len(diags))
+	}
+	return nil
+}
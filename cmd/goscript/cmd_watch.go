@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"time"
+
+	"github.com/aperturerobotics/cli"
+	"github.com/aperturerobotics/goscript/compiler"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	cliWatchConfig     compiler.Config
+	cliWatchBuildFlags cli.StringSlice
+	cliWatchOverrides  cli.StringSlice
+	cliWatchInterval   time.Duration
+)
+
+// WatchCommands are commands related to watching code.
+var WatchCommands = []*cli.Command{{
+	Name:      "watch",
+	Category:  "compile",
+	Usage:     "compile Go packages to TypeScript and compile them again when they change",
+	ArgsUsage: "[packages]",
+	Action:    watchPackages,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "the output typescript path to use",
+			Destination: &cliWatchConfig.OutputPath,
+			Value:       "./output",
+			EnvVars:     []string{"GOSCRIPT_OUTPUT"},
+		},
+		&cli.StringFlag{
+			Name:        "dir",
+			Usage:       "the working directory to use for the compiler (default: current directory)",
+			Destination: &cliWatchConfig.Dir,
+			Value:       "",
+			EnvVars:     []string{"GOSCRIPT_DIR"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "build-flags",
+			Aliases:     []string{"b", "buildflags", "build-flag", "buildflag"},
+			Usage:       "Go build flags (tags) to use during analysis",
+			Destination: &cliWatchBuildFlags,
+			EnvVars:     []string{"GOSCRIPT_BUILD_FLAGS"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "override-dir",
+			Aliases:     []string{"overrides"},
+			Usage:       "directory of handwritten TypeScript packages replacing Go packages, laid out like gs/ (repeatable, takes precedence over the built-in packages)",
+			Destination: &cliWatchOverrides,
+			EnvVars:     []string{"GOSCRIPT_OVERRIDE_DIRS"},
+		},
+		&cli.DurationFlag{
+			Name:        "interval",
+			Usage:       "how often to check the files for changes",
+			Destination: &cliWatchInterval,
+			Value:       300 * time.Millisecond,
+			EnvVars:     []string{"GOSCRIPT_WATCH_INTERVAL"},
+		},
+		&cli.BoolFlag{
+			Name:        "disable-emit-builtin",
+			Usage:       "disable emitting built-in packages that have handwritten equivalents",
+			Destination: &cliWatchConfig.DisableEmitBuiltin,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_DISABLE_EMIT_BUILTIN"},
+		},
+		&cli.BoolFlag{
+			Name:        "all-dependencies",
+			Usage:       "compile all dependencies of the requested packages",
+			Aliases:     []string{"all-deps", "deps"},
+			Destination: &cliWatchConfig.AllDependencies,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_ALL_DEPENDENCIES"},
+		},
+		&cli.BoolFlag{
+			Name:        "int64-bigint",
+			Usage:       "represent int64, uint64 and uintptr as bigint with exact 64-bit semantics",
+			Destination: &cliWatchConfig.Int64AsBigInt,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_INT64_BIGINT"},
+		},
+		&cli.BoolFlag{
+			Name:        "strict-integers",
+			Usage:       "wrap fixed-width integer arithmetic on overflow like Go (slower)",
+			Destination: &cliWatchConfig.StrictIntegers,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_STRICT_INTEGERS"},
+		},
+		&cli.BoolFlag{
+			Name:        "preemptive",
+			Usage:       "make loops in async functions yield to other goroutines when their time slice is used up",
+			Destination: &cliWatchConfig.Preemptive,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_PREEMPTIVE"},
+		},
+		&cli.BoolFlag{
+			Name:        "source-map",
+			Usage:       "write a source map next to each generated file, mapping it back to the Go sources",
+			Destination: &cliWatchConfig.SourceMap,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SOURCE_MAP"},
+		},
+		&cli.BoolFlag{
+			Name:        "source-map-sources",
+			Usage:       "embed the Go sources in the source maps",
+			Destination: &cliWatchConfig.SourceMapSourcesContent,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SOURCE_MAP_SOURCES"},
+		},
+		&cli.BoolFlag{
+			Name:        "facade",
+			Usage:       "write a facade.ts next to each compiled package wrapping its exported functions with plain TypeScript values",
+			Destination: &cliWatchConfig.Facade,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_FACADE"},
+		},
+		&cli.BoolFlag{
+			Name:        "schemas",
+			Usage:       "write a schema.ts next to each compiled package with JSON Schema and zod schemas of its exported structs",
+			Destination: &cliWatchConfig.Schemas,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SCHEMAS"},
+		},
+		&cli.BoolFlag{
+			Name:        "enum-unions",
+			Usage:       "write named integer and string types only holding the values of their constants as literal union types",
+			Destination: &cliWatchConfig.EnumUnions,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_ENUM_UNIONS"},
+		},
+		&cli.BoolFlag{
+			Name:        "sealed-unions",
+			Usage:       "write interfaces with an unexported method implemented only by structs of their package as discriminated unions of the structs",
+			Destination: &cliWatchConfig.SealedUnions,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_SEALED_UNIONS"},
+		},
+		&cli.BoolFlag{
+			Name:        "json-methods",
+			Usage:       "write toJSON, toPlain and fromJSON methods converting struct classes to and from their encoding/json encoding",
+			Destination: &cliWatchConfig.JSONMethods,
+			Value:       false,
+			EnvVars:     []string{"GOSCRIPT_JSON_METHODS"},
+		},
+	},
+}}
+
+// watchPackages compiles the packages and compiles them again when they
+// change, until interrupted.
+func watchPackages(c *cli.Context) error {
+	patterns := c.Args().Slice()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	conf := cliWatchConfig
+	conf.BuildFlags = slices.Clone(cliWatchBuildFlags.Value())
+	conf.OverrideDirs = slices.Clone(cliWatchOverrides.Value())
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+	le := logrus.NewEntry(logger)
+	comp, err := compiler.NewCompiler(&conf, le, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	err = comp.Watch(ctx, cliWatchInterval, func(event *compiler.WatchEvent) {
+		entry := le.WithField("duration", event.Duration.Round(time.Millisecond))
+		if len(event.Changed) != 0 {
+			entry = entry.WithField("changed", len(event.Changed))
+		}
+		var diagErr *compiler.DiagnosticsError
+		if event.Result != nil {
+			for _, diag := range event.Result.Diagnostics {
+				fmt.Fprintln(os.Stderr, diag.String())
+			}
+		}
+		if errors.As(event.Err, &diagErr) {
+			entry.WithField("errors", len(diagErr.Diagnostics)).Error("compile failed, waiting for changes")
+			return
+		}
+		if event.Err != nil {
+			entry.WithError(event.Err).Error("compile failed, waiting for changes")
+			return
+		}
+		entry.
+			WithField("compiled", len(event.Result.CompiledPackages)).
+			WithField("reused", len(event.Result.ReusedPackages)).
+			Info("compiled, waiting for changes")
+	}, patterns...)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
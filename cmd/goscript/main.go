@@ -19,6 +19,10 @@ func main() {
 
 	app.Usage = "GoScript compiles Go to Typescript."
 	app.Commands = append(app.Commands, CompileCommands...)
+	app.Commands = append(app.Commands, WatchCommands...)
+	app.Commands = append(app.Commands, VetCommands...)
+	app.Commands = append(app.Commands, TestCommands...)
+	app.Commands = append(app.Commands, DiffTestCommands...)
 
 	if err := app.Run(os.Args); err != nil {
 		_, _ = os.Stderr.WriteString(err.Error() + "\n")
@@ -1,7 +1,6 @@
 package compiler
 
 import (
-	"encoding/json"
 	"go/ast"
 	"go/token"
 	"go/types"
@@ -9,7 +8,6 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/aperturerobotics/goscript"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -94,6 +92,10 @@ type NodeInfo struct {
 type GsMetadata struct {
 	Dependencies []string        `json:"dependencies,omitempty"`
 	AsyncMethods map[string]bool `json:"asyncMethods,omitempty"`
+	BigIntParams map[string]bool `json:"bigintParams,omitempty"`
+	// Unsupported maps the functions and methods which the package does not
+	// implement to the reason, reported by CheckPortability.
+	Unsupported map[string]string `json:"unsupported,omitempty"`
 }
 
 // InterfaceMethodKey uniquely identifies an interface method
@@ -166,6 +168,14 @@ type Analysis struct {
 	// This is computed once during analysis and reused during code generation
 	MethodAsyncStatus map[MethodKey]bool
 
+	// BigIntParamFuncs lists the functions of handwritten packages which accept
+	// bigint arguments for their int64 and uint64 parameters (see Config.Int64AsBigInt).
+	BigIntParamFuncs map[MethodKey]bool
+
+	// UnsupportedFuncs maps the functions of handwritten packages which are
+	// not implemented to the reason, from the "unsupported" of their meta.json.
+	UnsupportedFuncs map[MethodKey]string
+
 	// ReferencedTypesPerFile tracks which named types are referenced in each file.
 	// This is used to filter synthetic imports to only include packages needed
 	// by types actually used in each specific file, not all types in the package.
@@ -175,6 +185,57 @@ type Analysis struct {
 	// SyntheticImportsPerFile stores synthetic imports needed per file.
 	// Key: file path, Value: map of package name to import info
 	SyntheticImportsPerFile map[string]map[string]*fileImport
+
+	// GotoBlocks stores the goto lowering plan for statement lists containing goto targets.
+	// Key: the owner of the statement list (*ast.BlockStmt, *ast.CaseClause or *ast.CommClause)
+	GotoBlocks map[ast.Node]*GotoBlockPlan
+
+	// GotoBranches stores how goto statements, and unlabeled break/continue
+	// statements leaving a goto region, are written.
+	GotoBranches map[*ast.BranchStmt]*GotoBranch
+
+	// GotoOnlyLabels tracks labels which are only targeted by goto statements.
+	// These labels are implemented by goto regions and are not emitted.
+	GotoOnlyLabels map[*types.Label]bool
+
+	// NonNilPointerVars tracks local pointer variables which are only ever
+	// assigned pointers that are not nil, like &T{} or new(T).
+	// These do not need to become typed nils when converted to interfaces.
+	NonNilPointerVars map[types.Object]bool
+
+	// TypeDictParams tracks the type parameters of generic functions which
+	// take a runtime type dictionary, in this package and the compiled
+	// packages it imports.
+	TypeDictParams map[*types.TypeName]bool
+
+	// TypeDictFuncValues tracks the generic functions taking type
+	// dictionaries which are used as values instead of being called.
+	TypeDictFuncValues map[ast.Expr]bool
+
+	// typeDictPackages tracks the packages analyzed for type dictionaries.
+	typeDictPackages map[string]bool
+
+	// YieldLoopBodies maps the bodies of loops which may get a preemption
+	// check to the function declaration or literal enclosing the loop.
+	// See Config.Preemptive.
+	YieldLoopBodies map[*ast.BlockStmt]ast.Node
+
+	// EmbedFiles maps the variable specs with a //go:embed directive to the
+	// files they embed, sorted by name.
+	EmbedFiles map[*ast.ValueSpec][]*embedFile
+
+	// EnumTypes maps the named integer and string types of the package
+	// which only ever hold the values of their constants to the constants.
+	// These are written as literal union types.
+	EnumTypes map[*types.TypeName]*EnumInfo
+
+	// SealedInterfaces maps the interfaces of the package with an unexported
+	// method which are only implemented by structs to the structs, in
+	// declaration order. See Config.SealedUnions.
+	SealedInterfaces map[*types.TypeName][]SealedImplementation
+
+	// overrides finds the handwritten packages replacing Go packages.
+	overrides *overrides
 }
 
 // PackageAnalysis holds cross-file analysis data for a package
@@ -223,8 +284,22 @@ func NewAnalysis(allPackages map[string]*packages.Package) *Analysis {
 		AllPackages:              allPackages,
 		InterfaceImplementations: make(map[InterfaceMethodKey][]ImplementationInfo),
 		MethodAsyncStatus:        make(map[MethodKey]bool),
+		BigIntParamFuncs:         make(map[MethodKey]bool),
+		UnsupportedFuncs:         make(map[MethodKey]string),
 		ReferencedTypesPerFile:   make(map[string]map[*types.Named]bool),
 		SyntheticImportsPerFile:  make(map[string]map[string]*fileImport),
+		GotoBlocks:               make(map[ast.Node]*GotoBlockPlan),
+		GotoBranches:             make(map[*ast.BranchStmt]*GotoBranch),
+		GotoOnlyLabels:           make(map[*types.Label]bool),
+		NonNilPointerVars:        make(map[types.Object]bool),
+		TypeDictParams:           make(map[*types.TypeName]bool),
+		TypeDictFuncValues:       make(map[ast.Expr]bool),
+		typeDictPackages:         make(map[string]bool),
+		YieldLoopBodies:          make(map[*ast.BlockStmt]ast.Node),
+		EmbedFiles:               make(map[*ast.ValueSpec][]*embedFile),
+		EnumTypes:                make(map[*types.TypeName]*EnumInfo),
+		SealedInterfaces:         make(map[*types.TypeName][]SealedImplementation),
+		overrides:                embeddedOverrides(),
 	}
 }
 
@@ -1282,7 +1357,16 @@ func (v *analysisVisitor) containsReceiverUsage(node ast.Node, receiver *types.V
 // with information that will be used during code generation to properly handle pointers,
 // variables that need varRefing, receiver usage, etc. This replaces the old file-by-file analysis.
 func AnalyzePackageFiles(pkg *packages.Package, allPackages map[string]*packages.Package) *Analysis {
+	return analyzePackageFiles(pkg, allPackages, nil)
+}
+
+// analyzePackageFiles is AnalyzePackageFiles with the handwritten packages of
+// o, or of goscript if o is nil.
+func analyzePackageFiles(pkg *packages.Package, allPackages map[string]*packages.Package, o *overrides) *Analysis {
 	analysis := NewAnalysis(allPackages)
+	if o != nil {
+		analysis.overrides = o
+	}
 
 	// Load package metadata for async function detection
 	analysis.LoadPackageMetadata()
@@ -1390,6 +1474,27 @@ func AnalyzePackageFiles(pkg *packages.Package, allPackages map[string]*packages
 	// Fourth pass: collect imports needed by promoted methods from embedded structs
 	analysis.addImportsForPromotedMethods(pkg)
 
+	// Fifth pass: plan the lowering of goto statements
+	analysis.analyzeGotos(pkg)
+
+	// Sixth pass: find pointer variables which are never nil
+	analysis.analyzeNonNilPointers(pkg)
+
+	// Seventh pass: find the type parameters which take type dictionaries
+	analysis.analyzeTypeDicts(pkg)
+
+	// Eighth pass: find the loops which may yield to other goroutines
+	analysis.analyzeYieldLoops(pkg)
+
+	// Ninth pass: find the files embedded with //go:embed
+	analysis.analyzeEmbeds(pkg)
+
+	// Tenth pass: find the named types which are enums
+	analysis.analyzeEnums(pkg)
+
+	// Eleventh pass: find the sealed interfaces
+	analysis.analyzeSealedInterfaces(pkg)
+
 	return analysis
 }
 
@@ -1870,93 +1975,102 @@ func AnalyzePackageImports(pkg *packages.Package) *PackageAnalysis {
 	return analysis
 }
 
-// LoadPackageMetadata loads metadata from gs packages using embedded JSON files
+// LoadPackageMetadata loads the meta.json metadata of the handwritten packages
 func (a *Analysis) LoadPackageMetadata() {
-	// Discover all packages in the embedded gs/ directory
-	packagePaths := a.discoverEmbeddedGsPackages()
-
-	for _, pkgPath := range packagePaths {
-		metaFilePath := filepath.Join("gs", pkgPath, "meta.json")
-
-		// Try to read the meta.json file from embedded filesystem
-		// We need access to the embedded FS, which should be imported from the parent package
-		if metadata := a.loadGsMetadata(metaFilePath); metadata != nil {
-			// Store async method information
-			for methodKey, isAsync := range metadata.AsyncMethods {
-				// Convert method key to our internal key format
-				parts := strings.Split(methodKey, ".")
-				var typeName, methodName string
-
-				if len(parts) == 2 {
-					// "Type.Method" format for methods
-					typeName = parts[0]
-					methodName = parts[1]
-				} else if len(parts) == 1 {
-					// "Function" format for package-level functions
-					typeName = "" // Empty type name for package-level functions
-					methodName = parts[0]
-				} else {
-					// Skip invalid formats
-					continue
-				}
+	for _, pkgPath := range a.overrides.packages() {
+		metadata, err := a.overrides.readMetadata(pkgPath)
+		if err != nil || metadata == nil {
+			// Skip packages without valid metadata
+			continue
+		}
 
-				// Use MethodKey instead of PackageMetadataKey for consistency
-				key := MethodKey{
-					PackagePath:  pkgPath,
-					ReceiverType: typeName,
-					MethodName:   methodName,
-				}
+		// Store async method information
+		for methodKey, isAsync := range metadata.AsyncMethods {
+			key, ok := parseMetadataMethodKey(pkgPath, methodKey)
+			if !ok {
+				// Skip invalid formats
+				continue
+			}
+
+			// Store the async value directly in MethodAsyncStatus
+			a.MethodAsyncStatus[key] = isAsync
+		}
+
+		// Store functions which accept bigint arguments
+		for methodKey, acceptsBigInt := range metadata.BigIntParams {
+			if key, ok := parseMetadataMethodKey(pkgPath, methodKey); ok {
+				a.BigIntParamFuncs[key] = acceptsBigInt
+			}
+		}
 
-				// Store the async value directly in MethodAsyncStatus
-				a.MethodAsyncStatus[key] = isAsync
+		// Store functions which are not implemented
+		for methodKey, reason := range metadata.Unsupported {
+			if key, ok := parseMetadataMethodKey(pkgPath, methodKey); ok {
+				a.UnsupportedFuncs[key] = reason
 			}
 		}
 	}
 }
 
-// discoverEmbeddedGsPackages finds all packages in the embedded gs/ directory
-func (a *Analysis) discoverEmbeddedGsPackages() []string {
-	var packageList []string
-
-	// Read the gs/ directory from the embedded filesystem
-	entries, err := goscript.GsOverrides.ReadDir("gs")
-	if err != nil {
-		// If we can't read the gs/ directory, return empty list
-		return packageList
-	}
+// parseMetadataMethodKey converts a meta.json method key ("Function" or
+// "Type.Method") to a MethodKey.
+func parseMetadataMethodKey(pkgPath, methodKey string) (MethodKey, bool) {
+	parts := strings.Split(methodKey, ".")
+	var typeName, methodName string
 
-	// Iterate through all entries in gs/
-	for _, entry := range entries {
-		if entry.IsDir() {
-			packageList = append(packageList, entry.Name())
-		}
+	if len(parts) == 2 {
+		// "Type.Method" format for methods
+		typeName = parts[0]
+		methodName = parts[1]
+	} else if len(parts) == 1 {
+		// "Function" format for package-level functions
+		typeName = "" // Empty type name for package-level functions
+		methodName = parts[0]
+	} else {
+		return MethodKey{}, false
 	}
 
-	return packageList
+	// Use MethodKey instead of PackageMetadataKey for consistency
+	return MethodKey{
+		PackagePath:  pkgPath,
+		ReceiverType: typeName,
+		MethodName:   methodName,
+	}, true
 }
 
-// loadGsMetadata loads metadata from a meta.json file in the embedded filesystem
-func (a *Analysis) loadGsMetadata(metaFilePath string) *GsMetadata {
-	// Read the meta.json file from the embedded filesystem
-	content, err := goscript.GsOverrides.ReadFile(metaFilePath)
-	if err != nil {
-		return nil // No metadata file found
+// funcMethodKey returns the MethodKey of the function or method fn, matching
+// the keys parsed by parseMetadataMethodKey.
+func funcMethodKey(fn *types.Func) MethodKey {
+	key := MethodKey{MethodName: fn.Name()}
+	if fn.Pkg() != nil {
+		key.PackagePath = fn.Pkg().Path()
 	}
-
-	var metadata GsMetadata
-	if err := json.Unmarshal(content, &metadata); err != nil {
-		return nil // Invalid JSON
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		recvType := recv.Type()
+		if ptr, ok := recvType.(*types.Pointer); ok {
+			recvType = ptr.Elem()
+		}
+		if named, ok := types.Unalias(recvType).(*types.Named); ok {
+			key.ReceiverType = named.Obj().Name()
+		}
 	}
+	return key
+}
 
-	return &metadata
+// discoverEmbeddedGsPackages finds all packages with metadata in the embedded gs/ directory
+func (a *Analysis) discoverEmbeddedGsPackages() []string {
+	return embeddedOverrides().packages()
+}
+
+// hasGsOverride checks if a package is replaced by a handwritten package instead of being compiled
+func (a *Analysis) hasGsOverride(pkgPath string) bool {
+	return a.overrides.lookup(pkgPath) != nil
 }
 
-// isHandwrittenPackage checks if a package path corresponds to a handwritten package in gs/
+// isHandwrittenPackage checks if a package path corresponds to a handwritten
+// package, with or without a meta.json
 func (a *Analysis) isHandwrittenPackage(pkgPath string) bool {
-	// Check if the package exists in the embedded gs/ directory
-	metaFilePath := filepath.Join("gs", pkgPath, "meta.json")
-	_, err := goscript.GsOverrides.ReadFile(metaFilePath)
-	return err == nil
+	return a.hasGsOverride(pkgPath)
 }
 
 // IsMethodAsync checks if a method call is async based on package metadata
@@ -2832,8 +2946,9 @@ func (v *analysisVisitor) buildMethodCallGraph() map[MethodKey][]MethodKey {
 						methodCalls[methodKey] = []MethodKey{}
 					}
 
-					// Extract method calls from the function body
-					if funcDecl.Body != nil {
+					// Extract method calls from the function body, unless
+					// the function is implemented in TypeScript
+					if funcDecl.Body != nil && !isExternFuncDecl(funcDecl) {
 						callees := v.extractMethodCalls(funcDecl.Body, pkg)
 						methodCalls[methodKey] = callees
 					}
@@ -3102,6 +3217,14 @@ func (v *analysisVisitor) analyzeMethodAsyncTopological(methodKey MethodKey, cal
 		funcDecl = v.findMethodDecl(methodKey.ReceiverType, methodKey.MethodName, pkg)
 	}
 
+	// Functions implemented in TypeScript declare whether they are async
+	if funcDecl != nil && funcDecl.Recv == nil {
+		if ext, _ := parseExternDirective(funcDecl.Doc, funcDecl.Name.Name); ext != nil {
+			v.analysis.MethodAsyncStatus[methodKey] = ext.async
+			return
+		}
+	}
+
 	if funcDecl == nil || funcDecl.Body == nil {
 		// No body to analyze, assume sync
 		v.analysis.MethodAsyncStatus[methodKey] = false
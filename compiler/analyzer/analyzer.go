@@ -0,0 +1,79 @@
+// Package analyzer provides a go/analysis Analyzer reporting Go code which
+// compiles with goscript but does not behave like in Go, like goto statements
+// goscript cannot lower, int64 values beyond 2^53, nil pointers converted to
+// interfaces, unsafe and unsupported functions of the handwritten packages.
+//
+// The checks are compiler.CheckPortability, which uses the analysis passes of
+// the compiler. The analyzer can be run by gopls, go vet -vettool or
+// goscript vet.
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/aperturerobotics/goscript/compiler"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// Analyzer reports Go code which goscript does not translate faithfully.
+var Analyzer = &analysis.Analyzer{
+	Name: "goscript",
+	Doc: `report Go code which goscript does not translate faithfully
+
+The goscript analyzer reports Go code which compiles with goscript but does
+not behave like in Go once translated to TypeScript:
+
+  - goto statements which cannot be lowered to labeled blocks and loops
+  - int64, uint64 and uintptr constants beyond 2^53 and bitwise operators
+    on them, which JavaScript numbers cannot represent (unless -int64-bigint)
+  - pointers which may be nil converted to an interface in composite
+    literals, channel sends, append and tuple assignments, which become nil
+    interfaces instead of interfaces holding a nil pointer
+  - uses of package unsafe
+  - functions the handwritten packages do not implement, like
+    runtime.SetFinalizer, fmt.Scan and reflect.Value.Call`,
+	URL: "https://github.com/aperturerobotics/goscript",
+	Run: run,
+}
+
+var (
+	// int64AsBigInt is the -int64-bigint flag, see compiler.Config.Int64AsBigInt.
+	int64AsBigInt bool
+	// overrideDirs is the -override-dirs flag, see compiler.Config.OverrideDirs.
+	overrideDirs string
+)
+
+func init() {
+	Analyzer.Flags.BoolVar(&int64AsBigInt, "int64-bigint", false, "int64, uint64 and uintptr are compiled as bigint")
+	Analyzer.Flags.StringVar(&overrideDirs, "override-dirs", "", "comma-separated directories of handwritten TypeScript packages")
+}
+
+// run reports the portability issues of the package.
+func run(pass *analysis.Pass) (any, error) {
+	config := &compiler.Config{Int64AsBigInt: int64AsBigInt}
+	if overrideDirs != "" {
+		config.OverrideDirs = strings.Split(overrideDirs, ",")
+	}
+	pkg := &packages.Package{
+		ID:        pass.Pkg.Path(),
+		Name:      pass.Pkg.Name(),
+		PkgPath:   pass.Pkg.Path(),
+		Fset:      pass.Fset,
+		Syntax:    pass.Files,
+		Types:     pass.Pkg,
+		TypesInfo: pass.TypesInfo,
+	}
+	for _, issue := range compiler.CheckPortability(pkg, config) {
+		msg := issue.Message
+		if issue.Suggestion != "" {
+			msg += " (" + issue.Suggestion + ")"
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      issue.Pos,
+			Category: issue.Category,
+			Message:  msg,
+		})
+	}
+	return nil, nil
+}
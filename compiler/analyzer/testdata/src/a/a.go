@@ -0,0 +1,65 @@
+package a
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+type Animal interface{ Sound() string }
+
+type Dog struct{}
+
+func (d *Dog) Sound() string { return "woof" }
+
+type Pair struct{ A Animal }
+
+func find() *Dog { return nil }
+
+func typedNils(ch chan Animal) {
+	d := find()
+	var a Animal = d // written as a typed nil
+	_ = []Animal{d}
+	_ = Pair{A: d}
+	ch <- d
+	var as []Animal
+	as = append(as, d)
+	var b Animal
+	a, b = d, &Dog{} // want `\*a.Dog which may be nil becomes a nil a.Animal here`
+	_, _, _ = a, b, as
+}
+
+const big int64 = 1 << 60 // want `constant 1152921504606846976 of type int64 is beyond 2\^53`
+
+const small int64 = 1 << 40
+
+func ints(x, y int64, i int) int64 {
+	_ = i & 1
+	_ = x + small
+	x ^= y       // want `operator \^= on int64 only uses the low 32 bits`
+	return x & y // want `operator & on int64 only uses the low 32 bits`
+}
+
+func unsafeUses(s []byte) {
+	_ = unsafe.Sizeof(s)                      // want `unsafe.Sizeof is not supported`
+	_ = unsafe.String(unsafe.SliceData(s), 1) // want `unsafe.String is not supported` `unsafe.SliceData is not supported`
+}
+
+func unsupported(v reflect.Value) {
+	var n int
+	fmt.Sscan("1", &n)            // want `fmt.Sscan is not supported by goscript: scanning is not implemented`
+	runtime.SetFinalizer(&n, nil) // want `runtime.SetFinalizer is not supported by goscript`
+	_ = v.MethodByName("Sound")   // want `\(reflect.Value\).MethodByName is not supported by goscript`
+	fmt.Println(v.Kind(), n)
+}
+
+func gotos(ch chan int) {
+	for {
+		select {
+		case <-ch:
+			goto done // want `goto done: jumping out of a type switch or select case is not supported`
+		}
+	}
+done:
+}
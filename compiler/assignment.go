@@ -39,6 +39,21 @@ func (c *GoToTSCompiler) writeAssignmentCore(lhs, rhs []ast.Expr, tok token.Toke
 			return c.writeBlankIdentifierAssign(rhs[0])
 		}
 
+		// Handle compound assignments on int64 and uint64 in bigint mode
+		if handled, err := c.writeBigIntCompoundAssign(lhs[0], rhs[0], tok); handled {
+			return err
+		}
+
+		// Handle compound assignments on complex values
+		if handled, err := c.writeComplexCompoundAssign(lhs[0], rhs[0], tok); handled {
+			return err
+		}
+
+		// Handle compound assignments which wrap in strict integer mode
+		if handled, err := c.writeStrictIntCompoundAssign(lhs[0], rhs[0], tok); handled {
+			return err
+		}
+
 		// Handle the special case of "*p = val" or "*p += val" (assignment to dereferenced pointer)
 		if starExpr, ok := lhs[0].(*ast.StarExpr); ok {
 			return c.writePointerDerefAssign(starExpr, rhs[0], tok)
@@ -310,7 +325,11 @@ func (c *GoToTSCompiler) writeAssignmentCore(lhs, rhs []ast.Expr, tok token.Toke
 				continue
 			}
 
-			if err := c.WriteValueExpr(r); err != nil { // RHS is a non-struct value
+			var lhsType types.Type
+			if len(lhs) == len(rhs) {
+				lhsType = c.pkg.TypesInfo.TypeOf(lhs[i])
+			}
+			if err := c.writeInterfaceValue(r, lhsType); err != nil { // RHS is a non-struct value
 				return err
 			}
 		}
@@ -2,8 +2,11 @@ package compiler
 
 import (
 	"fmt"
+	"go/token"
 	"io"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // TSCodeWriter writes TypeScript code.
@@ -12,6 +15,14 @@ type TSCodeWriter struct {
 	indentLevel        int
 	sectionWrittenFlag bool
 	lineWritten        bool
+
+	// line and col are the current output position, see SourceMap.
+	line, col int
+	// fset and sourceMap are set if positions are recorded.
+	fset      *token.FileSet
+	sourceMap *SourceMap
+	// pendingPos is the Go position of the next code written.
+	pendingPos token.Pos
 }
 
 // NewTSCodeWriter builds a new TypeScript code writer.
@@ -19,12 +30,56 @@ func NewTSCodeWriter(w io.Writer) *TSCodeWriter {
 	return &TSCodeWriter{w: w}
 }
 
+// SetSourceMap makes the writer record the Go positions marked with MarkPos
+// in the source map, resolving them with fset.
+func (w *TSCodeWriter) SetSourceMap(fset *token.FileSet, sourceMap *SourceMap) {
+	w.fset = fset
+	w.sourceMap = sourceMap
+}
+
+// MarkPos marks the next code written as translated from the Go code at pos.
+// It has no effect unless a source map was set with SetSourceMap.
+func (w *TSCodeWriter) MarkPos(pos token.Pos) {
+	if w.sourceMap != nil && pos.IsValid() {
+		w.pendingPos = pos
+	}
+}
+
+// write writes b to the output, keeping track of the output position.
+func (w *TSCodeWriter) write(b []byte) {
+	w.w.Write(b) //nolint:errcheck
+	if w.sourceMap == nil {
+		return
+	}
+	for len(b) != 0 {
+		if b[0] == '\n' {
+			w.line++
+			w.col = 0
+			b = b[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(b)
+		w.col += utf16.RuneLen(r)
+		b = b[size:]
+	}
+}
+
+// writeCode writes code to the output, adding the pending source mapping.
+func (w *TSCodeWriter) writeCode(b []byte) {
+	if w.pendingPos.IsValid() && len(b) != 0 {
+		position := w.fset.Position(w.pendingPos)
+		w.sourceMap.AddMapping(w.line, w.col, position.Filename, position.Line-1, position.Column-1)
+		w.pendingPos = token.NoPos
+	}
+	w.write(b)
+}
+
 // WriteLinePreamble writes the indentation.
 func (w *TSCodeWriter) WriteLinePreamble() {
 	w.sectionWrittenFlag = true
 	w.lineWritten = false
 	for range w.indentLevel {
-		w.w.Write([]byte{byte('\t')}) //nolint:errcheck
+		w.write([]byte{byte('\t')})
 	}
 }
 
@@ -33,8 +88,8 @@ func (w *TSCodeWriter) WriteLine(line string) {
 	if line != "" && w.lineWritten {
 		w.WriteLinePreamble()
 	}
-	w.w.Write([]byte(line))       //nolint:errcheck
-	w.w.Write([]byte{byte('\n')}) //nolint:errcheck
+	w.writeCode([]byte(line))
+	w.write([]byte{byte('\n')})
 	w.lineWritten = true
 }
 
@@ -76,9 +131,9 @@ func (w *TSCodeWriter) WriteCommentLinef(format string, args ...any) {
 
 // WriteCommentInline write a comment within /* */.
 func (w *TSCodeWriter) WriteCommentInline(commentText string) {
-	w.w.Write([]byte("/* "))       //nolint:errcheck
-	w.w.Write([]byte(commentText)) //nolint:errcheck
-	w.w.Write([]byte(" */"))       //nolint:errcheck
+	w.write([]byte("/* "))
+	w.write([]byte(commentText))
+	w.write([]byte(" */"))
 }
 
 // WriteCommentInlinef writes a formatted comment within /* */.
@@ -92,7 +147,7 @@ func (w *TSCodeWriter) WriteLiterally(literal string) {
 	if w.lineWritten {
 		w.WriteLinePreamble()
 	}
-	w.w.Write([]byte(literal)) //nolint:errcheck
+	w.writeCode([]byte(literal))
 }
 
 // WriteLiterallyf writes something to the output with formatting.
@@ -103,7 +158,7 @@ func (w *TSCodeWriter) WriteLiterallyf(literal string, args ...any) {
 	}
 
 	l := fmt.Sprintf(literal, args...)
-	w.w.Write([]byte(l)) //nolint:errcheck
+	w.writeCode([]byte(l))
 }
 
 // WriteSectionTail writes the end of a section.
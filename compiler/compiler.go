@@ -5,12 +5,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/constant"
 	"go/token"
 	"go/types"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -28,6 +31,9 @@ type Compiler struct {
 	le     *logrus.Entry
 	config Config
 	opts   packages.Config
+
+	// overrides finds the handwritten packages replacing Go packages.
+	overrides *overrides
 }
 
 // NewCompiler builds a new Compiler instance.
@@ -61,6 +67,7 @@ func NewCompiler(conf *Config, le *logrus.Entry, opts *packages.Config) (*Compil
 	// NeedSyntax adds Syntax.
 	// NeedTypesInfo adds TypesInfo.
 	// NeedTypesSizes adds TypesSizes.
+	// NeedEmbedFiles and NeedEmbedPatterns add EmbedFiles and EmbedPatterns.
 	// TODO: disable these if not needed
 	opts.Mode |= packages.NeedName |
 		packages.NeedFiles |
@@ -71,13 +78,22 @@ func NewCompiler(conf *Config, le *logrus.Entry, opts *packages.Config) (*Compil
 		packages.NeedTypes |
 		packages.NeedSyntax |
 		packages.NeedTypesInfo |
-		packages.NeedTypesSizes
+		packages.NeedTypesSizes |
+		packages.NeedEmbedFiles |
+		packages.NeedEmbedPatterns
 
-	return &Compiler{
+	c := &Compiler{
 		config: *conf,
 		le:     le,
 		opts:   *opts,
-	}, nil
+	}
+	overrideDirs, err := conf.absOverrideDirs()
+	if err != nil {
+		return nil, err
+	}
+	c.config.OverrideDirs = overrideDirs
+	c.overrides = newOverrides(overrideDirs)
+	return c, nil
 }
 
 // CompilationResult contains information about what was compiled
@@ -88,6 +104,12 @@ type CompilationResult struct {
 	CopiedPackages []string
 	// OriginalPackages contains the package paths that were explicitly requested for compilation
 	OriginalPackages []string
+	// ReusedPackages contains the package paths of the packages which were up to date
+	// and not compiled again with Config.Incremental
+	ReusedPackages []string
+	// Diagnostics contains the problems found in all the packages. If there are
+	// errors, CompilePackages returns a DiagnosticsError with them.
+	Diagnostics []Diagnostic
 }
 
 // CompilePackages loads Go packages based on the provided patterns and
@@ -98,7 +120,33 @@ type CompilationResult struct {
 // If c.config.AllDependencies is true, it will also compile all dependencies
 // of the requested packages, including standard library dependencies.
 // Returns a CompilationResult with information about what was compiled.
+// Packages which fail to load or compile do not stop the compile of the
+// others: their problems are collected in the Diagnostics of the result, which
+// is returned along with a *DiagnosticsError.
 func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*CompilationResult, error) {
+	pkgs, patternPkgPaths, err := c.loadPackages(ctx, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompilationResult{
+		OriginalPackages: patternPkgPaths,
+	}
+	if err := c.compileLoadedPackages(ctx, pkgs, patternPkgPaths, result); err != nil {
+		var diagErr *DiagnosticsError
+		if errors.As(err, &diagErr) {
+			return result, err
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// loadPackages loads the packages matched by patterns, and all their
+// dependencies if c.config.AllDependencies is true. It returns the packages
+// to compile and the paths of the packages matched by patterns.
+func (c *Compiler) loadPackages(ctx context.Context, patterns []string) ([]*packages.Package, []string, error) {
 	opts := c.opts
 	opts.Context = ctx
 
@@ -106,7 +154,7 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 	opts.Mode |= packages.NeedImports
 	pkgs, err := packages.Load(&opts, patterns...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load packages: %w", err)
+		return nil, nil, fmt.Errorf("failed to load packages: %w", err)
 	}
 
 	// build a list of packages that patterns matched
@@ -115,60 +163,9 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 		patternPkgPaths = append(patternPkgPaths, pkg.PkgPath)
 	}
 
-	result := &CompilationResult{
-		OriginalPackages: patternPkgPaths,
-	}
-
 	// If AllDependencies is true, we need to collect all dependencies
 	if c.config.AllDependencies {
-		// Create a set to track processed packages by their ID
-		processed := make(map[string]bool)
-		var allPkgs []*packages.Package
-
-		// Helper function to check if a package has a handwritten equivalent
-		hasHandwrittenEquivalent := func(pkgPath string) bool {
-			gsSourcePath := "gs/" + pkgPath
-			_, gsErr := gs.GsOverrides.ReadDir(gsSourcePath)
-			return gsErr == nil
-		}
-
-		// Visit all packages and their dependencies
-		var visit func(pkg *packages.Package)
-		visit = func(pkg *packages.Package) {
-			if pkg == nil || processed[pkg.ID] {
-				return
-			}
-			processed[pkg.ID] = true
-
-			// Add this package to the list of all packages
-			allPkgs = append(allPkgs, pkg)
-
-			// Check if this package has a handwritten equivalent
-			if hasHandwrittenEquivalent(pkg.PkgPath) {
-				// Add this package but don't visit its dependencies
-				return
-			}
-
-			// Visit all imports, including standard library packages
-			for _, imp := range pkg.Imports {
-				// Skip protobuf-go-lite packages and their dependencies
-				if isProtobufGoLitePackage(imp.PkgPath) {
-					continue
-				}
-
-				// Skip packages that are only used by .pb.go files
-				if isPackageOnlyUsedByProtobufFiles(pkg, imp.PkgPath) {
-					continue
-				}
-
-				visit(imp)
-			}
-		}
-
-		// Start visiting from the initial packages
-		for _, pkg := range pkgs {
-			visit(pkg)
-		}
+		allPkgs := c.collectDependencies(pkgs)
 
 		// Now we have collected all dependencies, but they only have minimal information.
 		// We need to reload them with complete type information for compilation.
@@ -188,11 +185,11 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 			fullOpts := c.opts
 			fullOpts.Context = ctx
 			// Use LoadAllSyntax to get complete type information, syntax trees, and type checking
-			fullOpts.Mode = packages.LoadAllSyntax
+			fullOpts.Mode = packages.LoadAllSyntax | packages.NeedEmbedFiles | packages.NeedEmbedPatterns
 
 			reloadedPkgs, err := packages.Load(&fullOpts, pkgPaths...)
 			if err != nil {
-				return nil, fmt.Errorf("failed to reload packages with complete type information: %w", err)
+				return nil, nil, fmt.Errorf("failed to reload packages with complete type information: %w", err)
 			}
 
 			// Replace the minimal packages with the fully loaded ones
@@ -203,13 +200,72 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 		}
 	}
 
+	return pkgs, patternPkgPaths, nil
+}
+
+// collectDependencies returns pkgs and all the packages they depend on, in
+// visiting order. The dependencies of packages with a handwritten equivalent
+// are not visited, as the handwritten package is copied instead.
+func (c *Compiler) collectDependencies(pkgs []*packages.Package) []*packages.Package {
+	// Create a set to track processed packages by their ID
+	processed := make(map[string]bool)
+	var allPkgs []*packages.Package
+
+	// Visit all packages and their dependencies
+	var visit func(pkg *packages.Package)
+	visit = func(pkg *packages.Package) {
+		if pkg == nil || processed[pkg.ID] {
+			return
+		}
+		processed[pkg.ID] = true
+
+		// Add this package to the list of all packages
+		allPkgs = append(allPkgs, pkg)
+
+		// Check if this package has a handwritten equivalent
+		if c.overrides.lookup(pkg.PkgPath) != nil {
+			// Add this package but don't visit its dependencies
+			return
+		}
+
+		// Visit all imports, including standard library packages
+		for _, imp := range pkg.Imports {
+			// Skip protobuf-go-lite packages and their dependencies
+			if isProtobufGoLitePackage(imp.PkgPath) {
+				continue
+			}
+
+			// Skip packages that are only used by .pb.go files
+			if isPackageOnlyUsedByProtobufFiles(pkg, imp.PkgPath) {
+				continue
+			}
+
+			visit(imp)
+		}
+	}
+
+	// Start visiting from the initial packages
+	for _, pkg := range pkgs {
+		visit(pkg)
+	}
+
+	return allPkgs
+}
+
+// compileLoadedPackages compiles the loaded pkgs into the output path and
+// records them in result. Packages which are not in patternPkgPaths and have
+// a handwritten equivalent are copied instead of compiled.
+func (c *Compiler) compileLoadedPackages(ctx context.Context, pkgs []*packages.Package, patternPkgPaths []string, result *CompilationResult) error {
 	// If DisableEmitBuiltin is false, we need to copy the builtin package to the output directory
 	if !c.config.DisableEmitBuiltin {
 		c.le.Debugf("Copying builtin package to output directory")
-		builtinPath := "gs/builtin"
+		builtinFS, err := fs.Sub(gs.GsOverrides, "gs/builtin")
+		if err != nil {
+			return err
+		}
 		outputPath := ComputeModulePath(c.config.OutputPath, "builtin")
-		if err := c.copyEmbeddedPackage(builtinPath, outputPath); err != nil {
-			return nil, fmt.Errorf("failed to copy builtin package to output directory: %w", err)
+		if err := c.copyPackageFiles(builtinFS, ".", outputPath); err != nil {
+			return fmt.Errorf("failed to copy builtin package to output directory: %w", err)
 		}
 		result.CopiedPackages = append(result.CopiedPackages, "builtin")
 	}
@@ -223,17 +279,15 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 		allPackages[pkg.PkgPath] = pkg
 	}
 
+	// Packages to compile incrementally
+	var compilePkgs []*packages.Package
+
 	// Compile all packages
 	for _, pkg := range pkgs {
 		// Check if the package has a handwritten equivalent
 		// If the package was explicitly requested, skip this logic
 		if !slices.Contains(patternPkgPaths, pkg.PkgPath) {
-			gsSourcePath := "gs/" + pkg.PkgPath
-			_, gsErr := gs.GsOverrides.ReadDir(gsSourcePath)
-			if gsErr != nil && !os.IsNotExist(gsErr) {
-				return nil, gsErr
-			}
-			if gsErr == nil {
+			if c.overrides.lookup(pkg.PkgPath) != nil {
 				if c.config.DisableEmitBuiltin {
 					// c.le.Infof("Skipping compilation for overridden package %s", pkg.PkgPath)
 					result.CopiedPackages = append(result.CopiedPackages, pkg.PkgPath)
@@ -241,30 +295,36 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 				} else {
 					// If DisableEmitBuiltin is false, we need to copy the handwritten package and its dependencies
 					if err := c.copyGsPackageWithDependencies(pkg.PkgPath, processedGsPackages, result); err != nil {
-						return nil, fmt.Errorf("failed to copy handwritten package %s with dependencies: %w", pkg.PkgPath, err)
+						return fmt.Errorf("failed to copy handwritten package %s with dependencies: %w", pkg.PkgPath, err)
 					}
 					continue
 				}
 			}
 		}
 
-		// Fail fast on packages that failed to load to avoid hiding errors
+		// Report the packages that failed to load instead of compiling them
 		if len(pkg.Errors) > 0 {
-			var msgs []string
-			for _, e := range pkg.Errors {
-				// packages.Error is a struct; collect all messages
-				msgs = append(msgs, e.Error())
-			}
-			return nil, fmt.Errorf("package %s has load errors: %s", pkg.PkgPath, strings.Join(msgs, "; "))
+			result.Diagnostics = append(result.Diagnostics, packageDiagnostics(pkg)...)
+			continue
+		}
+
+		if c.config.Incremental {
+			compilePkgs = append(compilePkgs, pkg)
+			continue
 		}
 
 		pkgCompiler, err := NewPackageCompiler(c.le, &c.config, pkg, allPackages)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create package compiler for %s: %w", pkg.PkgPath, err)
+			return fmt.Errorf("failed to create package compiler for %s: %w", pkg.PkgPath, err)
 		}
+		pkgCompiler.overrides = c.overrides
 
-		if err := pkgCompiler.Compile(ctx); err != nil {
-			return nil, fmt.Errorf("failed to compile package %s: %w", pkg.PkgPath, err)
+		err = pkgCompiler.Compile(ctx)
+		if err := addDiagnostics(result, pkgCompiler.diagnostics, err); err != nil {
+			return fmt.Errorf("failed to compile package %s: %w", pkg.PkgPath, err)
+		}
+		if err != nil {
+			continue
 		}
 
 		c.le.Info(pkg.PkgPath)
@@ -272,7 +332,14 @@ func (c *Compiler) CompilePackages(ctx context.Context, patterns ...string) (*Co
 		result.CompiledPackages = append(result.CompiledPackages, pkg.PkgPath)
 	}
 
-	return result, nil
+	// Compile the packages which are not up to date
+	if c.config.Incremental {
+		if err := c.compileIncremental(ctx, compilePkgs, allPackages, patternPkgPaths, result); err != nil {
+			return err
+		}
+	}
+
+	return diagnosticsError(result.Diagnostics)
 }
 
 // PackageCompiler is responsible for compiling an entire Go package into
@@ -284,6 +351,19 @@ type PackageCompiler struct {
 	outputPath   string
 	pkg          *packages.Package
 	allPackages  map[string]*packages.Package
+
+	// analysis is the analysis of the package, set by Compile.
+	analysis *Analysis
+	// compiledFiles are the files re-exported by index.ts, set by Compile.
+	compiledFiles []string
+	// extraFiles are the files generated besides the compiled files and
+	// index.ts, like facade.ts, set by Compile.
+	extraFiles []string
+	// diagnostics are the problems found in the files, set by Compile.
+	diagnostics []Diagnostic
+	// overrides finds the handwritten packages replacing Go packages, the
+	// ones of goscript if nil.
+	overrides *overrides
 }
 
 // NewPackageCompiler creates a new `PackageCompiler` for a given Go package.
@@ -331,7 +411,10 @@ func (c *PackageCompiler) Compile(ctx context.Context) error {
 	packageAnalysis := AnalyzePackageImports(c.pkg)
 
 	// Perform comprehensive package-level analysis for code generation
-	analysis := AnalyzePackageFiles(c.pkg, c.allPackages)
+	analysis := analyzePackageFiles(c.pkg, c.allPackages, c.overrides)
+	if c.compilerConf.SealedUnions {
+		packageAnalysis.addSealedUnionImports(c.pkg, analysis)
+	}
 
 	// Track all compiled files for later generating the index.ts
 	compiledFiles := make([]string, 0, len(c.pkg.CompiledGoFiles))
@@ -369,7 +452,14 @@ func (c *PackageCompiler) Compile(ctx context.Context) error {
 
 		// log just the filename
 		c.le.Debugf("GS: %s", filepath.Base(fileName))
-		if err := c.CompileFile(ctx, fileName, f, analysis, packageAnalysis); err != nil {
+		fileDiags, err := c.CompileFile(ctx, fileName, f, analysis, packageAnalysis)
+		c.diagnostics = append(c.diagnostics, fileDiags...)
+		if err != nil {
+			// Go on with the other files to report all the problems
+			var diagErr *DiagnosticsError
+			if errors.As(err, &diagErr) {
+				continue
+			}
 			return err
 		}
 
@@ -379,11 +469,42 @@ func (c *PackageCompiler) Compile(ctx context.Context) error {
 		compiledFiles = append(compiledFiles, gsFileName)
 	}
 
+	if err := diagnosticsError(c.diagnostics); err != nil {
+		return err
+	}
+
+	// Copy the TypeScript modules implementing //goscript:extern functions
+	if err := c.copyExternModules(); err != nil {
+		return err
+	}
+
+	c.analysis = analysis
+	c.compiledFiles = compiledFiles
+
 	// After compiling all files, generate the index.ts file
 	if err := c.generateIndexFile(compiledFiles); err != nil {
 		return err
 	}
 
+	// Generate the facade with the analysis of the async functions
+	if c.compilerConf.Facade {
+		if err := c.generateFacadeFile(); err != nil {
+			return err
+		}
+		c.extraFiles = append(c.extraFiles, facadeFileName)
+	}
+
+	// Generate the schemas of the structs
+	if c.compilerConf.Schemas {
+		written, err := c.generateSchemaFile()
+		if err != nil {
+			return err
+		}
+		if written {
+			c.extraFiles = append(c.extraFiles, schemaFileName)
+		}
+	}
+
 	return nil
 }
 
@@ -465,6 +586,12 @@ func (c *PackageCompiler) generateIndexFile(compiledFiles []string) error {
 								if _, isStruct := s.Type.(*ast.StructType); isStruct {
 									// Structs become TypeScript classes and need both type and value exports
 									structSymbols = append(structSymbols, sanitizeIdentifier(s.Name.Name))
+								} else if typeName, ok := c.pkg.TypesInfo.Defs[s.Name].(*types.TypeName); ok && c.compilerConf.EnumUnions && c.analysis.EnumTypes[typeName] != nil {
+									// Enums are both a union type and a const object of the constants
+									structSymbols = append(structSymbols, s.Name.Name)
+									if c.analysis.EnumTypes[typeName].Names != nil {
+										valueSymbols = append(valueSymbols, s.Name.Name+"_name", s.Name.Name+"_value")
+									}
 								} else {
 									// Other type declarations (interfaces, type definitions, type aliases)
 									// become TypeScript types and must be exported with "export type"
@@ -522,13 +649,15 @@ func (c *PackageCompiler) generateIndexFile(compiledFiles []string) error {
 // It uses the pre-computed package-level analysis for accurate TypeScript generation
 // (e.g., about varRefing, async functions, defer statements, receiver usage across files).
 // Then, it creates a `FileCompiler` instance for the file and invokes its
-// `Compile` method to generate the TypeScript code.
-func (p *PackageCompiler) CompileFile(ctx context.Context, name string, syntax *ast.File, analysis *Analysis, packageAnalysis *PackageAnalysis) error {
+// `Compile` method to generate the TypeScript code. It returns the problems
+// found in the file.
+func (p *PackageCompiler) CompileFile(ctx context.Context, name string, syntax *ast.File, analysis *Analysis, packageAnalysis *PackageAnalysis) ([]Diagnostic, error) {
 	fileCompiler, err := NewFileCompiler(p.compilerConf, p.pkg, syntax, name, analysis, packageAnalysis)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return fileCompiler.Compile(ctx)
+	err = fileCompiler.Compile(ctx)
+	return fileCompiler.Diagnostics, err
 }
 
 // FileCompiler is responsible for compiling a single Go source file (`ast.File`)
@@ -543,6 +672,9 @@ type FileCompiler struct {
 	fullPath        string
 	Analysis        *Analysis
 	PackageAnalysis *PackageAnalysis
+
+	// Diagnostics are the problems found by Compile.
+	Diagnostics []Diagnostic
 }
 
 // NewFileCompiler creates a new `FileCompiler` for a specific Go file.
@@ -592,8 +724,14 @@ func (c *FileCompiler) Compile(ctx context.Context) error {
 
 	c.codeWriter = NewTSCodeWriter(of)
 
+	var sourceMap *SourceMap
+	if c.compilerConfig.SourceMap {
+		sourceMap = NewSourceMap(filepath.Base(outputFilePathAbs))
+		c.codeWriter.SetSourceMap(c.pkg.Fset, sourceMap)
+	}
+
 	// Pass analysis to compiler
-	goWriter := NewGoToTSCompiler(c.codeWriter, c.pkg, c.Analysis, c.fullPath)
+	goWriter := NewGoToTSCompiler(c.codeWriter, c.pkg, c.Analysis, c.compilerConfig, c.fullPath)
 
 	// Add import for the goscript runtime using namespace import and alias
 	c.codeWriter.WriteLinef("import * as $ from %q", "@goscript/builtin/index.js")
@@ -603,6 +741,9 @@ func (c *FileCompiler) Compile(ctx context.Context) error {
 		return fmt.Errorf("failed to add protobuf imports: %w", err)
 	}
 
+	// Import the TypeScript modules implementing //goscript:extern functions
+	goWriter.writeExternImports(f)
+
 	// Generate auto-imports for functions from other files in the same package
 	currentFileName := strings.TrimSuffix(filepath.Base(c.fullPath), ".go")
 	if imports := c.PackageAnalysis.FunctionCalls[currentFileName]; imports != nil {
@@ -721,6 +862,34 @@ func (c *FileCompiler) Compile(ctx context.Context) error {
 		return fmt.Errorf("failed to write declarations: %w", err)
 	}
 
+	// Fail after writing all the declarations to report all the problems
+	c.Diagnostics = goWriter.diagnostics
+	if err := diagnosticsError(c.Diagnostics); err != nil {
+		return err
+	}
+
+	if sourceMap != nil {
+		return c.writeSourceMap(sourceMap, outputFilePathAbs)
+	}
+
+	return nil
+}
+
+// writeSourceMap writes the source map of the generated file outputFilePathAbs
+// to outputFilePathAbs + ".map" and links it with a sourceMappingURL comment.
+func (c *FileCompiler) writeSourceMap(sourceMap *SourceMap, outputFilePathAbs string) error {
+	data, err := sourceMap.MarshalSourceMap(
+		filepath.Dir(outputFilePathAbs),
+		c.compilerConfig.SourceMapSourcesContent,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to encode source map: %w", err)
+	}
+	mapPath := outputFilePathAbs + ".map"
+	if err := os.WriteFile(mapPath, data, 0o644); err != nil {
+		return err
+	}
+	c.codeWriter.WriteLinef("//# sourceMappingURL=%s", filepath.Base(mapPath))
 	return nil
 }
 
@@ -734,6 +903,9 @@ type GoToTSCompiler struct {
 
 	analysis *Analysis
 
+	// config is the compiler configuration, used for code generation options.
+	config *Config
+
 	// currentFilePath is the path of the file being compiled
 	// Used for looking up per-file synthetic imports
 	currentFilePath string
@@ -744,17 +916,35 @@ type GoToTSCompiler struct {
 	// renamedVars tracks variables that have been renamed to avoid type shadowing
 	// Key: types.Object of the original variable, Value: new name to use
 	renamedVars map[types.Object]string
+
+	// asyncFuncs tracks whether the function declarations and literals
+	// written so far are async functions, see writeYieldPoint.
+	asyncFuncs map[ast.Node]bool
+
+	// externFuncs maps the functions of the file implemented in TypeScript
+	// to their implementation, see writeExternImports.
+	externFuncs map[*ast.FuncDecl]*externFunc
+
+	// diagnostics are the problems found in the file, see writeRecovering.
+	diagnostics []Diagnostic
 }
 
 // NewGoToTSCompiler creates a new GoToTSCompiler with a TSCodeWriter for output,
-// Go package information, pre-computed analysis results, and the current file path.
-func NewGoToTSCompiler(tsw *TSCodeWriter, pkg *packages.Package, analysis *Analysis, filePath string) *GoToTSCompiler {
+// Go package information, pre-computed analysis results, the compiler configuration,
+// and the current file path.
+func NewGoToTSCompiler(tsw *TSCodeWriter, pkg *packages.Package, analysis *Analysis, config *Config, filePath string) *GoToTSCompiler {
+	if config == nil {
+		config = &Config{}
+	}
 	return &GoToTSCompiler{
 		tsw:             tsw,
 		pkg:             pkg,
 		analysis:        analysis,
+		config:          config,
 		currentFilePath: filePath,
 		renamedVars:     make(map[types.Object]string),
+		asyncFuncs:      make(map[ast.Node]bool),
+		externFuncs:     make(map[*ast.FuncDecl]*externFunc),
 	}
 }
 
@@ -872,6 +1062,15 @@ func (c *GoToTSCompiler) WriteIdent(exp *ast.Ident, accessVarRefedValue bool) {
 				// For now, let's be conservative and evaluate current package constants
 				// to literals to maintain compatibility with existing behavior.
 				// This handles iota-based constants correctly.
+				if c.isEnumType(constObj.Type()) {
+					// Keep the type of the value when it is inferred
+					c.tsw.WriteLiterally("(")
+					c.writeConstantValue(constObj)
+					c.tsw.WriteLiterally(" as ")
+					c.WriteGoType(constObj.Type(), GoTypeContextGeneral)
+					c.tsw.WriteLiterally(")")
+					return
+				}
 				c.writeConstantValue(constObj)
 				return
 			}
@@ -914,7 +1113,10 @@ func (c *GoToTSCompiler) WriteCaseClause(exp *ast.CaseClause) error {
 		// break
 		for i, caseExpr := range exp.List {
 			c.tsw.WriteLiterally("case ")
-			if err := c.WriteValueExpr(caseExpr); err != nil {
+			if tv := c.pkg.TypesInfo.Types[caseExpr]; tv.Value != nil && c.isEnumType(tv.Type) {
+				// Case labels do not need the type of enum constants
+				c.writeEnumValue(tv.Value)
+			} else if err := c.WriteValueExpr(caseExpr); err != nil {
 				return fmt.Errorf("failed to write case clause expression: %w", err)
 			}
 			c.tsw.WriteLiterally(":")
@@ -930,10 +1132,14 @@ func (c *GoToTSCompiler) WriteCaseClause(exp *ast.CaseClause) error {
 	// The body is written once, after all case labels for this clause.
 	// Wrap in block to provide Go-like case scope semantics.
 	c.tsw.Indent(1)
-	for _, stmt := range exp.Body {
+	for i, stmt := range exp.Body {
+		if err := c.writeGotoRegionsOpen(exp, i); err != nil {
+			return err
+		}
 		if err := c.WriteStmt(stmt); err != nil {
 			return fmt.Errorf("failed to write statement in case clause body: %w", err)
 		}
+		c.writeGotoRegionsClose(exp, i, stmt)
 	}
 	// Add break statement (Go's switch has implicit breaks, TS needs explicit break)
 	c.tsw.WriteLine("break")
@@ -1098,9 +1304,20 @@ func (c *GoToTSCompiler) writeConstantValue(constObj *types.Const) {
 	switch val.Kind() {
 	case constant.Int:
 		// For integer constants, write the string representation
+		if c.isBigIntType(constObj.Type()) && c.writeBigIntConstant(val) {
+			return
+		}
+		if isComplexType(constObj.Type()) {
+			c.writeComplexConstant(val, constObj.Type())
+			return
+		}
 		c.tsw.WriteLiterally(val.String())
 	case constant.Float:
 		// For float constants, write the string representation
+		if isComplexType(constObj.Type()) {
+			c.writeComplexConstant(val, constObj.Type())
+			return
+		}
 		c.tsw.WriteLiterally(val.String())
 	case constant.String:
 		// For string constants, write as a quoted string literal
@@ -1116,33 +1333,33 @@ func (c *GoToTSCompiler) writeConstantValue(constObj *types.Const) {
 			c.tsw.WriteLiterally("false")
 		}
 	case constant.Complex:
-		// For complex constants, we need to handle them specially
-		// For now, write as a comment indicating unsupported
-		c.tsw.WriteLiterally("/* complex constant: " + val.String() + " */")
+		// For complex constants, write a $.complex value
+		c.writeComplexConstant(val, constObj.Type())
 	default:
 		// For unknown constant types, write as a comment
 		c.tsw.WriteLiterally("/* unknown constant: " + val.String() + " */")
 	}
 }
 
-// copyEmbeddedPackage recursively copies files from an embedded FS path to a filesystem directory.
+// copyPackageFiles recursively copies the files in the directory dir of the
+// handwritten package fsys to a filesystem directory.
 // It handles both regular files and directories, but only copies .gs.ts and .ts files.
 // It preserves existing subdirectories that aren't being overwritten.
-func (c *Compiler) copyEmbeddedPackage(embeddedPath string, outputPath string) error {
+func (c *Compiler) copyPackageFiles(fsys fs.FS, dir string, outputPath string) error {
 	// Create the output path if it doesn't exist
 	if err := os.MkdirAll(outputPath, 0o755); err != nil {
 		return fmt.Errorf("failed to create output directory %s: %w", outputPath, err)
 	}
 
-	// List the entries in the embedded path
-	entries, err := gs.GsOverrides.ReadDir(embeddedPath)
+	// List the entries in the package directory
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
-		return fmt.Errorf("failed to read embedded directory %s: %w", embeddedPath, err)
+		return fmt.Errorf("failed to read package directory %s: %w", dir, err)
 	}
 
 	// Process each entry
 	for _, entry := range entries {
-		entryPath := filepath.Join(embeddedPath, entry.Name())
+		entryPath := path.Join(dir, entry.Name())
 		outputEntryPath := filepath.Join(outputPath, entry.Name())
 
 		if entry.IsDir() {
@@ -1152,7 +1369,7 @@ func (c *Compiler) copyEmbeddedPackage(embeddedPath string, outputPath string) e
 			}
 
 			// Recursively copy the directory contents
-			if err := c.copyEmbeddedPackage(entryPath, outputEntryPath); err != nil {
+			if err := c.copyPackageFiles(fsys, entryPath, outputEntryPath); err != nil {
 				return err
 			}
 		} else {
@@ -1176,10 +1393,10 @@ func (c *Compiler) copyEmbeddedPackage(embeddedPath string, outputPath string) e
 				}
 			}
 
-			// Read the file content from the embedded FS
-			content, err := gs.GsOverrides.ReadFile(entryPath)
+			// Read the file content from the package
+			content, err := fs.ReadFile(fsys, entryPath)
 			if err != nil {
-				return fmt.Errorf("failed to read embedded file %s: %w", entryPath, err)
+				return fmt.Errorf("failed to read package file %s: %w", entryPath, err)
 			}
 
 			// Write the content to the output file
@@ -1199,16 +1416,22 @@ type GsPackageMetadata struct {
 	AsyncMethods map[string]bool `json:"asyncMethods,omitempty"`
 }
 
-// ReadGsPackageMetadata reads dependency metadata from meta.json file in a gs/ package
+// ReadGsPackageMetadata reads dependency metadata from meta.json file in a gs/ package.
+// gsSourcePath is "gs/" followed by the import path of the package, which is
+// looked up in the override directories before the packages built into goscript.
 func (c *Compiler) ReadGsPackageMetadata(gsSourcePath string) (*GsPackageMetadata, error) {
 	metadata := &GsPackageMetadata{
 		Dependencies: []string{},
 		AsyncMethods: make(map[string]bool),
 	}
 
+	pkgFS := c.overrides.lookup(strings.TrimPrefix(gsSourcePath, "gs/"))
+	if pkgFS == nil {
+		return metadata, nil
+	}
+
 	// Try to read meta.json file
-	metaFilePath := filepath.Join(gsSourcePath, "meta.json")
-	content, err := gs.GsOverrides.ReadFile(metaFilePath)
+	content, err := fs.ReadFile(pkgFS, "meta.json")
 	if err != nil {
 		// Only treat missing file as "no metadata"; surface other errors
 		if os.IsNotExist(err) {
@@ -1239,13 +1462,10 @@ func (c *Compiler) copyGsPackageWithDependencies(packagePath string, processedPa
 	gsSourcePath := "gs/" + packagePath
 
 	// Check if the gs package actually exists
-	_, gsErr := gs.GsOverrides.ReadDir(gsSourcePath)
-	if gsErr != nil {
-		if os.IsNotExist(gsErr) {
-			c.le.Debugf("gs package %s does not exist, skipping", packagePath)
-			return nil
-		}
-		return gsErr
+	pkgFS := c.overrides.lookup(packagePath)
+	if pkgFS == nil {
+		c.le.Debugf("gs package %s does not exist, skipping", packagePath)
+		return nil
 	}
 
 	// Read metadata to get dependencies
@@ -1280,9 +1500,9 @@ func (c *Compiler) copyGsPackageWithDependencies(packagePath string, processedPa
 		return fmt.Errorf("failed to create output directory for %s: %w", packagePath, err)
 	}
 
-	// Copy files from embedded FS to output directory
-	if err := c.copyEmbeddedPackage(gsSourcePath, outputPath); err != nil {
-		return fmt.Errorf("failed to copy embedded package %s: %w", packagePath, err)
+	// Copy files of the handwritten package to output directory
+	if err := c.copyPackageFiles(pkgFS, ".", outputPath); err != nil {
+		return fmt.Errorf("failed to copy handwritten package %s: %w", packagePath, err)
 	}
 
 	result.CopiedPackages = append(result.CopiedPackages, packagePath)
@@ -13,7 +13,8 @@ import (
 // TypeScript equivalent.
 //
 // It handles several types of composite literals:
-//   - Map literals (e.g., `map[K]V{k1: v1}`): Translated to `new Map([[k1_ts, v1_ts]])`.
+//   - Map literals (e.g., `map[K]V{k1: v1}`): Translated to `new Map([[k1_ts, v1_ts]])`,
+//     or a `$.HashMap` for keys that need Go equality semantics.
 //     Values are processed by `WriteVarRefedValue`.
 //   - Array/Slice literals (e.g., `[]T{e1, e2}`, `[N]T{idx: val}`):
 //   - For `[]byte{...}`, translated to `new Uint8Array([...])`.
@@ -46,7 +47,27 @@ func (c *GoToTSCompiler) WriteCompositeLit(exp *ast.CompositeLit) error {
 			_, isMapType = litType.Underlying().(*types.Map)
 		}
 		if isMapType {
-			c.tsw.WriteLiterally("new Map([")
+			var mapType *types.Map
+			if litType != nil {
+				mapType, _ = litType.Underlying().(*types.Map)
+			}
+			if mapType != nil && c.mapKeyNeedsHashing(mapType.Key()) {
+				// Keys that are not compared by identity need a hashing map
+				c.tsw.WriteLiterally("new $.HashMap<")
+				c.WriteGoType(mapType.Key(), GoTypeContextGeneral)
+				c.tsw.WriteLiterally(", ")
+				c.WriteGoType(mapType.Elem(), GoTypeContextGeneral)
+				c.tsw.WriteLiterallyf(">(%s, [", c.mapKeyDescriptor(mapType.Key()))
+			} else if mapType != nil && (c.isEnumType(mapType.Key()) || c.isEnumType(mapType.Elem())) {
+				// The types of enum keys and values are not inferred from literals
+				c.tsw.WriteLiterally("new Map<")
+				c.WriteGoType(mapType.Key(), GoTypeContextGeneral)
+				c.tsw.WriteLiterally(", ")
+				c.WriteGoType(mapType.Elem(), GoTypeContextGeneral)
+				c.tsw.WriteLiterally(">([")
+			} else {
+				c.tsw.WriteLiterally("new Map([")
+			}
 
 			// Add each key-value pair as an entry
 			for i, elm := range exp.Elts {
@@ -56,11 +77,15 @@ func (c *GoToTSCompiler) WriteCompositeLit(exp *ast.CompositeLit) error {
 
 				if kv, ok := elm.(*ast.KeyValueExpr); ok {
 					c.tsw.WriteLiterally("[")
-					if err := c.WriteVarRefedValue(kv.Key); err != nil {
+					var keyType, elemType types.Type
+					if mapType != nil {
+						keyType, elemType = mapType.Key(), mapType.Elem()
+					}
+					if err := c.writeCompositeLitValue(kv.Key, keyType); err != nil {
 						return fmt.Errorf("failed to write map literal key: %w", err)
 					}
 					c.tsw.WriteLiterally(", ")
-					if err := c.WriteVarRefedValue(kv.Value); err != nil {
+					if err := c.writeCompositeLitValue(kv.Value, elemType); err != nil {
 						return fmt.Errorf("failed to write map literal value: %w", err)
 					}
 					c.tsw.WriteLiterally("]")
@@ -107,7 +132,7 @@ func (c *GoToTSCompiler) WriteCompositeLit(exp *ast.CompositeLit) error {
 			// Use type info to get array length and element type
 			var arrayLen int
 			var elemType ast.Expr
-			var goElemType interface{}
+			var goElemType types.Type
 			if typ := c.pkg.TypesInfo.TypeOf(exp.Type); typ != nil {
 				if at, ok := typ.Underlying().(*types.Array); ok {
 					arrayLen = int(at.Len())
@@ -189,7 +214,7 @@ func (c *GoToTSCompiler) WriteCompositeLit(exp *ast.CompositeLit) error {
 					c.tsw.WriteLiterally(", ")
 				}
 				if elm, ok := elements[i]; ok && elm != nil {
-					if err := c.WriteVarRefedValue(elm); err != nil {
+					if err := c.writeCompositeLitValue(elm, goElemType); err != nil {
 						return fmt.Errorf("failed to write array literal element: %w", err)
 					}
 				} else {
@@ -308,7 +333,7 @@ func (c *GoToTSCompiler) WriteCompositeLit(exp *ast.CompositeLit) error {
 				}
 
 				// Write all fields
-				if err := c.writeStructLiteralFields(directFields, embeddedFields, explicitEmbedded, litType); err != nil {
+				if err := c.writeStructLiteralFields(directFields, embeddedFields, explicitEmbedded, structType, litType); err != nil {
 					return err
 				}
 
@@ -377,12 +402,19 @@ func (c *GoToTSCompiler) WriteCompositeLit(exp *ast.CompositeLit) error {
 
 // writeUntypedArrayLiteral handles untyped composite literals that are arrays/slices
 func (c *GoToTSCompiler) writeUntypedArrayLiteral(exp *ast.CompositeLit) error {
+	var elemType types.Type
+	switch t := typeUnderlying(c.pkg.TypesInfo.TypeOf(exp)).(type) {
+	case *types.Array:
+		elemType = t.Elem()
+	case *types.Slice:
+		elemType = t.Elem()
+	}
 	c.tsw.WriteLiterally("[ ")
 	for i, elm := range exp.Elts {
 		if i != 0 {
 			c.tsw.WriteLiterally(", ")
 		}
-		if err := c.WriteVarRefedValue(elm); err != nil {
+		if err := c.writeCompositeLitValue(elm, elemType); err != nil {
 			return fmt.Errorf("failed to write untyped array literal element: %w", err)
 		}
 	}
@@ -450,7 +482,7 @@ func (c *GoToTSCompiler) writeUntypedStructLiteral(exp *ast.CompositeLit, actual
 
 		c.tsw.WriteLiterally(fieldName)
 		c.tsw.WriteLiterally(": ")
-		if err := c.WriteVarRefedValue(directFields[keyName]); err != nil {
+		if err := c.writeCompositeLitValue(directFields[keyName], structFieldType(structType, keyName)); err != nil {
 			return err
 		}
 		firstFieldWritten = true
@@ -485,6 +517,10 @@ func (c *GoToTSCompiler) writeUntypedStructLiteral(exp *ast.CompositeLit, actual
 //     often translates to accessing the `.value` field of the pointer (e.g., `ptr.value`).
 //   - Basic Literals (`*ast.BasicLit`, e.g., `123`, `"hello"`): Delegates to
 //     `c.WriteBasicLit(e)` for direct translation.
+//   - Constants of a bigint type (e.g. the `1` of `[]int64{1}`): Written as
+//     bigint literals (`1n`) by `c.writeBigIntConstantExpr(e)`.
+//   - Constants of a complex type (e.g. the `3` of `[]complex128{3}`): Written
+//     as `$.complex(3, 0)` by `c.writeComplexConstantExpr(e)`.
 //   - Other expression types: Falls back to `c.WriteValueExpr(expr)` for general
 //     expression handling. This is important for complex expressions like function
 //     calls or binary operations that might appear as values within a composite literal.
@@ -512,6 +548,11 @@ func (c *GoToTSCompiler) WriteVarRefedValue(expr ast.Expr) error {
 		return fmt.Errorf("nil expression passed to write var refed value")
 	}
 
+	// Constants of bigint and complex types are written like in WriteValueExpr
+	if c.writeBigIntConstantExpr(expr) || c.writeComplexConstantExpr(expr) {
+		return nil
+	}
+
 	// Handle different expression types
 	switch e := expr.(type) {
 	case *ast.Ident:
@@ -531,6 +572,27 @@ func (c *GoToTSCompiler) WriteVarRefedValue(expr ast.Expr) error {
 	}
 }
 
+// writeCompositeLitValue writes an element, key or field value of a composite
+// literal whose type is target at its position in the literal. Pointers which
+// may be nil are wrapped with $.typedNilOf if target is an interface type.
+func (c *GoToTSCompiler) writeCompositeLitValue(expr ast.Expr, target types.Type) error {
+	if c.needsTypedNil(expr, target) {
+		return c.writeInterfaceValue(expr, target)
+	}
+	return c.WriteVarRefedValue(expr)
+}
+
+// structFieldType returns the type of the field name of structType, or nil if
+// structType has no such field.
+func structFieldType(structType *types.Struct, name string) types.Type {
+	for i := range structType.NumFields() {
+		if field := structType.Field(i); field.Name() == name {
+			return field.Type()
+		}
+	}
+	return nil
+}
+
 // evaluateConstantExpr attempts to evaluate a Go expression as a compile-time constant.
 // It returns the constant value if successful, or nil if the expression is not a constant.
 // This is used for evaluating array literal keys that are constant expressions.
@@ -672,6 +734,7 @@ func (c *GoToTSCompiler) writeStructLiteralFields(
 	directFields map[string]ast.Expr,
 	embeddedFields map[string]map[string]ast.Expr,
 	explicitEmbedded map[string]ast.Expr,
+	structType *types.Struct,
 	litType types.Type,
 ) error {
 	firstFieldWritten := false
@@ -695,7 +758,7 @@ func (c *GoToTSCompiler) writeStructLiteralFields(
 
 		c.tsw.WriteLiterally(fieldName)
 		c.tsw.WriteLiterally(": ")
-		if err := c.WriteVarRefedValue(directFields[keyName]); err != nil {
+		if err := c.writeCompositeLitValue(directFields[keyName], structFieldType(structType, keyName)); err != nil {
 			return err
 		}
 		firstFieldWritten = true
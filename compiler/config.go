@@ -2,6 +2,8 @@ package compiler
 
 import (
 	"go/token"
+	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 )
@@ -24,6 +26,63 @@ type Config struct {
 	// If true, builtin packages will not be emitted; if false, they will be emitted if referenced.
 	// Default is false (emit builtin packages).
 	DisableEmitBuiltin bool
+	// Int64AsBigInt controls how int64, uint64 and uintptr are represented.
+	// If true, they are emitted as bigint with exact 64-bit wraparound semantics.
+	// If false (the default), they are emitted as number and lose precision above 2^53.
+	Int64AsBigInt bool
+	// StrictIntegers controls whether fixed-width integer arithmetic wraps like in Go.
+	// If true, results of int8 to int32 and uint8 to uint32 operations are truncated
+	// to their type and integer division truncates and panics on a zero divisor.
+	// If false (the default), integer arithmetic uses plain JavaScript numbers.
+	StrictIntegers bool
+	// SourceMap controls whether a Source Map v3 is written next to each
+	// generated file (foo.gs.ts.map), mapping it back to the Go source.
+	SourceMap bool
+	// SourceMapSourcesContent controls whether the Go sources are embedded in
+	// the source maps, so they can be used without access to the Go files.
+	SourceMapSourcesContent bool
+	// Preemptive controls whether the loops of async functions yield to other
+	// goroutines and the event loop once the current time slice is used up.
+	// If false (the default), goroutines only yield when they block.
+	Preemptive bool
+	// OverrideDirs are directories of handwritten TypeScript packages which
+	// replace Go packages instead of compiling them, laid out like gs/: the
+	// package for an import path is the directory at that path with an
+	// index.ts and an optional meta.json. Earlier directories take
+	// precedence over later ones and over the packages built into goscript.
+	// Relative paths are relative to Dir.
+	OverrideDirs []string
+	// Incremental controls whether a goscript.meta.json with the analysis
+	// results and the hash of the inputs is written next to each compiled
+	// package, and packages whose inputs did not change since the last
+	// compile are reused instead of being compiled again.
+	Incremental bool
+	// Facade controls whether a facade.ts is written next to the index.ts of
+	// each compiled package, wrapping its exported functions in an API using
+	// plain TypeScript values: errors are thrown as exceptions, slices are
+	// arrays, structs are plain objects and context.Context parameters are
+	// filled from an AbortSignal.
+	Facade bool
+	// Schemas controls whether a schema.ts is written next to the index.ts
+	// of each compiled package with exported structs, with a JSON Schema
+	// document and a zod schema of the JSON encoding of each struct, following
+	// the json tags like encoding/json.
+	Schemas bool
+	// EnumUnions controls whether the named integer and string types which
+	// the package only assigns the values of their constants are written as
+	// a literal union type of these values and a const object of the
+	// constants. Values of the types computed by importing packages are not
+	// checked, so these must only use the constants.
+	EnumUnions bool
+	// SealedUnions controls whether the interfaces with an unexported method
+	// which are only implemented by structs of their package are written as
+	// the union of the struct classes, discriminated by a __type property,
+	// and type switches over them as switch statements over __type.
+	SealedUnions bool
+	// JSONMethods controls whether the struct classes have toJSON and
+	// toPlain methods and a static fromJSON method converting them to and
+	// from the plain objects of their encoding/json encoding.
+	JSONMethods bool
 }
 
 // Validate checks the config.
@@ -37,5 +96,35 @@ func (c *Config) Validate() error {
 	if c.OutputPath == "" {
 		return errors.New("output path root must be specified")
 	}
+	overrideDirs, err := c.absOverrideDirs()
+	if err != nil {
+		return err
+	}
+	for i, dir := range overrideDirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return errors.Wrap(err, "override directory")
+		}
+		if !info.IsDir() {
+			return errors.Errorf("override directory %s is not a directory", c.OverrideDirs[i])
+		}
+	}
 	return nil
 }
+
+// absOverrideDirs returns the OverrideDirs as absolute paths, resolving
+// relative paths against Dir.
+func (c *Config) absOverrideDirs() ([]string, error) {
+	dirs := make([]string, len(c.OverrideDirs))
+	for i, dir := range c.OverrideDirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(c.Dir, dir)
+		}
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		dirs[i] = absDir
+	}
+	return dirs, nil
+}
@@ -22,7 +22,8 @@ import (
 // Type declarations are sorted by dependencies to ensure referenced types are
 // defined before types that reference them, avoiding initialization order issues.
 // A newline is added after each processed declaration or spec group for readability.
-// Unknown declaration types result in a printed diagnostic message.
+// Declarations which cannot be written are recorded as diagnostics and skipped,
+// see writeRecovering.
 func (c *GoToTSCompiler) WriteDecls(decls []ast.Decl) error {
 	// Separate type declarations from other declarations for dependency sorting
 	var typeSpecs []*ast.TypeSpec
@@ -67,25 +68,25 @@ func (c *GoToTSCompiler) WriteDecls(decls []ast.Decl) error {
 
 	// Write non-type, non-var declarations first (imports, constants)
 	for _, spec := range otherSpecs {
-		if err := c.WriteSpec(spec); err != nil {
-			return err
-		}
+		c.writeRecovering(spec.Pos(), func() error {
+			return c.WriteSpec(spec)
+		})
 		c.tsw.WriteLine("") // Add space after spec
 	}
 
 	// Write sorted type declarations
 	for _, typeSpec := range sortedTypeSpecs {
-		if err := c.WriteSpec(typeSpec); err != nil {
-			return err
-		}
+		c.writeRecovering(typeSpec.Pos(), func() error {
+			return c.WriteSpec(typeSpec)
+		})
 		c.tsw.WriteLine("") // Add space after spec
 	}
 
 	// Write sorted variable declarations
 	for _, varSpec := range sortedVarSpecs {
-		if err := c.WriteSpec(varSpec); err != nil {
-			return err
-		}
+		c.writeRecovering(varSpec.Pos(), func() error {
+			return c.WriteSpec(varSpec)
+		})
 		c.tsw.WriteLine("") // Add space after spec
 	}
 
@@ -93,9 +94,9 @@ func (c *GoToTSCompiler) WriteDecls(decls []ast.Decl) error {
 	for _, decl := range otherDecls {
 		switch d := decl.(type) {
 		case *ast.FuncDecl:
-			if err := c.WriteFuncDeclAsFunction(d); err != nil {
-				return err
-			}
+			c.writeRecovering(d.Pos(), func() error {
+				return c.WriteFuncDeclAsFunction(d)
+			})
 			c.tsw.WriteLine("") // Add space after function
 		default:
 			return fmt.Errorf("unknown decl: %#v", decl)
@@ -439,6 +440,7 @@ func (c *GoToTSCompiler) WriteFuncDeclAsFunction(decl *ast.FuncDecl) error {
 	if decl.Doc != nil {
 		c.WriteDoc(decl.Doc)
 	}
+	c.tsw.MarkPos(decl.Pos())
 
 	// Export all functions for intra-package visibility
 	// This allows other files in the same package to import functions
@@ -454,6 +456,7 @@ func (c *GoToTSCompiler) WriteFuncDeclAsFunction(decl *ast.FuncDecl) error {
 	if decl.Name.Name == "main" && c.pkg.Name == "main" {
 		isAsync = true
 	}
+	c.markAsyncFunc(decl, isAsync)
 
 	if isAsync {
 		c.tsw.WriteLiterally("async ")
@@ -473,6 +476,12 @@ func (c *GoToTSCompiler) WriteFuncDeclAsFunction(decl *ast.FuncDecl) error {
 	c.WriteFuncType(decl.Type, isAsync) // Write signature (params, return type)
 	c.tsw.WriteLiterally(" ")
 
+	// Functions with a //goscript:extern directive call their implementation
+	if ext := c.externFuncs[decl]; ext != nil {
+		c.writeExternFuncBody(decl, ext)
+		return nil
+	}
+
 	if c.hasNamedReturns(decl.Type.Results) {
 		c.tsw.WriteLine("{")
 		c.tsw.Indent(1)
@@ -560,6 +569,7 @@ func (c *GoToTSCompiler) writeMethodSignature(decl *ast.FuncDecl) (bool, error)
 	if decl.Doc != nil {
 		c.WriteDoc(decl.Doc)
 	}
+	c.tsw.MarkPos(decl.Pos())
 
 	// Determine if method is async
 	var isAsync bool
@@ -588,6 +598,8 @@ func (c *GoToTSCompiler) writeMethodSignature(decl *ast.FuncDecl) (bool, error)
 		}
 	}
 
+	c.markAsyncFunc(decl, isAsync)
+
 	// Methods are typically public in the TS output
 	c.tsw.WriteLiterally("public ")
 
@@ -692,10 +704,14 @@ func (c *GoToTSCompiler) writeMethodBodyWithReceiverBinding(decl *ast.FuncDecl,
 			}
 
 			// write method body without outer braces
-			for _, stmt := range decl.Body.List {
+			for i, stmt := range decl.Body.List {
+				if err := c.writeGotoRegionsOpen(decl.Body, i); err != nil {
+					return err
+				}
 				if err := c.WriteStmt(stmt); err != nil {
 					return fmt.Errorf("failed to write statement in function body: %w", err)
 				}
+				c.writeGotoRegionsClose(decl.Body, i, stmt)
 			}
 			c.tsw.Indent(-1)
 			c.tsw.WriteLine("}")
@@ -0,0 +1,282 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DiagnosticSeverity is the severity of a Diagnostic.
+type DiagnosticSeverity string
+
+const (
+	// SeverityError is the severity of problems failing the compile.
+	SeverityError DiagnosticSeverity = "error"
+	// SeverityWarning is the severity of problems not failing the compile.
+	SeverityWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic codes identify the kind of problem reported by a Diagnostic.
+const (
+	// DiagnosticParse is the code of Go syntax errors.
+	DiagnosticParse = "parse"
+	// DiagnosticType is the code of Go type errors.
+	DiagnosticType = "type"
+	// DiagnosticLoad is the code of errors loading packages, like missing
+	// modules or import cycles.
+	DiagnosticLoad = "load"
+	// DiagnosticUnsupported is the code of Go constructs goscript does not
+	// support.
+	DiagnosticUnsupported = "unsupported"
+	// DiagnosticDirective is the code of invalid goscript directives.
+	DiagnosticDirective = "directive"
+	// DiagnosticInternal is the code of unexpected compiler failures.
+	DiagnosticInternal = "internal"
+)
+
+// Diagnostic is a problem found while compiling a package.
+type Diagnostic struct {
+	// Severity is the severity of the problem.
+	Severity DiagnosticSeverity `json:"severity"`
+	// Code identifies the kind of problem, like DiagnosticUnsupported.
+	Code string `json:"code"`
+	// Package is the import path of the package.
+	Package string `json:"package,omitempty"`
+	// File is the path of the Go file, empty if the position is unknown.
+	File string `json:"file,omitempty"`
+	// Line is the 1-based line in File, 0 if unknown.
+	Line int `json:"line,omitempty"`
+	// Column is the 1-based column in bytes in Line, 0 if unknown.
+	Column int `json:"column,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Suggestion describes how to work around the problem, if known.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// String formats the diagnostic like the errors of the go command, with the
+// suggestion on a second line.
+func (d *Diagnostic) String() string {
+	var sb strings.Builder
+	switch {
+	case d.File != "" && d.Line != 0 && d.Column != 0:
+		fmt.Fprintf(&sb, "%s:%d:%d: ", d.File, d.Line, d.Column)
+	case d.File != "" && d.Line != 0:
+		fmt.Fprintf(&sb, "%s:%d: ", d.File, d.Line)
+	case d.File != "":
+		fmt.Fprintf(&sb, "%s: ", d.File)
+	case d.Package != "":
+		fmt.Fprintf(&sb, "%s: ", d.Package)
+	}
+	if d.Severity == SeverityWarning {
+		sb.WriteString("warning: ")
+	}
+	sb.WriteString(d.Message)
+	if d.Suggestion != "" {
+		sb.WriteString("\n\t")
+		sb.WriteString(d.Suggestion)
+	}
+	return sb.String()
+}
+
+// DiagnosticsError is the error of a compile which failed with the error
+// diagnostics, also listed in CompilationResult.Diagnostics.
+type DiagnosticsError struct {
+	Diagnostics []Diagnostic
+}
+
+// Error returns the first diagnostic and the number of the others.
+func (e *DiagnosticsError) Error() string {
+	if len(e.Diagnostics) == 0 {
+		return "compile failed"
+	}
+	msg := e.Diagnostics[0].String()
+	if n := len(e.Diagnostics) - 1; n == 1 {
+		msg += " (and 1 more error)"
+	} else if n > 1 {
+		msg += fmt.Sprintf(" (and %d more errors)", n)
+	}
+	return msg
+}
+
+// unsupportedSuggestion is the suggestion of the unsupported constructs
+// without a more specific one.
+const unsupportedSuggestion = "rewrite the code without this construct, or implement the function in TypeScript with //goscript:extern or an override package"
+
+// internalSuggestion is the suggestion of the internal errors.
+const internalSuggestion = "this is a bug in goscript, please report it with the Go code"
+
+// diagnosticError is an error of the code generation at a position of the
+// Go sources, reported as a Diagnostic.
+type diagnosticError struct {
+	pos        token.Pos
+	code       string
+	suggestion string
+	err        error
+}
+
+// newDiagnosticError returns the error with the code and suggestion at pos.
+func newDiagnosticError(pos token.Pos, code, suggestion, format string, args ...any) error {
+	return &diagnosticError{pos: pos, code: code, suggestion: suggestion, err: fmt.Errorf(format, args...)}
+}
+
+func (e *diagnosticError) Error() string {
+	return e.err.Error()
+}
+
+func (e *diagnosticError) Unwrap() error {
+	return e.err
+}
+
+// withDiagnosticPos returns err positioned at pos, unless err already has a
+// position from an inner node.
+func withDiagnosticPos(pos token.Pos, err error) error {
+	var diagErr *diagnosticError
+	if errors.As(err, &diagErr) {
+		if diagErr.pos.IsValid() || !pos.IsValid() {
+			return err
+		}
+		positioned := *diagErr
+		positioned.pos = pos
+		return &positioned
+	}
+	return &diagnosticError{pos: pos, err: err}
+}
+
+// diagnosticFromError returns the diagnostic of the code generation error err
+// in pkg. Errors without a code are classified from their message.
+func diagnosticFromError(pkg *packages.Package, err error) Diagnostic {
+	diag := Diagnostic{Severity: SeverityError, Package: pkg.PkgPath}
+
+	var diagErr *diagnosticError
+	if errors.As(err, &diagErr) {
+		diag.Code = diagErr.code
+		diag.Suggestion = diagErr.suggestion
+		if diagErr.pos.IsValid() && pkg.Fset != nil {
+			position := pkg.Fset.Position(diagErr.pos)
+			diag.File, diag.Line, diag.Column = position.Filename, position.Line, position.Column
+		}
+	}
+
+	// The wrapped errors only add which node was being written, so the
+	// message is the innermost error.
+	cause := err
+	for next := errors.Unwrap(cause); next != nil; next = errors.Unwrap(cause) {
+		cause = next
+	}
+	diag.Message = cause.Error()
+
+	if diag.Code == "" {
+		switch msg := strings.ToLower(diag.Message); {
+		case strings.Contains(msg, "unsupported"), strings.Contains(msg, "not supported"), strings.Contains(msg, "unhandled"):
+			diag.Code = DiagnosticUnsupported
+		default:
+			diag.Code = DiagnosticInternal
+		}
+	}
+	if diag.Suggestion == "" {
+		switch diag.Code {
+		case DiagnosticUnsupported:
+			diag.Suggestion = unsupportedSuggestion
+		case DiagnosticInternal:
+			diag.Suggestion = internalSuggestion
+		}
+	}
+	return diag
+}
+
+// packageDiagnostics returns the diagnostics of the errors loading pkg. The
+// errors reported by the go command repeat the parse and type errors, so they
+// are left out if there are any.
+func packageDiagnostics(pkg *packages.Package) []Diagnostic {
+	var diags, listDiags []Diagnostic
+	for _, pkgErr := range pkg.Errors {
+		diag := Diagnostic{Severity: SeverityError, Package: pkg.PkgPath, Message: pkgErr.Msg}
+		diag.File, diag.Line, diag.Column = parsePosition(pkgErr.Pos)
+		switch pkgErr.Kind {
+		case packages.ParseError:
+			diag.Code = DiagnosticParse
+		case packages.TypeError:
+			diag.Code = DiagnosticType
+		default:
+			diag.Code = DiagnosticLoad
+			listDiags = append(listDiags, diag)
+			continue
+		}
+		diags = append(diags, diag)
+	}
+	if len(diags) == 0 {
+		return listDiags
+	}
+	return diags
+}
+
+// parsePosition parses a position formatted as file:line:col, file:line or
+// file. Positions which are "-" or empty are unknown.
+func parsePosition(pos string) (file string, line, column int) {
+	if pos == "" || pos == "-" {
+		return "", 0, 0
+	}
+	file = pos
+	if i := strings.LastIndexByte(file, ':'); i >= 0 {
+		if n, err := strconv.Atoi(file[i+1:]); err == nil {
+			file, line = file[:i], n
+			if j := strings.LastIndexByte(file, ':'); j >= 0 {
+				if m, err := strconv.Atoi(file[j+1:]); err == nil {
+					file, line, column = file[:j], m, n
+				}
+			}
+		}
+	}
+	return file, line, column
+}
+
+// writeRecovering calls write and records its error, or a panic, as a
+// diagnostic at pos, so that the compile goes on with the next declaration or
+// statement and reports all the problems of the file.
+func (c *GoToTSCompiler) writeRecovering(pos token.Pos, write func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.addDiagnosticError(newDiagnosticError(pos, DiagnosticInternal, internalSuggestion, "panic: %v", r))
+		}
+	}()
+	if err := write(); err != nil {
+		c.addDiagnosticError(withDiagnosticPos(pos, err))
+	}
+}
+
+// addDiagnosticError records the diagnostic of the error err.
+func (c *GoToTSCompiler) addDiagnosticError(err error) {
+	c.diagnostics = append(c.diagnostics, diagnosticFromError(c.pkg, err))
+}
+
+// diagnosticsError returns the error of the error diagnostics, or nil if
+// there are none.
+func diagnosticsError(diags []Diagnostic) error {
+	var errs []Diagnostic
+	for _, diag := range diags {
+		if diag.Severity == SeverityError {
+			errs = append(errs, diag)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &DiagnosticsError{Diagnostics: errs}
+}
+
+// addDiagnostics records the diagnostics of the compile of a package which
+// returned err in result. It returns err if the compile failed for another
+// reason than error diagnostics.
+func addDiagnostics(result *CompilationResult, diags []Diagnostic, err error) error {
+	result.Diagnostics = append(result.Diagnostics, diags...)
+	var diagErr *DiagnosticsError
+	if err != nil && !errors.As(err, &diagErr) {
+		return err
+	}
+	return nil
+}
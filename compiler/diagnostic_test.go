@@ -0,0 +1,110 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompileDiagnostics(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"bad/bad.go": `package bad
+
+// Wait waits for ch.
+func Wait(ch chan int) {
+	select {
+	case <-ch:
+		goto done
+	}
+done:
+	println("done")
+}
+
+// Poll polls ch.
+func Poll(ch chan int) {
+	for {
+		select {
+		case <-ch:
+			goto done
+		default:
+		}
+	}
+done:
+}
+`,
+		"bad/extern.go": `package bad
+
+//goscript:extern
+func Now() int64 { return 0 }
+`,
+		"broken/broken.go": "package broken\n\nfunc Broken() int { return \"\" }\n",
+		"good/good.go":     "package good\n\n// Answer returns the answer.\nfunc Answer() int { return 42 }\n",
+	})
+
+	comp, err := NewCompiler(&Config{
+		Dir:        dir,
+		OutputPath: filepath.Join(dir, "output"),
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := comp.CompilePackages(context.Background(), "./...")
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("CompilePackages() error = %v, want a DiagnosticsError", err)
+	}
+	if !slices.Equal(result.CompiledPackages, []string{"example.com/app/good"}) {
+		t.Errorf("CompiledPackages = %v, want only the good package", result.CompiledPackages)
+	}
+
+	type position struct {
+		code string
+		file string
+		line int
+	}
+	var got []position
+	for _, diag := range result.Diagnostics {
+		if diag.Severity != SeverityError || diag.Message == "" {
+			t.Errorf("invalid diagnostic %+v", diag)
+		}
+		rel, _ := filepath.Rel(dir, diag.File)
+		got = append(got, position{diag.Code, filepath.ToSlash(rel), diag.Line})
+	}
+	want := []position{
+		{DiagnosticDirective, "bad/extern.go", 4},
+		{DiagnosticUnsupported, "bad/bad.go", 7},
+		{DiagnosticUnsupported, "bad/bad.go", 18},
+		{DiagnosticType, "broken/broken.go", 3},
+	}
+	for _, w := range want {
+		if !slices.Contains(got, w) {
+			t.Errorf("missing diagnostic %+v in %+v", w, got)
+		}
+	}
+	if len(diagErr.Diagnostics) != len(result.Diagnostics) {
+		t.Errorf("DiagnosticsError has %d diagnostics, want %d", len(diagErr.Diagnostics), len(result.Diagnostics))
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	for _, tc := range []struct {
+		pos          string
+		file         string
+		line, column int
+	}{
+		{"/src/a.go:3:7", "/src/a.go", 3, 7},
+		{"/src/a.go:3", "/src/a.go", 3, 0},
+		{"C:\\src\\a.go:3:7", "C:\\src\\a.go", 3, 7},
+		{"/src/a.go", "/src/a.go", 0, 0},
+		{"-", "", 0, 0},
+	} {
+		file, line, column := parsePosition(tc.pos)
+		if file != tc.file || line != tc.line || column != tc.column {
+			t.Errorf("parsePosition(%q) = %q, %d, %d, want %q, %d, %d", tc.pos, file, line, column, tc.file, tc.line, tc.column)
+		}
+	}
+}
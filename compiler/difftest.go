@@ -0,0 +1,386 @@
+package compiler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// DiffTestConfig configures DiffTest.
+type DiffTestConfig struct {
+	// Runtime is the JavaScript runtime running the transpiled program, see
+	// TestRunConfig.
+	Runtime string
+	// Args are passed to both programs on every run.
+	Args []string
+	// Runs is the number of runs with generated arguments appended to Args.
+	// If zero, the programs run once with Args.
+	Runs int
+	// Seed seeds the generated arguments: run i uses rand.NewPCG(Seed, i).
+	Seed uint64
+	// GenerateArgs generates the arguments of a run.
+	// Defaults to RandomArgs.
+	GenerateArgs func(r *rand.Rand) []string
+	// Timeout limits each run of a program. Zero means no limit.
+	Timeout time.Duration
+}
+
+// DiffTestResult is the outcome of DiffTest for a main package.
+type DiffTestResult struct {
+	// PkgPath is the import path of the main package.
+	PkgPath string
+	// OutputPath holds the native binary, the TypeScript output and its
+	// runner.
+	OutputPath string
+	// Runs is the number of runs which were compared.
+	Runs int
+	// Args are the arguments of the last run, the diverging one if Diff is set.
+	Args []string
+	// Native and Transpiled are the results of the last run.
+	Native, Transpiled *RunOutput
+	// Diff is the first divergence, nil if the programs behaved the same.
+	Diff *Divergence
+}
+
+// RunOutput is the observed behavior of a program run.
+type RunOutput struct {
+	// Output is the standard output and standard error of the program,
+	// without the crash report. They are compared together since println
+	// writes to standard error in Go but to standard output in TypeScript.
+	Output string
+	// ExitCode is the exit code of the program.
+	ExitCode int
+	// Panic is the first line of the crash report, like "panic: boom" or
+	// "fatal error: all goroutines are asleep - deadlock!". Empty if the
+	// program did not crash.
+	Panic string
+}
+
+// DivergenceKind is what differs between the native and transpiled runs.
+type DivergenceKind string
+
+const (
+	// DivergenceOutput is a differing line of output.
+	DivergenceOutput DivergenceKind = "output"
+	// DivergencePanic is a differing panic message.
+	DivergencePanic DivergenceKind = "panic"
+	// DivergenceExitCode is a differing exit code.
+	DivergenceExitCode DivergenceKind = "exit code"
+)
+
+// Divergence is the first difference between the native and transpiled runs
+// of a program. Output lines are compared first, then the panic messages,
+// then the exit codes.
+type Divergence struct {
+	// Kind is what differs.
+	Kind DivergenceKind
+	// Line is the 1-based line of output which differs.
+	Line int
+	// Native and Transpiled are the differing values, formatted for display.
+	Native, Transpiled string
+}
+
+// String describes the divergence.
+func (d *Divergence) String() string {
+	what := string(d.Kind)
+	if d.Kind == DivergenceOutput {
+		what = fmt.Sprintf("output line %d", d.Line)
+	}
+	return fmt.Sprintf("%s: native %s, transpiled %s", what, d.Native, d.Transpiled)
+}
+
+// diffTestEntryFile is the name of the generated runner of the transpiled
+// program.
+const diffTestEntryFile = "difftest.ts"
+
+// DiffTest builds the main packages matching patterns with the Go toolchain
+// and compiles them to TypeScript with CompilePackages, runs both versions
+// with the same arguments and compares what they print, their panic
+// messages and exit codes. Each package goes to its own directory below the
+// output path: <output>/<package path>.difftest. Packages which are not
+// main packages are ignored.
+//
+// With conf.Runs set, the programs run repeatedly with arguments generated
+// from conf.Seed until they diverge.
+func (c *Compiler) DiffTest(ctx context.Context, conf *DiffTestConfig, patterns ...string) ([]*DiffTestResult, error) {
+	opts := c.opts
+	opts.Context = ctx
+	pkgs, err := packages.Load(&opts, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var results []*DiffTestResult
+	for _, pkg := range pkgs {
+		if pkg.Name != "main" {
+			continue
+		}
+		if len(pkg.Errors) != 0 {
+			return nil, errors.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		res, err := c.diffTestPackage(ctx, pkg, conf)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 {
+		return nil, errors.Errorf("no main packages match %v", patterns)
+	}
+	return results, nil
+}
+
+// diffTestPackage builds, compiles and compares the main package pkg.
+func (c *Compiler) diffTestPackage(ctx context.Context, pkg *packages.Package, conf *DiffTestConfig) (*DiffTestResult, error) {
+	res := &DiffTestResult{
+		PkgPath:    pkg.PkgPath,
+		OutputPath: filepath.Join(c.config.OutputPath, filepath.FromSlash(pkg.PkgPath)+".difftest"),
+	}
+	if err := os.MkdirAll(res.OutputPath, 0o755); err != nil {
+		return nil, err
+	}
+
+	mainFile := findMainFile(pkg)
+	if mainFile == "" {
+		return nil, errors.Errorf("package %s has no main function", pkg.PkgPath)
+	}
+
+	nativePath := filepath.Join(res.OutputPath, "native")
+	if runtime.GOOS == "windows" {
+		nativePath += ".exe"
+	}
+	buildArgs := append([]string{"build", "-o", nativePath}, c.config.BuildFlags...)
+	build := exec.CommandContext(ctx, "go", append(buildArgs, pkg.PkgPath)...)
+	build.Dir = c.config.Dir
+	if out, err := build.CombinedOutput(); err != nil {
+		return nil, errors.Errorf("go build %s: %v\n%s", pkg.PkgPath, err, out)
+	}
+
+	// The transpiled program needs the builtin and handwritten packages next
+	// to it, like the tests compiled by CompileTests.
+	dc := &Compiler{le: c.le, config: c.config, opts: c.opts, overrides: c.overrides}
+	dc.config.OutputPath = res.OutputPath
+	dc.config.AllDependencies = true
+	dc.config.DisableEmitBuiltin = false
+	if _, err := dc.CompilePackages(ctx, pkg.PkgPath); err != nil {
+		return nil, err
+	}
+	entryPath := filepath.Join(res.OutputPath, diffTestEntryFile)
+	if err := os.WriteFile(entryPath, []byte(generateDiffTestMain(pkg.PkgPath, mainFile)), 0o644); err != nil {
+		return nil, err
+	}
+	if err := writeRunnerTsConfig(res.OutputPath, diffTestEntryFile); err != nil {
+		return nil, err
+	}
+
+	generate := conf.GenerateArgs
+	if generate == nil {
+		generate = RandomArgs
+	}
+	runs := max(conf.Runs, 1)
+	for i := range runs {
+		args := conf.Args
+		if conf.Runs != 0 {
+			r := rand.New(rand.NewPCG(conf.Seed, uint64(i)))
+			args = append(args[:len(args):len(args)], generate(r)...)
+		}
+		res.Runs, res.Args = i+1, args
+
+		var err error
+		res.Native, err = runDiffTestProgram(ctx, conf.Timeout, func(ctx context.Context) (*exec.Cmd, error) {
+			cmd := exec.CommandContext(ctx, nativePath, args...)
+			cmd.Dir = res.OutputPath
+			return cmd, nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "native %s", pkg.PkgPath)
+		}
+		res.Transpiled, err = runDiffTestProgram(ctx, conf.Timeout, func(ctx context.Context) (*exec.Cmd, error) {
+			return runtimeCommand(ctx, conf.Runtime, entryPath, args)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "transpiled %s", pkg.PkgPath)
+		}
+
+		if res.Diff = compareRuns(res.Native, res.Transpiled); res.Diff != nil {
+			break
+		}
+	}
+	return res, nil
+}
+
+// findMainFile returns the name of the file declaring the main function of
+// pkg, or "" if there is none.
+func findMainFile(pkg *packages.Package) string {
+	for i, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				return filepath.Base(pkg.CompiledGoFiles[i])
+			}
+		}
+	}
+	return ""
+}
+
+// generateDiffTestMain returns the runner of the main function declared in
+// mainFile of the package pkgPath. It reports a panic or deadlock like the Go
+// runtime, so that the crash reports can be compared.
+func generateDiffTestMain(pkgPath, mainFile string) string {
+	mainModule := translateGoPathToTypescriptPath(pkgPath) + "/" + strings.TrimSuffix(mainFile, ".go") + ".gs.js"
+	return fmt.Sprintf(`// Code generated by goscript difftest. DO NOT EDIT.
+
+import * as $ from "@goscript/builtin/index.js"
+import { main } from %q
+
+try {
+	await $.runMain(main)
+} catch (err) {
+	const msg = err instanceof Error ? err.message : String(err)
+	if (err instanceof $.DeadlockError || msg.startsWith("panic: ")) {
+		process.stderr.write(msg + "\n")
+	} else {
+		process.stderr.write("panic: " + msg + "\n")
+	}
+	process.exitCode = 2
+}
+`, mainModule)
+}
+
+// runDiffTestProgram runs the command returned by newCmd and collects its
+// output. It is an error if the program could not be started or ran longer
+// than timeout.
+func runDiffTestProgram(ctx context.Context, timeout time.Duration, newCmd func(ctx context.Context) (*exec.Cmd, error)) (*RunOutput, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd, err := newCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			return nil, errors.Errorf("timed out after %v\n%s", timeout, out.String())
+		}
+		return nil, ctxErr
+	}
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, err
+	}
+	return parseRunOutput(out.String(), exitCode), nil
+}
+
+// parseRunOutput splits the crash report off the output of a program which
+// exited with exitCode.
+func parseRunOutput(out string, exitCode int) *RunOutput {
+	res := &RunOutput{Output: out, ExitCode: exitCode}
+	if exitCode == 0 {
+		return res
+	}
+	lines := strings.SplitAfter(out, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: ") {
+			res.Output = strings.Join(lines[:i], "")
+			res.Panic = strings.TrimSuffix(strings.TrimRight(line, "\r\n"), " [recovered]")
+			break
+		}
+	}
+	return res
+}
+
+// compareRuns returns the first divergence between the native and
+// transpiled runs of a program, or nil if there is none.
+func compareRuns(native, transpiled *RunOutput) *Divergence {
+	nativeLines, transpiledLines := outputLines(native.Output), outputLines(transpiled.Output)
+	for i := range max(len(nativeLines), len(transpiledLines)) {
+		n, t := displayLine(nativeLines, i), displayLine(transpiledLines, i)
+		if n != t {
+			return &Divergence{Kind: DivergenceOutput, Line: i + 1, Native: n, Transpiled: t}
+		}
+	}
+	if native.Panic != transpiled.Panic {
+		return &Divergence{Kind: DivergencePanic, Native: displayPanic(native.Panic), Transpiled: displayPanic(transpiled.Panic)}
+	}
+	if native.ExitCode != transpiled.ExitCode {
+		return &Divergence{
+			Kind:       DivergenceExitCode,
+			Native:     strconv.Itoa(native.ExitCode),
+			Transpiled: strconv.Itoa(transpiled.ExitCode),
+		}
+	}
+	return nil
+}
+
+// outputLines splits out into lines.
+func outputLines(out string) []string {
+	if out == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+}
+
+// displayLine returns line i of lines quoted, or "end of output".
+func displayLine(lines []string, i int) string {
+	if i >= len(lines) {
+		return "end of output"
+	}
+	return strconv.Quote(lines[i])
+}
+
+// displayPanic returns the panic message msg quoted, or "no panic".
+func displayPanic(msg string) string {
+	if msg == "" {
+		return "no panic"
+	}
+	return strconv.Quote(msg)
+}
+
+// randomArgWords are the words RandomArgs picks from.
+var randomArgWords = []string{"", "a", "go", "hello", "Hello, 世界", "-", "--flag", "x=1", "true", "NaN", " spaced ", "émoji 🎉"}
+
+// randomArgInts are the edge values of the integer types RandomArgs picks
+// from.
+var randomArgInts = []int64{0, 1, -1, 127, -128, 255, 32767, -32768, 65535, math.MaxInt32, math.MinInt32, math.MaxUint32, 1 << 53, math.MaxInt64, math.MinInt64}
+
+// RandomArgs generates between one and four command line arguments: edge
+// values of the integer types, random integers and floats, and words.
+func RandomArgs(r *rand.Rand) []string {
+	args := make([]string, 1+r.IntN(4))
+	for i := range args {
+		switch r.IntN(5) {
+		case 0:
+			args[i] = strconv.FormatInt(randomArgInts[r.IntN(len(randomArgInts))], 10)
+		case 1:
+			args[i] = strconv.FormatInt(r.Int64N(2001)-1000, 10)
+		case 2:
+			args[i] = strconv.FormatInt(r.Int64(), 10)
+		case 3:
+			args[i] = strconv.FormatFloat(r.NormFloat64()*1000, 'g', -1, 64)
+		default:
+			args[i] = randomArgWords[r.IntN(len(randomArgWords))]
+		}
+	}
+	return args
+}
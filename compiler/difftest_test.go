@@ -0,0 +1,115 @@
+package compiler
+
+import (
+	"context"
+	"math/rand/v2"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseRunOutput(t *testing.T) {
+	goPanic := "hello\npanic: boom [recovered]\n\tpanic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/src/main.go:4 +0x25\n"
+	res := parseRunOutput(goPanic, 2)
+	if res.Output != "hello\n" || res.Panic != "panic: boom" || res.ExitCode != 2 {
+		t.Errorf("parseRunOutput(go panic) = %+v", res)
+	}
+
+	deadlock := "fatal error: all goroutines are asleep - deadlock!\n\ngoroutine 1 [chan receive]:\n"
+	if res := parseRunOutput(deadlock, 2); res.Output != "" || res.Panic != "fatal error: all goroutines are asleep - deadlock!" {
+		t.Errorf("parseRunOutput(deadlock) = %+v", res)
+	}
+
+	// A program printing a panic-like line and exiting normally did not crash.
+	if res := parseRunOutput("panic: not really\n", 0); res.Output != "panic: not really\n" || res.Panic != "" {
+		t.Errorf("parseRunOutput(exit 0) = %+v", res)
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	tests := []struct {
+		native, transpiled RunOutput
+		want               string
+	}{
+		{RunOutput{Output: "a\nb\n"}, RunOutput{Output: "a\nb\n"}, ""},
+		{RunOutput{Output: "a\nb\n"}, RunOutput{Output: "a\nc\n"}, `output line 2: native "b", transpiled "c"`},
+		{RunOutput{Output: "a\nb\n"}, RunOutput{Output: "a\n"}, `output line 2: native "b", transpiled end of output`},
+		{
+			RunOutput{Output: "a\n", Panic: "panic: boom", ExitCode: 2},
+			RunOutput{Output: "a\n", ExitCode: 0},
+			`panic: native "panic: boom", transpiled no panic`,
+		},
+		{RunOutput{ExitCode: 3}, RunOutput{ExitCode: 1}, "exit code: native 3, transpiled 1"},
+	}
+	for _, tt := range tests {
+		got := ""
+		if d := compareRuns(&tt.native, &tt.transpiled); d != nil {
+			got = d.String()
+		}
+		if got != tt.want {
+			t.Errorf("compareRuns(%+v, %+v) = %q, want %q", tt.native, tt.transpiled, got, tt.want)
+		}
+	}
+}
+
+func TestRandomArgs(t *testing.T) {
+	a := RandomArgs(rand.New(rand.NewPCG(1, 2)))
+	b := RandomArgs(rand.New(rand.NewPCG(1, 2)))
+	if len(a) == 0 || len(a) > 4 || !slices.Equal(a, b) {
+		t.Errorf("RandomArgs is not deterministic: %q, %q", a, b)
+	}
+}
+
+func TestGenerateDiffTestMain(t *testing.T) {
+	runner := generateDiffTestMain("example.com/cmd/tool", "tool.go")
+	if want := `import { main } from "@goscript/example.com/cmd/tool/tool.gs.js"`; !strings.Contains(runner, want) {
+		t.Errorf("runner does not contain %q:\n%s", want, runner)
+	}
+}
+
+func TestDiffTest(t *testing.T) {
+	if _, err := exec.LookPath("bun"); err != nil {
+		t.Skip("bun is required to run the transpiled program")
+	}
+
+	dir := writeTestModule(t, map[string]string{
+		"go.mod": "module example.com/difftest\n\ngo 1.24\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	for _, arg := range os.Args[1:] {
+		fmt.Println(arg)
+	}
+	panic("done")
+}
+`,
+	})
+
+	comp, err := NewCompiler(&Config{Dir: dir, OutputPath: filepath.Join(dir, "output")}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := comp.DiffTest(context.Background(), &DiffTestConfig{Args: []string{"a"}, Runs: 3}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	res := results[0]
+	if res.Diff != nil {
+		t.Errorf("unexpected divergence: %v", res.Diff)
+	}
+	if res.Runs != 3 || res.Native.Panic != "panic: done" || res.Native.ExitCode != 2 {
+		t.Errorf("unexpected result: %+v, native: %+v", res, res.Native)
+	}
+}
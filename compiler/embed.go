@@ -0,0 +1,230 @@
+package compiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Variables with a //go:embed directive are initialized with the contents of
+// the embedded files, read at compile time:
+//
+//	//go:embed schema.sql     export let schema: string = "CREATE TABLE ..."
+//	var schema string
+//
+//	//go:embed rules.json     export let rules: $.Bytes = $.stringToBytes("{...}")
+//	var rules []byte
+//
+//	//go:embed static         export let static: embed.FS = new embed.FS([
+//	var static embed.FS         { name: "static/index.html", data: "<html>..." },
+//	                          ])
+//
+// Files which are not valid UTF-8 are written as byte arrays instead.
+
+// embedFile is a file embedded by a //go:embed directive.
+type embedFile struct {
+	// name is the slash-separated path of the file relative to the package
+	// directory, as seen through an embed.FS.
+	name string
+	// path is the absolute path of the file.
+	path string
+}
+
+// analyzeEmbeds records the files embedded in the variables of pkg with
+// //go:embed directives. The patterns are matched against pkg.EmbedFiles,
+// the files the go command resolved for all the directives of the package.
+func (a *Analysis) analyzeEmbeds(pkg *packages.Package) {
+	if len(pkg.EmbedFiles) == 0 {
+		return
+	}
+	for i, file := range pkg.Syntax {
+		pkgDir := filepath.Dir(pkg.CompiledGoFiles[i])
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vspec := spec.(*ast.ValueSpec)
+				doc := vspec.Doc
+				if doc == nil && !gen.Lparen.IsValid() {
+					doc = gen.Doc
+				}
+				patterns := parseEmbedPatterns(doc)
+				if len(patterns) == 0 {
+					continue
+				}
+				a.EmbedFiles[vspec] = matchEmbedFiles(pkgDir, pkg.EmbedFiles, patterns)
+			}
+		}
+	}
+}
+
+// parseEmbedPatterns returns the patterns of the //go:embed directives in
+// doc. Patterns are separated by spaces and may be quoted.
+func parseEmbedPatterns(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var patterns []string
+	for _, comment := range doc.List {
+		args, ok := strings.CutPrefix(comment.Text, "//go:embed")
+		if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+			continue
+		}
+		for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+			var pattern string
+			switch args[0] {
+			case '"', '`':
+				quoted, err := strconv.QuotedPrefix(args)
+				if err != nil {
+					return patterns
+				}
+				pattern, _ = strconv.Unquote(quoted)
+				args = args[len(quoted):]
+			default:
+				end := strings.IndexAny(args, " \t")
+				if end < 0 {
+					end = len(args)
+				}
+				pattern, args = args[:end], args[end:]
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// matchEmbedFiles returns the files of embedFiles matched by patterns, sorted
+// by name. A pattern matches a file, or a directory whose files it embeds
+// recursively except for those whose path below the directory has an element
+// starting with '.' or '_', unless the pattern has the all: prefix.
+func matchEmbedFiles(pkgDir string, embedFiles []string, patterns []string) []*embedFile {
+	var files []*embedFile
+	for _, filePath := range embedFiles {
+		rel, err := filepath.Rel(pkgDir, filePath)
+		if err != nil {
+			continue
+		}
+		name := filepath.ToSlash(rel)
+		if slices.ContainsFunc(patterns, func(pattern string) bool { return embedPatternMatches(pattern, name) }) {
+			files = append(files, &embedFile{name: name, path: filePath})
+		}
+	}
+	slices.SortFunc(files, func(x, y *embedFile) int { return strings.Compare(x.name, y.name) })
+	return files
+}
+
+// embedPatternMatches reports whether the //go:embed pattern embeds the file
+// name, see matchEmbedFiles.
+func embedPatternMatches(pattern, name string) bool {
+	pattern, all := strings.CutPrefix(pattern, "all:")
+	if ok, _ := path.Match(pattern, name); ok {
+		return true
+	}
+	// Look for a directory of name matched by the pattern.
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if ok, _ := path.Match(pattern, dir); !ok {
+			continue
+		}
+		if all {
+			return true
+		}
+		for _, elem := range strings.Split(name[len(dir)+1:], "/") {
+			if strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// writeEmbedValue writes the value of a variable of type goType initialized
+// by a //go:embed directive with files: a string, a byte slice or an
+// embed.FS.
+func (c *GoToTSCompiler) writeEmbedValue(goType types.Type, files []*embedFile) error {
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return fmt.Errorf("failed to read embedded file: %w", err)
+		}
+		contents[i] = data
+	}
+
+	switch goType.Underlying().(type) {
+	case *types.Basic:
+		if len(files) != 1 {
+			return fmt.Errorf("//go:embed of a string must match exactly one file, got %d", len(files))
+		}
+		c.tsw.WriteLiterally(tsStringLiteral(string(contents[0])))
+		return nil
+	case *types.Slice:
+		if len(files) != 1 {
+			return fmt.Errorf("//go:embed of a byte slice must match exactly one file, got %d", len(files))
+		}
+		if utf8.Valid(contents[0]) {
+			c.tsw.WriteLiterallyf("$.stringToBytes(%s)", tsStringLiteral(string(contents[0])))
+		} else {
+			c.writeUint8Array(contents[0])
+		}
+		return nil
+	case *types.Struct:
+		// embed.FS
+		c.tsw.WriteLiterally("new ")
+		c.WriteGoType(goType, GoTypeContextGeneral)
+		if len(files) == 0 {
+			c.tsw.WriteLiterally("()")
+			return nil
+		}
+		c.tsw.WriteLine("([")
+		c.tsw.Indent(1)
+		for i, f := range files {
+			c.tsw.WriteLiterallyf("{ name: %s, data: ", tsStringLiteral(f.name))
+			if utf8.Valid(contents[i]) {
+				c.tsw.WriteLiterally(tsStringLiteral(string(contents[i])))
+			} else {
+				c.writeUint8Array(contents[i])
+			}
+			c.tsw.WriteLine(" },")
+		}
+		c.tsw.Indent(-1)
+		c.tsw.WriteLiterally("])")
+		return nil
+	default:
+		return fmt.Errorf("//go:embed cannot apply to a variable of type %s", goType)
+	}
+}
+
+// writeUint8Array writes data as a Uint8Array literal.
+func (c *GoToTSCompiler) writeUint8Array(data []byte) {
+	c.tsw.WriteLiterally("new Uint8Array([")
+	for i, b := range data {
+		if i != 0 {
+			c.tsw.WriteLiterally(", ")
+		}
+		c.tsw.WriteLiterally(strconv.Itoa(int(b)))
+	}
+	c.tsw.WriteLiterally("])")
+}
+
+// tsStringLiteral returns s as a TypeScript string literal.
+func tsStringLiteral(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
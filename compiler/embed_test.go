@@ -0,0 +1,50 @@
+package compiler
+
+import (
+	"go/ast"
+	"slices"
+	"testing"
+)
+
+func TestParseEmbedPatterns(t *testing.T) {
+	doc := &ast.CommentGroup{List: []*ast.Comment{
+		{Text: "// files are the static files."},
+		{Text: "//go:embed static/*.html  \"with space.txt\""},
+		{Text: "//go:embed `a.txt` all:data"},
+		{Text: "//go:embedded not a directive"},
+	}}
+	got := parseEmbedPatterns(doc)
+	want := []string{"static/*.html", "with space.txt", "a.txt", "all:data"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseEmbedPatterns() = %q, want %q", got, want)
+	}
+}
+
+func TestEmbedPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"hello.txt", "hello.txt", true},
+		{"*.txt", "hello.txt", true},
+		{"*.txt", "data/hello.txt", false},
+		{"data", "data/hello.txt", true},
+		{"data", "data/sub/hello.txt", true},
+		{"data", "data/.hidden/hello.txt", false},
+		{"data", "data/_hello.txt", false},
+		{"all:data", "data/.hidden/hello.txt", true},
+		{"data/_hello.txt", "data/_hello.txt", true},
+		{"dat", "data/hello.txt", false},
+	}
+	for _, tt := range tests {
+		if got := embedPatternMatches(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("embedPatternMatches(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTsStringLiteral(t *testing.T) {
+	if got, want := tsStringLiteral("<a href=\"x\">\n"), `"<a href=\"x\">\n"`; got != want {
+		t.Errorf("tsStringLiteral() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,413 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// With Config.EnumUnions, a named integer or string type which only ever
+// holds the values of its constants is an enum, written as a literal union
+// type and a const object of the constants:
+//
+//	type Priority int                 export type Priority = 0 | 1 | 2
+//	                                  export const Priority = {
+//	const (                             PriorityLow: 0,
+//		PriorityLow Priority = iota       PriorityMedium: 1,
+//		PriorityMedium                    PriorityHigh: 2,
+//		PriorityHigh                    } as const
+//	)
+//
+// The zero value is always part of the union. A type is not an enum if the
+// package computes values of the type with arithmetic, converts non-constant
+// values to it, or converts constants which are not the value of a constant
+// of the type, as these would not type check against the union. Importing
+// packages are not checked, so the option is opt-in.
+//
+// When the names of the values are returned by a switch in the String
+// method of the type, or in a function named like PriorityString, maps
+// between the values and the names are written like the maps of protobuf
+// enums:
+//
+//	export const Priority_name: ReadonlyMap<Priority, string> = new Map<Priority, string>([[0, "low"], ...])
+//	export const Priority_value: ReadonlyMap<string, Priority> = new Map<string, Priority>([["low", 0], ...])
+
+// maxSafeEnumValue is the largest integer represented exactly by a number.
+const maxSafeEnumValue = 1<<53 - 1
+
+// EnumInfo describes a named type written as a literal union type.
+type EnumInfo struct {
+	// Consts are the constants of the type in declaration order.
+	Consts []*types.Const
+	// Values are the distinct values of the union, starting with the zero
+	// value followed by the values of the constants in declaration order.
+	Values []constant.Value
+	// Names are the names returned by String for the values, in the order
+	// of the cases. Names is nil if they cannot be determined statically.
+	Names []EnumName
+}
+
+// EnumName is the name String returns for a value of an enum.
+type EnumName struct {
+	Value constant.Value
+	Name  string
+}
+
+// hasValue reports whether val is one of the values of the union.
+func (e *EnumInfo) hasValue(val constant.Value) bool {
+	return slices.ContainsFunc(e.Values, func(v constant.Value) bool {
+		return constant.Compare(v, token.EQL, val)
+	})
+}
+
+// analyzeEnums finds the enum types of pkg and the names of their values.
+func (a *Analysis) analyzeEnums(pkg *packages.Package) {
+	scope := pkg.Types.Scope()
+	enums := make(map[*types.TypeName]*EnumInfo)
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || typeName.IsAlias() {
+			continue
+		}
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() != 0 || a.isBigIntType(named) {
+			continue
+		}
+		basic, ok := named.Underlying().(*types.Basic)
+		if !ok || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+			continue
+		}
+		enums[typeName] = &EnumInfo{Values: []constant.Value{enumZeroValue(basic)}}
+	}
+	if len(enums) == 0 {
+		return
+	}
+
+	// Collect the constants of the types
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok {
+			consts = append(consts, c)
+		}
+	}
+	slices.SortFunc(consts, func(x, y *types.Const) int { return int(x.Pos() - y.Pos()) })
+	for _, c := range consts {
+		named, ok := c.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		enum := enums[named.Obj()]
+		if enum == nil {
+			continue
+		}
+		val := c.Val()
+		if val.Kind() == constant.Int {
+			if v, exact := constant.Int64Val(val); !exact || v > maxSafeEnumValue || v < -maxSafeEnumValue {
+				delete(enums, named.Obj())
+				continue
+			}
+		}
+		enum.Consts = append(enum.Consts, c)
+		if !enum.hasValue(val) {
+			enum.Values = append(enum.Values, val)
+		}
+	}
+
+	// Drop the types producing values which are not in the union
+	enumOf := func(expr ast.Expr) *EnumInfo {
+		if named, ok := pkg.TypesInfo.TypeOf(expr).(*types.Named); ok {
+			return enums[named.Obj()]
+		}
+		return nil
+	}
+	drop := func(expr ast.Expr) {
+		if named, ok := pkg.TypesInfo.TypeOf(expr).(*types.Named); ok {
+			delete(enums, named.Obj())
+		}
+	}
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.GenDecl:
+				if n.Tok != token.CONST {
+					return true
+				}
+				// Constant declarations are folded to their values
+				for _, spec := range n.Specs {
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						c, ok := pkg.TypesInfo.Defs[name].(*types.Const)
+						if !ok {
+							continue
+						}
+						if named, ok := c.Type().(*types.Named); ok && enums[named.Obj()] != nil && !enums[named.Obj()].hasValue(c.Val()) {
+							delete(enums, named.Obj())
+						}
+					}
+				}
+				return false
+			case *ast.IncDecStmt:
+				drop(n.X)
+			case *ast.AssignStmt:
+				if n.Tok != token.ASSIGN && n.Tok != token.DEFINE {
+					drop(n.Lhs[0])
+				}
+			case *ast.RangeStmt:
+				drop(n.X)
+			case ast.Expr:
+				enum := enumOf(n)
+				if enum == nil {
+					return true
+				}
+				tv := pkg.TypesInfo.Types[n]
+				switch n := n.(type) {
+				case *ast.BinaryExpr:
+					if !isComparisonOp(n.Op) {
+						drop(n)
+						return false
+					}
+				case *ast.UnaryExpr:
+					if n.Op == token.SUB || n.Op == token.XOR || n.Op == token.ADD {
+						drop(n)
+						return false
+					}
+				case *ast.CallExpr:
+					// Conversions of non-constant values may produce any value
+					if funTV := pkg.TypesInfo.Types[n.Fun]; funTV.IsType() && tv.Value == nil {
+						drop(n)
+						return false
+					}
+					// min and max are written as Math.min and Math.max
+					if fun, ok := ast.Unparen(n.Fun).(*ast.Ident); ok {
+						if _, isBuiltin := pkg.TypesInfo.Uses[fun].(*types.Builtin); isBuiltin && (fun.Name == "min" || fun.Name == "max") {
+							drop(n)
+							return false
+						}
+					}
+				}
+				if tv.Value != nil {
+					if !enum.hasValue(tv.Value) {
+						drop(n)
+					}
+					// Nested constants are written as part of this one
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	for typeName, enum := range enums {
+		if len(enum.Consts) == 0 {
+			continue
+		}
+		enum.Names = enumNames(pkg, typeName, enum)
+		a.EnumTypes[typeName] = enum
+	}
+}
+
+// isComparisonOp reports whether op compares its operands.
+func isComparisonOp(op token.Token) bool {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return true
+	}
+	return false
+}
+
+// enumZeroValue returns the zero value of the underlying type of an enum.
+func enumZeroValue(basic *types.Basic) constant.Value {
+	if basic.Info()&types.IsString != 0 {
+		return constant.MakeString("")
+	}
+	return constant.MakeInt64(0)
+}
+
+// enumNames returns the names of the values of the enum typeName returned
+// by the switch in its String method or in the function <Type>String, or
+// nil if there is no such switch.
+func enumNames(pkg *packages.Package, typeName *types.TypeName, enum *EnumInfo) []EnumName {
+	// The maps must not collide with the declarations of the package
+	if pkg.Types.Scope().Lookup(typeName.Name()+"_name") != nil ||
+		pkg.Types.Scope().Lookup(typeName.Name()+"_value") != nil {
+		return nil
+	}
+	named := typeName.Type().(*types.Named)
+	for method := range named.Methods() {
+		if method.Name() == "name" || method.Name() == "value" {
+			return nil
+		}
+	}
+
+	var stringFunc *types.Func
+	if obj, _, _ := types.LookupFieldOrMethod(named, false, pkg.Types, "String"); obj != nil {
+		stringFunc, _ = obj.(*types.Func)
+	} else {
+		stringFunc, _ = pkg.Types.Scope().Lookup(typeName.Name() + "String").(*types.Func)
+	}
+	if stringFunc == nil {
+		return nil
+	}
+	sig := stringFunc.Type().(*types.Signature)
+	if sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), types.Typ[types.String]) {
+		return nil
+	}
+	var param *types.Var
+	switch {
+	case sig.Recv() != nil && sig.Params().Len() == 0:
+		param = sig.Recv()
+	case sig.Recv() == nil && sig.Params().Len() == 1 && types.Identical(sig.Params().At(0).Type(), named):
+		param = sig.Params().At(0)
+	default:
+		return nil
+	}
+
+	decl := findFuncDecl(pkg, stringFunc)
+	if decl == nil || decl.Body == nil || len(decl.Body.List) == 0 {
+		return nil
+	}
+	sw, ok := decl.Body.List[0].(*ast.SwitchStmt)
+	if !ok || sw.Init != nil || sw.Tag == nil {
+		return nil
+	}
+	if tag, ok := sw.Tag.(*ast.Ident); !ok || pkg.TypesInfo.Uses[tag] != param {
+		return nil
+	}
+
+	var names []EnumName
+	for _, stmt := range sw.Body.List {
+		clause := stmt.(*ast.CaseClause)
+		if clause.List == nil {
+			continue
+		}
+		if len(clause.Body) != 1 {
+			return nil
+		}
+		ret, ok := clause.Body[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			return nil
+		}
+		name := pkg.TypesInfo.Types[ret.Results[0]].Value
+		if name == nil || name.Kind() != constant.String {
+			return nil
+		}
+		for _, expr := range clause.List {
+			val := pkg.TypesInfo.Types[expr].Value
+			if val == nil || !enum.hasValue(val) {
+				return nil
+			}
+			names = append(names, EnumName{Value: val, Name: constant.StringVal(name)})
+		}
+	}
+	return names
+}
+
+// findFuncDecl returns the declaration of the function or method fn in pkg.
+func findFuncDecl(pkg *packages.Package, fn *types.Func) *ast.FuncDecl {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && pkg.TypesInfo.Defs[funcDecl.Name] == fn {
+				return funcDecl
+			}
+		}
+	}
+	return nil
+}
+
+// enumInfo returns the enum of the type typeName, or nil if it is not an
+// enum or Config.EnumUnions is not set.
+func (c *GoToTSCompiler) enumInfo(typeName *types.TypeName) *EnumInfo {
+	if !c.config.EnumUnions {
+		return nil
+	}
+	return c.analysis.EnumTypes[typeName]
+}
+
+// enumOf returns the enum declared by the type specification a, or nil if
+// it does not declare an enum.
+func (c *GoToTSCompiler) enumOf(a *ast.TypeSpec) *EnumInfo {
+	typeName, ok := c.pkg.TypesInfo.Defs[a.Name].(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	return c.enumInfo(typeName)
+}
+
+// isEnumType reports whether t is an enum type of the package.
+func (c *GoToTSCompiler) isEnumType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && c.enumInfo(named.Obj()) != nil
+}
+
+// writeEnumValue writes a value of an enum as a literal.
+func (c *GoToTSCompiler) writeEnumValue(val constant.Value) {
+	if val.Kind() == constant.String {
+		c.tsw.WriteLiterally(strconv.Quote(constant.StringVal(val)))
+		return
+	}
+	c.tsw.WriteLiterally(val.ExactString())
+}
+
+// writeEnumTypeSpec writes the literal union type and the const object of
+// the enum declared by a, and the maps between its values and their names.
+func (c *GoToTSCompiler) writeEnumTypeSpec(a *ast.TypeSpec, enum *EnumInfo) {
+	name := a.Name.Name
+	c.tsw.WriteLiterallyf("export type %s = ", name)
+	for i, val := range enum.Values {
+		if i != 0 {
+			c.tsw.WriteLiterally(" | ")
+		}
+		c.writeEnumValue(val)
+	}
+	c.tsw.WriteLine(";")
+	c.tsw.WriteLine("")
+
+	c.tsw.WriteLinef("export const %s = {", name)
+	c.tsw.Indent(1)
+	for _, constObj := range enum.Consts {
+		c.tsw.WriteLiterallyf("%s: ", constObj.Name())
+		c.writeEnumValue(constObj.Val())
+		c.tsw.WriteLine(",")
+	}
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("} as const;")
+
+	if enum.Names == nil {
+		return
+	}
+	var names, values []EnumName
+	for _, n := range enum.Names {
+		if !slices.ContainsFunc(names, func(o EnumName) bool { return constant.Compare(o.Value, token.EQL, n.Value) }) {
+			names = append(names, n)
+		}
+		if !slices.ContainsFunc(values, func(o EnumName) bool { return o.Name == n.Name }) {
+			values = append(values, n)
+		}
+	}
+
+	c.tsw.WriteLine("")
+	c.tsw.WriteLinef("export const %s_name: ReadonlyMap<%s, string> = new Map<%s, string>([", name, name, name)
+	c.tsw.Indent(1)
+	for _, n := range names {
+		c.tsw.WriteLiterally("[")
+		c.writeEnumValue(n.Value)
+		c.tsw.WriteLiterallyf(", %s],", strconv.Quote(n.Name))
+		c.tsw.WriteLine("")
+	}
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("]);")
+	c.tsw.WriteLine("")
+	c.tsw.WriteLinef("export const %s_value: ReadonlyMap<string, %s> = new Map<string, %s>([", name, name, name)
+	c.tsw.Indent(1)
+	for _, n := range values {
+		c.tsw.WriteLiterallyf("[%s, ", strconv.Quote(n.Name))
+		c.writeEnumValue(n.Value)
+		c.tsw.WriteLine("],")
+	}
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("]);")
+}
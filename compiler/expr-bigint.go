@@ -0,0 +1,414 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// When Config.Int64AsBigInt is set, int64, uint64 and uintptr values are
+// represented as TypeScript bigint instead of number. Go integer arithmetic
+// wraps around on overflow while bigint arithmetic is unbounded, so every
+// operation that may leave the 64-bit range is wrapped in BigInt.asIntN or
+// BigInt.asUintN:
+//
+//	a + b      -> BigInt.asIntN(64, a + b)
+//	a << n     -> BigInt.asIntN(64, a << BigInt(n))
+//	^u         -> BigInt.asUintN(64, ~u)
+//	int64(i)   -> $.int64(i)
+//	int(a)     -> Number(a)
+//	s[a]       -> s![Number(a)]
+//
+// Handwritten gs/ packages are written against number, so bigint arguments are
+// converted with Number() when calling into them and bigint results are
+// converted back with $.int64 / $.uint64. Functions listed under "bigintParams"
+// in the package meta.json accept bigint arguments as they are. Named types
+// declared by handwritten packages (e.g. time.Duration) keep using number.
+
+// isBigIntType reports whether values of type t are represented as bigint.
+func (c *GoToTSCompiler) isBigIntType(t types.Type) bool {
+	if c.config == nil || !c.config.Int64AsBigInt {
+		return false
+	}
+	return c.analysis.isBigIntType(t)
+}
+
+// isBigIntType reports whether values of type t are represented as bigint
+// when Config.Int64AsBigInt is set: int64, uint64 and uintptr, except for the
+// named types of handwritten packages.
+func (a *Analysis) isBigIntType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok {
+		if pkg := named.Obj().Pkg(); pkg != nil && a.isHandwrittenPackage(pkg.Path()) {
+			return false
+		}
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	switch basic.Kind() {
+	case types.Int64, types.Uint64, types.Uintptr:
+		return true
+	}
+	return false
+}
+
+// isBigIntExpr reports whether the value of expr is represented as bigint.
+func (c *GoToTSCompiler) isBigIntExpr(expr ast.Expr) bool {
+	return c.isBigIntType(c.pkg.TypesInfo.TypeOf(expr))
+}
+
+// isUnsignedType reports whether the underlying type of t is an unsigned integer.
+func isUnsignedType(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsUnsigned != 0
+}
+
+// writeBigIntWrapOpen writes the opening of the call which wraps a bigint
+// value of type t into the 64-bit range of t.
+func (c *GoToTSCompiler) writeBigIntWrapOpen(t types.Type) {
+	if isUnsignedType(t) {
+		c.tsw.WriteLiterally("BigInt.asUintN(64, ")
+	} else {
+		c.tsw.WriteLiterally("BigInt.asIntN(64, ")
+	}
+}
+
+// writeBigIntConstantExpr writes expr as a bigint literal if it is a constant
+// expression of a bigint type. It returns false if expr was not written.
+func (c *GoToTSCompiler) writeBigIntConstantExpr(expr ast.Expr) bool {
+	tv, ok := c.pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || !c.isBigIntType(tv.Type) {
+		return false
+	}
+	// Typed constants declared with a bigint type are already bigint, keep the name.
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		if constObj, isConst := c.objectOfIdent(ident).(*types.Const); isConst && constObj.Pkg() != nil &&
+			constObj.Pkg() != c.pkg.Types && c.isBigIntType(constObj.Type()) {
+			return false
+		}
+	}
+	return c.writeBigIntConstant(tv.Value)
+}
+
+// writeBigIntConstant writes an integer constant value as an exact bigint literal.
+func (c *GoToTSCompiler) writeBigIntConstant(val constant.Value) bool {
+	val = constant.ToInt(val)
+	if val.Kind() != constant.Int {
+		return false
+	}
+	c.tsw.WriteLiterally(val.ExactString() + "n")
+	return true
+}
+
+// writeBigIntOperand writes expr as a bigint, converting number values with BigInt().
+func (c *GoToTSCompiler) writeBigIntOperand(expr ast.Expr) error {
+	if c.isBigIntExpr(expr) {
+		return c.WriteValueExpr(expr)
+	}
+	if tv, ok := c.pkg.TypesInfo.Types[expr]; ok && tv.Value != nil && c.writeBigIntConstant(tv.Value) {
+		return nil
+	}
+	c.tsw.WriteLiterally("BigInt(")
+	if err := c.WriteValueExpr(expr); err != nil {
+		return err
+	}
+	c.tsw.WriteLiterally(")")
+	return nil
+}
+
+// writeNumberExpr writes an integer expression in a position where TypeScript
+// requires a number, such as an index, a slice bound or a length.
+func (c *GoToTSCompiler) writeNumberExpr(expr ast.Expr) error {
+	if !c.isBigIntExpr(expr) {
+		return c.WriteValueExpr(expr)
+	}
+	c.tsw.WriteLiterally("Number(")
+	if err := c.WriteValueExpr(expr); err != nil {
+		return err
+	}
+	c.tsw.WriteLiterally(")")
+	return nil
+}
+
+// isBigIntBinaryOp reports whether the binary operation x op y involves a bigint operand.
+func (c *GoToTSCompiler) isBigIntBinaryOp(x ast.Expr, op token.Token, y ast.Expr) bool {
+	if c.isBigIntExpr(x) {
+		return true
+	}
+	// A shift count may be a bigint even if the shifted value is not.
+	return (op == token.SHL || op == token.SHR) && c.isBigIntExpr(y)
+}
+
+// writeBigIntBinaryOp writes the binary operation x op y where at least one
+// operand is a bigint, wrapping the result into the 64-bit range if needed.
+func (c *GoToTSCompiler) writeBigIntBinaryOp(x ast.Expr, op token.Token, y ast.Expr) error {
+	xType := c.pkg.TypesInfo.TypeOf(x)
+
+	if op == token.SHL || op == token.SHR {
+		if !c.isBigIntType(xType) {
+			// number shifted by a bigint count
+			c.tsw.WriteLiterally("(")
+			if err := c.WriteValueExpr(x); err != nil {
+				return err
+			}
+			c.tsw.WriteLiterallyf(" %s ", tokenMap[op])
+			if err := c.writeNumberExpr(y); err != nil {
+				return err
+			}
+			c.tsw.WriteLiterally(")")
+			return nil
+		}
+		if op == token.SHL {
+			c.writeBigIntWrapOpen(xType)
+		} else {
+			c.tsw.WriteLiterally("(")
+		}
+		if err := c.WriteValueExpr(x); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterallyf(" %s ", tokenMap[op])
+		if err := c.writeBigIntOperand(y); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterally(")")
+		return nil
+	}
+
+	tokStr, ok := TokenToTs(op)
+	if !ok {
+		return errors.Errorf("unhandled bigint binary op: %s", op.String())
+	}
+
+	wrap := false
+	parens := false
+	switch op {
+	case token.ADD, token.SUB, token.MUL:
+		wrap = true
+	case token.QUO:
+		// Only the signed division of the minimum value by -1 overflows.
+		wrap = !isUnsignedType(xType)
+	case token.AND, token.OR, token.XOR, token.AND_NOT:
+		parens = true
+	}
+
+	if wrap {
+		c.writeBigIntWrapOpen(xType)
+	} else if parens {
+		c.tsw.WriteLiterally("(")
+	}
+	if err := c.WriteValueExpr(x); err != nil {
+		return fmt.Errorf("failed to write bigint binary expression left operand: %w", err)
+	}
+	if op == token.AND_NOT {
+		c.tsw.WriteLiterally(" & ~(")
+	} else {
+		c.tsw.WriteLiterallyf(" %s ", tokStr)
+	}
+	if err := c.WriteValueExpr(y); err != nil {
+		return fmt.Errorf("failed to write bigint binary expression right operand: %w", err)
+	}
+	if op == token.AND_NOT {
+		c.tsw.WriteLiterally(")")
+	}
+	if wrap || parens {
+		c.tsw.WriteLiterally(")")
+	}
+	return nil
+}
+
+// writeBigIntUnaryExpr writes a unary -, + or ^ on a bigint operand.
+func (c *GoToTSCompiler) writeBigIntUnaryExpr(exp *ast.UnaryExpr) error {
+	xType := c.pkg.TypesInfo.TypeOf(exp.X)
+	switch exp.Op {
+	case token.ADD:
+		return c.WriteValueExpr(exp.X)
+	case token.SUB:
+		c.writeBigIntWrapOpen(xType)
+		c.tsw.WriteLiterally("-")
+	case token.XOR:
+		// The complement of a signed value stays in range.
+		if !isUnsignedType(xType) {
+			c.tsw.WriteLiterally("~(")
+			if err := c.WriteValueExpr(exp.X); err != nil {
+				return err
+			}
+			c.tsw.WriteLiterally(")")
+			return nil
+		}
+		c.writeBigIntWrapOpen(xType)
+		c.tsw.WriteLiterally("~")
+	default:
+		return errors.Errorf("unhandled bigint unary op: %s", exp.Op.String())
+	}
+	c.tsw.WriteLiterally("(")
+	if err := c.WriteValueExpr(exp.X); err != nil {
+		return err
+	}
+	c.tsw.WriteLiterally("))")
+	return nil
+}
+
+// writeBigIntIncDec writes x++ or x-- on a bigint operand as a wrapping assignment.
+func (c *GoToTSCompiler) writeBigIntIncDec(stmt *ast.IncDecStmt) error {
+	if err := c.WriteValueExpr(stmt.X); err != nil {
+		return err
+	}
+	c.tsw.WriteLiterally(" = ")
+	c.writeBigIntWrapOpen(c.pkg.TypesInfo.TypeOf(stmt.X))
+	if err := c.WriteValueExpr(stmt.X); err != nil {
+		return err
+	}
+	if stmt.Tok == token.INC {
+		c.tsw.WriteLiterally(" + 1n)")
+	} else {
+		c.tsw.WriteLiterally(" - 1n)")
+	}
+	return nil
+}
+
+// compoundAssignOps maps compound assignment tokens to their binary operator.
+var compoundAssignOps = map[token.Token]token.Token{
+	token.ADD_ASSIGN:     token.ADD,
+	token.SUB_ASSIGN:     token.SUB,
+	token.MUL_ASSIGN:     token.MUL,
+	token.QUO_ASSIGN:     token.QUO,
+	token.REM_ASSIGN:     token.REM,
+	token.AND_ASSIGN:     token.AND,
+	token.OR_ASSIGN:      token.OR,
+	token.XOR_ASSIGN:     token.XOR,
+	token.SHL_ASSIGN:     token.SHL,
+	token.SHR_ASSIGN:     token.SHR,
+	token.AND_NOT_ASSIGN: token.AND_NOT,
+}
+
+// writeBigIntCompoundAssign writes `lhs op= rhs` on a bigint operand as
+// `lhs = <lhs op rhs>`. It returns false if the assignment is not a compound
+// assignment involving a bigint.
+func (c *GoToTSCompiler) writeBigIntCompoundAssign(lhs, rhs ast.Expr, tok token.Token) (bool, error) {
+	op, ok := compoundAssignOps[tok]
+	if !ok || !c.isBigIntBinaryOp(lhs, op, rhs) {
+		return false, nil
+	}
+	// Map elements are not assignable in TypeScript, leave them to $.mapSet
+	if indexExpr, isIndex := lhs.(*ast.IndexExpr); isIndex {
+		if _, isMap := c.pkg.TypesInfo.TypeOf(indexExpr.X).Underlying().(*types.Map); isMap {
+			return false, nil
+		}
+	}
+	if err := c.WriteValueExpr(lhs); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(" = ")
+	return true, c.writeBigIntBinaryOp(lhs, op, rhs)
+}
+
+// writeBigIntConversion handles conversions between bigint and number
+// represented integer types, e.g. int64(i), uint64(a) or float64(a).
+func (c *GoToTSCompiler) writeBigIntConversion(exp *ast.CallExpr) (handled bool, err error) {
+	if len(exp.Args) != 1 {
+		return false, nil
+	}
+	tv, ok := c.pkg.TypesInfo.Types[exp.Fun]
+	if !ok || !tv.IsType() {
+		return false, nil
+	}
+	arg := exp.Args[0]
+	targetType := tv.Type
+	argType := c.pkg.TypesInfo.TypeOf(arg)
+	targetBig := c.isBigIntType(targetType)
+	argBig := c.isBigIntType(argType)
+	if !targetBig && !argBig {
+		return false, nil
+	}
+	if basic, ok := targetType.Underlying().(*types.Basic); !ok || basic.Info()&types.IsNumeric == 0 {
+		return false, nil
+	}
+
+	switch {
+	case targetBig && argBig:
+		if isUnsignedType(targetType) == isUnsignedType(argType) {
+			return true, c.WriteValueExpr(arg)
+		}
+		c.writeBigIntWrapOpen(targetType)
+	case targetBig && isUnsignedType(targetType):
+		c.tsw.WriteLiterally("$.uint64(")
+	case targetBig:
+		c.tsw.WriteLiterally("$.int64(")
+	default:
+		// bigint to a number represented type
+		if bits, unsigned, fixed := c.fixedIntWidth(targetType); fixed {
+			if unsigned {
+				c.tsw.WriteLiterallyf("Number(BigInt.asUintN(%d, ", bits)
+			} else {
+				c.tsw.WriteLiterallyf("Number(BigInt.asIntN(%d, ", bits)
+			}
+			if err := c.WriteValueExpr(arg); err != nil {
+				return true, fmt.Errorf("failed to write argument for bigint conversion: %w", err)
+			}
+			c.tsw.WriteLiterally("))")
+			return true, nil
+		}
+		c.tsw.WriteLiterally("Number(")
+	}
+	if err := c.WriteValueExpr(arg); err != nil {
+		return true, fmt.Errorf("failed to write argument for bigint conversion: %w", err)
+	}
+	c.tsw.WriteLiterally(")")
+	return true, nil
+}
+
+// handwrittenCallee returns the function called by exp if it is declared in a
+// handwritten gs/ package, or nil otherwise.
+func (c *GoToTSCompiler) handwrittenCallee(exp *ast.CallExpr) *types.Func {
+	fn, ok := typeutil.Callee(c.pkg.TypesInfo, exp).(*types.Func)
+	if !ok || fn.Pkg() == nil || !c.analysis.isHandwrittenPackage(fn.Pkg().Path()) {
+		return nil
+	}
+	return fn
+}
+
+// acceptsBigIntParams reports whether the handwritten function fn accepts
+// bigint arguments for its int64 and uint64 parameters.
+func (c *GoToTSCompiler) acceptsBigIntParams(fn *types.Func) bool {
+	return c.analysis.BigIntParamFuncs[funcMethodKey(fn)]
+}
+
+// writeHandwrittenCallResultsOpen writes the conversion of the bigint results
+// of a call into a handwritten package, which returns them as number.
+// It returns the text to write after the call.
+func (c *GoToTSCompiler) writeHandwrittenCallResultsOpen(fn *types.Func) string {
+	results := fn.Type().(*types.Signature).Results()
+	kinds := make([]string, results.Len())
+	hasBigInt := false
+	for i := 0; i < results.Len(); i++ {
+		kinds[i] = "null"
+		if resultType := results.At(i).Type(); c.isBigIntType(resultType) {
+			kinds[i] = "'int64'"
+			if isUnsignedType(resultType) {
+				kinds[i] = "'uint64'"
+			}
+			hasBigInt = true
+		}
+	}
+	if !hasBigInt {
+		return ""
+	}
+	if results.Len() == 1 {
+		c.tsw.WriteLiterallyf("$.%s(", strings.Trim(kinds[0], "'"))
+		return ")"
+	}
+	// $.bigintResults(tuple, ['int64', null]) converts the listed tuple elements
+	c.tsw.WriteLiterally("$.bigintResults(")
+	return ", [" + strings.Join(kinds, ", ") + "])"
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 
 	"github.com/pkg/errors"
 )
@@ -55,6 +56,19 @@ func (c *GoToTSCompiler) writeBuiltinFunction(exp *ast.CallExpr, funName string)
 		if len(exp.Args) != 1 {
 			return true, errors.Errorf("unhandled new call with incorrect number of arguments: %d != 1", len(exp.Args))
 		}
+		// Pointers to structs are the struct instance, other pointers are a VarRef
+		if t := c.pkg.TypesInfo.TypeOf(exp.Args[0]); t != nil {
+			if dictName, ok := c.typeDictParamName(t); ok {
+				c.tsw.WriteLiterallyf("$.newOf(%s)", dictName)
+				return true, nil
+			}
+			if _, isStruct := t.Underlying().(*types.Struct); !isStruct {
+				c.tsw.WriteLiterally("$.varRef(")
+				c.WriteZeroValueForType(t)
+				c.tsw.WriteLiterally(")")
+				return true, nil
+			}
+		}
 		c.tsw.WriteLiterally("new ")
 		c.WriteTypeExpr(exp.Args[0]) // This should write the TypeScript type T_ts
 		c.tsw.WriteLiterally("()")
@@ -71,6 +85,18 @@ func (c *GoToTSCompiler) writeBuiltinFunction(exp *ast.CallExpr, funName string)
 		}
 		c.tsw.WriteLiterally("$.copy")
 		return true, nil
+	case "complex":
+		if len(exp.Args) != 2 {
+			return true, errors.Errorf("unhandled complex call with incorrect number of arguments: %d != 2", len(exp.Args))
+		}
+		c.tsw.WriteLiterally("$.complex")
+		return true, nil
+	case "real", "imag":
+		if len(exp.Args) != 1 {
+			return true, errors.Errorf("unhandled %s call with incorrect number of arguments: %d != 1", funName, len(exp.Args))
+		}
+		c.tsw.WriteLiterallyf("$.%s", funName)
+		return true, nil
 	case "recover":
 		if len(exp.Args) != 0 {
 			return true, errors.Errorf("unhandled recover call with incorrect number of arguments: %d != 0", len(exp.Args))
@@ -162,6 +188,10 @@ func (c *GoToTSCompiler) writeAppendCall(exp *ast.CallExpr) error {
 	}
 
 	// The remaining arguments are the elements to append
+	var elemType types.Type
+	if slice, ok := typeUnderlying(c.pkg.TypesInfo.TypeOf(exp)).(*types.Slice); ok {
+		elemType = slice.Elem()
+	}
 	elemsToAppend := exp.Args[1:]
 	for i, arg := range elemsToAppend {
 		if i > 0 || len(exp.Args) > 1 {
@@ -199,7 +229,7 @@ func (c *GoToTSCompiler) writeAppendCall(exp *ast.CallExpr) error {
 			continue
 		}
 
-		if err := c.WriteValueExpr(arg); err != nil {
+		if err := c.writeInterfaceValue(arg, elemType); err != nil {
 			return fmt.Errorf("failed to write argument %d in append call: %w", i+1, err)
 		}
 	}
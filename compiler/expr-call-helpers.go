@@ -78,6 +78,14 @@ func (c *GoToTSCompiler) writeGenericSliceCreation(elemType types.Type, lengthAr
 
 // writeSliceTypeHint writes the type hint parameter for makeSlice calls
 func (c *GoToTSCompiler) writeSliceTypeHint(elemType types.Type, hasCapacity bool) {
+	// Elements of a type parameter are created by the dictionary of the type argument
+	if dictName, ok := c.typeDictParamName(elemType); ok {
+		if !hasCapacity {
+			c.tsw.WriteLiterally(", undefined")
+		}
+		c.tsw.WriteLiterallyf(", %s.zero", dictName)
+		return
+	}
 	typeHint := c.getTypeHintForSliceElement(elemType)
 	if typeHint != "" {
 		if !hasCapacity {
@@ -101,8 +109,8 @@ func (c *GoToTSCompiler) writeExprOrDefault(expr interface{}, defaultValue strin
 		c.tsw.WriteLiterally(e)
 		return nil
 	case ast.Expr:
-		// If it's an ast.Expr, call WriteValueExpr directly
-		return c.WriteValueExpr(e)
+		// Lengths and capacities are numbers, even if given as int64
+		return c.writeNumberExpr(e)
 	default:
 		// If we can't handle the type, return an error
 		return errors.Errorf("unsupported expression type in writeExprOrDefault: %T", e)
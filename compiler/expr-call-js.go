@@ -0,0 +1,264 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// syscallJSPath is the import path of the JavaScript interop package.
+const syscallJSPath = "syscall/js"
+
+// The values of syscall/js are js.Value objects holding the JavaScript value
+// in their ref property. Calls of the Value methods which access properties
+// or call functions are lowered to the equivalent JavaScript, unwrapping the
+// receivers and arguments and wrapping the result only once per chain:
+//
+//	js.Global().Get("document").Call("getElementById", id)
+//
+// becomes
+//
+//	js.wrap(globalThis.document.getElementById(id))
+//
+// The other methods, and calls in files not importing syscall/js, use the
+// methods of js.Value in gs/syscall/js, which behave the same.
+
+// syscallJSCallee returns the function or method of syscall/js called by exp,
+// and the import alias of syscall/js in the current file.
+func (c *GoToTSCompiler) syscallJSCallee(exp *ast.CallExpr) (*types.Func, string) {
+	fn, ok := typeutil.Callee(c.pkg.TypesInfo, exp).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != syscallJSPath {
+		return nil, ""
+	}
+	alias, found := c.resolveImportAlias(fn.Pkg())
+	if !found {
+		return nil, ""
+	}
+	return fn, alias
+}
+
+// isSyscallJSValueType reports whether t is js.Value or a type embedding it,
+// which are all represented by the js.Value class.
+func isSyscallJSValueType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != syscallJSPath {
+		return false
+	}
+	switch named.Obj().Name() {
+	case "Value", "Func", "Error":
+		return true
+	}
+	return false
+}
+
+// writeSyscallJSCall writes a call of a js.Value method as JavaScript, see
+// above. It returns false if the call is not lowered.
+func (c *GoToTSCompiler) writeSyscallJSCall(exp *ast.CallExpr) (bool, error) {
+	fn, alias := c.syscallJSCallee(exp)
+	if fn == nil || fn.Type().(*types.Signature).Recv() == nil {
+		return false, nil
+	}
+	switch fn.Name() {
+	case "Get", "Index", "Call", "Invoke", "New":
+		c.tsw.WriteLiterallyf("%s.wrap(", alias)
+		if err := c.writeSyscallJSRef(exp); err != nil {
+			return true, err
+		}
+		c.tsw.WriteLiterally(")")
+	case "Set", "SetIndex":
+		if err := c.writeSyscallJSProperty(exp.Fun.(*ast.SelectorExpr).X, exp.Args[0]); err != nil {
+			return true, err
+		}
+		c.tsw.WriteLiterally(" = ")
+		if err := c.writeSyscallJSArg(exp.Args[1], alias); err != nil {
+			return true, err
+		}
+	case "Delete":
+		c.tsw.WriteLiterally("delete ")
+		if err := c.writeSyscallJSProperty(exp.Fun.(*ast.SelectorExpr).X, exp.Args[0]); err != nil {
+			return true, err
+		}
+	case "Length":
+		if err := c.writeSyscallJSRefOf(exp.Fun.(*ast.SelectorExpr).X); err != nil {
+			return true, err
+		}
+		c.tsw.WriteLiterally(".length")
+	case "Truthy":
+		c.tsw.WriteLiterally("!!")
+		if err := c.writeSyscallJSRefOf(exp.Fun.(*ast.SelectorExpr).X); err != nil {
+			return true, err
+		}
+	case "IsNull", "IsUndefined", "Equal":
+		c.tsw.WriteLiterally("(")
+		if err := c.writeSyscallJSRefOf(exp.Fun.(*ast.SelectorExpr).X); err != nil {
+			return true, err
+		}
+		switch fn.Name() {
+		case "IsNull":
+			c.tsw.WriteLiterally(" === null")
+		case "IsUndefined":
+			c.tsw.WriteLiterally(" === undefined")
+		default:
+			c.tsw.WriteLiterally(" === ")
+			if err := c.writeSyscallJSRefOf(exp.Args[0]); err != nil {
+				return true, err
+			}
+		}
+		c.tsw.WriteLiterally(")")
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// writeSyscallJSRef writes the JavaScript value resulting from a call of
+// syscall/js lowered by writeSyscallJSCall, without wrapping it.
+func (c *GoToTSCompiler) writeSyscallJSRef(exp *ast.CallExpr) error {
+	fn, alias := c.syscallJSCallee(exp)
+	if fn.Type().(*types.Signature).Recv() == nil {
+		switch fn.Name() {
+		case "Global":
+			c.tsw.WriteLiterally("globalThis")
+		case "Null":
+			c.tsw.WriteLiterally("null")
+		case "Undefined":
+			c.tsw.WriteLiterally("undefined")
+		case "ValueOf":
+			return c.writeSyscallJSArg(exp.Args[0], alias)
+		}
+		return nil
+	}
+
+	recv := exp.Fun.(*ast.SelectorExpr).X
+	switch fn.Name() {
+	case "Get", "Index":
+		return c.writeSyscallJSProperty(recv, exp.Args[0])
+	case "Call":
+		if err := c.writeSyscallJSProperty(recv, exp.Args[0]); err != nil {
+			return err
+		}
+		return c.writeSyscallJSArgs(exp.Args[1:], exp.Ellipsis.IsValid(), alias)
+	case "Invoke":
+		if err := c.writeSyscallJSRefOf(recv); err != nil {
+			return err
+		}
+		return c.writeSyscallJSArgs(exp.Args, exp.Ellipsis.IsValid(), alias)
+	default: // New
+		c.tsw.WriteLiterally("new (")
+		if err := c.writeSyscallJSRefOf(recv); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterally(")")
+		return c.writeSyscallJSArgs(exp.Args, exp.Ellipsis.IsValid(), alias)
+	}
+}
+
+// writeSyscallJSRefOf writes the JavaScript value of the js.Value expr.
+func (c *GoToTSCompiler) writeSyscallJSRefOf(expr ast.Expr) error {
+	if call, ok := ast.Unparen(expr).(*ast.CallExpr); ok && c.isSyscallJSRefCall(call) {
+		return c.writeSyscallJSRef(call)
+	}
+	switch ast.Unparen(expr).(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr:
+		if err := c.WriteValueExpr(expr); err != nil {
+			return err
+		}
+	default:
+		c.tsw.WriteLiterally("(")
+		if err := c.WriteValueExpr(expr); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterally(")")
+	}
+	c.tsw.WriteLiterally(".ref")
+	return nil
+}
+
+// isSyscallJSRefCall reports whether writeSyscallJSRef can write the result
+// of the call.
+func (c *GoToTSCompiler) isSyscallJSRefCall(exp *ast.CallExpr) bool {
+	fn, _ := c.syscallJSCallee(exp)
+	if fn == nil {
+		return false
+	}
+	switch fn.Name() {
+	case "Global", "Null", "Undefined", "ValueOf":
+		return fn.Type().(*types.Signature).Recv() == nil
+	case "Get", "Index", "Call", "Invoke", "New":
+		return fn.Type().(*types.Signature).Recv() != nil
+	}
+	return false
+}
+
+// writeSyscallJSProperty writes the access of the property or index key of
+// the js.Value recv, using dot notation for constant identifiers.
+func (c *GoToTSCompiler) writeSyscallJSProperty(recv, key ast.Expr) error {
+	if err := c.writeSyscallJSRefOf(recv); err != nil {
+		return err
+	}
+	if tv, ok := c.pkg.TypesInfo.Types[key]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		// Go keywords are valid property names in JavaScript
+		if name := constant.StringVal(tv.Value); token.IsIdentifier(name) || token.IsKeyword(name) {
+			c.tsw.WriteLiterally(".")
+			c.tsw.WriteLiterally(name)
+			return nil
+		}
+	}
+	c.tsw.WriteLiterally("[")
+	if err := c.WriteValueExpr(key); err != nil {
+		return err
+	}
+	c.tsw.WriteLiterally("]")
+	return nil
+}
+
+// writeSyscallJSArgs writes the parenthesized arguments of a JavaScript call.
+// A spread Go slice is converted element by element.
+func (c *GoToTSCompiler) writeSyscallJSArgs(args []ast.Expr, spread bool, alias string) error {
+	c.tsw.WriteLiterally("(")
+	for i, arg := range args {
+		if i != 0 {
+			c.tsw.WriteLiterally(", ")
+		}
+		if spread && i == len(args)-1 {
+			c.tsw.WriteLiterally("...$.asArray(")
+			if err := c.WriteValueExpr(arg); err != nil {
+				return err
+			}
+			c.tsw.WriteLiterallyf(").map(%s.unwrap)", alias)
+			continue
+		}
+		if err := c.writeSyscallJSArg(arg, alias); err != nil {
+			return err
+		}
+	}
+	c.tsw.WriteLiterally(")")
+	return nil
+}
+
+// writeSyscallJSArg writes the Go value arg as a JavaScript value, like
+// js.ValueOf. Values of basic types are the same in JavaScript.
+func (c *GoToTSCompiler) writeSyscallJSArg(arg ast.Expr, alias string) error {
+	argType := c.pkg.TypesInfo.TypeOf(arg)
+	if isSyscallJSValueType(argType) {
+		return c.writeSyscallJSRefOf(arg)
+	}
+	if basic, ok := argType.Underlying().(*types.Basic); ok && !c.isBigIntType(argType) {
+		if basic.Kind() == types.UntypedNil {
+			c.tsw.WriteLiterally("null")
+			return nil
+		}
+		if basic.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 && basic.Info()&types.IsComplex == 0 {
+			return c.WriteValueExpr(arg)
+		}
+	}
+	c.tsw.WriteLiterallyf("%s.unwrap(", alias)
+	if err := c.WriteValueExpr(arg); err != nil {
+		return err
+	}
+	c.tsw.WriteLiterally(")")
+	return nil
+}
@@ -10,6 +10,9 @@ import (
 
 // getTypeHintForSliceElement returns the appropriate type hint for makeSlice based on the Go element type
 func (c *GoToTSCompiler) getTypeHintForSliceElement(elemType types.Type) string {
+	if c.isBigIntType(elemType) {
+		return "bigint"
+	}
 	if basicType, isBasic := elemType.(*types.Basic); isBasic {
 		switch basicType.Kind() {
 		case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
@@ -36,7 +39,7 @@ func (c *GoToTSCompiler) writeMakeChannel(chanType *types.Chan, bufferArg ast.Ex
 
 	// If buffer size is provided, add it
 	if bufferArg != nil {
-		if err := c.WriteValueExpr(bufferArg); err != nil {
+		if err := c.writeNumberExpr(bufferArg); err != nil {
 			return fmt.Errorf("failed to write buffer size in makeChannel: %w", err)
 		}
 	} else {
@@ -127,7 +130,11 @@ func (c *GoToTSCompiler) WriteCallExprMake(exp *ast.CallExpr) error {
 			c.WriteTypeExpr(mapType.Key) // Write the key type
 			c.tsw.WriteLiterally(", ")
 			c.WriteTypeExpr(mapType.Value) // Write the value type
-			c.tsw.WriteLiterally(">()")
+			c.tsw.WriteLiterally(">(")
+			if keyType := c.pkg.TypesInfo.TypeOf(mapType.Key); keyType != nil && c.mapKeyNeedsHashing(keyType) {
+				c.tsw.WriteLiterally(c.mapKeyDescriptor(keyType))
+			}
+			c.tsw.WriteLiterally(")")
 			return nil // Handled make for map
 		}
 
@@ -151,12 +158,12 @@ func (c *GoToTSCompiler) WriteCallExprMake(exp *ast.CallExpr) error {
 				c.tsw.WriteLiterally(">(")
 
 				if len(exp.Args) >= 2 {
-					if err := c.WriteValueExpr(exp.Args[1]); err != nil { // Length
+					if err := c.writeNumberExpr(exp.Args[1]); err != nil { // Length
 						return err
 					}
 					if len(exp.Args) == 3 {
 						c.tsw.WriteLiterally(", ")
-						if err := c.WriteValueExpr(exp.Args[2]); err != nil { // Capacity
+						if err := c.writeNumberExpr(exp.Args[2]); err != nil { // Capacity
 							return err
 						}
 					} else if len(exp.Args) > 3 {
@@ -166,6 +173,7 @@ func (c *GoToTSCompiler) WriteCallExprMake(exp *ast.CallExpr) error {
 					// If no length is provided, default to 0
 					c.tsw.WriteLiterally("0")
 				}
+				c.writeSliceTypeHint(goUnderlyingType.Elem(), len(exp.Args) == 3)
 				c.tsw.WriteLiterally(")")
 				return nil // Handled make for []E where E is type parameter
 			}
@@ -226,11 +234,7 @@ func (c *GoToTSCompiler) WriteCallExprMake(exp *ast.CallExpr) error {
 
 						// Handle named types with map underlying types: make(NamedMapType)
 						if mapType, isMap := namedType.Underlying().(*types.Map); isMap {
-							c.tsw.WriteLiterally("$.makeMap<")
-							c.WriteGoType(mapType.Key(), GoTypeContextGeneral) // Write the key type
-							c.tsw.WriteLiterally(", ")
-							c.WriteGoType(mapType.Elem(), GoTypeContextGeneral) // Write the value type
-							c.tsw.WriteLiterally(">()")
+							c.writeMakeMap(mapType)
 							return nil // Handled make for named map type
 						}
 
@@ -257,11 +261,7 @@ func (c *GoToTSCompiler) WriteCallExprMake(exp *ast.CallExpr) error {
 
 			// Handle instantiated generic map types: make(GenericMap[K, V])
 			if mapType, isMap := underlying.(*types.Map); isMap {
-				c.tsw.WriteLiterally("$.makeMap<")
-				c.WriteGoType(mapType.Key(), GoTypeContextGeneral) // Write the key type
-				c.tsw.WriteLiterally(", ")
-				c.WriteGoType(mapType.Elem(), GoTypeContextGeneral) // Write the value type
-				c.tsw.WriteLiterally(">()")
+				c.writeMakeMap(mapType)
 				return nil // Handled make for instantiated generic map type
 			}
 
@@ -290,11 +290,7 @@ func (c *GoToTSCompiler) WriteCallExprMake(exp *ast.CallExpr) error {
 
 			// Handle selector expression map types: make(pkg.MapType)
 			if mapType, isMap := underlying.(*types.Map); isMap {
-				c.tsw.WriteLiterally("$.makeMap<")
-				c.WriteGoType(mapType.Key(), GoTypeContextGeneral) // Write the key type
-				c.tsw.WriteLiterally(", ")
-				c.WriteGoType(mapType.Elem(), GoTypeContextGeneral) // Write the value type
-				c.tsw.WriteLiterally(">()")
+				c.writeMakeMap(mapType)
 				return nil // Handled make for selector expression map type
 			}
 
@@ -30,16 +30,9 @@ func (c *GoToTSCompiler) writeNilConversion(exp *ast.CallExpr) (handled bool, er
 	if typ := c.pkg.TypesInfo.TypeOf(exp.Fun); typ != nil {
 		// For pointer types, create a typed nil that preserves type information
 		if ptrType, ok := typ.(*types.Pointer); ok {
-			// Use a qualifier that returns the package name for local types
+			// Use the package name for local types
 			// This matches Go's reflect output format (e.g., "main.Stringer")
-			qualifier := func(pkg *types.Package) string {
-				if pkg == nil {
-					return ""
-				}
-				return pkg.Name()
-			}
-			typeName := types.TypeString(ptrType, qualifier)
-			c.tsw.WriteLiterallyf("$.typedNil(%q)", typeName)
+			c.tsw.WriteLiterallyf("$.typedNil(%q)", goTypeName(ptrType))
 			return true, nil
 		}
 	}
@@ -34,13 +34,54 @@ import (
 //
 // Arguments are recursively translated using `WriteValueExpr`.
 func (c *GoToTSCompiler) WriteCallExpr(exp *ast.CallExpr) error {
+	c.tsw.MarkPos(exp.Pos())
+	// Handwritten packages return int64 and uint64 as number, convert them to bigint
+	if fn := c.handwrittenCallee(exp); fn != nil {
+		if closing := c.writeHandwrittenCallResultsOpen(fn); closing != "" {
+			if err := c.writeCallExpr(exp); err != nil {
+				return err
+			}
+			c.tsw.WriteLiterally(closing)
+			return nil
+		}
+	}
+	return c.writeCallExpr(exp)
+}
+
+// writeCallExpr writes a call expression, see WriteCallExpr.
+func (c *GoToTSCompiler) writeCallExpr(exp *ast.CallExpr) error {
 	expFun := exp.Fun
 
+	// Handle conversions between bigint and number represented integers
+	if handled, err := c.writeBigIntConversion(exp); handled {
+		return err
+	}
+
+	// Handle conversions to complex types
+	if handled, err := c.writeComplexConversion(exp); handled {
+		return err
+	}
+
+	// Handle truncating conversions to fixed-width integers in strict integer mode
+	if handled, err := c.writeStrictIntConversion(exp); handled {
+		return err
+	}
+
+	// Handle conversions of pointers which may be nil to interfaces
+	if handled, err := c.writeTypedNilConversion(exp); handled {
+		return err
+	}
+
 	// Handle protobuf method calls
 	if handled, err := c.writeProtobufMethodCall(exp); handled {
 		return err
 	}
 
+	// Handle calls of syscall/js methods accessing JavaScript values
+	if handled, err := c.writeSyscallJSCall(exp); handled {
+		return err
+	}
+
 	// Handle any type conversion with nil argument
 	if handled, err := c.writeNilConversion(exp); handled {
 		return err
@@ -51,6 +92,11 @@ func (c *GoToTSCompiler) WriteCallExpr(exp *ast.CallExpr) error {
 		return err
 	}
 
+	// Handle calls of generic functions taking type dictionaries
+	if handled, err := c.writeTypeDictCall(exp); handled {
+		return err
+	}
+
 	// Handle built-in functions called as identifiers
 	if funIdent, funIsIdent := expFun.(*ast.Ident); funIsIdent {
 		// Check for built-in functions first
@@ -91,6 +137,11 @@ func (c *GoToTSCompiler) WriteCallExpr(exp *ast.CallExpr) error {
 			return err
 		}
 
+		// Handle method calls on type parameters: v.Method() -> $.callMethod($T, v, "Method")
+		if handled, err := c.writeTypeDictMethodCall(exp); handled {
+			return err
+		}
+
 		// Handle wrapper type method calls: obj.Method() -> TypeName_Method(obj, ...)
 		if handled, err := c.writeWrapperTypeMethodCall(exp, selectorExpr); handled {
 			return err
@@ -151,6 +202,11 @@ func (c *GoToTSCompiler) WriteCallExpr(exp *ast.CallExpr) error {
 func (c *GoToTSCompiler) writeCallArguments(exp *ast.CallExpr) error {
 	c.tsw.WriteLiterally("(")
 
+	// Generic functions take the dictionaries of their type arguments first
+	if c.writeTypeDictArgs(exp.Fun) && len(exp.Args) != 0 {
+		c.tsw.WriteLiterally(", ")
+	}
+
 	// Get function signature for parameter type checking
 	var funcSig *types.Signature
 	if c.pkg != nil && c.pkg.TypesInfo != nil {
@@ -161,10 +217,25 @@ func (c *GoToTSCompiler) writeCallArguments(exp *ast.CallExpr) error {
 		}
 	}
 
+	// Handwritten packages take int64 and uint64 parameters as number
+	// and nil pointers as null
+	numberParams := false
+	handwritten := false
+	if fn := c.handwrittenCallee(exp); fn != nil {
+		numberParams = !c.acceptsBigIntParams(fn)
+		handwritten = true
+	}
+
 	for i, arg := range exp.Args {
 		if i != 0 {
 			c.tsw.WriteLiterally(", ")
 		}
+		if numberParams && funcSig != nil && i < funcSig.Params().Len() && c.isBigIntType(funcSig.Params().At(i).Type()) {
+			if err := c.writeNumberExpr(arg); err != nil {
+				return fmt.Errorf("failed to write argument: %w", err)
+			}
+			continue
+		}
 		// Check if this is the last argument and we have ellipsis (variadic call)
 		if exp.Ellipsis != token.NoPos && i == len(exp.Args)-1 {
 			c.tsw.WriteLiterally("...(")
@@ -177,6 +248,13 @@ func (c *GoToTSCompiler) writeCallArguments(exp *ast.CallExpr) error {
 			continue
 		}
 
+		if paramType := signatureParamType(funcSig, i); !handwritten && c.needsTypedNil(arg, paramType) {
+			if err := c.writeInterfaceValue(arg, paramType); err != nil {
+				return fmt.Errorf("failed to write argument: %w", err)
+			}
+			continue
+		}
+
 		if err := c.writeArgumentWithTypeHandling(arg, funcSig, i); err != nil {
 			return err
 		}
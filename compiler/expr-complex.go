@@ -0,0 +1,183 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// complex64 and complex128 values are represented by the immutable $.Complex
+// class of the runtime. Operators on them are lowered to runtime calls:
+//
+//	a + b   $.complexAdd(a, b)     a == b  $.complexEquals(a, b)
+//	a - b   $.complexSub(a, b)     a != b  !$.complexEquals(a, b)
+//	a * b   $.complexMul(a, b)     -a      $.complexNeg(a)
+//	a / b   $.complexDiv(a, b)     2i      $.complex(0, 2)
+//
+// Results of complex64 arithmetic are rounded to float32 with $.complex64.
+
+// isComplexType reports whether t is a typed or untyped complex type.
+func isComplexType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsComplex != 0
+}
+
+// isComplexExpr reports whether expr has a complex type.
+func (c *GoToTSCompiler) isComplexExpr(expr ast.Expr) bool {
+	return isComplexType(c.pkg.TypesInfo.TypeOf(expr))
+}
+
+// isComplex64Type reports whether t is complex64.
+func isComplex64Type(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Complex64
+}
+
+// writeComplexConstantExpr writes expr as a $.complex value if it is a
+// constant expression of a complex type. It returns false if expr was not written.
+func (c *GoToTSCompiler) writeComplexConstantExpr(expr ast.Expr) bool {
+	tv, ok := c.pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || !isComplexType(tv.Type) {
+		return false
+	}
+	c.writeComplexConstant(tv.Value, tv.Type)
+	return true
+}
+
+// writeComplexConstant writes a numeric constant value as a $.complex value of type t.
+// The parts of complex64 constants are rounded to float32.
+func (c *GoToTSCompiler) writeComplexConstant(val constant.Value, t types.Type) {
+	re, _ := constant.Float64Val(constant.Real(val))
+	im, _ := constant.Float64Val(constant.Imag(val))
+	if isComplex64Type(t) {
+		re, im = float64(float32(re)), float64(float32(im))
+	}
+	c.tsw.WriteLiterallyf(
+		"$.complex(%s, %s)",
+		strconv.FormatFloat(re, 'g', -1, 64),
+		strconv.FormatFloat(im, 'g', -1, 64),
+	)
+}
+
+// complexBinaryOps maps binary operators on complex values to runtime functions.
+var complexBinaryOps = map[token.Token]string{
+	token.ADD: "$.complexAdd",
+	token.SUB: "$.complexSub",
+	token.MUL: "$.complexMul",
+	token.QUO: "$.complexDiv",
+	token.EQL: "$.complexEquals",
+	token.NEQ: "!$.complexEquals",
+}
+
+// writeComplexBinaryOp writes x op y on complex values as a runtime call.
+// It returns false if the operands are not complex.
+func (c *GoToTSCompiler) writeComplexBinaryOp(x ast.Expr, op token.Token, y ast.Expr) (bool, error) {
+	xType := c.pkg.TypesInfo.TypeOf(x)
+	fn, ok := complexBinaryOps[op]
+	if !ok || !isComplexType(xType) {
+		return false, nil
+	}
+	round := isComplex64Type(xType) && op != token.EQL && op != token.NEQ
+	if round {
+		c.tsw.WriteLiterally("$.complex64(")
+	}
+	c.tsw.WriteLiterallyf("%s(", fn)
+	if err := c.WriteValueExpr(x); err != nil {
+		return true, fmt.Errorf("failed to write complex binary expression left operand: %w", err)
+	}
+	c.tsw.WriteLiterally(", ")
+	if err := c.WriteValueExpr(y); err != nil {
+		return true, fmt.Errorf("failed to write complex binary expression right operand: %w", err)
+	}
+	c.tsw.WriteLiterally(")")
+	if round {
+		c.tsw.WriteLiterally(")")
+	}
+	return true, nil
+}
+
+// writeComplexUnaryExpr writes -x or +x on a complex value.
+// It returns false if the operand is not complex.
+func (c *GoToTSCompiler) writeComplexUnaryExpr(exp *ast.UnaryExpr) (bool, error) {
+	if (exp.Op != token.SUB && exp.Op != token.ADD) || !c.isComplexExpr(exp.X) {
+		return false, nil
+	}
+	if exp.Op == token.ADD {
+		return true, c.WriteValueExpr(exp.X)
+	}
+	c.tsw.WriteLiterally("$.complexNeg(")
+	if err := c.WriteValueExpr(exp.X); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(")")
+	return true, nil
+}
+
+// writeComplexIncDec writes x++ or x-- on a complex value as an assignment.
+// It returns false if x is not complex.
+func (c *GoToTSCompiler) writeComplexIncDec(stmt *ast.IncDecStmt) (bool, error) {
+	if !c.isComplexExpr(stmt.X) {
+		return false, nil
+	}
+	if err := c.WriteValueExpr(stmt.X); err != nil {
+		return true, err
+	}
+	if stmt.Tok == token.INC {
+		c.tsw.WriteLiterally(" = $.complexAdd(")
+	} else {
+		c.tsw.WriteLiterally(" = $.complexSub(")
+	}
+	if err := c.WriteValueExpr(stmt.X); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(", $.complex(1, 0))")
+	return true, nil
+}
+
+// writeComplexCompoundAssign writes `lhs op= rhs` on a complex value as
+// `lhs = <lhs op rhs>`. It returns false if the assignment was not written.
+func (c *GoToTSCompiler) writeComplexCompoundAssign(lhs, rhs ast.Expr, tok token.Token) (bool, error) {
+	op, ok := compoundAssignOps[tok]
+	if !ok || !c.isComplexExpr(lhs) {
+		return false, nil
+	}
+	// Map elements are not assignable in TypeScript, leave them to $.mapSet
+	if indexExpr, isIndex := lhs.(*ast.IndexExpr); isIndex {
+		if _, isMap := c.pkg.TypesInfo.TypeOf(indexExpr.X).Underlying().(*types.Map); isMap {
+			return false, nil
+		}
+	}
+	if err := c.WriteValueExpr(lhs); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(" = ")
+	return c.writeComplexBinaryOp(lhs, op, rhs)
+}
+
+// writeComplexConversion writes a non-constant conversion to a complex type.
+// It returns false if exp is not a conversion to a complex type.
+func (c *GoToTSCompiler) writeComplexConversion(exp *ast.CallExpr) (bool, error) {
+	if len(exp.Args) != 1 {
+		return false, nil
+	}
+	tv, ok := c.pkg.TypesInfo.Types[exp.Fun]
+	if !ok || !tv.IsType() || !isComplexType(tv.Type) {
+		return false, nil
+	}
+	arg := exp.Args[0]
+	if !isComplex64Type(tv.Type) || isComplex64Type(c.pkg.TypesInfo.TypeOf(arg)) {
+		return true, c.WriteValueExpr(arg)
+	}
+	c.tsw.WriteLiterally("$.complex64(")
+	if err := c.WriteValueExpr(arg); err != nil {
+		return true, fmt.Errorf("failed to write argument for complex64 conversion: %w", err)
+	}
+	c.tsw.WriteLiterally(")")
+	return true, nil
+}
@@ -131,7 +131,13 @@ func (c *GoToTSCompiler) writeTypeDescription(typeExpr ast.Expr) {
 		}
 
 		if isPrimitiveType(t.Name) {
-			if tsType, ok := GoBuiltinToTypescript(t.Name); ok {
+			if c.isBigIntExpr(t) || c.isComplexExpr(t) {
+				// Use the Go name to tell bigint and complex represented types apart from number
+				c.tsw.WriteLiterally("{")
+				c.tsw.WriteLiterally("kind: $.TypeKind.Basic, ")
+				c.tsw.WriteLiterallyf("name: '%s'", t.Name)
+				c.tsw.WriteLiterally("}")
+			} else if tsType, ok := GoBuiltinToTypescript(t.Name); ok {
 				c.tsw.WriteLiterally("{")
 				c.tsw.WriteLiterally("kind: $.TypeKind.Basic, ")
 				c.tsw.WriteLiterallyf("name: '%s'", tsType)
@@ -0,0 +1,266 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// In Go an interface holding a nil pointer is not nil: it keeps the dynamic
+// type, so `a != nil` is true, type assertions to the pointer type succeed and
+// methods are called with a nil receiver. Interfaces hold bare values in
+// TypeScript, so a pointer which may be nil is wrapped with $.typedNilOf where
+// it is converted to an interface. A nil pointer then becomes the $.typedNil of
+// its type, an object which is not null and dispatches methods to the type:
+//
+//	var a Animal = d   let a: Animal = $.typedNilOf(d, "*main.Dog")
+//	return d           return $.typedNilOf(d, "*main.Dog")
+//
+// The conversion is written for assignments, variable declarations, returns,
+// explicit conversions, arguments of compiled functions, composite literal
+// elements, channel sends and appended elements. Pointers which are known not
+// to be nil, like &T{} and new(T), are not wrapped.
+
+// goTypeName returns the name of t as printed by Go's reflect and %T, e.g. *main.Dog.
+func goTypeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == nil {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// isNonNilPointerExpr reports whether expr is a pointer which is never nil.
+func isNonNilPointerExpr(info *types.Info, expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		return e.Op == token.AND
+	case *ast.CallExpr:
+		if ident, ok := ast.Unparen(e.Fun).(*ast.Ident); ok {
+			if builtin, isBuiltin := info.Uses[ident].(*types.Builtin); isBuiltin {
+				return builtin.Name() == "new"
+			}
+		}
+	}
+	return false
+}
+
+// analyzeNonNilPointers finds the local pointer variables of the package
+// which are only assigned pointers that are never nil, and whose address is
+// not taken, see Analysis.NonNilPointerVars.
+func (a *Analysis) analyzeNonNilPointers(pkg *packages.Package) {
+	info := pkg.TypesInfo
+	nonNil := make(map[types.Object]bool)
+	maybeNil := make(map[types.Object]bool)
+
+	// localPointerVar returns the local pointer variable denoted by expr, if any.
+	localPointerVar := func(expr ast.Expr) types.Object {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		v, ok := info.ObjectOf(ident).(*types.Var)
+		if !ok || v.IsField() || v.Parent() == nil || v.Parent() == pkg.Types.Scope() {
+			return nil
+		}
+		if _, isPtr := v.Type().Underlying().(*types.Pointer); !isPtr {
+			return nil
+		}
+		return v
+	}
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			obj := localPointerVar(l)
+			if obj == nil {
+				continue
+			}
+			if len(lhs) == len(rhs) && isNonNilPointerExpr(info, rhs[i]) {
+				nonNil[obj] = true
+			} else {
+				maybeNil[obj] = true
+			}
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				assign(n.Lhs, n.Rhs)
+			case *ast.ValueSpec:
+				names := make([]ast.Expr, len(n.Names))
+				for i, name := range n.Names {
+					names[i] = name
+				}
+				assign(names, n.Values)
+			case *ast.RangeStmt:
+				assign([]ast.Expr{n.Key, n.Value}, nil)
+			case *ast.FuncDecl:
+				// Receivers may be nil
+				if n.Recv != nil {
+					for _, field := range n.Recv.List {
+						for _, name := range field.Names {
+							assign([]ast.Expr{name}, nil)
+						}
+					}
+				}
+			case *ast.FuncType:
+				// Parameters may be nil and named results start out nil
+				for _, list := range []*ast.FieldList{n.Params, n.Results} {
+					if list == nil {
+						continue
+					}
+					for _, field := range list.List {
+						for _, name := range field.Names {
+							assign([]ast.Expr{name}, nil)
+						}
+					}
+				}
+			case *ast.UnaryExpr:
+				// The variable may be set to nil through its address
+				if obj := localPointerVar(n.X); n.Op == token.AND && obj != nil {
+					maybeNil[obj] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for obj := range nonNil {
+		if !maybeNil[obj] {
+			a.NonNilPointerVars[obj] = true
+		}
+	}
+}
+
+// mayBeNilPointer reports whether expr is a pointer which may be nil, see
+// Analysis.mayBeNilPointer.
+func (c *GoToTSCompiler) mayBeNilPointer(expr ast.Expr) bool {
+	return c.analysis.mayBeNilPointer(c.pkg.TypesInfo, expr)
+}
+
+// mayBeNilPointer reports whether expr is a pointer which may be nil.
+func (a *Analysis) mayBeNilPointer(info *types.Info, expr ast.Expr) bool {
+	t := info.TypeOf(expr)
+	if t == nil {
+		return false
+	}
+	if _, isPtr := t.Underlying().(*types.Pointer); !isPtr {
+		return false
+	}
+	// &x and new(T) are never nil
+	if isNonNilPointerExpr(info, expr) {
+		return false
+	}
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		// (*T)(nil) is already written as a typed nil
+		if tv, ok := info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			if ident, ok := e.Args[0].(*ast.Ident); ok && ident.Name == "nil" {
+				return false
+			}
+		}
+	case *ast.Ident:
+		// Local variables only ever assigned non-nil pointers are not nil
+		if obj := info.Uses[e]; obj != nil && a.NonNilPointerVars[obj] {
+			return false
+		}
+	}
+	return true
+}
+
+// needsTypedNil reports whether expr is converted to the interface type target
+// and is a pointer which may be nil.
+func (c *GoToTSCompiler) needsTypedNil(expr ast.Expr, target types.Type) bool {
+	if target == nil {
+		return false
+	}
+	if _, isTypeParam := target.(*types.TypeParam); isTypeParam {
+		return false
+	}
+	if !types.IsInterface(target) {
+		return false
+	}
+	return c.mayBeNilPointer(expr)
+}
+
+// writeInterfaceValue writes expr converted to the interface type target,
+// wrapping pointers which may be nil with $.typedNilOf.
+func (c *GoToTSCompiler) writeInterfaceValue(expr ast.Expr, target types.Type) error {
+	if !c.needsTypedNil(expr, target) {
+		return c.WriteValueExpr(expr)
+	}
+	c.tsw.WriteLiterally("$.typedNilOf(")
+	if err := c.WriteValueExpr(expr); err != nil {
+		return fmt.Errorf("failed to write value converted to interface: %w", err)
+	}
+	c.tsw.WriteLiterallyf(", %q)", goTypeName(c.pkg.TypesInfo.TypeOf(expr)))
+	return nil
+}
+
+// writeTypedNilConversion writes a conversion of a pointer which may be nil
+// to an interface type. It returns false if exp is not such a conversion.
+func (c *GoToTSCompiler) writeTypedNilConversion(exp *ast.CallExpr) (bool, error) {
+	if len(exp.Args) != 1 {
+		return false, nil
+	}
+	tv, ok := c.pkg.TypesInfo.Types[exp.Fun]
+	if !ok || !tv.IsType() || !c.needsTypedNil(exp.Args[0], tv.Type) {
+		return false, nil
+	}
+	return true, c.writeInterfaceValue(exp.Args[0], tv.Type)
+}
+
+// chanElemType returns the element type of the channel ch, or nil if it is not known.
+func (c *GoToTSCompiler) chanElemType(ch ast.Expr) types.Type {
+	if chanType, ok := typeUnderlying(c.pkg.TypesInfo.TypeOf(ch)).(*types.Chan); ok {
+		return chanType.Elem()
+	}
+	return nil
+}
+
+// returnResultType returns the type of the i-th result of the function
+// enclosing the return statement ret, or nil if it is not known.
+func (c *GoToTSCompiler) returnResultType(ret *ast.ReturnStmt, i int) types.Type {
+	nodeInfo := c.analysis.NodeData[ret]
+	if nodeInfo == nil {
+		return nil
+	}
+	var sig *types.Signature
+	switch {
+	case nodeInfo.EnclosingFuncDecl != nil:
+		if obj := c.pkg.TypesInfo.ObjectOf(nodeInfo.EnclosingFuncDecl.Name); obj != nil {
+			sig, _ = obj.Type().(*types.Signature)
+		}
+	case nodeInfo.EnclosingFuncLit != nil:
+		sig, _ = c.pkg.TypesInfo.TypeOf(nodeInfo.EnclosingFuncLit).(*types.Signature)
+	}
+	if sig == nil || sig.Results().Len() != len(ret.Results) || i >= sig.Results().Len() {
+		return nil
+	}
+	return sig.Results().At(i).Type()
+}
+
+// signatureParamType returns the type of the i-th argument of a call to a
+// function with signature sig, or nil if it is not known. Arguments passed to
+// a variadic parameter have the element type of the parameter.
+func signatureParamType(sig *types.Signature, i int) types.Type {
+	if sig == nil {
+		return nil
+	}
+	params := sig.Params()
+	if sig.Variadic() && i >= params.Len()-1 {
+		if slice, ok := params.At(params.Len() - 1).Type().Underlying().(*types.Slice); ok {
+			return slice.Elem()
+		}
+		return nil
+	}
+	if i >= params.Len() {
+		return nil
+	}
+	return params.At(i).Type()
+}
@@ -26,6 +26,20 @@ import (
 // - Function literals (`ast.FuncLit`): Delegates to `WriteFuncLitValue`.
 // Unhandled value expressions result in a comment.
 func (c *GoToTSCompiler) WriteValueExpr(a ast.Expr) error {
+	// Constant expressions of a bigint type are written as exact bigint literals
+	if c.writeBigIntConstantExpr(a) {
+		return nil
+	}
+	// Constant expressions of a complex type are written as $.complex values
+	if c.writeComplexConstantExpr(a) {
+		return nil
+	}
+
+	// Generic functions used as values get their type dictionaries bound
+	if handled, err := c.writeTypeDictFuncValue(a); handled {
+		return err
+	}
+
 	switch exp := a.(type) {
 	case *ast.Ident:
 		c.WriteIdent(exp, true) // adds .value accessor
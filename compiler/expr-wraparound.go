@@ -0,0 +1,371 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// When Config.StrictIntegers is set, arithmetic on the fixed-width integer
+// types int8, int16, int32, uint8, uint16 and uint32 wraps around like in Go.
+// The result of each operation which may leave the range of its type is
+// truncated inline:
+//
+//	int32   ((a + b) | 0)          uint32  ((a + b) >>> 0)
+//	int16   ((a + b) << 16 >> 16)  uint16  ((a + b) & 0xffff)
+//	int8    ((a + b) << 24 >> 24)  uint8   ((a + b) & 0xff)
+//
+// 32-bit multiplication uses Math.imul as the float product may lose precision.
+// Shifts by a non-constant count and integer division and modulo of all
+// integer types go through runtime helpers, which implement Go's semantics for
+// large shift counts and panic on division by zero.
+
+// isStrictIntegers reports whether fixed-width integer wraparound is enabled.
+func (c *GoToTSCompiler) isStrictIntegers() bool {
+	return c.config != nil && c.config.StrictIntegers
+}
+
+// fixedIntWidth returns the bit width and signedness of t if it is a
+// fixed-width integer type whose arithmetic wraps in strict integer mode.
+func (c *GoToTSCompiler) fixedIntWidth(t types.Type) (bits int, unsigned bool, ok bool) {
+	if t == nil || !c.isStrictIntegers() {
+		return 0, false, false
+	}
+	basic, isBasic := t.Underlying().(*types.Basic)
+	if !isBasic {
+		return 0, false, false
+	}
+	switch basic.Kind() {
+	case types.Int8:
+		return 8, false, true
+	case types.Int16:
+		return 16, false, true
+	case types.Int32:
+		return 32, false, true
+	case types.Uint8:
+		return 8, true, true
+	case types.Uint16:
+		return 16, true, true
+	case types.Uint32:
+		return 32, true, true
+	}
+	return 0, false, false
+}
+
+// intWrapSuffix returns the code closing `((` which truncates the value to the
+// given fixed-width integer type.
+func intWrapSuffix(bits int, unsigned bool) string {
+	switch {
+	case bits == 32 && unsigned:
+		return ") >>> 0)"
+	case bits == 32:
+		return ") | 0)"
+	case unsigned:
+		return fmt.Sprintf(") & 0x%x)", uint32(1)<<bits-1)
+	default:
+		return fmt.Sprintf(") << %d >> %d)", 32-bits, 32-bits)
+	}
+}
+
+// isIntegerType reports whether t is a typed or untyped integer type.
+func isIntegerType(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// constantShiftCount returns the value of a constant shift count.
+func (c *GoToTSCompiler) constantShiftCount(expr ast.Expr) (int64, bool) {
+	tv, ok := c.pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return 0, false
+	}
+	return constant.Int64Val(constant.ToInt(tv.Value))
+}
+
+// needsStrictIntOp reports whether the binary operation op on operands of type
+// xType needs Go's integer semantics in strict integer mode.
+func (c *GoToTSCompiler) needsStrictIntOp(xType types.Type, op token.Token) bool {
+	if !c.isStrictIntegers() || xType == nil {
+		return false
+	}
+	if op == token.QUO || op == token.REM {
+		return isIntegerType(xType) && !c.isBigIntType(xType)
+	}
+	bits, unsigned, fixed := c.fixedIntWidth(xType)
+	if !fixed {
+		return false
+	}
+	switch op {
+	case token.ADD, token.SUB, token.MUL, token.SHL, token.SHR:
+		return true
+	case token.AND, token.OR, token.XOR, token.AND_NOT:
+		// Bitwise operators produce int32 values in JavaScript
+		return bits == 32 && unsigned
+	}
+	return false
+}
+
+// writeStrictIntBinaryOp writes x op y with Go's fixed-width integer semantics.
+// It returns false if the operation does not need special handling.
+func (c *GoToTSCompiler) writeStrictIntBinaryOp(x ast.Expr, op token.Token, y ast.Expr) (bool, error) {
+	xType := c.pkg.TypesInfo.TypeOf(x)
+	if !c.needsStrictIntOp(xType, op) {
+		return false, nil
+	}
+	bits, unsigned, fixed := c.fixedIntWidth(xType)
+
+	writeCall := func(fn string) error {
+		c.tsw.WriteLiterallyf("%s(", fn)
+		if err := c.WriteValueExpr(x); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterally(", ")
+		if err := c.WriteValueExpr(y); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterally(")")
+		return nil
+	}
+	writeWrapped := func(write func() error) error {
+		c.tsw.WriteLiterally("((")
+		if err := write(); err != nil {
+			return err
+		}
+		c.tsw.WriteLiterally(intWrapSuffix(bits, unsigned))
+		return nil
+	}
+	writeInfix := func(tok string) func() error {
+		return func() error {
+			if err := c.WriteValueExpr(x); err != nil {
+				return err
+			}
+			c.tsw.WriteLiterallyf(" %s ", tok)
+			return c.WriteValueExpr(y)
+		}
+	}
+
+	switch op {
+	case token.QUO, token.REM:
+		// Integer division and modulo of all integer types panic on a zero divisor
+		fn := "$.idiv"
+		if op == token.REM {
+			fn = "$.imod"
+		}
+		if !fixed || unsigned || op == token.REM {
+			return true, writeCall(fn)
+		}
+		// The minimum value divided by -1 overflows
+		return true, writeWrapped(func() error { return writeCall(fn) })
+	}
+
+	switch op {
+	case token.ADD, token.SUB:
+		return true, writeWrapped(writeInfix(tokenMap[op]))
+	case token.MUL:
+		if bits == 32 {
+			if !unsigned {
+				return true, writeCall("Math.imul")
+			}
+			return true, writeWrapped(func() error { return writeCall("Math.imul") })
+		}
+		return true, writeWrapped(writeInfix("*"))
+	case token.SHL:
+		count, isConst := c.constantShiftCount(y)
+		if !isConst {
+			if unsigned {
+				c.tsw.WriteLiterally("$.shlUint(")
+			} else {
+				c.tsw.WriteLiterally("$.shlInt(")
+			}
+			if err := c.WriteValueExpr(x); err != nil {
+				return true, err
+			}
+			c.tsw.WriteLiterally(", ")
+			if err := c.writeNumberExpr(y); err != nil {
+				return true, err
+			}
+			c.tsw.WriteLiterallyf(", %d)", bits)
+			return true, nil
+		}
+		if count >= int64(bits) {
+			c.tsw.WriteLiterally("0")
+			return true, nil
+		}
+		return true, writeWrapped(writeInfix("<<"))
+	case token.SHR:
+		count, isConst := c.constantShiftCount(y)
+		if !isConst {
+			if unsigned {
+				c.tsw.WriteLiterally("$.shrUint(")
+			} else {
+				c.tsw.WriteLiterally("$.shrInt(")
+			}
+			if err := c.WriteValueExpr(x); err != nil {
+				return true, err
+			}
+			c.tsw.WriteLiterally(", ")
+			if err := c.writeNumberExpr(y); err != nil {
+				return true, err
+			}
+			c.tsw.WriteLiterally(")")
+			return true, nil
+		}
+		if count >= int64(bits) {
+			if unsigned {
+				c.tsw.WriteLiterally("0")
+				return true, nil
+			}
+			count = 31
+		}
+		shr := ">>"
+		if unsigned {
+			shr = ">>>"
+		}
+		c.tsw.WriteLiterally("(")
+		if err := c.WriteValueExpr(x); err != nil {
+			return true, err
+		}
+		c.tsw.WriteLiterallyf(" %s %d)", shr, count)
+		return true, nil
+	case token.AND, token.OR, token.XOR, token.AND_NOT:
+		if op == token.AND_NOT {
+			return true, writeWrapped(func() error {
+				if err := c.WriteValueExpr(x); err != nil {
+					return err
+				}
+				c.tsw.WriteLiterally(" & ~(")
+				if err := c.WriteValueExpr(y); err != nil {
+					return err
+				}
+				c.tsw.WriteLiterally(")")
+				return nil
+			})
+		}
+		return true, writeWrapped(writeInfix(tokenMap[op]))
+	}
+	return false, nil
+}
+
+// writeStrictIntUnaryExpr writes -x or ^x with Go's fixed-width integer semantics.
+// It returns false if the operation does not need special handling.
+func (c *GoToTSCompiler) writeStrictIntUnaryExpr(exp *ast.UnaryExpr) (bool, error) {
+	bits, unsigned, fixed := c.fixedIntWidth(c.pkg.TypesInfo.TypeOf(exp.X))
+	if !fixed || (exp.Op != token.SUB && exp.Op != token.XOR) {
+		return false, nil
+	}
+	// Constants are folded by the Go type checker, as ^uint32(0) is not ~0
+	if tv := c.pkg.TypesInfo.Types[exp]; tv.Value != nil {
+		c.tsw.WriteLiterally(tv.Value.ExactString())
+		return true, nil
+	}
+	// The complement of a signed value stays in range
+	if exp.Op == token.XOR && !unsigned {
+		return false, nil
+	}
+	if exp.Op == token.SUB {
+		c.tsw.WriteLiterally("((-(")
+	} else {
+		c.tsw.WriteLiterally("((~(")
+	}
+	if err := c.WriteValueExpr(exp.X); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(")" + intWrapSuffix(bits, unsigned))
+	return true, nil
+}
+
+// writeStrictIntIncDec writes x++ or x-- on a fixed-width integer as a
+// wrapping assignment. It returns false if x is not a fixed-width integer.
+func (c *GoToTSCompiler) writeStrictIntIncDec(stmt *ast.IncDecStmt) (bool, error) {
+	bits, unsigned, fixed := c.fixedIntWidth(c.pkg.TypesInfo.TypeOf(stmt.X))
+	if !fixed {
+		return false, nil
+	}
+	// Map elements are not assignable in TypeScript
+	if indexExpr, isIndex := stmt.X.(*ast.IndexExpr); isIndex {
+		if _, isMap := c.pkg.TypesInfo.TypeOf(indexExpr.X).Underlying().(*types.Map); isMap {
+			return false, nil
+		}
+	}
+	if err := c.WriteValueExpr(stmt.X); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(" = ((")
+	if err := c.WriteValueExpr(stmt.X); err != nil {
+		return true, err
+	}
+	if stmt.Tok == token.INC {
+		c.tsw.WriteLiterally(" + 1")
+	} else {
+		c.tsw.WriteLiterally(" - 1")
+	}
+	c.tsw.WriteLiterally(intWrapSuffix(bits, unsigned))
+	return true, nil
+}
+
+// writeStrictIntCompoundAssign writes `lhs op= rhs` as `lhs = <lhs op rhs>`
+// if the operation needs Go's fixed-width integer semantics.
+// It returns false if the assignment was not written.
+func (c *GoToTSCompiler) writeStrictIntCompoundAssign(lhs, rhs ast.Expr, tok token.Token) (bool, error) {
+	op, ok := compoundAssignOps[tok]
+	if !ok {
+		return false, nil
+	}
+	// Map elements are not assignable in TypeScript, leave them to $.mapSet
+	if indexExpr, isIndex := lhs.(*ast.IndexExpr); isIndex {
+		if _, isMap := c.pkg.TypesInfo.TypeOf(indexExpr.X).Underlying().(*types.Map); isMap {
+			return false, nil
+		}
+	}
+
+	if !c.needsStrictIntOp(c.pkg.TypesInfo.TypeOf(lhs), op) {
+		return false, nil
+	}
+
+	if err := c.WriteValueExpr(lhs); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(" = ")
+	_, err := c.writeStrictIntBinaryOp(lhs, op, rhs)
+	return true, err
+}
+
+// writeStrictIntConversion writes a conversion to a fixed-width integer type
+// which truncates the value like Go. It returns false if no truncation is needed.
+func (c *GoToTSCompiler) writeStrictIntConversion(exp *ast.CallExpr) (bool, error) {
+	if len(exp.Args) != 1 || !c.isStrictIntegers() {
+		return false, nil
+	}
+	tv, ok := c.pkg.TypesInfo.Types[exp.Fun]
+	if !ok || !tv.IsType() {
+		return false, nil
+	}
+	// Constant conversions are checked by the Go compiler
+	if c.pkg.TypesInfo.Types[exp].Value != nil {
+		return false, nil
+	}
+	bits, unsigned, fixed := c.fixedIntWidth(tv.Type)
+	if !fixed {
+		return false, nil
+	}
+	arg := exp.Args[0]
+	argType := c.pkg.TypesInfo.TypeOf(arg)
+	argBasic, isBasic := argType.Underlying().(*types.Basic)
+	if !isBasic || argBasic.Info()&types.IsNumeric == 0 || argBasic.Info()&types.IsComplex != 0 {
+		return false, nil
+	}
+	// Widening conversions keep the value
+	if argBits, argUnsigned, argFixed := c.fixedIntWidth(argType); argFixed {
+		if (argUnsigned == unsigned && argBits <= bits) || (argUnsigned && !unsigned && argBits < bits) {
+			return false, nil
+		}
+	}
+	c.tsw.WriteLiterally("((")
+	if err := c.writeNumberExpr(arg); err != nil {
+		return true, fmt.Errorf("failed to write argument for integer conversion: %w", err)
+	}
+	c.tsw.WriteLiterally(intWrapSuffix(bits, unsigned))
+	return true, nil
+}
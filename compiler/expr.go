@@ -63,7 +63,7 @@ func (c *GoToTSCompiler) WriteIndexExpr(exp *ast.IndexExpr) error {
 				return err
 			}
 			c.tsw.WriteLiterally(", ")
-			if err := c.WriteValueExpr(exp.Index); err != nil {
+			if err := c.writeNumberExpr(exp.Index); err != nil {
 				return err
 			}
 			c.tsw.WriteLiterally(")")
@@ -110,7 +110,7 @@ func (c *GoToTSCompiler) WriteIndexExpr(exp *ast.IndexExpr) error {
 							return err
 						}
 						c.tsw.WriteLiterally(", ")
-						if err := c.WriteValueExpr(exp.Index); err != nil {
+						if err := c.writeNumberExpr(exp.Index); err != nil {
 							return err
 						}
 						c.tsw.WriteLiterally(")")
@@ -124,7 +124,7 @@ func (c *GoToTSCompiler) WriteIndexExpr(exp *ast.IndexExpr) error {
 							return err
 						}
 						c.tsw.WriteLiterally("![") // non-null assertion
-						if err := c.WriteValueExpr(exp.Index); err != nil {
+						if err := c.writeNumberExpr(exp.Index); err != nil {
 							return err
 						}
 						c.tsw.WriteLiterally("]")
@@ -140,7 +140,7 @@ func (c *GoToTSCompiler) WriteIndexExpr(exp *ast.IndexExpr) error {
 				return err
 			}
 			c.tsw.WriteLiterally(", ")
-			if err := c.WriteValueExpr(exp.Index); err != nil {
+			if err := c.writeNumberExpr(exp.Index); err != nil {
 				return err
 			}
 			c.tsw.WriteLiterally(")")
@@ -153,7 +153,7 @@ func (c *GoToTSCompiler) WriteIndexExpr(exp *ast.IndexExpr) error {
 		return err
 	}
 	c.tsw.WriteLiterally("![") // non-null assertion
-	if err := c.WriteValueExpr(exp.Index); err != nil {
+	if err := c.writeNumberExpr(exp.Index); err != nil {
 		return err
 	}
 	c.tsw.WriteLiterally("]")
@@ -277,13 +277,18 @@ func (c *GoToTSCompiler) WriteBinaryExpr(exp *ast.BinaryExpr) error {
 			return fmt.Errorf("failed to write channel send target: %w", err)
 		}
 		c.tsw.WriteLiterally(", ")
-		if err := c.WriteValueExpr(exp.Y); err != nil {
+		if err := c.writeInterfaceValue(exp.Y, c.chanElemType(exp.X)); err != nil {
 			return fmt.Errorf("failed to write channel send value: %w", err)
 		}
 		c.tsw.WriteLiterally(")")
 		return nil
 	}
 
+	// Comparisons of type parameter values use the dictionary of the type argument
+	if handled, err := c.writeTypeDictEquality(exp); handled {
+		return err
+	}
+
 	// Check if this is a nil comparison for a pointer
 	isNilComparison := false
 	var ptrExpr ast.Expr
@@ -412,6 +417,23 @@ func (c *GoToTSCompiler) WriteBinaryExpr(exp *ast.BinaryExpr) error {
 		return nil
 	}
 
+	// Operations on complex values are runtime calls
+	if handled, err := c.writeComplexBinaryOp(exp.X, exp.Op, exp.Y); handled {
+		return err
+	}
+
+	// Operations on int64 and uint64 in bigint mode need 64-bit wraparound
+	if c.isBigIntBinaryOp(exp.X, exp.Op, exp.Y) {
+		return c.writeBigIntBinaryOp(exp.X, exp.Op, exp.Y)
+	}
+
+	// Non-constant integer operations wrap around in strict integer mode
+	if c.pkg.TypesInfo.Types[exp].Value == nil {
+		if handled, err := c.writeStrictIntBinaryOp(exp.X, exp.Op, exp.Y); handled {
+			return err
+		}
+	}
+
 	// Check if the operator is a bitwise operator
 	isBitwise := false
 	switch exp.Op {
@@ -592,6 +614,16 @@ func (c *GoToTSCompiler) WriteUnaryExpr(exp *ast.UnaryExpr) error {
 		return nil
 	}
 
+	if handled, err := c.writeComplexUnaryExpr(exp); handled {
+		return err
+	}
+	if (exp.Op == token.ADD || exp.Op == token.SUB || exp.Op == token.XOR) && c.isBigIntExpr(exp.X) {
+		return c.writeBigIntUnaryExpr(exp)
+	}
+	if handled, err := c.writeStrictIntUnaryExpr(exp); handled {
+		return err
+	}
+
 	// Handle other unary operators (+, -, !, ^)
 	tokStr, ok := TokenToTs(exp.Op)
 	if !ok {
@@ -661,7 +693,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		}
 		c.tsw.WriteLiterally(", ")
 		if exp.Low != nil {
-			if err := c.WriteValueExpr(exp.Low); err != nil {
+			if err := c.writeNumberExpr(exp.Low); err != nil {
 				return err
 			}
 		} else {
@@ -669,7 +701,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		}
 		c.tsw.WriteLiterally(", ")
 		if exp.High != nil {
-			if err := c.WriteValueExpr(exp.High); err != nil {
+			if err := c.writeNumberExpr(exp.High); err != nil {
 				return err
 			}
 		} else {
@@ -678,7 +710,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		if exp.Slice3 {
 			c.tsw.WriteLiterally(", ")
 			if exp.Max != nil {
-				if err := c.WriteValueExpr(exp.Max); err != nil {
+				if err := c.writeNumberExpr(exp.Max); err != nil {
 					return err
 				}
 			} else {
@@ -697,7 +729,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		}
 		c.tsw.WriteLiterally(", ")
 		if exp.Low != nil {
-			if err := c.WriteValueExpr(exp.Low); err != nil {
+			if err := c.writeNumberExpr(exp.Low); err != nil {
 				return err
 			}
 		} else {
@@ -707,7 +739,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		}
 		c.tsw.WriteLiterally(", ")
 		if exp.High != nil {
-			if err := c.WriteValueExpr(exp.High); err != nil {
+			if err := c.writeNumberExpr(exp.High); err != nil {
 				return err
 			}
 		} else {
@@ -725,7 +757,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		}
 		c.tsw.WriteLiterally(", ")
 		if exp.Low != nil {
-			if err := c.WriteValueExpr(exp.Low); err != nil {
+			if err := c.writeNumberExpr(exp.Low); err != nil {
 				return err
 			}
 		} else {
@@ -733,7 +765,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		}
 		c.tsw.WriteLiterally(", ")
 		if exp.High != nil {
-			if err := c.WriteValueExpr(exp.High); err != nil {
+			if err := c.writeNumberExpr(exp.High); err != nil {
 				return err
 			}
 		} else {
@@ -742,7 +774,7 @@ func (c *GoToTSCompiler) WriteSliceExpr(exp *ast.SliceExpr) error {
 		if exp.Slice3 {
 			c.tsw.WriteLiterally(", ")
 			if exp.Max != nil {
-				if err := c.WriteValueExpr(exp.Max); err != nil {
+				if err := c.writeNumberExpr(exp.Max); err != nil {
 					return err
 				}
 			} else {
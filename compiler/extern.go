@@ -0,0 +1,269 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Functions with a //goscript:extern directive are implemented by a function
+// exported from a TypeScript module instead of their Go body:
+//
+//	//goscript:extern "./clock.ts" now     import * as __extern_clock from "./clock.js"
+//	func Now() int64                       export function Now(): number {
+//	                                         return __extern_clock.now()
+//	                                       }
+//
+// The directive takes the module, the name of the exported function which
+// defaults to the name of the Go function, and async if the implementation
+// returns a Promise. The module is either a path relative to the package
+// directory, copied next to the generated files of the package, or a bare
+// module specifier imported as is.
+//
+// The Go function may have a body, used when the package is built by the go
+// command, or none if the package is only compiled by goscript.
+
+// externDirective is the directive binding a function to TypeScript.
+const externDirective = "//goscript:extern"
+
+// externFunc is the TypeScript implementation of a function declared with a
+// //goscript:extern directive.
+type externFunc struct {
+	// module is the path of the module relative to the package directory,
+	// starting with "./", or a bare module specifier.
+	module string
+	// name is the name of the function exported by the module.
+	name string
+	// async indicates the function returns a Promise.
+	async bool
+	// alias is the name of the module import in the generated file.
+	alias string
+}
+
+// parseExternDirective parses the //goscript:extern directive in the doc of
+// the function funcName. It returns nil if there is no directive.
+func parseExternDirective(doc *ast.CommentGroup, funcName string) (*externFunc, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	for _, comment := range doc.List {
+		args, ok := strings.CutPrefix(comment.Text, externDirective)
+		if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+			continue
+		}
+		ext := &externFunc{name: funcName}
+		args = strings.TrimSpace(args)
+		if args != "" && (args[0] == '"' || args[0] == '`') {
+			quoted, err := strconv.QuotedPrefix(args)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid module %s", externDirective, args)
+			}
+			ext.module, _ = strconv.Unquote(quoted)
+			args = args[len(quoted):]
+		} else if end := strings.IndexAny(args, " \t"); end >= 0 {
+			ext.module, args = args[:end], args[end:]
+		} else {
+			ext.module, args = args, ""
+		}
+		if ext.module == "" {
+			return nil, fmt.Errorf("%s: missing module", externDirective)
+		}
+		if clean := path.Clean(ext.module); strings.HasPrefix(ext.module, ".") && (clean == ".." || strings.HasPrefix(clean, "../")) {
+			return nil, fmt.Errorf("%s: module %s is outside of the package directory", externDirective, ext.module)
+		}
+
+		fields := strings.Fields(args)
+		if n := len(fields); n != 0 && fields[n-1] == "async" {
+			ext.async = true
+			fields = fields[:n-1]
+		}
+		switch {
+		case len(fields) > 1:
+			return nil, fmt.Errorf("%s: unexpected arguments %s", externDirective, strings.Join(fields[1:], " "))
+		case len(fields) == 1:
+			if !isJSIdentifier(fields[0]) {
+				return nil, fmt.Errorf("%s: invalid function name %s", externDirective, fields[0])
+			}
+			ext.name = fields[0]
+		}
+		return ext, nil
+	}
+	return nil, nil
+}
+
+// isExternFuncDecl reports whether the function decl is implemented in
+// TypeScript. Its body is not compiled.
+func isExternFuncDecl(decl *ast.FuncDecl) bool {
+	ext, _ := parseExternDirective(decl.Doc, decl.Name.Name)
+	return ext != nil && decl.Recv == nil
+}
+
+// isJSIdentifier reports whether name is a valid JavaScript identifier made
+// of ASCII characters.
+func isJSIdentifier(name string) bool {
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+		case i != 0 && '0' <= r && r <= '9':
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// isLocal reports whether the module is a file of the package.
+func (e *externFunc) isLocal() bool {
+	return strings.HasPrefix(e.module, "./")
+}
+
+// importPath returns the path the generated code imports the module from.
+func (e *externFunc) importPath() string {
+	if !e.isLocal() {
+		return e.module
+	}
+	p := "./" + path.Clean(e.module)
+	if ext := path.Ext(p); ext == ".ts" || ext == ".tsx" {
+		p = strings.TrimSuffix(p, ext) + ".js"
+	}
+	return p
+}
+
+// writeExternImports finds the functions of file implemented in TypeScript
+// and writes the imports of their modules. Invalid directives are reported
+// as diagnostics.
+func (c *GoToTSCompiler) writeExternImports(file *ast.File) {
+	aliases := make(map[string]string)
+	used := make(map[string]bool)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ext, err := parseExternDirective(funcDecl.Doc, funcDecl.Name.Name)
+		if err != nil {
+			c.addDiagnosticError(newDiagnosticError(funcDecl.Pos(), DiagnosticDirective, "", "function %s: %s", funcDecl.Name.Name, err))
+			continue
+		}
+		if ext == nil {
+			continue
+		}
+		if err := checkExternFuncDecl(funcDecl); err != nil {
+			c.addDiagnosticError(newDiagnosticError(funcDecl.Pos(), DiagnosticDirective, "", "%s", err))
+			continue
+		}
+
+		importPath := ext.importPath()
+		alias, ok := aliases[importPath]
+		if !ok {
+			base := strings.TrimSuffix(path.Base(importPath), path.Ext(importPath))
+			base = strings.Map(func(r rune) rune {
+				if r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+					return r
+				}
+				return '_'
+			}, base)
+			alias = "__extern_" + base
+			for i := 2; used[alias]; i++ {
+				alias = fmt.Sprintf("__extern_%s%d", base, i)
+			}
+			used[alias] = true
+			aliases[importPath] = alias
+			c.tsw.WriteLinef("import * as %s from %q", alias, importPath)
+		}
+		ext.alias = alias
+		c.externFuncs[funcDecl] = ext
+	}
+}
+
+// checkExternFuncDecl returns an error if the function decl cannot be bound
+// to TypeScript: the generated function forwards its parameters by name.
+func checkExternFuncDecl(decl *ast.FuncDecl) error {
+	switch {
+	case decl.Recv != nil:
+		return fmt.Errorf("method %s: %s is only supported on functions", decl.Name.Name, externDirective)
+	case decl.Type.TypeParams != nil:
+		return fmt.Errorf("function %s: %s is not supported on generic functions", decl.Name.Name, externDirective)
+	}
+	for _, field := range decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			return fmt.Errorf("function %s: %s requires named parameters", decl.Name.Name, externDirective)
+		}
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				return fmt.Errorf("function %s: %s requires named parameters", decl.Name.Name, externDirective)
+			}
+		}
+	}
+	return nil
+}
+
+// writeExternFuncBody writes the body of the function decl implemented by
+// ext, calling the TypeScript function with the parameters.
+func (c *GoToTSCompiler) writeExternFuncBody(decl *ast.FuncDecl, ext *externFunc) {
+	c.tsw.WriteLine("{")
+	c.tsw.Indent(1)
+	if decl.Type.Results != nil && len(decl.Type.Results.List) != 0 {
+		c.tsw.WriteLiterally("return ")
+	} else if ext.async {
+		c.tsw.WriteLiterally("await ")
+	}
+	c.tsw.WriteLiterallyf("%s.%s(", ext.alias, ext.name)
+	for i, field := range decl.Type.Params.List {
+		_, variadic := field.Type.(*ast.Ellipsis)
+		for j, name := range field.Names {
+			if i != 0 || j != 0 {
+				c.tsw.WriteLiterally(", ")
+			}
+			if variadic {
+				c.tsw.WriteLiterally("...")
+			}
+			c.tsw.WriteLiterally(c.sanitizeIdentifier(name.Name))
+		}
+	}
+	c.tsw.WriteLine(")")
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("}")
+}
+
+// copyExternModules copies the TypeScript modules of the package implementing
+// functions with a //goscript:extern directive to the output directory.
+func (c *PackageCompiler) copyExternModules() error {
+	copied := make(map[string]bool)
+	for i, file := range c.pkg.Syntax {
+		pkgDir := filepath.Dir(c.pkg.CompiledGoFiles[i])
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			ext, err := parseExternDirective(funcDecl.Doc, funcDecl.Name.Name)
+			if err != nil || ext == nil || !ext.isLocal() || copied[ext.module] {
+				continue
+			}
+			copied[ext.module] = true
+			if err := copyExternModule(pkgDir, c.outputPath, path.Clean(ext.module)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyExternModule copies the module at the slash-separated path rel in
+// pkgDir to outputPath.
+func copyExternModule(pkgDir, outputPath, rel string) error {
+	content, err := os.ReadFile(filepath.Join(pkgDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return fmt.Errorf("failed to read %s module: %w", externDirective, err)
+	}
+	dest := filepath.Join(outputPath, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, content, 0o644)
+}
@@ -0,0 +1,56 @@
+package compiler
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestParseExternDirective(t *testing.T) {
+	tests := []struct {
+		directive string
+		want      *externFunc
+		wantErr   bool
+	}{
+		{`//goscript:extern "./impl.ts" now`, &externFunc{module: "./impl.ts", name: "now"}, false},
+		{`//goscript:extern ./impl.ts`, &externFunc{module: "./impl.ts", name: "Now"}, false},
+		{`//goscript:extern ./impl.ts async`, &externFunc{module: "./impl.ts", name: "Now", async: true}, false},
+		{`//goscript:extern "@host/clock" $now async`, &externFunc{module: "@host/clock", name: "$now", async: true}, false},
+		{`//goscript:extern`, nil, true},
+		{`//goscript:extern ../impl.ts`, nil, true},
+		{`//goscript:extern ./sub/../../impl.ts`, nil, true},
+		{`//goscript:extern ./impl.ts now later`, nil, true},
+		{`//goscript:extern ./impl.ts now-later`, nil, true},
+		{`//goscript:externs ./impl.ts`, nil, false},
+	}
+	for _, tt := range tests {
+		doc := &ast.CommentGroup{List: []*ast.Comment{
+			{Text: "// Now returns the current time."},
+			{Text: tt.directive},
+		}}
+		got, err := parseExternDirective(doc, "Now")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseExternDirective(%s) error = %v, want error %v", tt.directive, err, tt.wantErr)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+			t.Errorf("parseExternDirective(%s) = %+v, want %+v", tt.directive, got, tt.want)
+		}
+	}
+}
+
+func TestExternImportPath(t *testing.T) {
+	tests := []struct {
+		module, want string
+	}{
+		{"./impl.ts", "./impl.js"},
+		{"./platform/web.tsx", "./platform/web.js"},
+		{"./impl.js", "./impl.js"},
+		{"@host/clock", "@host/clock"},
+	}
+	for _, tt := range tests {
+		ext := &externFunc{module: tt.module}
+		if got := ext.importPath(); got != tt.want {
+			t.Errorf("importPath(%s) = %s, want %s", tt.module, got, tt.want)
+		}
+	}
+}
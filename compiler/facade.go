@@ -0,0 +1,512 @@
+package compiler
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// With Config.Facade, a facade.ts is written next to the index.ts of each
+// compiled package. It wraps the exported functions of the package in an API
+// which is idiomatic in TypeScript:
+//
+//   - a trailing error result is thrown as a $.GoErrorException instead of
+//     being returned, and the other results are returned as a single value
+//     or a tuple
+//   - slices are converted to and from arrays, []byte to Uint8Array
+//   - exported structs whose fields are all exported and convertible are
+//     converted to and from plain objects, declared as interfaces with the
+//     name of the struct. Other structs are passed as instances of the
+//     compiled classes.
+//   - context.Context parameters are dropped and filled from an optional
+//     trailing AbortSignal parameter
+//
+// Values are copied when they cross the facade: changes made by the function
+// to a struct or slice passed to it are not visible to the caller. Functions
+// using other types, like interfaces, channels or functions, and generic
+// functions are not part of the facade.
+
+// facadeFileName is the name of the facade file of a compiled package.
+const facadeFileName = "facade.ts"
+
+// facadeType describes how values of a Go type cross the facade.
+type facadeType struct {
+	// ts is the TypeScript type of the values in the facade.
+	ts string
+	// toGo converts the facade value expr to the value of the compiled code,
+	// nil if they are the same.
+	toGo func(expr string) string
+	// fromGo converts the value expr of the compiled code to the facade
+	// value, nil if they are the same.
+	fromGo func(expr string) string
+}
+
+// convertToGo returns the expression converting the facade value expr.
+func (t *facadeType) convertToGo(expr string) string {
+	if t.toGo == nil {
+		return expr
+	}
+	return t.toGo(expr)
+}
+
+// convertFromGo returns the expression converting the compiled value expr.
+func (t *facadeType) convertFromGo(expr string) string {
+	if t.fromGo == nil {
+		return expr
+	}
+	return t.fromGo(expr)
+}
+
+// facadeWriter writes the facade of a compiled package.
+type facadeWriter struct {
+	pkg      *types.Package
+	analysis *Analysis
+	// int64AsBigInt is Config.Int64AsBigInt.
+	int64AsBigInt bool
+	// pkgAlias is the name the compiled package is imported as.
+	pkgAlias string
+	// contextAlias is the name the context package is imported as.
+	contextAlias string
+	// usesContext is set if a function has a context.Context parameter.
+	usesContext bool
+	// plainStructs caches whether the structs of the package are converted
+	// to plain objects.
+	plainStructs map[*types.TypeName]bool
+}
+
+// generateFacadeFile writes the facade.ts of the package.
+func (c *PackageCompiler) generateFacadeFile() error {
+	w := &facadeWriter{
+		pkg:           c.pkg.Types,
+		analysis:      c.analysis,
+		int64AsBigInt: c.compilerConf.Int64AsBigInt,
+		pkgAlias:      sanitizeIdentifier(c.pkg.Name),
+		contextAlias:  "context",
+		plainStructs:  make(map[*types.TypeName]bool),
+	}
+	if w.pkgAlias == w.contextAlias {
+		w.contextAlias = "gocontext"
+	}
+	return os.WriteFile(filepath.Join(c.outputPath, facadeFileName), []byte(w.write()), 0o644) //nolint:gosec
+}
+
+// write returns the content of the facade file.
+func (w *facadeWriter) write() string {
+	var body strings.Builder
+	scope := w.pkg.Scope()
+
+	// Plain object interfaces and their converters
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if ok && w.isPlainStruct(obj) {
+			w.writeStruct(&body, obj)
+		}
+	}
+
+	// Functions
+	for _, name := range scope.Names() {
+		fn, ok := scope.Lookup(name).(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+		if reason := w.writeFunc(&body, fn); reason != "" {
+			fmt.Fprintf(&body, "// %s is not part of the facade: %s.\n\n", name, reason)
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "// Facade of the Go package %s using plain TypeScript values.\n", w.pkg.Path())
+	out.WriteString("// Errors returned by the functions are thrown as a $.GoErrorException.\n\n")
+	out.WriteString("import * as $ from \"@goscript/builtin/index.js\"\n")
+	if w.usesContext {
+		fmt.Fprintf(&out, "import * as %s from \"@goscript/context/index.js\"\n", w.contextAlias)
+	}
+	fmt.Fprintf(&out, "import * as %s from \"./index.js\"\n\n", w.pkgAlias)
+	out.WriteString(strings.TrimSuffix(body.String(), "\n"))
+	return out.String()
+}
+
+// isPlainStruct reports whether obj is an exported struct of the package
+// converted to a plain object: all its fields are exported, not embedded and
+// of types the facade supports.
+func (w *facadeWriter) isPlainStruct(obj *types.TypeName) bool {
+	if plain, ok := w.plainStructs[obj]; ok {
+		return plain
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok || obj.IsAlias() || obj.Pkg() != w.pkg || !obj.Exported() || named.TypeParams().Len() != 0 {
+		return false
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	// Assume the struct is plain while checking its fields, for recursive
+	// structs. The fields of struct types never depend on it, see facadeType.
+	w.plainStructs[obj] = true
+	plain := true
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		if !field.Exported() || field.Embedded() {
+			plain = false
+			break
+		}
+		if _, reason := w.facadeType(field.Type()); reason != "" {
+			plain = false
+			break
+		}
+	}
+	w.plainStructs[obj] = plain
+	return plain
+}
+
+// localStruct returns the exported struct of the package t refers to, nil if
+// it is not one.
+func (w *facadeWriter) localStruct(t types.Type) *types.TypeName {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != w.pkg || !named.Obj().Exported() || named.TypeParams().Len() != 0 {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named.Obj()
+}
+
+// facadeType returns how values of type t cross the facade, or the reason
+// why they cannot.
+func (w *facadeWriter) facadeType(t types.Type) (*facadeType, string) {
+	t = types.Unalias(t)
+
+	if obj := w.localStruct(t); obj != nil {
+		name := sanitizeIdentifier(obj.Name())
+		if !w.isPlainStruct(obj) {
+			// Instances of the compiled class, copied like struct values
+			return &facadeType{
+				ts:   w.pkgAlias + "." + name,
+				toGo: func(expr string) string { return "$.markAsStructValue(" + expr + ".clone())" },
+			}, ""
+		}
+		return &facadeType{
+			ts:     name,
+			toGo:   func(expr string) string { return "$.markAsStructValue(toGo" + name + "(" + expr + "))" },
+			fromGo: func(expr string) string { return "fromGo" + name + "(" + expr + ")" },
+		}, ""
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		return w.basicType(t, t)
+	case *types.Named:
+		if t.TypeParams().Len() != 0 || t.TypeArgs().Len() != 0 {
+			return nil, "generic type " + t.String() + " is not supported"
+		}
+		basic, ok := t.Underlying().(*types.Basic)
+		if ok && t.Obj().Pkg() == w.pkg && t.Obj().Exported() {
+			ft, reason := w.basicType(t, basic)
+			if reason != "" {
+				return nil, reason
+			}
+			ft.ts = w.pkgAlias + "." + sanitizeIdentifier(t.Obj().Name())
+			return ft, ""
+		}
+		if ok {
+			return w.basicType(t, basic)
+		}
+		if t.Obj().Pkg() == w.pkg {
+			if _, ok := t.Underlying().(*types.Slice); ok {
+				return w.facadeType(t.Underlying())
+			}
+			if _, ok := t.Underlying().(*types.Map); ok {
+				return w.facadeType(t.Underlying())
+			}
+		}
+		return nil, "type " + t.String() + " is not supported"
+	case *types.Pointer:
+		obj := w.localStruct(types.Unalias(t.Elem()))
+		if obj == nil {
+			return nil, "pointer type " + t.String() + " is not supported"
+		}
+		name := sanitizeIdentifier(obj.Name())
+		if !w.isPlainStruct(obj) {
+			return &facadeType{ts: w.pkgAlias + "." + name + " | null"}, ""
+		}
+		return &facadeType{
+			ts:     name + " | null",
+			toGo:   func(expr string) string { return expr + " === null ? null : toGo" + name + "(" + expr + ")" },
+			fromGo: func(expr string) string { return expr + " === null ? null : fromGo" + name + "(" + expr + ")" },
+		}, ""
+	case *types.Slice:
+		if elem, ok := types.Unalias(t.Elem()).(*types.Basic); ok && elem.Kind() == types.Uint8 {
+			return &facadeType{
+				ts:     "Uint8Array",
+				fromGo: func(expr string) string { return "$.bytesToUint8Array(" + expr + ")" },
+			}, ""
+		}
+		elem, reason := w.facadeType(t.Elem())
+		if reason != "" {
+			return nil, reason
+		}
+		return &facadeType{
+			ts: arrayTS(elem.ts),
+			toGo: func(expr string) string {
+				if elem.toGo != nil {
+					expr += ".map((x) => " + elem.toGo("x") + ")"
+				}
+				return "$.arrayToSlice(" + expr + ")"
+			},
+			fromGo: func(expr string) string {
+				expr = "$.asArray(" + expr + ")"
+				if elem.fromGo != nil {
+					expr += ".map((x) => " + elem.fromGo("x") + ")"
+				}
+				return expr
+			},
+		}, ""
+	case *types.Map:
+		key, reason := w.facadeType(t.Key())
+		if reason != "" {
+			return nil, reason
+		}
+		if _, ok := t.Key().Underlying().(*types.Basic); !ok {
+			return nil, "map key type " + t.Key().String() + " is not supported"
+		}
+		elem, reason := w.facadeType(t.Elem())
+		if reason != "" {
+			return nil, reason
+		}
+		if elem.toGo != nil || elem.fromGo != nil {
+			return nil, "map value type " + t.Elem().String() + " is not supported"
+		}
+		return &facadeType{
+			ts:     "Map<" + key.ts + ", " + elem.ts + ">",
+			fromGo: func(expr string) string { return expr + " ?? new Map()" },
+		}, ""
+	}
+	return nil, "type " + t.String() + " is not supported"
+}
+
+// basicType returns how values of type t with the underlying type basic cross
+// the facade: as they are.
+func (w *facadeWriter) basicType(t types.Type, basic *types.Basic) (*facadeType, string) {
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return &facadeType{ts: "boolean"}, ""
+	case info&types.IsString != 0:
+		return &facadeType{ts: "string"}, ""
+	case info&types.IsComplex != 0:
+		return nil, "complex type " + t.String() + " is not supported"
+	case info&types.IsNumeric != 0:
+		if w.int64AsBigInt && w.analysis.isBigIntType(t) {
+			return &facadeType{ts: "bigint"}, ""
+		}
+		return &facadeType{ts: "number"}, ""
+	}
+	return nil, "type " + t.String() + " is not supported"
+}
+
+// arrayTS returns the TypeScript type of arrays of elements of type ts.
+func arrayTS(ts string) string {
+	if strings.Contains(ts, " ") {
+		return "(" + ts + ")[]"
+	}
+	return ts + "[]"
+}
+
+// isContextType reports whether t is context.Context.
+func isContextType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// isErrorType reports whether t is the error interface.
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// writeStruct writes the interface of the plain objects of the struct obj and
+// the functions converting them to and from the compiled class.
+func (w *facadeWriter) writeStruct(b *strings.Builder, obj *types.TypeName) {
+	name := sanitizeIdentifier(obj.Name())
+	class := w.pkgAlias + "." + name
+	st := obj.Type().Underlying().(*types.Struct)
+
+	fields := make([]*facadeType, st.NumFields())
+	for i := range st.NumFields() {
+		fields[i], _ = w.facadeType(st.Field(i).Type())
+	}
+
+	fmt.Fprintf(b, "// %s is a %s as a plain object.\n", name, class)
+	fmt.Fprintf(b, "export interface %s {\n", name)
+	for i, ft := range fields {
+		fmt.Fprintf(b, "\t%s: %s\n", st.Field(i).Name(), ft.ts)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "// toGo%s converts the plain object v to a %s.\n", name, class)
+	fmt.Fprintf(b, "export function toGo%s(v: %s): %s {\n", name, name, class)
+	fmt.Fprintf(b, "\treturn new %s({\n", class)
+	for i, ft := range fields {
+		fieldName := st.Field(i).Name()
+		fmt.Fprintf(b, "\t\t%s: %s,\n", fieldName, ft.convertToGo("v."+fieldName))
+	}
+	b.WriteString("\t})\n}\n\n")
+
+	fmt.Fprintf(b, "// fromGo%s converts v to a plain object.\n", name)
+	fmt.Fprintf(b, "export function fromGo%s(v: %s): %s {\n", name, class, name)
+	b.WriteString("\treturn {\n")
+	for i, ft := range fields {
+		fieldName := st.Field(i).Name()
+		fmt.Fprintf(b, "\t\t%s: %s,\n", fieldName, ft.convertFromGo("v."+fieldName))
+	}
+	b.WriteString("\t}\n}\n\n")
+}
+
+// writeFunc writes the facade of the function fn, or returns the reason why
+// it is not part of the facade.
+func (w *facadeWriter) writeFunc(b *strings.Builder, fn *types.Func) string {
+	sig := fn.Type().(*types.Signature)
+	if sig.TypeParams().Len() != 0 {
+		return "it has type parameters"
+	}
+
+	// Names which the parameters must not shadow
+	reserved := map[string]bool{w.pkgAlias: true, w.contextAlias: true, "signal": true}
+
+	var params, args []string
+	usesContext := false
+	for i := range sig.Params().Len() {
+		param := sig.Params().At(i)
+		if isContextType(param.Type()) {
+			usesContext = true
+			args = append(args, w.contextAlias+".fromAbortSignal(signal)")
+			continue
+		}
+
+		name := sanitizeIdentifier(param.Name())
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		for reserved[name] {
+			name += "_"
+		}
+		reserved[name] = true
+
+		paramType := param.Type()
+		variadic := sig.Variadic() && i == sig.Params().Len()-1
+		if variadic {
+			paramType = paramType.(*types.Slice).Elem()
+		}
+		ft, reason := w.facadeType(paramType)
+		if reason != "" {
+			return fmt.Sprintf("parameter %s: %s", param.Name(), reason)
+		}
+		if variadic {
+			params = append(params, "..."+name+": "+arrayTS(ft.ts))
+			if ft.toGo != nil {
+				args = append(args, "..."+name+".map((x) => "+ft.toGo("x")+")")
+			} else {
+				args = append(args, "..."+name)
+			}
+			continue
+		}
+		params = append(params, name+": "+ft.ts)
+		args = append(args, ft.convertToGo(name))
+	}
+	if usesContext {
+		w.usesContext = true
+		params = append(params, "signal?: AbortSignal")
+	}
+
+	// Results, without a trailing error
+	results := make([]*facadeType, 0, sig.Results().Len())
+	hasError := false
+	for i := range sig.Results().Len() {
+		result := sig.Results().At(i)
+		if i == sig.Results().Len()-1 && isErrorType(result.Type()) {
+			hasError = true
+			continue
+		}
+		ft, reason := w.facadeType(result.Type())
+		if reason != "" {
+			return "result: " + reason
+		}
+		results = append(results, ft)
+	}
+
+	var resultTS string
+	switch len(results) {
+	case 0:
+		resultTS = "void"
+	case 1:
+		resultTS = results[0].ts
+	default:
+		tsTypes := make([]string, len(results))
+		for i, ft := range results {
+			tsTypes[i] = ft.ts
+		}
+		resultTS = "[" + strings.Join(tsTypes, ", ") + "]"
+	}
+
+	name := sanitizeIdentifier(fn.Name())
+	call := w.pkgAlias + "." + name + "(" + strings.Join(args, ", ") + ")"
+	async := w.analysis.IsAsyncFunc(fn)
+	if async {
+		resultTS = "Promise<" + resultTS + ">"
+		call = "await " + call
+		b.WriteString("export async function ")
+	} else {
+		b.WriteString("export function ")
+	}
+	fmt.Fprintf(b, "%s(%s): %s {\n", name, strings.Join(params, ", "), resultTS)
+
+	// Return the results of the call if they do not need to be converted
+	converted := hasError
+	for _, ft := range results {
+		converted = converted || ft.fromGo != nil
+	}
+	if !converted {
+		if len(results) == 0 {
+			fmt.Fprintf(b, "\t%s\n}\n\n", call)
+		} else {
+			fmt.Fprintf(b, "\treturn %s\n}\n\n", call)
+		}
+		return ""
+	}
+
+	// The variables of the results of the call
+	var vars []string
+	for i := range results {
+		vars = append(vars, fmt.Sprintf("_r%d", i))
+	}
+	if hasError {
+		vars = append(vars, "_err")
+	}
+	switch len(vars) {
+	case 1:
+		fmt.Fprintf(b, "\tconst %s = %s\n", vars[0], call)
+	default:
+		fmt.Fprintf(b, "\tconst [%s] = %s\n", strings.Join(vars, ", "), call)
+	}
+	if hasError {
+		b.WriteString("\tif (_err !== null) {\n\t\tthrow new $.GoErrorException(_err)\n\t}\n")
+	}
+
+	switch len(results) {
+	case 0:
+	case 1:
+		fmt.Fprintf(b, "\treturn %s\n", results[0].convertFromGo(vars[0]))
+	default:
+		values := make([]string, len(results))
+		for i, ft := range results {
+			values[i] = ft.convertFromGo(vars[i])
+		}
+		fmt.Fprintf(b, "\treturn [%s]\n", strings.Join(values, ", "))
+	}
+	b.WriteString("}\n\n")
+	return ""
+}
@@ -0,0 +1,123 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompileFacade(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"shapes/shapes.go": `package shapes
+
+import (
+	"context"
+	"errors"
+)
+
+// Point is a point with labels.
+type Point struct {
+	X, Y   int
+	Labels []string
+	Next   *Point
+}
+
+// Counter has unexported fields.
+type Counter struct {
+	n int
+}
+
+// Kind is a kind of shape.
+type Kind int
+
+// Sum returns the sum of xs.
+func Sum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+// Div divides a by b.
+func Div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+// Move moves p.
+func Move(p Point, dx int) Point {
+	p.X += dx
+	return p
+}
+
+// NewCounter returns a new Counter.
+func NewCounter(kind Kind) *Counter {
+	return &Counter{n: int(kind)}
+}
+
+// Wait waits until ctx is done.
+func Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Apply calls f.
+func Apply(f func() int) int {
+	return f()
+}
+
+// Identity returns v.
+func Identity[T any](v T) T {
+	return v
+}
+`,
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	comp, err := NewCompiler(&Config{
+		Dir:        dir,
+		OutputPath: outputDir,
+		Facade:     true,
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.CompilePackages(context.Background(), "./shapes"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "shapes", "facade.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	facade := string(data)
+
+	for _, want := range []string{
+		`import * as context from "@goscript/context/index.js"`,
+		`import * as shapes from "./index.js"`,
+		"export interface Point {\n\tX: number\n\tY: number\n\tLabels: string[]\n\tNext: Point | null\n}",
+		"\t\tLabels: $.arrayToSlice(v.Labels),\n",
+		"\t\tNext: v.Next === null ? null : fromGoPoint(v.Next),\n",
+		"export function Sum(xs: number[]): number {\n\treturn shapes.Sum($.arrayToSlice(xs))\n}",
+		"export function Div(a: number, b: number): number {\n\tconst [_r0, _err] = shapes.Div(a, b)\n\tif (_err !== null) {\n\t\tthrow new $.GoErrorException(_err)\n\t}\n\treturn _r0\n}",
+		"export function Move(p: Point, dx: number): Point {\n\tconst _r0 = shapes.Move($.markAsStructValue(toGoPoint(p)), dx)\n\treturn fromGoPoint(_r0)\n}",
+		"export function NewCounter(kind: shapes.Kind): shapes.Counter | null {",
+		"export async function Wait(signal?: AbortSignal): Promise<void> {\n\tconst _err = await shapes.Wait(context.fromAbortSignal(signal))",
+		"// Apply is not part of the facade: parameter f: type func() int is not supported.",
+		"// Identity is not part of the facade: it has type parameters.",
+	} {
+		if !strings.Contains(facade, want) {
+			t.Errorf("facade.ts does not contain %q:\n%s", want, facade)
+		}
+	}
+	if strings.Contains(facade, "interface Counter") {
+		t.Errorf("struct with unexported fields converted to a plain object:\n%s", facade)
+	}
+}
@@ -0,0 +1,361 @@
+package compiler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// TestPackage is a package whose tests were compiled by CompileTests.
+type TestPackage struct {
+	// PkgPath is the import path of the package under test.
+	PkgPath string
+	// OutputPath is the root of the TypeScript output of the tests, holding
+	// the runner, its tsconfig.json and the compiled packages.
+	OutputPath string
+	// EntryPath is the path of the generated test runner.
+	// Empty if the package has no test files.
+	EntryPath string
+	// Tests are the names of the Test functions, in the order they run.
+	Tests []string
+	// Benchmarks are the names of the Benchmark functions.
+	Benchmarks []string
+	// Result lists the packages which were compiled or copied.
+	Result *CompilationResult
+}
+
+// testVariants are the packages loaded for testing a package.
+type testVariants struct {
+	// pkg is the package itself.
+	pkg *packages.Package
+	// internal is pkg compiled with its _test.go files of the same package.
+	internal *packages.Package
+	// external is the package_test package of the _test.go files.
+	external *packages.Package
+}
+
+// testEntryFile is the name of the generated test runner.
+const testEntryFile = "testmain.ts"
+
+// CompileTests loads the test variants of the packages matching patterns and
+// compiles each package together with its _test.go files and all their
+// dependencies into its own directory below the output path:
+// <output>/<package path>.test. Test and Benchmark functions are collected
+// into a generated runner, which RunTests executes.
+//
+// Example and Fuzz functions are not run.
+func (c *Compiler) CompileTests(ctx context.Context, patterns ...string) ([]*TestPackage, error) {
+	opts := c.opts
+	opts.Context = ctx
+	opts.Tests = true
+	opts.Mode |= packages.NeedForTest
+	pkgs, err := packages.Load(&opts, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	// Group the variants by the package under test, in load order.
+	var pkgPaths []string
+	variants := make(map[string]*testVariants)
+	variantsOf := func(pkgPath string) *testVariants {
+		v, ok := variants[pkgPath]
+		if !ok {
+			v = &testVariants{}
+			variants[pkgPath] = v
+			pkgPaths = append(pkgPaths, pkgPath)
+		}
+		return v
+	}
+	for _, pkg := range pkgs {
+		switch {
+		case pkg.ForTest == "":
+			// Skip the generated test main package, goscript writes its own.
+			if pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test") {
+				continue
+			}
+			variantsOf(pkg.PkgPath).pkg = pkg
+		case pkg.PkgPath == pkg.ForTest:
+			variantsOf(pkg.ForTest).internal = pkg
+		default:
+			variantsOf(pkg.ForTest).external = pkg
+		}
+	}
+
+	var testPkgs []*TestPackage
+	for _, pkgPath := range pkgPaths {
+		tp, err := c.compileTestPackage(ctx, pkgPath, variants[pkgPath])
+		if err != nil {
+			return nil, err
+		}
+		testPkgs = append(testPkgs, tp)
+	}
+	return testPkgs, nil
+}
+
+// compileTestPackage compiles the test variants v of the package pkgPath and
+// writes the test runner.
+func (c *Compiler) compileTestPackage(ctx context.Context, pkgPath string, v *testVariants) (*TestPackage, error) {
+	tp := &TestPackage{
+		PkgPath:    pkgPath,
+		OutputPath: filepath.Join(c.config.OutputPath, filepath.FromSlash(pkgPath)+".test"),
+	}
+	if v.internal == nil && v.external == nil {
+		return tp, nil
+	}
+
+	roots := []*packages.Package{v.internal}
+	if v.internal == nil {
+		roots[0] = v.pkg
+	}
+	if v.external != nil {
+		roots = append(roots, v.external)
+	}
+
+	var funcs []testFuncs
+	var rootPaths []string
+	for _, pkg := range roots {
+		if pkg == nil {
+			return nil, errors.Errorf("package %s was not loaded", pkgPath)
+		}
+		f := findTestFuncs(pkg)
+		tp.Tests = append(tp.Tests, f.tests...)
+		tp.Benchmarks = append(tp.Benchmarks, f.benchmarks...)
+		funcs = append(funcs, f)
+		rootPaths = append(rootPaths, pkg.PkgPath)
+	}
+
+	// The runner needs the builtin and handwritten packages next to it.
+	tc := &Compiler{le: c.le, config: c.config, opts: c.opts, overrides: c.overrides}
+	tc.config.OutputPath = tp.OutputPath
+	tc.config.DisableEmitBuiltin = false
+	tp.Result = &CompilationResult{OriginalPackages: rootPaths}
+	if err := tc.compileLoadedPackages(ctx, tc.collectDependencies(roots), rootPaths, tp.Result); err != nil {
+		return nil, err
+	}
+
+	tp.EntryPath = filepath.Join(tp.OutputPath, testEntryFile)
+	if err := os.WriteFile(tp.EntryPath, []byte(generateTestMain(pkgPath, funcs)), 0o644); err != nil {
+		return nil, err
+	}
+	if err := writeRunnerTsConfig(tp.OutputPath, testEntryFile); err != nil {
+		return nil, err
+	}
+	return tp, nil
+}
+
+// testFuncs are the test functions declared in the _test.go files of a
+// package.
+type testFuncs struct {
+	// pkgPath is the import path of the package.
+	pkgPath string
+	// tests and benchmarks are the names of the Test and Benchmark functions.
+	tests, benchmarks []string
+	// testMain is set if the package declares TestMain.
+	testMain bool
+}
+
+// findTestFuncs collects the Test and Benchmark functions and TestMain
+// declared in the _test.go files of pkg.
+func findTestFuncs(pkg *packages.Package) testFuncs {
+	funcs := testFuncs{pkgPath: pkg.PkgPath}
+	for i, file := range pkg.Syntax {
+		if !strings.HasSuffix(pkg.CompiledGoFiles[i], "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			name := fn.Name.Name
+			switch {
+			case name == "TestMain" && isTestFuncOf(pkg, fn, "M"):
+				funcs.testMain = true
+			case isTestName(name, "Test") && isTestFuncOf(pkg, fn, "T"):
+				funcs.tests = append(funcs.tests, name)
+			case isTestName(name, "Benchmark") && isTestFuncOf(pkg, fn, "B"):
+				funcs.benchmarks = append(funcs.benchmarks, name)
+			}
+		}
+	}
+	return funcs
+}
+
+// isTestName reports whether name is a test function name with the prefix,
+// like TestFoo but not Testfoo.
+func isTestName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// isTestFuncOf reports whether fn takes a single *testing.<typeName> and
+// returns nothing.
+func isTestFuncOf(pkg *packages.Package, fn *ast.FuncDecl, typeName string) bool {
+	obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := obj.Type().(*types.Signature)
+	if sig.TypeParams().Len() != 0 || sig.Params().Len() != 1 || sig.Results().Len() != 0 {
+		return false
+	}
+	ptr, ok := sig.Params().At(0).Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	tn := named.Obj()
+	return tn.Pkg() != nil && tn.Pkg().Path() == "testing" && tn.Name() == typeName
+}
+
+// generateTestMain returns the test runner for the package pkgPath with the
+// test functions of its test packages.
+func generateTestMain(pkgPath string, pkgFuncs []testFuncs) string {
+	var b strings.Builder
+	b.WriteString("// Code generated by goscript test. DO NOT EDIT.\n\n")
+	b.WriteString("import * as $ from \"@goscript/builtin/index.js\"\n")
+	b.WriteString("import * as testing from \"@goscript/testing/index.js\"\n")
+
+	aliases := make([]string, len(pkgFuncs))
+	for i, f := range pkgFuncs {
+		aliases[i] = "_test"
+		if f.pkgPath != pkgPath {
+			aliases[i] = "_xtest"
+		}
+		fmt.Fprintf(&b, "import * as %s from %q\n", aliases[i], translateGoPathToTypescriptPath(f.pkgPath)+"/index.js")
+	}
+
+	writeList := func(names func(testFuncs) []string) {
+		b.WriteString("[")
+		n := 0
+		for i, f := range pkgFuncs {
+			for _, name := range names(f) {
+				if n == 0 {
+					b.WriteString("\n")
+				}
+				fmt.Fprintf(&b, "\t\t{ Name: %q, F: %s.%s },\n", name, aliases[i], sanitizeIdentifier(name))
+				n++
+			}
+		}
+		if n != 0 {
+			b.WriteString("\t")
+		}
+		b.WriteString("]")
+	}
+
+	fmt.Fprintf(&b, "\nconst m = testing.MainStart(\n\t%q,\n\t", pkgPath)
+	writeList(func(f testFuncs) []string { return f.tests })
+	b.WriteString(",\n\t")
+	writeList(func(f testFuncs) []string { return f.benchmarks })
+	b.WriteString(",\n)\n\n")
+
+	testMain := ""
+	for i, f := range pkgFuncs {
+		if f.testMain {
+			testMain = ", " + aliases[i] + ".TestMain"
+		}
+	}
+	fmt.Fprintf(&b, "await $.runMain(() => testing.runTestMain(m%s))\n", testMain)
+	return b.String()
+}
+
+// writeRunnerTsConfig writes the tsconfig.json resolving the @goscript/
+// imports of the runner entryFile in outputPath.
+func writeRunnerTsConfig(outputPath, entryFile string) error {
+	tsconfig := map[string]any{
+		"compilerOptions": map[string]any{
+			"target":           "es2022",
+			"lib":              []string{"es2022", "esnext.disposable", "dom"},
+			"module":           "esnext",
+			"moduleResolution": "bundler",
+			"strict":           true,
+			"noEmit":           true,
+			"paths": map[string][]string{
+				"@goscript/*": {"./@goscript/*"},
+			},
+		},
+		"include": []string{entryFile},
+	}
+	data, err := json.MarshalIndent(tsconfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputPath, "tsconfig.json"), append(data, '\n'), 0o644)
+}
+
+// TestRunConfig configures how RunTests executes compiled tests.
+type TestRunConfig struct {
+	// Runtime is the JavaScript runtime running the tests: "bun" (the
+	// default), or "node" which loads the TypeScript with tsx.
+	Runtime string
+	// Args are passed to the runner, such as -test.v or -test.run=TestFoo.
+	Args []string
+	// Stdout and Stderr receive the output of the runner.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// RunTests runs the compiled tests of tp and reports whether they passed.
+// An error is returned if the runner could not be started.
+func RunTests(ctx context.Context, tp *TestPackage, conf *TestRunConfig) (bool, error) {
+	if tp.EntryPath == "" {
+		return true, nil
+	}
+
+	cmd, err := runtimeCommand(ctx, conf.Runtime, tp.EntryPath, conf.Args)
+	if err != nil {
+		return false, err
+	}
+	cmd.Stdout = conf.Stdout
+	cmd.Stderr = conf.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// runtimeCommand returns the command running the TypeScript program
+// entryPath with args under the JavaScript runtime: "bun" (the default), or
+// "node" which loads the TypeScript with tsx. The command runs in the
+// directory of entryPath, next to its tsconfig.json.
+func runtimeCommand(ctx context.Context, runtime, entryPath string, args []string) (*exec.Cmd, error) {
+	var name string
+	var runtimeArgs []string
+	switch runtime {
+	case "", "bun":
+		name, runtimeArgs = "bun", []string{"run", entryPath}
+	case "node":
+		name, runtimeArgs = "node", []string{"--import", "tsx", entryPath}
+	default:
+		return nil, errors.Errorf("unknown JavaScript runtime: %s", runtime)
+	}
+	cmd := exec.CommandContext(ctx, name, append(runtimeArgs, args...)...)
+	cmd.Dir = filepath.Dir(entryPath)
+	return cmd, nil
+}
@@ -0,0 +1,150 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestIsTestName(t *testing.T) {
+	tests := []struct {
+		name, prefix string
+		want         bool
+	}{
+		{"Test", "Test", true},
+		{"TestFoo", "Test", true},
+		{"Test_foo", "Test", true},
+		{"Test1", "Test", true},
+		{"Testfoo", "Test", false},
+		{"Testéfoo", "Test", false},
+		{"BenchmarkFoo", "Benchmark", true},
+		{"Foo", "Test", false},
+	}
+	for _, tt := range tests {
+		if got := isTestName(tt.name, tt.prefix); got != tt.want {
+			t.Errorf("isTestName(%q, %q) = %v, want %v", tt.name, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestCompileTests(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"go.mod":       "module example.com/gotest\n\ngo 1.24\n",
+		"calc/calc.go": "package calc\n\nfunc Add(a, b int) int { return a + b }\n",
+		"calc/calc_test.go": `package calc
+
+import "testing"
+
+func TestMain(m *testing.M) { m.Run() }
+
+func TestAdd(t *testing.T) {
+	t.Run("sub", func(t *testing.T) {
+		if Add(1, 2) != 3 {
+			t.Fatal("wrong sum")
+		}
+	})
+}
+
+func Testlower(t *testing.T) {}
+
+func TestHelper(s string) {}
+
+func BenchmarkAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Add(i, i)
+	}
+}
+`,
+		"calc/external_test.go": `package calc_test
+
+import (
+	"testing"
+
+	"example.com/gotest/calc"
+)
+
+func TestExternal(t *testing.T) {
+	if calc.Add(2, 2) != 4 {
+		t.Error("wrong sum")
+	}
+}
+
+func ExampleAdd() {}
+`,
+		"notests/notests.go": "package notests\n\nfunc F() {}\n",
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	le := logrus.NewEntry(logrus.New())
+	comp, err := NewCompiler(&Config{Dir: dir, OutputPath: outputDir}, le, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testPkgs, err := comp.CompileTests(context.Background(), "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calc, notests *TestPackage
+	for _, tp := range testPkgs {
+		switch tp.PkgPath {
+		case "example.com/gotest/calc":
+			calc = tp
+		case "example.com/gotest/notests":
+			notests = tp
+		default:
+			t.Errorf("unexpected test package %s", tp.PkgPath)
+		}
+	}
+	if calc == nil || notests == nil {
+		t.Fatalf("missing test packages: %v", testPkgs)
+	}
+	if notests.EntryPath != "" {
+		t.Errorf("package without test files has a runner: %s", notests.EntryPath)
+	}
+
+	if want := []string{"TestAdd", "TestExternal"}; !slices.Equal(calc.Tests, want) {
+		t.Errorf("Tests = %v, want %v", calc.Tests, want)
+	}
+	if want := []string{"BenchmarkAdd"}; !slices.Equal(calc.Benchmarks, want) {
+		t.Errorf("Benchmarks = %v, want %v", calc.Benchmarks, want)
+	}
+
+	runner, err := os.ReadFile(calc.EntryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`import * as _test from "@goscript/example.com/gotest/calc/index.js"`,
+		`import * as _xtest from "@goscript/example.com/gotest/calc_test/index.js"`,
+		`{ Name: "TestAdd", F: _test.TestAdd },`,
+		`{ Name: "TestExternal", F: _xtest.TestExternal },`,
+		`{ Name: "BenchmarkAdd", F: _test.BenchmarkAdd },`,
+		`await $.runMain(() => testing.runTestMain(m, _test.TestMain))`,
+	} {
+		if !strings.Contains(string(runner), want) {
+			t.Errorf("runner does not contain %q:\n%s", want, runner)
+		}
+	}
+
+	for _, name := range []string{
+		"tsconfig.json",
+		"@goscript/builtin/index.ts",
+		"@goscript/testing/testing.ts",
+		"@goscript/example.com/gotest/calc/calc.gs.ts",
+		"@goscript/example.com/gotest/calc/calc_test.gs.ts",
+		"@goscript/example.com/gotest/calc_test/external_test.gs.ts",
+	} {
+		if _, err := os.Stat(filepath.Join(calc.OutputPath, name)); err != nil {
+			t.Errorf("missing output file: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(calc.OutputPath, "@goscript/testing/testing.test.ts")); err == nil {
+		t.Errorf("tests of the testing package were copied")
+	}
+}
@@ -0,0 +1,621 @@
+package compiler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// With Config.Incremental, each compiled package gets a goscript.meta.json
+// next to its generated files recording the results of its analysis and the
+// key of its inputs: the compiler, the options affecting the generated code,
+// the files of the package and the API of its dependencies. A package whose
+// key is unchanged is reused without analyzing and generating it again, so
+// editing the body of a function only compiles its own package again.
+//
+// The generated code of a package also depends on the packages importing it
+// when they implement its interfaces with async methods. A reused package is
+// compiled again if a package implementing its interfaces was compiled, and
+// the packages importing a package whose async methods changed are compiled
+// again as well.
+
+// packageMetadataFile is the name of the metadata file of a compiled package.
+const packageMetadataFile = "goscript.meta.json"
+
+// packageMetadataVersion is the version of the metadata format. Metadata of
+// another version is ignored.
+const packageMetadataVersion = 2
+
+// PackageMetadata is the metadata written next to a compiled package.
+type PackageMetadata struct {
+	// Version is the version of the metadata format.
+	Version int `json:"version"`
+	// PkgPath is the import path of the package.
+	PkgPath string `json:"pkgPath"`
+	// Key is the hash of the inputs of the package.
+	Key string `json:"key"`
+	// Files are the generated files, relative to the package output directory.
+	Files []string `json:"files"`
+	// AsyncMethods are the async functions ("Func") and methods
+	// ("Type.Method") of the package, like in the meta.json of handwritten
+	// packages.
+	AsyncMethods map[string]bool `json:"asyncMethods,omitempty"`
+	// InterfaceImplementations maps the interfaces declared in the package,
+	// by name or type for unnamed interfaces, to the types implementing them
+	// in the compiled packages ("path/to/pkg.Type").
+	InterfaceImplementations map[string][]string `json:"interfaceImplementations,omitempty"`
+}
+
+// ReadPackageMetadata reads the metadata of the package compiled to
+// outputPath. It returns nil if there is no metadata of the current version.
+func ReadPackageMetadata(outputPath string) (*PackageMetadata, error) {
+	content, err := os.ReadFile(filepath.Join(outputPath, packageMetadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var metadata PackageMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.Version != packageMetadataVersion {
+		return nil, nil
+	}
+	return &metadata, nil
+}
+
+// compilerHash returns the hash of the running compiler executable, so that
+// packages are compiled again after updating goscript.
+var compilerHash = sync.OnceValue(func() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return ""
+	}
+	defer f.Close() //nolint:errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+})
+
+// incrementalBuild computes the keys of the packages of a compile.
+type incrementalBuild struct {
+	c *Compiler
+	// patternPkgPaths are the packages compiled even if overridden.
+	patternPkgPaths []string
+	// base is the part of the keys shared by all packages.
+	base string
+	// sourceKeys are the hashes of the files of the packages by package path.
+	sourceKeys map[string]string
+	// apiKeys are the hashes of the declarations of the packages by package
+	// path, see apiKey.
+	apiKeys map[string]string
+	// deps are the transitive dependencies by package path.
+	deps map[string]map[string]bool
+	// pkgs are the packages by package path.
+	pkgs map[string]*packages.Package
+	// previous is the metadata found before compiling by package path.
+	previous map[string]*PackageMetadata
+	// compiled is the metadata of the packages compiled by package path.
+	compiled map[string]*PackageMetadata
+}
+
+// newIncrementalBuild returns the incremental build of the compiler c.
+func newIncrementalBuild(c *Compiler, patternPkgPaths []string) (*incrementalBuild, error) {
+	options, err := json.Marshal(struct {
+		BuildFlags              []string
+		Int64AsBigInt           bool
+		StrictIntegers          bool
+		SourceMap               bool
+		SourceMapSourcesContent bool
+		Preemptive              bool
+		OverrideDirs            []string
+		Facade                  bool
+		Schemas                 bool
+		EnumUnions              bool
+		SealedUnions            bool
+		JSONMethods             bool
+	}{
+		c.config.BuildFlags,
+		c.config.Int64AsBigInt,
+		c.config.StrictIntegers,
+		c.config.SourceMap,
+		c.config.SourceMapSourcesContent,
+		c.config.Preemptive,
+		c.config.OverrideDirs,
+		c.config.Facade,
+		c.config.Schemas,
+		c.config.EnumUnions,
+		c.config.SealedUnions,
+		c.config.JSONMethods,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &incrementalBuild{
+		c:               c,
+		patternPkgPaths: patternPkgPaths,
+		base:            fmt.Sprintf("goscript %d %s %s", packageMetadataVersion, compilerHash(), options),
+		sourceKeys:      make(map[string]string),
+		apiKeys:         make(map[string]string),
+		deps:            make(map[string]map[string]bool),
+		pkgs:            make(map[string]*packages.Package),
+		previous:        make(map[string]*PackageMetadata),
+		compiled:        make(map[string]*PackageMetadata),
+	}, nil
+}
+
+// packageKey returns the key of the inputs of pkg: its files and the API of
+// its dependencies, which must have been compiled or reused already.
+func (b *incrementalBuild) packageKey(pkg *packages.Package) (string, error) {
+	sourceKey, err := b.sourceKey(pkg)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\npackage %s %s\n", b.base, pkg.PkgPath, sourceKey)
+	for _, depPath := range slices.Sorted(maps.Keys(b.dependencies(pkg))) {
+		apiKey, err := b.apiKey(b.pkgs[depPath])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "import %s %s\n", depPath, apiKey)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isHandwritten reports whether pkg is replaced by a handwritten package.
+func (b *incrementalBuild) isHandwritten(pkg *packages.Package) bool {
+	return b.c.overrides.lookup(pkg.PkgPath) != nil && !slices.Contains(b.patternPkgPaths, pkg.PkgPath)
+}
+
+// sourceKey returns the hash of the files of pkg, or of its handwritten
+// package.
+func (b *incrementalBuild) sourceKey(pkg *packages.Package) (string, error) {
+	if key, ok := b.sourceKeys[pkg.PkgPath]; ok {
+		return key, nil
+	}
+
+	h := sha256.New()
+	if b.isHandwritten(pkg) {
+		fsys := b.c.overrides.lookup(pkg.PkgPath)
+		err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "override %s %x\n", p, sha256.Sum256(content))
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to hash handwritten package %s: %w", pkg.PkgPath, err)
+		}
+	} else {
+		for _, file := range packageInputFiles(pkg) {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(h, "file %s %x\n", filepath.Base(file), sha256.Sum256(content))
+		}
+	}
+
+	key := hex.EncodeToString(h.Sum(nil))
+	b.sourceKeys[pkg.PkgPath] = key
+	return key, nil
+}
+
+// apiKey returns the hash of the API of pkg which the generated code of the
+// packages importing it depends on: its exported functions, variables and
+// constants with their values, its types with their methods, and its async
+// functions and methods. Unexported types are included as they can be
+// reached through exported declarations. The API of a handwritten package
+// is its files.
+func (b *incrementalBuild) apiKey(pkg *packages.Package) (string, error) {
+	if b.isHandwritten(pkg) || pkg.Types == nil {
+		return b.sourceKey(pkg)
+	}
+
+	declKey, ok := b.apiKeys[pkg.PkgPath]
+	if !ok {
+		h := sha256.New()
+		qualifier := (*types.Package).Path
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			typeName, isType := obj.(*types.TypeName)
+			if !obj.Exported() && !isType {
+				continue
+			}
+			fmt.Fprintln(h, types.ObjectString(obj, qualifier))
+			if constObj, ok := obj.(*types.Const); ok {
+				fmt.Fprintln(h, "=", constObj.Val().ExactString())
+			}
+			if named, ok := obj.Type().(*types.Named); ok && isType && !typeName.IsAlias() {
+				for method := range named.Methods() {
+					fmt.Fprintln(h, types.ObjectString(method, qualifier))
+				}
+			}
+		}
+		declKey = hex.EncodeToString(h.Sum(nil))
+		b.apiKeys[pkg.PkgPath] = declKey
+	}
+
+	// The async methods are known once the package is compiled or reused.
+	h := sha256.New()
+	fmt.Fprintln(h, declKey)
+	if metadata := b.metadata(pkg.PkgPath); metadata != nil {
+		for _, name := range slices.Sorted(maps.Keys(metadata.AsyncMethods)) {
+			fmt.Fprintln(h, "async", name)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// metadata returns the metadata of the package compiled in this build, or
+// found before compiling it.
+func (b *incrementalBuild) metadata(pkgPath string) *PackageMetadata {
+	if metadata, ok := b.compiled[pkgPath]; ok {
+		return metadata
+	}
+	return b.previous[pkgPath]
+}
+
+// packageInputFiles returns the files the generated code of pkg depends on:
+// its Go files, embedded files and TypeScript files copied to the output.
+func packageInputFiles(pkg *packages.Package) []string {
+	files := slices.Concat(pkg.CompiledGoFiles, pkg.EmbedFiles)
+	seen := make(map[string]bool)
+	for i, file := range pkg.CompiledGoFiles {
+		pkgDir := filepath.Dir(file)
+		if pbTs := strings.TrimSuffix(file, ".pb.go") + ".pb.ts"; strings.HasSuffix(file, ".pb.go") {
+			if _, err := os.Stat(pbTs); err == nil {
+				files = append(files, pbTs)
+			}
+		}
+		if i >= len(pkg.Syntax) {
+			continue
+		}
+		for _, decl := range pkg.Syntax[i].Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			ext, err := parseExternDirective(funcDecl.Doc, funcDecl.Name.Name)
+			if err != nil || ext == nil || !ext.isLocal() {
+				continue
+			}
+			module := filepath.Join(pkgDir, filepath.FromSlash(path.Clean(ext.module)))
+			if !seen[module] {
+				seen[module] = true
+				files = append(files, module)
+			}
+		}
+	}
+	return files
+}
+
+// dependencies returns the transitive dependencies of pkg.
+func (b *incrementalBuild) dependencies(pkg *packages.Package) map[string]bool {
+	if deps, ok := b.deps[pkg.PkgPath]; ok {
+		return deps
+	}
+	deps := make(map[string]bool)
+	for _, imp := range pkg.Imports {
+		b.pkgs[imp.PkgPath] = imp
+		deps[imp.PkgPath] = true
+		for dep := range b.dependencies(imp) {
+			deps[dep] = true
+		}
+	}
+	b.deps[pkg.PkgPath] = deps
+	return deps
+}
+
+// isUpToDate reports whether the package compiled with metadata has the key
+// and all its generated files.
+func isUpToDate(outputPath string, metadata *PackageMetadata, key string) bool {
+	if metadata == nil || metadata.Key != key {
+		return false
+	}
+	for _, file := range metadata.Files {
+		if _, err := os.Stat(filepath.Join(outputPath, filepath.FromSlash(file))); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// compileIncremental compiles the packages pkgs which are not up to date and
+// writes their metadata, see above.
+func (c *Compiler) compileIncremental(
+	ctx context.Context,
+	pkgs []*packages.Package,
+	allPackages map[string]*packages.Package,
+	patternPkgPaths []string,
+	result *CompilationResult,
+) error {
+	b, err := newIncrementalBuild(c, patternPkgPaths)
+	if err != nil {
+		return err
+	}
+
+	var compiledPkgs []*packages.Package
+	compile := func(pkg *packages.Package) error {
+		pkgCompiler, err := NewPackageCompiler(c.le, &c.config, pkg, allPackages)
+		if err != nil {
+			return fmt.Errorf("failed to create package compiler for %s: %w", pkg.PkgPath, err)
+		}
+		pkgCompiler.overrides = c.overrides
+		err = pkgCompiler.Compile(ctx)
+		if err := addDiagnostics(result, pkgCompiler.diagnostics, err); err != nil {
+			return fmt.Errorf("failed to compile package %s: %w", pkg.PkgPath, err)
+		}
+		if err != nil {
+			// Do not reuse the output of the failed compile
+			if err := os.Remove(filepath.Join(pkgCompiler.outputPath, packageMetadataFile)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		}
+		b.compiled[pkg.PkgPath] = pkgCompiler.metadata()
+		compiledPkgs = append(compiledPkgs, pkg)
+		c.le.Info(pkg.PkgPath)
+		result.CompiledPackages = append(result.CompiledPackages, pkg.PkgPath)
+		return nil
+	}
+
+	var reused []*packages.Package
+	for _, pkg := range sortPackagesByImports(pkgs) {
+		key, err := b.packageKey(pkg)
+		if err != nil {
+			return fmt.Errorf("failed to hash package %s: %w", pkg.PkgPath, err)
+		}
+		outputPath := ComputeModulePath(c.config.OutputPath, pkg.PkgPath)
+		metadata, err := ReadPackageMetadata(outputPath)
+		if err != nil {
+			c.le.WithError(err).Warnf("ignoring invalid metadata of package %s", pkg.PkgPath)
+			metadata = nil
+		}
+		b.previous[pkg.PkgPath] = metadata
+		if isUpToDate(outputPath, metadata, key) {
+			reused = append(reused, pkg)
+			continue
+		}
+		if err := compile(pkg); err != nil {
+			return err
+		}
+	}
+
+	// Compile the reused packages depending on the compiled ones until none
+	// is affected anymore.
+	for {
+		var stale []*packages.Package
+		remaining := reused[:0]
+		for _, pkg := range reused {
+			if b.isStale(pkg) {
+				stale = append(stale, pkg)
+			} else {
+				remaining = append(remaining, pkg)
+			}
+		}
+		reused = remaining
+		if len(stale) == 0 {
+			break
+		}
+		for _, pkg := range stale {
+			if err := compile(pkg); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The keys of the compiled packages cover the final async methods of
+	// their dependencies.
+	for _, pkg := range compiledPkgs {
+		metadata := b.compiled[pkg.PkgPath]
+		key, err := b.packageKey(pkg)
+		if err != nil {
+			return fmt.Errorf("failed to hash package %s: %w", pkg.PkgPath, err)
+		}
+		metadata.Key = key
+		outputPath := ComputeModulePath(c.config.OutputPath, pkg.PkgPath)
+		if err := writePackageMetadata(outputPath, metadata); err != nil {
+			return fmt.Errorf("failed to write metadata of package %s: %w", pkg.PkgPath, err)
+		}
+	}
+
+	for _, pkg := range reused {
+		c.le.Debugf("reusing up to date package %s", pkg.PkgPath)
+		result.ReusedPackages = append(result.ReusedPackages, pkg.PkgPath)
+	}
+	return nil
+}
+
+// isStale reports whether the up to date package pkg must be compiled again:
+// a package implementing its interfaces outside of its dependencies was
+// compiled, or the async methods of a compiled dependency changed.
+func (b *incrementalBuild) isStale(pkg *packages.Package) bool {
+	deps := b.dependencies(pkg)
+	for _, impls := range b.previous[pkg.PkgPath].InterfaceImplementations {
+		for _, impl := range impls {
+			implPkgPath := impl[:strings.LastIndex(impl, ".")]
+			if implPkgPath != pkg.PkgPath && !deps[implPkgPath] && b.compiled[implPkgPath] != nil {
+				return true
+			}
+		}
+	}
+	for pkgPath, current := range b.compiled {
+		if !deps[pkgPath] {
+			continue
+		}
+		var before map[string]bool
+		if prev := b.previous[pkgPath]; prev != nil {
+			before = prev.AsyncMethods
+		}
+		if !asyncMethodsEqual(before, current.AsyncMethods) {
+			return true
+		}
+	}
+	return false
+}
+
+// asyncMethodsEqual reports whether the async methods a and b are the same.
+func asyncMethodsEqual(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// sortPackagesByImports returns pkgs sorted so that packages come after the
+// packages they import.
+func sortPackagesByImports(pkgs []*packages.Package) []*packages.Package {
+	sorted := make([]*packages.Package, 0, len(pkgs))
+	included := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		included[pkg.PkgPath] = true
+	}
+	visited := make(map[string]bool, len(pkgs))
+	var visit func(pkg *packages.Package)
+	visit = func(pkg *packages.Package) {
+		if visited[pkg.PkgPath] {
+			return
+		}
+		visited[pkg.PkgPath] = true
+		importPaths := make([]string, 0, len(pkg.Imports))
+		for importPath := range pkg.Imports {
+			importPaths = append(importPaths, importPath)
+		}
+		sort.Strings(importPaths)
+		for _, importPath := range importPaths {
+			visit(pkg.Imports[importPath])
+		}
+		if included[pkg.PkgPath] {
+			sorted = append(sorted, pkg)
+		}
+	}
+	for _, pkg := range pkgs {
+		visit(pkg)
+	}
+	return sorted
+}
+
+// writePackageMetadata writes the metadata of the package compiled to
+// outputPath.
+func writePackageMetadata(outputPath string, metadata *PackageMetadata) error {
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+	return os.WriteFile(filepath.Join(outputPath, packageMetadataFile), content, 0o644)
+}
+
+// metadata returns the metadata of the compiled package, without its key.
+func (c *PackageCompiler) metadata() *PackageMetadata {
+	metadata := &PackageMetadata{
+		Version: packageMetadataVersion,
+		PkgPath: c.pkg.PkgPath,
+		Files:   []string{"index.ts"},
+	}
+	for _, fileName := range c.compiledFiles {
+		metadata.Files = append(metadata.Files, fileName+".ts")
+	}
+	metadata.Files = append(metadata.Files, c.extraFiles...)
+
+	// Async functions and methods
+	visitor := &analysisVisitor{analysis: c.analysis, pkg: c.pkg}
+	for _, file := range c.pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			methodKey := visitor.getMethodKey(funcDecl, c.pkg)
+			if !c.analysis.MethodAsyncStatus[methodKey] {
+				continue
+			}
+			if metadata.AsyncMethods == nil {
+				metadata.AsyncMethods = make(map[string]bool)
+			}
+			name := methodKey.MethodName
+			if methodKey.ReceiverType != "" {
+				name = methodKey.ReceiverType + "." + name
+			}
+			metadata.AsyncMethods[name] = true
+		}
+	}
+
+	// Names of the interfaces
+	interfaceNames := make(map[string]string)
+	scope := c.pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if iface, ok := typeName.Type().Underlying().(*types.Interface); ok {
+			if _, exists := interfaceNames[iface.String()]; !exists {
+				interfaceNames[iface.String()] = name
+			}
+		}
+	}
+
+	// Interface implementations
+	for key, implementations := range c.analysis.InterfaceImplementations {
+		iface, ok := interfaceNames[key.InterfaceType]
+		if !ok {
+			iface = key.InterfaceType
+		}
+		for _, impl := range implementations {
+			implPkg := impl.StructType.Obj().Pkg()
+			if implPkg == nil {
+				continue
+			}
+			if metadata.InterfaceImplementations == nil {
+				metadata.InterfaceImplementations = make(map[string][]string)
+			}
+			implName := implPkg.Path() + "." + impl.StructType.Obj().Name()
+			if !slices.Contains(metadata.InterfaceImplementations[iface], implName) {
+				metadata.InterfaceImplementations[iface] = append(metadata.InterfaceImplementations[iface], implName)
+			}
+		}
+	}
+	for _, impls := range metadata.InterfaceImplementations {
+		sort.Strings(impls)
+	}
+
+	return metadata
+}
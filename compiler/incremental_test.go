@@ -0,0 +1,170 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompileIncremental(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"lib/lib.go": `package lib
+
+// Doer does something.
+type Doer interface {
+	Do() int
+}
+
+// Run calls d.Do.
+func Run(d Doer) int {
+	return d.Do()
+}
+`,
+		"impl/impl.go": `package impl
+
+// Impl implements lib.Doer.
+type Impl struct{}
+
+// Do returns 1.
+func (i Impl) Do() int { return 1 }
+`,
+		"main.go": `package main
+
+import (
+	"example.com/app/impl"
+	"example.com/app/lib"
+)
+
+func main() {
+	println(lib.Run(impl.Impl{}))
+}
+`,
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	compile := func() *CompilationResult {
+		t.Helper()
+		comp, err := NewCompiler(&Config{
+			Dir:         dir,
+			OutputPath:  outputDir,
+			Incremental: true,
+		}, logrus.NewEntry(logrus.New()), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := comp.CompilePackages(context.Background(), "./...")
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(result.CompiledPackages)
+		slices.Sort(result.ReusedPackages)
+		return result
+	}
+	libOut := filepath.Join(outputDir, "@goscript", "example.com", "app", "lib")
+	readMetadata := func() *PackageMetadata {
+		t.Helper()
+		metadata, err := ReadPackageMetadata(libOut)
+		if err != nil || metadata == nil {
+			t.Fatalf("ReadPackageMetadata() = %v, %v", metadata, err)
+		}
+		return metadata
+	}
+
+	result := compile()
+	if want := []string{"example.com/app", "example.com/app/impl", "example.com/app/lib"}; !slices.Equal(result.CompiledPackages, want) {
+		t.Fatalf("first compile: CompiledPackages = %v, want %v", result.CompiledPackages, want)
+	}
+	metadata := readMetadata()
+	if !slices.Equal(metadata.Files, []string{"index.ts", "lib.gs.ts"}) {
+		t.Errorf("Files = %v", metadata.Files)
+	}
+	if impls := metadata.InterfaceImplementations["Doer"]; !slices.Equal(impls, []string{"example.com/app/impl.Impl"}) {
+		t.Errorf("InterfaceImplementations = %v", metadata.InterfaceImplementations)
+	}
+	if len(metadata.AsyncMethods) != 0 {
+		t.Errorf("AsyncMethods = %v, want none", metadata.AsyncMethods)
+	}
+
+	result = compile()
+	if len(result.CompiledPackages) != 0 || len(result.ReusedPackages) != 3 {
+		t.Fatalf("unchanged compile: CompiledPackages = %v, ReusedPackages = %v", result.CompiledPackages, result.ReusedPackages)
+	}
+
+	writeTestFile(t, dir, "main.go", `package main
+
+import (
+	"example.com/app/impl"
+	"example.com/app/lib"
+)
+
+func main() {
+	println(lib.Run(impl.Impl{}) + 1)
+}
+`)
+	result = compile()
+	if want := []string{"example.com/app"}; !slices.Equal(result.CompiledPackages, want) {
+		t.Fatalf("main changed: CompiledPackages = %v, want %v", result.CompiledPackages, want)
+	}
+
+	// The body of lib.Run changes without changing its API or making it
+	// async, so the packages importing lib are reused.
+	writeTestFile(t, dir, "lib/lib.go", `package lib
+
+// Doer does something.
+type Doer interface {
+	Do() int
+}
+
+// Run calls d.Do.
+func Run(d Doer) int {
+	n := d.Do()
+	return n
+}
+`)
+	result = compile()
+	if want := []string{"example.com/app/lib"}; !slices.Equal(result.CompiledPackages, want) {
+		t.Fatalf("lib body changed: CompiledPackages = %v, want %v", result.CompiledPackages, want)
+	}
+	if want := []string{"example.com/app", "example.com/app/impl"}; !slices.Equal(result.ReusedPackages, want) {
+		t.Fatalf("lib body changed: ReusedPackages = %v, want %v", result.ReusedPackages, want)
+	}
+
+	// The method implementing lib.Doer becomes async, so lib is compiled
+	// again although it did not change.
+	writeTestFile(t, dir, "impl/impl.go", `package impl
+
+// Impl implements lib.Doer.
+type Impl struct{}
+
+// Do returns 1.
+func (i Impl) Do() int {
+	ch := make(chan int, 1)
+	ch <- 1
+	return <-ch
+}
+`)
+	result = compile()
+	if want := []string{"example.com/app", "example.com/app/impl", "example.com/app/lib"}; !slices.Equal(result.CompiledPackages, want) {
+		t.Fatalf("impl changed: CompiledPackages = %v, want %v", result.CompiledPackages, want)
+	}
+	if metadata := readMetadata(); !metadata.AsyncMethods["Run"] {
+		t.Errorf("AsyncMethods = %v, want Run", metadata.AsyncMethods)
+	}
+	libTs, err := os.ReadFile(filepath.Join(libOut, "lib.gs.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(libTs), "await d!.Do()") {
+		t.Errorf("call of the async implementation is not awaited:\n%s", libTs)
+	}
+
+	result = compile()
+	if len(result.CompiledPackages) != 0 {
+		t.Fatalf("unchanged compile after impl changed: CompiledPackages = %v", result.CompiledPackages)
+	}
+}
@@ -3,6 +3,7 @@ package compiler
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"regexp"
 	"strconv"
@@ -13,10 +14,10 @@ import (
 // TypeScript equivalent.
 //   - Character literals (e.g., `'a'`, `'\n'`) are translated to their numeric
 //     Unicode code point (e.g., `97`, `10`). Escape sequences are handled.
-//   - Integer, float, imaginary, and string literals are written directly as their
+//   - Integer, float, and string literals are written directly as their
 //     `exp.Value` string, which typically corresponds to valid TypeScript syntax
-//     (e.g., `123`, `3.14`, `"hello"`). Imaginary literals might need special
-//     handling if they are to be fully supported beyond direct string output.
+//     (e.g., `123`, `3.14`, `"hello"`).
+//   - Imaginary literals (e.g., `2i`) are written as `$.complex(0, 2)`.
 //   - Legacy octal literals (e.g., `0777`) are converted to modern TypeScript
 //     octal syntax (e.g., `0o777`) to avoid ES module compatibility issues.
 func (c *GoToTSCompiler) WriteBasicLit(exp *ast.BasicLit) {
@@ -88,8 +89,10 @@ func (c *GoToTSCompiler) WriteBasicLit(exp *ast.BasicLit) {
 			// Regular string literal (double quotes) - write as-is
 			c.tsw.WriteLiterally(value)
 		}
+	} else if exp.Kind == token.IMAG {
+		c.writeComplexConstant(constant.MakeFromLiteral(exp.Value, exp.Kind, 0), c.pkg.TypesInfo.TypeOf(exp))
 	} else {
-		// Other literals (FLOAT, IMAG)
+		// Other literals (FLOAT)
 		c.tsw.WriteLiterally(exp.Value)
 	}
 }
@@ -120,6 +123,7 @@ func (c *GoToTSCompiler) WriteFuncLitValue(exp *ast.FuncLit) error {
 
 	// Determine if the function literal should be async
 	isAsync := c.analysis.IsFuncLitAsync(exp)
+	c.markAsyncFunc(exp, isAsync)
 
 	if isAsync {
 		c.tsw.WriteLiterally("async ")
@@ -0,0 +1,76 @@
+package compiler
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// Maps are represented by the native JavaScript Map, which compares keys with
+// SameValueZero. That matches Go's == for strings, integers, booleans and
+// pointers, but not for struct, array, interface and floating-point keys.
+// Maps with such keys are created as a $.HashMap, which is given a descriptor
+// of the key type and hashes keys like Go compares them:
+//
+//	map[string]int      $.makeMap<string, number>()
+//	map[Point]int       $.makeMap<Point, number>({ fields: { "X": 'value', "Y": 'value' } })
+//	map[[2]float64]bool $.makeMap<number[], boolean>({ elem: 'float' })
+//	map[any]int         $.makeMap<any, number>('interface')
+//	map[K]int           $.makeMap<K, number>($K.key)
+
+// mapKeyDescriptor returns the $.MapKeyType descriptor for a map key of type t.
+func (c *GoToTSCompiler) mapKeyDescriptor(t types.Type) string {
+	if tp, isTypeParam := t.(*types.TypeParam); isTypeParam {
+		// The key type of the type argument is in its dictionary, if any
+		if dictName, ok := c.typeDictName(tp); ok {
+			return dictName + ".key"
+		}
+		return "'value'"
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsFloat != 0:
+			return "'float'"
+		case u.Info()&types.IsComplex != 0:
+			return "'complex'"
+		}
+	case *types.Pointer, *types.Chan:
+		return "'pointer'"
+	case *types.Interface:
+		return "'interface'"
+	case *types.Array:
+		return "{ elem: " + c.mapKeyDescriptor(u.Elem()) + " }"
+	case *types.Struct:
+		fields := make([]string, 0, u.NumFields())
+		for i := range u.NumFields() {
+			field := u.Field(i)
+			fields = append(fields, strconv.Quote(field.Name())+": "+c.mapKeyDescriptor(field.Type()))
+		}
+		return "{ fields: { " + strings.Join(fields, ", ") + " } }"
+	}
+	return "'value'"
+}
+
+// mapKeyNeedsHashing reports whether maps with keys of type t must be
+// created as a $.HashMap to compare keys with Go semantics.
+func (c *GoToTSCompiler) mapKeyNeedsHashing(t types.Type) bool {
+	switch c.mapKeyDescriptor(t) {
+	case "'value'", "'pointer'":
+		return false
+	}
+	return true
+}
+
+// writeMakeMap writes the creation of an empty map of type mapType.
+func (c *GoToTSCompiler) writeMakeMap(mapType *types.Map) {
+	c.tsw.WriteLiterally("$.makeMap<")
+	c.WriteGoType(mapType.Key(), GoTypeContextGeneral) // Write the key type
+	c.tsw.WriteLiterally(", ")
+	c.WriteGoType(mapType.Elem(), GoTypeContextGeneral) // Write the value type
+	c.tsw.WriteLiterally(">(")
+	if c.mapKeyNeedsHashing(mapType.Key()) {
+		c.tsw.WriteLiterally(c.mapKeyDescriptor(mapType.Key()))
+	}
+	c.tsw.WriteLiterally(")")
+}
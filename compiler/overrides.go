@@ -0,0 +1,110 @@
+package compiler
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+
+	gs "github.com/aperturerobotics/goscript"
+)
+
+// overrides finds the handwritten TypeScript packages which replace Go
+// packages instead of being compiled.
+//
+// Each root holds packages in directories named after their import path,
+// like the gs/ directory embedded in goscript: a directory with an index.ts
+// replaces the Go package, and its optional meta.json lists the packages it
+// depends on and its async methods. The first root with a package provides
+// the whole package: the override directories of the Config in order, then
+// the packages embedded in goscript.
+type overrides struct {
+	roots []fs.FS
+
+	// pkgPaths caches the result of packages.
+	pkgPaths     []string
+	pkgPathsOnce sync.Once
+}
+
+// embeddedOverrides returns the overrides of the packages embedded in
+// goscript, shared by the analyses without override directories.
+var embeddedOverrides = sync.OnceValue(func() *overrides {
+	return newOverrides(nil)
+})
+
+// newOverrides returns the overrides in the directories dirs followed by the
+// packages embedded in goscript.
+func newOverrides(dirs []string) *overrides {
+	o := &overrides{}
+	for _, dir := range dirs {
+		o.roots = append(o.roots, os.DirFS(dir))
+	}
+	embedded, err := fs.Sub(gs.GsOverrides, "gs")
+	if err != nil {
+		panic(err)
+	}
+	o.roots = append(o.roots, embedded)
+	return o
+}
+
+// lookup returns the files of the package replacing pkgPath, or nil if the
+// package is compiled.
+func (o *overrides) lookup(pkgPath string) fs.FS {
+	if !fs.ValidPath(pkgPath) || pkgPath == "." {
+		return nil
+	}
+	for _, root := range o.roots {
+		if _, err := fs.Stat(root, path.Join(pkgPath, "index.ts")); err == nil {
+			sub, err := fs.Sub(root, pkgPath)
+			if err != nil {
+				return nil
+			}
+			return sub
+		}
+	}
+	return nil
+}
+
+// readMetadata reads the meta.json of the package replacing pkgPath. It
+// returns nil if the package is compiled or has no meta.json.
+func (o *overrides) readMetadata(pkgPath string) (*GsMetadata, error) {
+	pkgFS := o.lookup(pkgPath)
+	if pkgFS == nil {
+		return nil, nil
+	}
+	content, err := fs.ReadFile(pkgFS, "meta.json")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var metadata GsMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// packages returns the import paths of the packages with a meta.json in all
+// roots, including the packages shadowed by an earlier root. The roots are
+// walked once, the first time it is called.
+func (o *overrides) packages() []string {
+	o.pkgPathsOnce.Do(func() {
+		seen := make(map[string]bool)
+		for _, root := range o.roots {
+			_ = fs.WalkDir(root, ".", func(p string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || path.Base(p) != "meta.json" {
+					return nil
+				}
+				if pkgPath := path.Dir(p); pkgPath != "." && !seen[pkgPath] {
+					seen[pkgPath] = true
+					o.pkgPaths = append(o.pkgPaths, pkgPath)
+				}
+				return nil
+			})
+		}
+	})
+	return o.pkgPaths
+}
@@ -0,0 +1,171 @@
+package compiler
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestOverridesPrecedence(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeTestFile(t, first, "strings/index.ts", "// first\n")
+	writeTestFile(t, first, "example.com/lib/meta.json", "{}\n") // no index.ts
+	writeTestFile(t, second, "strings/index.ts", "// second\n")
+	writeTestFile(t, second, "example.com/lib/index.ts", "// second\n")
+	writeTestFile(t, second, "example.com/lib/meta.json", `{"asyncMethods": {"Client.Do": true}}`)
+
+	o := newOverrides([]string{first, second})
+	readIndex := func(pkgPath string) string {
+		pkgFS := o.lookup(pkgPath)
+		if pkgFS == nil {
+			return ""
+		}
+		content, err := fs.ReadFile(pkgFS, "index.ts")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	if got := readIndex("strings"); got != "// first\n" {
+		t.Errorf("strings index.ts = %q, want the first override", got)
+	}
+	if got := readIndex("example.com/lib"); got != "// second\n" {
+		t.Errorf("example.com/lib index.ts = %q, want the second override", got)
+	}
+	if o.lookup("bytes") == nil {
+		t.Error("built-in bytes package not found")
+	}
+	for _, pkgPath := range []string{"example.com", "example.com/other", "internal", "../strings", ""} {
+		if o.lookup(pkgPath) != nil {
+			t.Errorf("lookup(%q) found a package", pkgPath)
+		}
+	}
+
+	metadata, err := o.readMetadata("example.com/lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata == nil || !metadata.AsyncMethods["Client.Do"] {
+		t.Errorf("readMetadata(example.com/lib) = %+v, want the metadata of the second override", metadata)
+	}
+	if pkgPaths := o.packages(); !slices.Contains(pkgPaths, "example.com/lib") || !slices.Contains(pkgPaths, "sync") {
+		t.Errorf("packages() = %v, want example.com/lib and sync", pkgPaths)
+	}
+}
+
+func TestCompileWithOverrideDir(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"native/native.go": `package native
+
+import "unsafe"
+
+// Fetch reads the value at p.
+func Fetch(p unsafe.Pointer) int { return *(*int)(p) }
+`,
+		"main.go": `package main
+
+import "example.com/app/native"
+
+func main() {
+	println(native.Fetch(nil))
+}
+`,
+		"overrides/example.com/app/native/index.ts":  "export async function Fetch(p: any): Promise<number> {\n  return 0\n}\n",
+		"overrides/example.com/app/native/meta.json": `{"dependencies": ["errors"], "asyncMethods": {"Fetch": true}}`,
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	le := logrus.NewEntry(logrus.New())
+	comp, err := NewCompiler(&Config{
+		Dir:             dir,
+		OutputPath:      outputDir,
+		AllDependencies: true,
+		OverrideDirs:    []string{"overrides"},
+	}, le, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := comp.CompilePackages(context.Background(), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkgPath := range []string{"example.com/app/native", "errors"} {
+		if !slices.Contains(result.CopiedPackages, pkgPath) {
+			t.Errorf("CopiedPackages = %v, want %s", result.CopiedPackages, pkgPath)
+		}
+	}
+	if slices.Contains(result.CompiledPackages, "example.com/app/native") {
+		t.Error("overridden package example.com/app/native was compiled")
+	}
+
+	nativeOut := filepath.Join(outputDir, "@goscript", "example.com", "app", "native")
+	if _, err := os.Stat(filepath.Join(nativeOut, "index.ts")); err != nil {
+		t.Errorf("override not copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(nativeOut, "native.gs.ts")); err == nil {
+		t.Error("overridden package was compiled to native.gs.ts")
+	}
+
+	mainOut, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "main.gs.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mainOut), "await native.Fetch(") {
+		t.Errorf("call of the async override is not awaited:\n%s", mainOut)
+	}
+}
+
+func TestConfigOverrideDirs(t *testing.T) {
+	dir := t.TempDir()
+	conf := &Config{Dir: dir, OutputPath: "out", OverrideDirs: []string{"missing"}}
+	if err := conf.Validate(); err == nil {
+		t.Error("Validate() accepted a missing override directory")
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "overrides"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dirs := []string{"overrides"}
+	conf = &Config{Dir: dir, OutputPath: "out", OverrideDirs: dirs}
+	if err := conf.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if dirs[0] != "overrides" {
+		t.Error("Validate() modified the OverrideDirs of the caller")
+	}
+
+	comp, err := NewCompiler(conf, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "overrides"); comp.config.OverrideDirs[0] != want {
+		t.Errorf("OverrideDirs = %v, want [%s]", comp.config.OverrideDirs, want)
+	}
+	if dirs[0] != "overrides" {
+		t.Error("NewCompiler() modified the OverrideDirs of the caller")
+	}
+}
+
+func TestOverridesPackagesCached(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "example.com/lib/meta.json", "{}")
+	metaPath := filepath.Join(dir, "example.com", "lib", "meta.json")
+	o := newOverrides([]string{dir})
+	if pkgPaths := o.packages(); !slices.Contains(pkgPaths, "example.com/lib") {
+		t.Fatalf("packages() = %v, want example.com/lib", pkgPaths)
+	}
+
+	// The roots are only walked once
+	if err := os.Remove(metaPath); err != nil {
+		t.Fatal(err)
+	}
+	if pkgPaths := o.packages(); !slices.Contains(pkgPaths, "example.com/lib") {
+		t.Errorf("packages() = %v after removing the package, want the cached example.com/lib", pkgPaths)
+	}
+}
@@ -0,0 +1,311 @@
+package compiler
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Some Go code compiles with goscript but does not behave like in Go: goto
+// statements which cannot be lowered, integers beyond 2^53 in a number,
+// nil pointers losing their type in an interface, unsafe and the functions
+// handwritten packages do not implement. CheckPortability finds them with the
+// same analysis passes the compiler uses to generate the code, so that the
+// goscript vet command and the analyzer in compiler/analyzer report what the
+// compiler actually does.
+
+// Portability categories identify the kind of problem reported by a
+// PortabilityIssue.
+const (
+	// PortabilityGoto is the category of goto statements which cannot be
+	// lowered to labeled blocks and loops.
+	PortabilityGoto = "goto"
+	// PortabilityInt64 is the category of 64-bit integers represented as a
+	// number, which holds integers exactly only up to 2^53 and whose bitwise
+	// operators use 32 bits.
+	PortabilityInt64 = "int64"
+	// PortabilityTypedNil is the category of pointers which may be nil
+	// converted to an interface where they become a nil interface.
+	PortabilityTypedNil = "typed-nil"
+	// PortabilityUnsafe is the category of uses of package unsafe.
+	PortabilityUnsafe = "unsafe"
+	// PortabilityUnsupported is the category of calls of functions listed
+	// as unsupported in the meta.json of handwritten packages.
+	PortabilityUnsupported = "unsupported"
+)
+
+// int64Suggestion is the suggestion of the int64 and uint64 values which do
+// not fit in a number.
+const int64Suggestion = "compile with --int64-bigint to represent int64, uint64 and uintptr as bigint"
+
+// PortabilityIssue is Go code which compiles with goscript but does not behave
+// like in Go.
+type PortabilityIssue struct {
+	// Pos is the position of the code.
+	Pos token.Pos
+	// Category is the kind of problem, like PortabilityGoto.
+	Category string
+	// Message describes the problem.
+	Message string
+	// Suggestion describes how to work around the problem, if known.
+	Suggestion string
+}
+
+// CheckPortability returns the portability issues of the package pkg compiled
+// with config, which may be nil for the default options, sorted by position.
+// The package must have its syntax and type information.
+func CheckPortability(pkg *packages.Package, config *Config) []PortabilityIssue {
+	if config == nil {
+		config = &Config{}
+	}
+	analysis := NewAnalysis(nil)
+	if overrideDirs, err := config.absOverrideDirs(); err == nil && len(overrideDirs) != 0 {
+		analysis.overrides = newOverrides(overrideDirs)
+	}
+	analysis.LoadPackageMetadata()
+	analysis.analyzeGotos(pkg)
+	analysis.analyzeNonNilPointers(pkg)
+
+	p := &portabilityChecker{analysis: analysis, pkg: pkg, int64AsBigInt: config.Int64AsBigInt}
+	p.checkGotos()
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, p.visit)
+	}
+	slices.SortStableFunc(p.issues, func(a, b PortabilityIssue) int {
+		return cmp.Compare(a.Pos, b.Pos)
+	})
+	return p.issues
+}
+
+// portabilityChecker collects the portability issues of a package.
+type portabilityChecker struct {
+	analysis      *Analysis
+	pkg           *packages.Package
+	int64AsBigInt bool
+	issues        []PortabilityIssue
+}
+
+// report records an issue at pos.
+func (p *portabilityChecker) report(pos token.Pos, category, suggestion, format string, args ...any) {
+	p.issues = append(p.issues, PortabilityIssue{
+		Pos:        pos,
+		Category:   category,
+		Message:    fmt.Sprintf(format, args...),
+		Suggestion: suggestion,
+	})
+}
+
+// checkGotos reports the goto statements which the compiler cannot lower.
+func (p *portabilityChecker) checkGotos() {
+	reportErr := func(pos token.Pos, err error) {
+		var diagErr *diagnosticError
+		if errors.As(err, &diagErr) && diagErr.pos.IsValid() {
+			pos = diagErr.pos
+		}
+		diag := diagnosticFromError(p.pkg, err)
+		p.report(pos, PortabilityGoto, diag.Suggestion, "%s", diag.Message)
+	}
+	for owner, plan := range p.analysis.GotoBlocks {
+		if plan.Err != nil {
+			reportErr(owner.Pos(), plan.Err)
+		}
+	}
+	for stmt, branch := range p.analysis.GotoBranches {
+		if branch.Err != nil {
+			reportErr(stmt.Pos(), branch.Err)
+		}
+	}
+}
+
+// visit checks a node of the syntax tree.
+func (p *portabilityChecker) visit(n ast.Node) bool {
+	info := p.pkg.TypesInfo
+	switch n := n.(type) {
+	case ast.Expr:
+		// Constant expressions are written as their value, except for the
+		// calls of unsafe.Sizeof, Alignof and Offsetof.
+		if tv, ok := info.Types[n]; ok && tv.Value != nil {
+			p.checkConstant(n, tv)
+			ast.Inspect(n, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok {
+					p.checkUnsafe(sel)
+				}
+				return true
+			})
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			p.checkBitwise(n.OpPos, n.Op, info.TypeOf(n.X))
+		case *ast.UnaryExpr:
+			p.checkBitwise(n.OpPos, n.Op, info.TypeOf(n.X))
+		case *ast.SelectorExpr:
+			p.checkUnsafe(n)
+		case *ast.Ident:
+			p.checkUnsupported(n)
+		}
+	case *ast.AssignStmt:
+		switch n.Tok {
+		case token.ASSIGN, token.DEFINE:
+			// Single assignments are written with the conversion.
+			if len(n.Lhs) > 1 && len(n.Lhs) == len(n.Rhs) {
+				for i, rhs := range n.Rhs {
+					p.checkTypedNil(rhs, info.TypeOf(n.Lhs[i]))
+				}
+			}
+		default:
+			p.checkBitwise(n.TokPos, n.Tok, info.TypeOf(n.Lhs[0]))
+		}
+	}
+	return true
+}
+
+// typeUnderlying returns the underlying type of t, or nil if t is nil.
+func typeUnderlying(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	return t.Underlying()
+}
+
+// isNumber64 reports whether t is a 64-bit integer type represented as a
+// number: int and uint, and int64, uint64 and uintptr unless they are
+// represented as bigint.
+func (p *portabilityChecker) isNumber64(t types.Type) bool {
+	basic, ok := typeUnderlying(t).(*types.Basic)
+	if !ok {
+		return false
+	}
+	switch basic.Kind() {
+	case types.Int, types.Uint:
+		return true
+	case types.Int64, types.Uint64, types.Uintptr:
+		return !p.int64AsBigInt || !p.analysis.isBigIntType(t)
+	}
+	return false
+}
+
+// checkConstant reports the constants of 64-bit integer types beyond 2^53
+// written as a number.
+func (p *portabilityChecker) checkConstant(expr ast.Expr, tv types.TypeAndValue) {
+	if tv.Value.Kind() != constant.Int || !p.isNumber64(tv.Type) {
+		return
+	}
+	maxSafe := constant.MakeInt64(1<<53 - 1)
+	if constant.Compare(tv.Value, token.LEQ, maxSafe) && constant.Compare(tv.Value, token.GEQ, constant.UnaryOp(token.SUB, maxSafe, 0)) {
+		return
+	}
+	suggestion := int64Suggestion
+	if basic := tv.Type.Underlying().(*types.Basic); basic.Kind() == types.Int || basic.Kind() == types.Uint {
+		suggestion = "use int64 or uint64 and compile with --int64-bigint"
+	}
+	p.report(expr.Pos(), PortabilityInt64, suggestion,
+		"constant %s of type %s is beyond 2^53 and loses precision as a JavaScript number", tv.Value.ExactString(), goTypeName(tv.Type))
+}
+
+// checkBitwise reports the bitwise operators on int64, uint64 and uintptr
+// values represented as a number, which JavaScript performs on 32 bits.
+func (p *portabilityChecker) checkBitwise(pos token.Pos, op token.Token, t types.Type) {
+	switch op {
+	case token.AND, token.OR, token.XOR, token.AND_NOT, token.SHL, token.SHR,
+		token.AND_ASSIGN, token.OR_ASSIGN, token.XOR_ASSIGN, token.AND_NOT_ASSIGN, token.SHL_ASSIGN, token.SHR_ASSIGN:
+	default:
+		return
+	}
+	basic, ok := typeUnderlying(t).(*types.Basic)
+	if !ok || !p.isNumber64(t) {
+		return
+	}
+	switch basic.Kind() {
+	case types.Int64, types.Uint64, types.Uintptr:
+		p.report(pos, PortabilityInt64, int64Suggestion,
+			"operator %s on %s only uses the low 32 bits of a JavaScript number", op, goTypeName(t))
+	}
+}
+
+// checkUnsafe reports the uses of package unsafe.
+func (p *portabilityChecker) checkUnsafe(sel *ast.SelectorExpr) {
+	obj := p.pkg.TypesInfo.Uses[sel.Sel]
+	if obj == nil || obj.Pkg() != types.Unsafe {
+		return
+	}
+	p.report(sel.Pos(), PortabilityUnsafe,
+		"use a safe equivalent, or replace the package in an override directory",
+		"unsafe.%s is not supported: JavaScript has no pointer arithmetic or raw memory", obj.Name())
+}
+
+// checkUnsupported reports the uses of functions and methods of handwritten
+// packages listed as unsupported in their meta.json.
+func (p *portabilityChecker) checkUnsupported(ident *ast.Ident) {
+	fn, ok := p.pkg.TypesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return
+	}
+	reason, ok := p.analysis.UnsupportedFuncs[funcMethodKey(fn)]
+	if !ok {
+		return
+	}
+	p.report(ident.Pos(), PortabilityUnsupported, unsupportedSuggestion,
+		"%s is not supported by goscript: %s", fn.FullName(), reason)
+}
+
+// checkTypedNil reports expr if it is a pointer which may be nil converted to
+// the interface type target where the compiler does not write a typed nil.
+func (p *portabilityChecker) checkTypedNil(expr ast.Expr, target types.Type) {
+	if target == nil || !types.IsInterface(target) {
+		return
+	}
+	if _, isTypeParam := target.(*types.TypeParam); isTypeParam {
+		return
+	}
+	if !p.analysis.mayBeNilPointer(p.pkg.TypesInfo, expr) {
+		return
+	}
+	p.report(expr.Pos(), PortabilityTypedNil,
+		"assign the pointer to a variable of the interface type first, or check it for nil",
+		"%s which may be nil becomes a nil %s here, not an interface holding a nil pointer", goTypeName(p.pkg.TypesInfo.TypeOf(expr)), goTypeName(target))
+}
+
+// Vet loads the packages matched by patterns and returns the diagnostics of
+// their portability issues as warnings, see CheckPortability, along with the
+// errors loading them. With Config.AllDependencies the dependencies compiled
+// with the packages are checked as well.
+func (c *Compiler) Vet(ctx context.Context, patterns ...string) ([]Diagnostic, error) {
+	pkgs, patternPkgPaths, err := c.loadPackages(ctx, patterns)
+	if err != nil {
+		return nil, err
+	}
+	var diags []Diagnostic
+	for _, pkg := range pkgs {
+		// Dependencies replaced by handwritten packages are not compiled
+		if !slices.Contains(patternPkgPaths, pkg.PkgPath) && c.overrides.lookup(pkg.PkgPath) != nil {
+			continue
+		}
+		if len(pkg.Errors) != 0 {
+			diags = append(diags, packageDiagnostics(pkg)...)
+			continue
+		}
+		for _, issue := range CheckPortability(pkg, &c.config) {
+			position := pkg.Fset.Position(issue.Pos)
+			diags = append(diags, Diagnostic{
+				Severity:   SeverityWarning,
+				Code:       issue.Category,
+				Package:    pkg.PkgPath,
+				File:       position.Filename,
+				Line:       position.Line,
+				Column:     position.Column,
+				Message:    issue.Message,
+				Suggestion: issue.Suggestion,
+			})
+		}
+	}
+	return diags, nil
+}
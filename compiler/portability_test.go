@@ -0,0 +1,70 @@
+package compiler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestVet(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"main.go": `package main
+
+import "runtime"
+
+func main() {
+	var n int
+	runtime.SetFinalizer(&n, nil)
+	var x uint64 = 1<<63 + 1
+	var d uint = 1 << 60
+	println(x>>1, d)
+}
+`,
+	})
+
+	vet := func(int64AsBigInt bool) []Diagnostic {
+		t.Helper()
+		comp, err := NewCompiler(&Config{
+			Dir:           dir,
+			OutputPath:    filepath.Join(dir, "output"),
+			Int64AsBigInt: int64AsBigInt,
+		}, logrus.NewEntry(logrus.New()), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diags, err := comp.Vet(context.Background(), ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diags
+	}
+
+	type want struct {
+		code string
+		line int
+	}
+	check := func(diags []Diagnostic, wants []want) {
+		t.Helper()
+		if len(diags) != len(wants) {
+			t.Fatalf("Vet() = %+v, want %v", diags, wants)
+		}
+		for i, diag := range diags {
+			if diag.Severity != SeverityWarning || diag.Code != wants[i].code || diag.Line != wants[i].line || diag.File != filepath.Join(dir, "main.go") {
+				t.Errorf("diagnostic %d = %+v, want %v", i, diag, wants[i])
+			}
+		}
+	}
+	check(vet(false), []want{
+		{PortabilityUnsupported, 7},
+		{PortabilityInt64, 8},
+		{PortabilityInt64, 9},
+		{PortabilityInt64, 10},
+	})
+	// uint is a number even with bigints
+	check(vet(true), []want{
+		{PortabilityUnsupported, 7},
+		{PortabilityInt64, 9},
+	})
+}
@@ -0,0 +1,111 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Goroutines are async functions started with $.go, so a goroutine
+// only gives way to the others when it awaits. With Config.Preemptive the
+// loops of async functions check at each iteration whether the goroutine used
+// up its time slice, and yield to the event loop if so:
+//
+//	for {                  for (;;) {
+//		work()                 if ($.shouldYield()) await $.yieldSlice()
+//	}                          work()
+//	                       }
+//
+// Functions which are not async cannot await, their loops still run to
+// completion. Function literals started by go statements are made async when
+// they contain such a loop, so go func() { for { ... } }() workers are
+// preempted.
+
+// analyzeYieldLoops records the bodies of the loops of pkg together with the
+// function enclosing them. Loops over channels and iterator functions are
+// left out: the former await at each iteration, the body of the latter is a
+// callback.
+func (a *Analysis) analyzeYieldLoops(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				if fn.Body != nil {
+					a.findYieldLoops(pkg, fn, fn.Body)
+				}
+				continue
+			}
+			a.findYieldLoops(pkg, nil, decl)
+		}
+	}
+}
+
+// findYieldLoops records the loops in node, which is enclosed by the function
+// declaration or literal fn, or by no function if fn is nil.
+func (a *Analysis) findYieldLoops(pkg *packages.Package, fn ast.Node, node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			a.findYieldLoops(pkg, n, n.Body)
+			return false
+		case *ast.ForStmt:
+			if fn != nil {
+				a.YieldLoopBodies[n.Body] = fn
+			}
+		case *ast.RangeStmt:
+			if fn != nil && rangeMayYield(pkg, n) {
+				a.YieldLoopBodies[n.Body] = fn
+			}
+		}
+		return true
+	})
+}
+
+// rangeMayYield reports whether a range loop over the value n.X may yield.
+func rangeMayYield(pkg *packages.Package, n *ast.RangeStmt) bool {
+	if n.X == nil {
+		return false
+	}
+	t := pkg.TypesInfo.TypeOf(n.X)
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Chan, *types.Signature, *types.Interface:
+		return false
+	}
+	return true
+}
+
+// markAsyncFunc records whether the function declaration or literal fn is
+// written as an async function.
+func (c *GoToTSCompiler) markAsyncFunc(fn ast.Node, isAsync bool) {
+	c.asyncFuncs[fn] = isAsync
+}
+
+// hasYieldLoops reports whether the function literal fn contains a loop which
+// yields to other goroutines when fn is async.
+func (c *GoToTSCompiler) hasYieldLoops(fn *ast.FuncLit) bool {
+	if !c.config.Preemptive {
+		return false
+	}
+	for _, loopFn := range c.analysis.YieldLoopBodies {
+		if loopFn == fn {
+			return true
+		}
+	}
+	return false
+}
+
+// writeYieldPoint writes the preemption check at the start of the body of a
+// loop of an async function, see Config.Preemptive.
+func (c *GoToTSCompiler) writeYieldPoint(body *ast.BlockStmt) {
+	if !c.config.Preemptive {
+		return
+	}
+	fn, ok := c.analysis.YieldLoopBodies[body]
+	if !ok || !c.asyncFuncs[fn] {
+		return
+	}
+	c.tsw.WriteLine("if ($.shouldYield()) await $.yieldSlice()")
+}
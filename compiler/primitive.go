@@ -7,12 +7,14 @@ import "go/token"
 // `GoBuiltinToTypescript` for direct type name translation.
 //
 // Key mappings include:
-// - `bool` -> `boolean`
-// - `string` -> `string`
-// - `int`, `int8`, `int16`, `int32`, `rune` (alias for int32) -> `number`
-// - `uint`, `uint8` (`byte`), `uint16`, `uint32` -> `number`
-// - `int64`, `uint64` -> `bigint` (requires ES2020+ TypeScript target)
-// - `float32`, `float64` -> `number`
+//   - `bool` -> `boolean`
+//   - `string` -> `string`
+//   - `int`, `int8`, `int16`, `int32`, `rune` (alias for int32) -> `number`
+//   - `uint`, `uint8` (`byte`), `uint16`, `uint32` -> `number`
+//   - `int64`, `uint64`, `uintptr` -> `number`, or `bigint` if Config.Int64AsBigInt
+//     is set (see expr-bigint.go)
+//   - `float32`, `float64` -> `number`
+//   - `complex64`, `complex128` -> `$.Complex` (see expr-complex.go)
 //
 // This mapping assumes a target environment similar to GOOS=js, GOARCH=wasm,
 // where Go's `int` and `uint` are 32-bit and fit within TypeScript's `number`.
@@ -30,8 +32,7 @@ var goToTypescriptPrimitives = map[string]string{
 	"int32": "number",
 	"rune":  "number", // alias for int32
 
-	// TODO: add bigint support
-	// "int64": "bigint", // Requires TypeScript target >= ES2020
+	// bigint if Config.Int64AsBigInt is set, see isBigIntType
 	"int64": "number",
 
 	// Unsigned Integers
@@ -41,13 +42,17 @@ var goToTypescriptPrimitives = map[string]string{
 	"uint16": "number",
 	"uint32": "number",
 
-	// TODO: add bigint support
-	// "uint64": "bigint", // Requires TypeScript target >= ES2020
-	"uint64": "number",
+	// bigint if Config.Int64AsBigInt is set, see isBigIntType
+	"uint64":  "number",
+	"uintptr": "number",
 
 	// Floating Point Numbers
 	"float32": "number",
 	"float64": "number",
+
+	// Complex Numbers
+	"complex64":  "$.Complex",
+	"complex128": "$.Complex",
 }
 
 func isPrimitiveType(name string) bool {
@@ -0,0 +1,783 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/constant"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// With Config.Schemas, a schema.ts is written next to the index.ts of each
+// compiled package with exported structs. For each exported struct Name, it
+// exports NameJSONSchema, a JSON Schema document (draft 2020-12) of the JSON
+// encoding of the struct, and NameSchema, a zod schema validating it. Both
+// follow encoding/json:
+//
+//   - fields are named and skipped by their json tags, and the fields of
+//     embedded structs are promoted with the same rules for conflicts
+//   - fields with omitempty or omitzero and pointers are optional, and
+//     pointers, slices and maps are nullable
+//   - the string option encodes numbers and booleans in strings
+//   - []byte is a base64 string, time.Time an RFC 3339 string and types with
+//     a MarshalText method are strings
+//   - named integer and string types of the package with constants are
+//     enums of the values of their constants
+//
+// Types encoding/json cannot encode, like channels and functions, accept no
+// value, and types with a MarshalJSON method accept any value.
+
+// schemaFileName is the name of the schema file of a compiled package.
+const schemaFileName = "schema.ts"
+
+// jsonSchemaDialect is the JSON Schema version of the documents.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is a JSON Schema.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 jsonSchemaType         `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	Properties           jsonSchemaProperties   `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	PropertyNames        *jsonSchema            `json:"propertyNames,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+	Not                  *jsonSchema            `json:"not,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// jsonSchemaType is the type keyword of a JSON Schema: a type or a list of
+// types.
+type jsonSchemaType []string
+
+// MarshalJSON marshals a single type as a string.
+func (t jsonSchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// jsonSchemaProperty is a property of an object schema.
+type jsonSchemaProperty struct {
+	Name   string
+	Schema *jsonSchema
+}
+
+// jsonSchemaProperties are the properties of an object schema, in order.
+type jsonSchemaProperties []jsonSchemaProperty
+
+// MarshalJSON marshals the properties as an object keeping their order.
+func (p jsonSchemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// schemaRef returns the reference to the definition of the struct name.
+func schemaRef(name string) string {
+	return "#/$defs/" + name
+}
+
+// nullableSchema returns a schema accepting the values of s and null.
+func nullableSchema(s *jsonSchema) *jsonSchema {
+	if len(s.Type) != 0 && s.Enum == nil {
+		nullable := *s
+		nullable.Type = append(slices.Clone(s.Type), "null")
+		return &nullable
+	}
+	if s.Ref == "" && s.Enum == nil && s.Not == nil && s.AnyOf == nil {
+		// Accepts any value
+		return s
+	}
+	return &jsonSchema{AnyOf: []*jsonSchema{s, {Type: jsonSchemaType{"null"}}}}
+}
+
+// schemaWriter writes the schemas of the structs of a compiled package.
+type schemaWriter struct {
+	pkg *types.Package
+	// structs are the schemas of the exported structs of the package.
+	structs map[*types.TypeName]*jsonSchema
+	// refs are the exported structs of the package the schema of a struct
+	// refers to.
+	refs map[*types.TypeName][]*types.TypeName
+	// inlining are the structs whose schema is being inlined.
+	inlining map[*types.Named]bool
+}
+
+// generateSchemaFile writes the schema.ts of the package, if it has exported
+// structs.
+func (c *PackageCompiler) generateSchemaFile() (bool, error) {
+	w := &schemaWriter{
+		pkg:      c.pkg.Types,
+		structs:  make(map[*types.TypeName]*jsonSchema),
+		refs:     make(map[*types.TypeName][]*types.TypeName),
+		inlining: make(map[*types.Named]bool),
+	}
+	content, err := w.write()
+	if err != nil || content == "" {
+		return false, err
+	}
+	return true, os.WriteFile(filepath.Join(c.outputPath, schemaFileName), []byte(content), 0o644) //nolint:gosec
+}
+
+// schemaStruct returns the exported struct of the package t refers to, nil
+// if it is not one.
+func (w *schemaWriter) schemaStruct(t types.Type) *types.TypeName {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() != w.pkg || !named.Obj().Exported() || named.TypeParams().Len() != 0 {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named.Obj()
+}
+
+// write returns the content of the schema file, empty if the package has no
+// exported structs.
+func (w *schemaWriter) write() (string, error) {
+	var names []*types.TypeName
+	scope := w.pkg.Scope()
+	for _, name := range scope.Names() {
+		if obj := w.schemaStruct(scope.Lookup(name).Type()); obj != nil && obj.Name() == name {
+			names = append(names, obj)
+			w.structSchema(obj)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "// Schemas of the JSON encoding of the structs of the Go package %s,\n", w.pkg.Path())
+	out.WriteString("// following encoding/json.\n\n")
+	out.WriteString("import { z } from \"zod\"\n")
+
+	// The zod schemas, after the schemas they refer to. The schemas of
+	// recursive structs refer to the others lazily.
+	written := make(map[*types.TypeName]bool)
+	visiting := make(map[*types.TypeName]bool)
+	var visit func(obj *types.TypeName)
+	visit = func(obj *types.TypeName) {
+		if written[obj] || visiting[obj] {
+			return
+		}
+		visiting[obj] = true
+		for _, ref := range w.refs[obj] {
+			visit(ref)
+		}
+		name := obj.Name()
+		fmt.Fprintf(&out, "\n// %sSchema validates the JSON encoding of a %s.\n", name, name)
+		fmt.Fprintf(&out, "export const %sSchema = %s\n", name, zodSchema(w.structs[obj], "", func(ref string) bool {
+			return written[scope.Lookup(ref).(*types.TypeName)]
+		}))
+		written[obj] = true
+	}
+	for _, obj := range names {
+		visit(obj)
+	}
+
+	// The JSON Schema documents, with the definitions of the structs they
+	// refer to.
+	for _, obj := range names {
+		doc := &jsonSchema{
+			Schema: jsonSchemaDialect,
+			Ref:    schemaRef(obj.Name()),
+			Defs:   make(map[string]*jsonSchema),
+		}
+		var addDefs func(obj *types.TypeName)
+		addDefs = func(obj *types.TypeName) {
+			if _, ok := doc.Defs[obj.Name()]; ok {
+				return
+			}
+			doc.Defs[obj.Name()] = w.structs[obj]
+			for _, ref := range w.refs[obj] {
+				addDefs(ref)
+			}
+		}
+		addDefs(obj)
+
+		data, err := json.MarshalIndent(doc, "", "\t")
+		if err != nil {
+			return "", err
+		}
+		name := obj.Name()
+		fmt.Fprintf(&out, "\n// %sJSONSchema is the JSON Schema of the JSON encoding of a %s.\n", name, name)
+		fmt.Fprintf(&out, "export const %sJSONSchema = %s as const\n", name, data)
+	}
+	return out.String(), nil
+}
+
+// structSchema returns the schema of the exported struct obj.
+func (w *schemaWriter) structSchema(obj *types.TypeName) *jsonSchema {
+	if schema, ok := w.structs[obj]; ok {
+		return schema
+	}
+	schema := &jsonSchema{}
+	w.structs[obj] = schema
+
+	refs := make(map[*types.TypeName]bool)
+	*schema = *w.objectSchema(obj.Type().(*types.Named), refs)
+	schema.Title = obj.Pkg().Path() + "." + obj.Name()
+	for ref := range refs {
+		w.refs[obj] = append(w.refs[obj], ref)
+	}
+	slices.SortFunc(w.refs[obj], func(a, b *types.TypeName) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return schema
+}
+
+// objectSchema returns the schema of the JSON objects encoding the struct t.
+func (w *schemaWriter) objectSchema(t types.Type, refs map[*types.TypeName]bool) *jsonSchema {
+	schema := &jsonSchema{Type: jsonSchemaType{"object"}}
+	for _, field := range jsonFields(t) {
+		var fieldSchema *jsonSchema
+		if field.quoted {
+			fieldSchema = quotedSchema(field.typ)
+		} else {
+			fieldSchema = w.typeSchema(field.typ, refs)
+		}
+		schema.Properties = append(schema.Properties, jsonSchemaProperty{Name: field.name, Schema: fieldSchema})
+		if !field.optional {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	return schema
+}
+
+// typeSchema returns the schema of the JSON encoding of the values of type t.
+func (w *schemaWriter) typeSchema(t types.Type, refs map[*types.TypeName]bool) *jsonSchema {
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		switch {
+		case obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time":
+			return &jsonSchema{Type: jsonSchemaType{"string"}, Format: "date-time"}
+		case hasMethod(named, "MarshalJSON"):
+			return &jsonSchema{}
+		case hasMethod(named, "MarshalText"):
+			return &jsonSchema{Type: jsonSchemaType{"string"}}
+		}
+		if obj := w.schemaStruct(named); obj != nil {
+			w.structSchema(obj)
+			refs[obj] = true
+			return &jsonSchema{Ref: schemaRef(obj.Name())}
+		}
+		if enum := w.enumSchema(named); enum != nil {
+			return enum
+		}
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			if w.inlining[named] {
+				// Recursive struct of another package
+				return &jsonSchema{}
+			}
+			w.inlining[named] = true
+			defer delete(w.inlining, named)
+			return w.objectSchema(named, refs)
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return basicSchema(u)
+	case *types.Pointer:
+		return nullableSchema(w.typeSchema(u.Elem(), refs))
+	case *types.Slice:
+		if isJSONBytes(u.Elem()) {
+			return nullableSchema(&jsonSchema{Type: jsonSchemaType{"string"}, ContentEncoding: "base64"})
+		}
+		return nullableSchema(&jsonSchema{Type: jsonSchemaType{"array"}, Items: w.typeSchema(u.Elem(), refs)})
+	case *types.Array:
+		n := int(u.Len())
+		return &jsonSchema{Type: jsonSchemaType{"array"}, Items: w.typeSchema(u.Elem(), refs), MinItems: &n, MaxItems: &n}
+	case *types.Map:
+		schema := &jsonSchema{Type: jsonSchemaType{"object"}, AdditionalProperties: w.typeSchema(u.Elem(), refs)}
+		key := types.Unalias(u.Key())
+		basic, _ := key.Underlying().(*types.Basic)
+		switch {
+		case basic != nil && basic.Info()&types.IsString != 0:
+		case isNamed(key) && hasMethod(key.(*types.Named), "MarshalText"):
+		case basic != nil && basic.Info()&types.IsInteger != 0:
+			schema.PropertyNames = quotedSchema(key)
+		default:
+			return &jsonSchema{Not: &jsonSchema{}}
+		}
+		return nullableSchema(schema)
+	case *types.Struct:
+		return w.objectSchema(u, refs)
+	case *types.Interface:
+		return &jsonSchema{}
+	}
+	// Channels, functions, complex numbers and unsafe pointers
+	return &jsonSchema{Not: &jsonSchema{}}
+}
+
+// enumSchema returns the schema of the values of the constants of the named
+// integer or string type t of the package, nil if it is not one.
+func (w *schemaWriter) enumSchema(t *types.Named) *jsonSchema {
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok || t.Obj().Pkg() != w.pkg || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+		return nil
+	}
+	var consts []*types.Const
+	scope := w.pkg.Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), t) {
+			consts = append(consts, c)
+		}
+	}
+	if len(consts) == 0 {
+		return nil
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	schema := basicSchema(basic)
+	schema.Minimum, schema.Maximum = nil, nil
+	seen := make(map[string]bool)
+	for _, c := range consts {
+		key := c.Val().ExactString()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if c.Val().Kind() == constant.String {
+			schema.Enum = append(schema.Enum, constant.StringVal(c.Val()))
+		} else if v, exact := constant.Int64Val(c.Val()); exact {
+			schema.Enum = append(schema.Enum, v)
+		} else {
+			return nil
+		}
+	}
+	return schema
+}
+
+// basicSchema returns the schema of the JSON encoding of the basic type t.
+func basicSchema(t *types.Basic) *jsonSchema {
+	info := t.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return &jsonSchema{Type: jsonSchemaType{"boolean"}}
+	case info&types.IsString != 0:
+		return &jsonSchema{Type: jsonSchemaType{"string"}}
+	case info&types.IsInteger != 0:
+		schema := &jsonSchema{Type: jsonSchemaType{"integer"}}
+		bounds := func(minimum, maximum float64) {
+			schema.Minimum, schema.Maximum = &minimum, &maximum
+		}
+		switch t.Kind() {
+		case types.Int8:
+			bounds(-1<<7, 1<<7-1)
+		case types.Int16:
+			bounds(-1<<15, 1<<15-1)
+		case types.Int32:
+			bounds(-1<<31, 1<<31-1)
+		case types.Uint8:
+			bounds(0, 1<<8-1)
+		case types.Uint16:
+			bounds(0, 1<<16-1)
+		case types.Uint32:
+			bounds(0, 1<<32-1)
+		case types.Uint, types.Uint64, types.Uintptr:
+			minimum := 0.0
+			schema.Minimum = &minimum
+		}
+		return schema
+	case info&types.IsFloat != 0:
+		return &jsonSchema{Type: jsonSchemaType{"number"}}
+	}
+	return &jsonSchema{Not: &jsonSchema{}}
+}
+
+// quotedSchema returns the schema of the values of type t encoded in a JSON
+// string with the string option.
+func quotedSchema(t types.Type) *jsonSchema {
+	basic, _ := t.Underlying().(*types.Basic)
+	if basic == nil {
+		return &jsonSchema{Not: &jsonSchema{}}
+	}
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return &jsonSchema{Type: jsonSchemaType{"string"}, Enum: []any{"false", "true"}}
+	case info&types.IsString != 0:
+		return &jsonSchema{Type: jsonSchemaType{"string"}, Pattern: `^"(?:[^"\\]|\\.)*"$`}
+	case info&types.IsUnsigned != 0:
+		return &jsonSchema{Type: jsonSchemaType{"string"}, Pattern: `^(?:0|[1-9][0-9]*)$`}
+	case info&types.IsInteger != 0:
+		return &jsonSchema{Type: jsonSchemaType{"string"}, Pattern: `^-?(?:0|[1-9][0-9]*)$`}
+	case info&types.IsFloat != 0:
+		return &jsonSchema{Type: jsonSchemaType{"string"}, Pattern: `^-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?$`}
+	}
+	return &jsonSchema{Not: &jsonSchema{}}
+}
+
+// isNamed reports whether t is a named type.
+func isNamed(t types.Type) bool {
+	_, ok := t.(*types.Named)
+	return ok
+}
+
+// hasMethod reports whether the named type t or a pointer to it has the
+// method name.
+func hasMethod(t *types.Named, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, t.Obj().Pkg(), name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// isJSONBytes reports whether the slices of elements of type t are encoded as
+// base64 strings: t is a byte type without marshal methods.
+func isJSONBytes(t types.Type) bool {
+	t = types.Unalias(t)
+	if basic, ok := t.Underlying().(*types.Basic); !ok || basic.Kind() != types.Uint8 {
+		return false
+	}
+	named, ok := t.(*types.Named)
+	return !ok || (!hasMethod(named, "MarshalJSON") && !hasMethod(named, "MarshalText"))
+}
+
+// jsonField is a field of the JSON encoding of a struct.
+type jsonField struct {
+	name   string
+	tagged bool
+	index  []int
+	typ    types.Type
+	// quoted is set for the string option.
+	quoted bool
+	// optional is set if the field can be left out.
+	optional bool
+	// omitEmpty and omitZero are set for the omitempty and omitzero options.
+	omitEmpty, omitZero bool
+}
+
+// jsonFields returns the fields of the JSON encoding of the struct t, like
+// typeFields of encoding/json: the fields of untagged embedded structs are
+// promoted, and of the fields with the same name, the least nested one is
+// kept, the tagged one if there are several, and none if it is ambiguous.
+func jsonFields(t types.Type) []jsonField {
+	type embedded struct {
+		typ      types.Type
+		index    []int
+		optional bool
+	}
+
+	var fields []jsonField
+	next := []embedded{{typ: t}}
+	var count, nextCount map[types.Type]int
+	visited := make(map[types.Type]bool)
+	for len(next) > 0 {
+		current := next
+		next = nil
+		count, nextCount = nextCount, make(map[types.Type]int)
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+			st := f.typ.Underlying().(*types.Struct)
+			for i := range st.NumFields() {
+				sf := st.Field(i)
+				ft := types.Unalias(sf.Type())
+				ptr, isPtr := ft.(*types.Pointer)
+				if isPtr {
+					ft = types.Unalias(ptr.Elem())
+				}
+				_, isStruct := ft.Underlying().(*types.Struct)
+				if sf.Embedded() {
+					if !sf.Exported() && !isStruct {
+						// Embedded fields of unexported non-struct types
+						continue
+					}
+				} else if !sf.Exported() {
+					continue
+				}
+
+				tag := reflect.StructTag(st.Tag(i)).Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				if !isValidJSONTag(name) {
+					name = ""
+				}
+				index := append(slices.Clone(f.index), i)
+
+				if name != "" || !sf.Embedded() || !isStruct {
+					quoted := false
+					if hasJSONOption(opts, "string") {
+						if basic, ok := ft.Underlying().(*types.Basic); ok {
+							quoted = basic.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 && basic.Info()&types.IsComplex == 0
+						}
+					}
+					field := jsonField{
+						name:      name,
+						tagged:    name != "",
+						index:     index,
+						typ:       sf.Type(),
+						quoted:    quoted,
+						optional:  f.optional || isPtr || hasJSONOption(opts, "omitzero") || (hasJSONOption(opts, "omitempty") && canBeEmpty(sf.Type())),
+						omitEmpty: hasJSONOption(opts, "omitempty"),
+						omitZero:  hasJSONOption(opts, "omitzero"),
+					}
+					if field.name == "" {
+						field.name = sf.Name()
+					}
+					if quoted && isPtr {
+						field.typ = ft
+					}
+					fields = append(fields, field)
+					if count[f.typ] > 1 {
+						// Several embedded structs of the same type at the
+						// same depth: add the field twice so that it is
+						// dropped as ambiguous below.
+						fields = append(fields, field)
+					}
+					continue
+				}
+
+				// Promote the fields of the embedded struct
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, embedded{typ: ft, index: index, optional: f.optional || isPtr})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(fields, func(a, b jsonField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) - len(b.index)
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	// Keep the dominant field of each name
+	var out []jsonField
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		dominant := fields[i]
+		if j-i == 1 || len(fields[i+1].index) != len(dominant.index) || fields[i+1].tagged != dominant.tagged {
+			out = append(out, dominant)
+		}
+		i = j
+	}
+
+	slices.SortFunc(out, func(a, b jsonField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return out
+}
+
+// hasJSONOption reports whether the comma-separated json tag options have
+// the option name.
+func hasJSONOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidJSONTag reports whether name is a valid field name in a json tag.
+func isValidJSONTag(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// canBeEmpty reports whether values of type t can be empty for the omitempty
+// option: false, 0, "", nil and empty arrays, slices and maps.
+func canBeEmpty(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		return false
+	case *types.Array:
+		return u.Len() == 0
+	}
+	return true
+}
+
+// zodSchema returns the zod schema validating the values of the JSON schema
+// s, with the lines indented by indent. It refers to the zod schemas of
+// struct definitions lazily unless written returns true for their name.
+func zodSchema(s *jsonSchema, indent string, written func(name string) bool) string {
+	switch {
+	case s.Ref != "":
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		if written(name) {
+			return name + "Schema"
+		}
+		return "z.lazy((): z.ZodType => " + name + "Schema)"
+	case s.Not != nil:
+		return "z.never()"
+	case s.AnyOf != nil:
+		if len(s.AnyOf) == 2 && slices.Equal(s.AnyOf[1].Type, jsonSchemaType{"null"}) {
+			return zodSchema(s.AnyOf[0], indent, written) + ".nullable()"
+		}
+		options := make([]string, len(s.AnyOf))
+		for i, option := range s.AnyOf {
+			options[i] = zodSchema(option, indent, written)
+		}
+		return "z.union([" + strings.Join(options, ", ") + "])"
+	case s.Enum != nil:
+		values := make([]string, len(s.Enum))
+		strs := true
+		for i, v := range s.Enum {
+			data, _ := json.Marshal(v)
+			values[i] = string(data)
+			_, isString := v.(string)
+			strs = strs && isString
+		}
+		if strs {
+			return "z.enum([" + strings.Join(values, ", ") + "])"
+		}
+		if len(values) == 1 {
+			return "z.literal(" + values[0] + ")"
+		}
+		for i, v := range values {
+			values[i] = "z.literal(" + v + ")"
+		}
+		return "z.union([" + strings.Join(values, ", ") + "])"
+	case len(s.Type) == 0:
+		return "z.unknown()"
+	}
+
+	nullable := slices.Contains(s.Type, "null")
+	var out string
+	switch s.Type[0] {
+	case "null":
+		return "z.null()"
+	case "boolean":
+		out = "z.boolean()"
+	case "string":
+		switch {
+		case s.Format == "date-time":
+			out = "z.iso.datetime({ offset: true })"
+		case s.ContentEncoding == "base64":
+			out = "z.base64()"
+		case s.Pattern != "":
+			out = "z.string().regex(/" + s.Pattern + "/)"
+		default:
+			out = "z.string()"
+		}
+	case "integer", "number":
+		out = "z.number()"
+		if s.Type[0] == "integer" {
+			out += ".int()"
+		}
+		if s.Minimum != nil {
+			out += ".min(" + strconv.FormatFloat(*s.Minimum, 'f', -1, 64) + ")"
+		}
+		if s.Maximum != nil {
+			out += ".max(" + strconv.FormatFloat(*s.Maximum, 'f', -1, 64) + ")"
+		}
+	case "array":
+		out = "z.array(" + zodSchema(s.Items, indent, written) + ")"
+		if s.MinItems != nil && s.MaxItems != nil && *s.MinItems == *s.MaxItems {
+			out += ".length(" + strconv.Itoa(*s.MinItems) + ")"
+		}
+	case "object":
+		if s.AdditionalProperties != nil {
+			key := "z.string()"
+			if s.PropertyNames != nil {
+				key = zodSchema(s.PropertyNames, indent, written)
+			}
+			out = "z.record(" + key + ", " + zodSchema(s.AdditionalProperties, indent, written) + ")"
+			break
+		}
+		if len(s.Properties) == 0 {
+			out = "z.object({})"
+			break
+		}
+		var b strings.Builder
+		b.WriteString("z.object({\n")
+		for _, prop := range s.Properties {
+			b.WriteString(indent + "\t" + zodPropertyName(prop.Name) + ": " + zodSchema(prop.Schema, indent+"\t", written))
+			if !slices.Contains(s.Required, prop.Name) {
+				b.WriteString(".optional()")
+			}
+			b.WriteString(",\n")
+		}
+		b.WriteString(indent + "})")
+		out = b.String()
+	default:
+		out = "z.unknown()"
+	}
+	if nullable {
+		out += ".nullable()"
+	}
+	return out
+}
+
+// zodPropertyName returns the property name in a zod object, quoted unless
+// it is an identifier.
+func zodPropertyName(name string) string {
+	for i, c := range name {
+		if c != '_' && c != '$' && !unicode.IsLetter(c) && (i == 0 || !unicode.IsDigit(c)) {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
@@ -0,0 +1,551 @@
+package compiler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jsonFieldsSrc declares the same structs as the types in TestJSONFields.
+const jsonFieldsSrc = `package p
+
+type Inner struct {
+	A int
+	B int ` + "`json:\"b\"`" + `
+	C int
+}
+
+type Other struct {
+	C int
+	D int
+}
+
+type Named struct {
+	X int
+}
+
+type Outer struct {
+	Inner
+	*Other
+	Named ` + "`json:\"named\"`" + `
+	A      int ` + "`json:\"a,omitempty\"`" + `
+	B      int
+	Skip   int ` + "`json:\"-\"`" + `
+	Dash   int ` + "`json:\"-,\"`" + `
+	hidden int
+}
+`
+
+type jsonInner struct {
+	A int
+	B int `json:"b"`
+	C int
+}
+
+type jsonOther struct {
+	C int
+	D int
+}
+
+type jsonNamed struct {
+	X int
+}
+
+type jsonOuter struct {
+	jsonInner
+	*jsonOther
+	jsonNamed `json:"named"`
+	A         int `json:"a,omitempty"`
+	B         int
+	Skip      int `json:"-"`
+	Dash      int `json:"-,"`
+	hidden    int //nolint:unused
+}
+
+func TestJSONFields(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", jsonFieldsSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("p", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, field := range jsonFields(pkg.Scope().Lookup("Outer").Type()) {
+		names = append(names, field.name)
+	}
+
+	// The names of the fields encoding/json writes
+	data, err := json.Marshal(jsonOuter{A: 1, jsonOther: &jsonOther{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, key.(string))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !slices.Equal(names, want) {
+		t.Errorf("jsonFields = %v, encoding/json writes %v", names, want)
+	}
+}
+
+func TestCompileSchemas(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"todo/todo.go": `package todo
+
+import "time"
+
+// Priority is the priority of a todo.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityHigh
+)
+
+// Todo is a todo item.
+type Todo struct {
+	ID       int64     ` + "`json:\"id,string\"`" + `
+	Title    string    ` + "`json:\"title\"`" + `
+	Note     *string   ` + "`json:\"note\"`" + `
+	Priority Priority  ` + "`json:\"priority,omitempty\"`" + `
+	Due      time.Time ` + "`json:\"due\"`" + `
+	Tags     []string  ` + "`json:\"tags\"`" + `
+	Parent   *Todo     ` + "`json:\"parent,omitempty\"`" + `
+}
+`,
+		"empty/empty.go": "package empty\n\n// Answer is the answer.\nfunc Answer() int { return 42 }\n",
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	comp, err := NewCompiler(&Config{
+		Dir:        dir,
+		OutputPath: outputDir,
+		Schemas:    true,
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.CompilePackages(context.Background(), "./todo", "./empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "@goscript", "example.com", "app", "empty", "schema.ts")); !os.IsNotExist(err) {
+		t.Errorf("schema.ts written for a package without structs: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "todo", "schema.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := string(data)
+	for _, want := range []string{
+		`import { z } from "zod"`,
+		"export const TodoSchema = z.object({\n" +
+			"\tid: z.string().regex(/^-?(?:0|[1-9][0-9]*)$/),\n" +
+			"\ttitle: z.string(),\n" +
+			"\tnote: z.string().nullable().optional(),\n" +
+			"\tpriority: z.union([z.literal(0), z.literal(1)]).optional(),\n" +
+			"\tdue: z.iso.datetime({ offset: true }),\n" +
+			"\ttags: z.array(z.string()).nullable(),\n" +
+			"\tparent: z.lazy((): z.ZodType => TodoSchema).nullable().optional(),\n" +
+			"})",
+		"export const TodoJSONSchema = {\n\t\"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n\t\"$ref\": \"#/$defs/Todo\",",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("schema.ts does not contain %q:\n%s", want, schema)
+		}
+	}
+
+	// The JSON Schema document is valid JSON
+	doc := schema[strings.Index(schema, "export const TodoJSONSchema = ")+len("export const TodoJSONSchema = "):]
+	doc = doc[:strings.LastIndex(doc, " as const")]
+	var parsed struct {
+		Defs map[string]struct {
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(doc), &parsed); err != nil {
+		t.Fatalf("invalid JSON Schema: %v\n%s", err, doc)
+	}
+	if got, want := parsed.Defs["Todo"].Required, []string{"id", "title", "due", "tags"}; !slices.Equal(got, want) {
+		t.Errorf("required = %v, want %v", got, want)
+	}
+}
+
+// shopSrc declares the same types as the types of TestSchemaValidatesJSON.
+const shopSrc = `package shop
+
+import "time"
+
+// Status is the status of an item.
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+// Part is a part of an item.
+type Part struct {
+	Name   string  ` + "`json:\"name\"`" + `
+	Weight float32 ` + "`json:\"weight,omitempty\"`" + `
+}
+
+// Meta is embedded in Item.
+type Meta struct {
+	Owner string ` + "`json:\"owner\"`" + `
+}
+
+// Item is an item.
+type Item struct {
+	*Meta
+	SKU      string            ` + "`json:\"sku\"`" + `
+	Count    uint8             ` + "`json:\"count,omitempty\"`" + `
+	Price    float64           ` + "`json:\"price\"`" + `
+	ID       int64             ` + "`json:\"id,string\"`" + `
+	Data     []byte            ` + "`json:\"data\"`" + `
+	Thumb    []byte            ` + "`json:\"thumb,omitempty\"`" + `
+	Status   Status            ` + "`json:\"status\"`" + `
+	Labels   map[string]string ` + "`json:\"labels,omitempty\"`" + `
+	Sizes    map[int]bool      ` + "`json:\"sizes\"`" + `
+	Dims     [2]float64        ` + "`json:\"dims\"`" + `
+	Next     *Item             ` + "`json:\"next,omitempty\"`" + `
+	Parts    []Part            ` + "`json:\"parts\"`" + `
+	Created  time.Time         ` + "`json:\"created\"`" + `
+	Extra    any               ` + "`json:\"extra,omitempty\"`" + `
+	Internal string            ` + "`json:\"-\"`" + `
+	Plain    int
+	hidden   int
+}
+`
+
+type shopStatus string
+
+type shopPart struct {
+	Name   string  `json:"name"`
+	Weight float32 `json:"weight,omitempty"`
+}
+
+type shopMeta struct {
+	Owner string `json:"owner"`
+}
+
+type shopItem struct {
+	*shopMeta
+	SKU      string            `json:"sku"`
+	Count    uint8             `json:"count,omitempty"`
+	Price    float64           `json:"price"`
+	ID       int64             `json:"id,string"`
+	Data     []byte            `json:"data"`
+	Thumb    []byte            `json:"thumb,omitempty"`
+	Status   shopStatus        `json:"status"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Sizes    map[int]bool      `json:"sizes"`
+	Dims     [2]float64        `json:"dims"`
+	Next     *shopItem         `json:"next,omitempty"`
+	Parts    []shopPart        `json:"parts"`
+	Created  time.Time         `json:"created"`
+	Extra    any               `json:"extra,omitempty"`
+	Internal string            `json:"-"`
+	Plain    int
+	hidden   int //nolint:unused
+}
+
+func TestSchemaValidatesJSON(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{"shop/shop.go": shopSrc})
+	outputDir := filepath.Join(dir, "output")
+	comp, err := NewCompiler(&Config{
+		Dir:        dir,
+		OutputPath: outputDir,
+		Schemas:    true,
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.CompilePackages(context.Background(), "./shop"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "shop", "schema.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := schemaDocument(t, string(data), "Item")
+
+	created := time.Date(2024, 5, 6, 7, 8, 9, 10, time.FixedZone("", 2*60*60))
+	items := map[string]shopItem{
+		// The values of enums are the values of their constants
+		"zero": {Status: "open"},
+		"full": {
+			shopMeta: &shopMeta{Owner: "gopher"},
+			SKU:      "sku-1",
+			Count:    255,
+			Price:    9.99,
+			ID:       -1 << 62,
+			Data:     []byte{0, 1, 2, 0xfe, 0xff},
+			Thumb:    []byte("thumb"),
+			Status:   "closed",
+			Labels:   map[string]string{"color": "red"},
+			Sizes:    map[int]bool{-2: false, 10: true},
+			Dims:     [2]float64{1.5, -2},
+			Next:     &shopItem{SKU: "next", Data: []byte{}, Status: "open"},
+			Parts:    []shopPart{{Name: "wheel", Weight: 1.25}, {Name: "bell"}},
+			Created:  created,
+			Extra:    map[string]any{"any": []int{1}},
+			Internal: "internal",
+			Plain:    7,
+			hidden:   8,
+		},
+	}
+	for name, item := range items {
+		value := marshalJSONValue(t, item)
+		if err := validateJSONSchema(doc, doc, value, "$"); err != nil {
+			t.Errorf("%s: encoding/json output does not match the schema: %v\n%v", name, err, value)
+		}
+	}
+
+	// Changes to the encoding of the full item the schema rejects
+	for _, tc := range []struct {
+		name   string
+		change func(obj map[string]any)
+	}{
+		{"renamed field", func(obj map[string]any) { obj["SKU"] = obj["sku"]; delete(obj, "sku") }},
+		{"missing field", func(obj map[string]any) { delete(obj, "Plain") }},
+		{"skipped field", func(obj map[string]any) { obj["Internal"] = "internal" }},
+		{"unexported field", func(obj map[string]any) { obj["hidden"] = json.Number("8") }},
+		{"bytes as array", func(obj map[string]any) { obj["data"] = []any{json.Number("1")} }},
+		{"invalid base64", func(obj map[string]any) { obj["data"] = "not base64!" }},
+		{"unquoted int64", func(obj map[string]any) { obj["id"] = json.Number("1") }},
+		{"uint8 overflow", func(obj map[string]any) { obj["count"] = json.Number("256") }},
+		{"unknown enum value", func(obj map[string]any) { obj["status"] = "lost" }},
+		{"non-integer map key", func(obj map[string]any) { obj["sizes"] = map[string]any{"x": true} }},
+		{"short array", func(obj map[string]any) { obj["dims"] = []any{json.Number("1")} }},
+		{"invalid nested item", func(obj map[string]any) { obj["next"].(map[string]any)["sku"] = json.Number("1") }},
+		{"invalid part", func(obj map[string]any) { obj["parts"] = []any{map[string]any{"weight": json.Number("1")}} }},
+		{"invalid time", func(obj map[string]any) { obj["created"] = "yesterday" }},
+	} {
+		obj := marshalJSONValue(t, items["full"]).(map[string]any)
+		tc.change(obj)
+		if err := validateJSONSchema(doc, doc, obj, "$"); err == nil {
+			t.Errorf("%s: the schema accepts %v", tc.name, obj)
+		}
+	}
+}
+
+// schemaDocument returns the JSON Schema document of the struct name of the
+// schema.ts content, decoded with numbers as json.Number.
+func schemaDocument(t *testing.T, schema, name string) map[string]any {
+	t.Helper()
+	prefix := "export const " + name + "JSONSchema = "
+	start := strings.Index(schema, prefix)
+	if start < 0 {
+		t.Fatalf("schema.ts has no %sJSONSchema:\n%s", name, schema)
+	}
+	doc := schema[start+len(prefix):]
+	doc = doc[:strings.Index(doc, " as const")]
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	var parsed map[string]any
+	if err := dec.Decode(&parsed); err != nil {
+		t.Fatalf("invalid JSON Schema: %v\n%s", err, doc)
+	}
+	return parsed
+}
+
+// marshalJSONValue returns the encoding/json encoding of v, decoded with
+// numbers as json.Number.
+func marshalJSONValue(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+// validateJSONSchema validates the JSON value at path against the schema, a
+// schema of the JSON Schema document doc, with the keywords the compiler
+// writes. Unlike JSON Schema, objects with properties may not have other
+// properties: the schemas list all the fields encoding/json writes.
+func validateJSONSchema(doc, schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name, ok := strings.CutPrefix(ref, "#/$defs/")
+		def, _ := doc["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok || def == nil {
+			return fmt.Errorf("%s: unknown $ref %s", path, ref)
+		}
+		if err := validateJSONSchema(doc, def, value, path); err != nil {
+			return err
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		var errs []error
+		for _, s := range anyOf {
+			if err := validateJSONSchema(doc, s.(map[string]any), value, path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) == len(anyOf) {
+			return fmt.Errorf("%s: no schema of anyOf matches: %w", path, errors.Join(errs...))
+		}
+	}
+	if not, ok := schema["not"].(map[string]any); ok && validateJSONSchema(doc, not, value, path) == nil {
+		return fmt.Errorf("%s: %v matches the not schema", path, value)
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+		return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+	}
+
+	if typ, ok := schema["type"]; ok {
+		var names []string
+		switch typ := typ.(type) {
+		case string:
+			names = []string{typ}
+		case []any:
+			for _, name := range typ {
+				names = append(names, name.(string))
+			}
+		}
+		valueType := jsonValueType(value)
+		if !slices.Contains(names, valueType) && (valueType != "integer" || !slices.Contains(names, "number")) {
+			return fmt.Errorf("%s: %v is not of type %v", path, value, names)
+		}
+	}
+
+	switch value := value.(type) {
+	case json.Number:
+		n, _ := value.Float64()
+		if minimum, ok := schema["minimum"].(json.Number); ok {
+			if m, _ := minimum.Float64(); n < m {
+				return fmt.Errorf("%s: %v is less than %v", path, value, minimum)
+			}
+		}
+		if maximum, ok := schema["maximum"].(json.Number); ok {
+			if m, _ := maximum.Float64(); n > m {
+				return fmt.Errorf("%s: %v is greater than %v", path, value, maximum)
+			}
+		}
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(value) {
+			return fmt.Errorf("%s: %q does not match %s", path, value, pattern)
+		}
+		if schema["contentEncoding"] == "base64" {
+			if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+				return fmt.Errorf("%s: %q is not base64: %w", path, value, err)
+			}
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time: %w", path, value, err)
+			}
+		}
+	case []any:
+		if minItems, ok := schema["minItems"].(json.Number); ok {
+			if n, _ := minItems.Int64(); int64(len(value)) < n {
+				return fmt.Errorf("%s: fewer than %v items", path, minItems)
+			}
+		}
+		if maxItems, ok := schema["maxItems"].(json.Number); ok {
+			if n, _ := maxItems.Int64(); int64(len(value)) > n {
+				return fmt.Errorf("%s: more than %v items", path, maxItems)
+			}
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, elem := range value {
+				if err := validateJSONSchema(doc, items, elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		props, hasProps := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := value[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing property %s", path, name)
+			}
+		}
+		for name, elem := range value {
+			elemPath := path + "." + name
+			if propertyNames, ok := schema["propertyNames"].(map[string]any); ok {
+				if err := validateJSONSchema(doc, propertyNames, name, elemPath); err != nil {
+					return err
+				}
+			}
+			if prop, ok := props[name].(map[string]any); ok {
+				if err := validateJSONSchema(doc, prop, elem, elemPath); err != nil {
+					return err
+				}
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				if err := validateJSONSchema(doc, additional, elem, elemPath); err != nil {
+					return err
+				}
+			} else if hasProps {
+				return fmt.Errorf("%s: unexpected property", elemPath)
+			}
+		}
+	}
+	return nil
+}
+
+// jsonValueType returns the JSON Schema type of the decoded JSON value.
+func jsonValueType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(value.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return ""
+}
@@ -0,0 +1,397 @@
+package compiler
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// An interface with an unexported method can only be implemented in its own
+// package. With Config.SealedUnions, such a sealed interface implemented by
+// structs is written as the union of their classes, and each class gets a
+// __type discriminant with the name of the struct:
+//
+//	type Shape interface {            export type Shape = null | Circle | Square
+//		Area() int
+//		isShape()                     export class Circle {
+//	}                                   public get __type(): "Circle" {
+//	                                      return "Circle"
+//	type Circle struct{ R int }         }
+//	type Square struct{ S int }         ...
+//
+// Type switches over a sealed interface whose cases are pointers to structs
+// implementing it with pointer receivers are written as switch statements
+// over the discriminant, which TypeScript narrows:
+//
+//	switch v := s.(type) {            switch (s?.__type) {
+//	case *Circle:                       case "Circle": {
+//		return v.R                          let v: Circle | null = $.isTypedNil(s) ? null : s
+//	case nil:                               return v!.R
+//		return 0                          }
+//	default:                            default: {
+//		return -1                           if (s == null) {
+//	}                                         return 0
+//	                                      } else {
+//	                                        return -1
+//	                                      }
+//	                                    }
+//	                                  }
+//
+// Structs of other packages or functions embedding an implementation also
+// implement the interface, but are not part of the union and have no
+// discriminant, so they take the default case like the nil interface.
+
+// sealedSubjectName is the variable holding the subject of a type switch
+// written as a switch over the discriminant.
+const sealedSubjectName = "_subject"
+
+// SealedImplementation is a struct implementing a sealed interface.
+type SealedImplementation struct {
+	// Type is the struct type.
+	Type *types.TypeName
+	// PointerOnly is set if only the pointer to the struct implements the
+	// interface, so the interface never holds the struct value.
+	PointerOnly bool
+}
+
+// analyzeSealedInterfaces finds the sealed interfaces of pkg implemented by
+// structs, see Analysis.SealedInterfaces.
+func (a *Analysis) analyzeSealedInterfaces(pkg *packages.Package) {
+	scope := pkg.Types.Scope()
+	var named []*types.TypeName
+	for _, name := range scope.Names() {
+		if typeName, ok := scope.Lookup(name).(*types.TypeName); ok && !typeName.IsAlias() {
+			if t, ok := typeName.Type().(*types.Named); ok && t.TypeParams().Len() == 0 {
+				named = append(named, typeName)
+			}
+		}
+	}
+	// Files are not parsed in a fixed order, so order by file name first
+	slices.SortFunc(named, func(x, y *types.TypeName) int {
+		px, py := pkg.Fset.Position(x.Pos()), pkg.Fset.Position(y.Pos())
+		return cmp.Or(strings.Compare(px.Filename, py.Filename), px.Offset-py.Offset)
+	})
+
+	for _, ifaceName := range named {
+		iface, ok := ifaceName.Type().Underlying().(*types.Interface)
+		if !ok || !iface.IsMethodSet() || !hasUnexportedMethod(iface) {
+			continue
+		}
+		var impls []SealedImplementation
+		sealed := true
+		for _, typeName := range named {
+			t := typeName.Type()
+			if types.IsInterface(t) {
+				continue
+			}
+			valueImpl := types.Implements(t, iface)
+			if !valueImpl && !types.Implements(types.NewPointer(t), iface) {
+				continue
+			}
+			if _, isStruct := t.Underlying().(*types.Struct); !isStruct {
+				sealed = false
+				break
+			}
+			impls = append(impls, SealedImplementation{Type: typeName, PointerOnly: !valueImpl})
+		}
+		if sealed && len(impls) != 0 {
+			a.SealedInterfaces[ifaceName] = impls
+		}
+	}
+}
+
+// hasUnexportedMethod reports whether iface has an unexported method.
+func hasUnexportedMethod(iface *types.Interface) bool {
+	for method := range iface.Methods() {
+		if !method.Exported() {
+			return true
+		}
+	}
+	return false
+}
+
+// sealedImplementation returns the implementation t of the sealed
+// interface iface, if any.
+func (a *Analysis) sealedImplementation(iface *types.TypeName, t types.Type) (SealedImplementation, bool) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return SealedImplementation{}, false
+	}
+	for _, impl := range a.SealedInterfaces[iface] {
+		if impl.Type == named.Obj() {
+			return impl, true
+		}
+	}
+	return SealedImplementation{}, false
+}
+
+// isSealedImplementation reports whether the struct typeName implements a
+// sealed interface.
+func (a *Analysis) isSealedImplementation(typeName *types.TypeName) bool {
+	for _, impls := range a.SealedInterfaces {
+		for _, impl := range impls {
+			if impl.Type == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addSealedUnionImports adds the implementations of the sealed interfaces
+// declared in other files to the types imported by the files declaring the
+// interfaces, as the unions refer to them.
+func (p *PackageAnalysis) addSealedUnionImports(pkg *packages.Package, analysis *Analysis) {
+	fileOf := func(obj types.Object) string {
+		return strings.TrimSuffix(filepath.Base(pkg.Fset.Position(obj.Pos()).Filename), ".go")
+	}
+	for iface, impls := range analysis.SealedInterfaces {
+		ifaceFile := fileOf(iface)
+		for _, impl := range impls {
+			implFile := fileOf(impl.Type)
+			if implFile == ifaceFile {
+				continue
+			}
+			if p.TypeCalls[ifaceFile] == nil {
+				p.TypeCalls[ifaceFile] = make(map[string][]string)
+			}
+			if !slices.Contains(p.TypeCalls[ifaceFile][implFile], impl.Type.Name()) {
+				p.TypeCalls[ifaceFile][implFile] = append(p.TypeCalls[ifaceFile][implFile], impl.Type.Name())
+			}
+		}
+	}
+}
+
+// sealedInterfaceOf returns the sealed interface declared by a, if it is
+// written as a union.
+func (c *GoToTSCompiler) sealedInterfaceOf(a *ast.TypeSpec) *types.TypeName {
+	if !c.config.SealedUnions {
+		return nil
+	}
+	typeName, ok := c.pkg.TypesInfo.Defs[a.Name].(*types.TypeName)
+	if !ok || c.analysis.SealedInterfaces[typeName] == nil {
+		return nil
+	}
+	return typeName
+}
+
+// writeSealedUnion writes the union of the implementations of the sealed
+// interface iface.
+func (c *GoToTSCompiler) writeSealedUnion(iface *types.TypeName) {
+	c.tsw.WriteLiterally("null")
+	for _, impl := range c.analysis.SealedInterfaces[iface] {
+		c.tsw.WriteLiterally(" | ")
+		c.tsw.WriteLiterally(impl.Type.Name())
+	}
+}
+
+// writeSealedDiscriminant writes the __type discriminant of the class of
+// the struct declared by a if it implements a sealed interface.
+func (c *GoToTSCompiler) writeSealedDiscriminant(a *ast.TypeSpec) {
+	if !c.config.SealedUnions {
+		return
+	}
+	typeName, ok := c.pkg.TypesInfo.Defs[a.Name].(*types.TypeName)
+	if !ok || !c.analysis.isSealedImplementation(typeName) {
+		return
+	}
+	c.tsw.WriteLine("")
+	c.tsw.WriteLinef("public get __type(): %q {", typeName.Name())
+	c.tsw.Indent(1)
+	c.tsw.WriteLinef("return %q", typeName.Name())
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("}")
+}
+
+// sealedTypeSwitchCases returns the discriminants matched by the cases of
+// the type switch over subject, or nil if it is not written as a switch
+// over the discriminant: the subject must be a sealed interface written as
+// a union and the cases nil or pointers to its implementations which only
+// implement it with pointer receivers. The nil case must be alone in its
+// clause, as it is written in the default case.
+func (c *GoToTSCompiler) sealedTypeSwitchCases(stmt *ast.TypeSwitchStmt, subject ast.Expr) map[ast.Expr]string {
+	if !c.config.SealedUnions {
+		return nil
+	}
+	named, ok := c.pkg.TypesInfo.TypeOf(subject).(*types.Named)
+	if !ok || c.analysis.SealedInterfaces[named.Obj()] == nil {
+		return nil
+	}
+	cases := make(map[ast.Expr]string)
+	for _, stmt := range stmt.Body.List {
+		for _, typeExpr := range stmt.(*ast.CaseClause).List {
+			tv := c.pkg.TypesInfo.Types[typeExpr]
+			if tv.IsNil() {
+				if len(stmt.(*ast.CaseClause).List) != 1 {
+					return nil
+				}
+				cases[typeExpr] = "nil"
+				continue
+			}
+			ptr, ok := tv.Type.(*types.Pointer)
+			if !ok {
+				return nil
+			}
+			impl, ok := c.analysis.sealedImplementation(named.Obj(), ptr.Elem())
+			if !ok || !impl.PointerOnly {
+				return nil
+			}
+			cases[typeExpr] = fmt.Sprintf("%q", impl.Type.Name())
+		}
+	}
+	return cases
+}
+
+// writeSealedTypeSwitch writes the type switch stmt over subject as a
+// switch over the discriminant of its sealed interface, with the
+// discriminants of the cases returned by sealedTypeSwitchCases.
+func (c *GoToTSCompiler) writeSealedTypeSwitch(stmt *ast.TypeSwitchStmt, subject ast.Expr, cases map[ast.Expr]string) error {
+	// The subject is evaluated once
+	subjectName := sealedSubjectName
+	ident, isIdent := subject.(*ast.Ident)
+	if isIdent && !c.analysis.NeedsVarRefAccess(c.objectOfIdent(ident)) && !shadowsSubject(stmt, ident) {
+		subjectName = c.sanitizeIdentifier(ident.Name)
+		if renamed, ok := c.renamedVars[c.objectOfIdent(ident)]; ok {
+			subjectName = c.sanitizeIdentifier(renamed)
+		}
+	} else {
+		isIdent = false
+		c.tsw.WriteLine("{")
+		c.tsw.Indent(1)
+		c.tsw.WriteLiterallyf("const %s = ", subjectName)
+		if err := c.WriteValueExpr(subject); err != nil {
+			return fmt.Errorf("failed to write subject expression in type switch: %w", err)
+		}
+		c.tsw.WriteLine("")
+	}
+
+	// The nil interface and the values without a discriminant take the
+	// default case, which tests for nil.
+	var nilClause, defaultClause *ast.CaseClause
+	c.tsw.WriteLinef("switch (%s?.__type) {", subjectName)
+	c.tsw.Indent(1)
+	for _, clauseStmt := range stmt.Body.List {
+		clause := clauseStmt.(*ast.CaseClause)
+		switch {
+		case clause.List == nil:
+			defaultClause = clause
+			continue
+		case cases[clause.List[0]] == "nil":
+			nilClause = clause
+			continue
+		}
+		for i, typeExpr := range clause.List {
+			c.tsw.WriteLiterallyf("case %s:", cases[typeExpr])
+			if i == len(clause.List)-1 {
+				c.tsw.WriteLine(" {")
+			} else {
+				c.tsw.WriteLine("")
+			}
+		}
+		c.tsw.Indent(1)
+		if err := c.writeSealedCaseClause(clause, subjectName); err != nil {
+			return err
+		}
+		c.tsw.WriteLine("break")
+		c.tsw.Indent(-1)
+		c.tsw.WriteLine("}")
+	}
+	if nilClause != nil || defaultClause != nil {
+		c.tsw.WriteLine("default: {")
+		c.tsw.Indent(1)
+		if nilClause != nil {
+			c.tsw.WriteLinef("if (%s == null) {", subjectName)
+			c.tsw.Indent(1)
+			if err := c.writeSealedCaseClause(nilClause, subjectName); err != nil {
+				return err
+			}
+			c.tsw.Indent(-1)
+			if defaultClause != nil {
+				c.tsw.WriteLine("} else {")
+				c.tsw.Indent(1)
+				if err := c.writeSealedCaseClause(defaultClause, subjectName); err != nil {
+					return err
+				}
+				c.tsw.Indent(-1)
+			}
+			c.tsw.WriteLine("}")
+		} else if err := c.writeSealedCaseClause(defaultClause, subjectName); err != nil {
+			return err
+		}
+		c.tsw.WriteLine("break")
+		c.tsw.Indent(-1)
+		c.tsw.WriteLine("}")
+	}
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("}")
+	if !isIdent {
+		c.tsw.Indent(-1)
+		c.tsw.WriteLine("}")
+	}
+	return nil
+}
+
+// writeSealedCaseClause writes the variable and the body of the clause of a
+// type switch written by writeSealedTypeSwitch.
+func (c *GoToTSCompiler) writeSealedCaseClause(clause *ast.CaseClause, subjectName string) error {
+	// The variable of the case, if it is used
+	if caseVar, ok := c.pkg.TypesInfo.Implicits[clause].(*types.Var); ok && usesObject(c.pkg.TypesInfo, clause.Body, caseVar) {
+		c.tsw.WriteLiterallyf("let %s: ", c.sanitizeIdentifier(caseVar.Name()))
+		if c.analysis.NeedsVarRef(caseVar) {
+			c.tsw.WriteLiterally("$.VarRef<")
+			c.WriteGoType(caseVar.Type(), GoTypeContextGeneral)
+			c.tsw.WriteLiterallyf("> = $.varRef(")
+		} else {
+			c.WriteGoType(caseVar.Type(), GoTypeContextGeneral)
+			c.tsw.WriteLiterally(" = ")
+		}
+		if _, isPointer := caseVar.Type().(*types.Pointer); isPointer {
+			// A typed nil in the interface is a nil pointer
+			c.tsw.WriteLiterallyf("$.isTypedNil(%s) ? null : %s", subjectName, subjectName)
+		} else {
+			c.tsw.WriteLiterally(subjectName)
+		}
+		if c.analysis.NeedsVarRef(caseVar) {
+			c.tsw.WriteLiterally(")")
+		}
+		c.tsw.WriteLine("")
+	}
+
+	for i, bodyStmt := range clause.Body {
+		if err := c.writeGotoRegionsOpen(clause, i); err != nil {
+			return err
+		}
+		if err := c.WriteStmt(bodyStmt); err != nil {
+			return fmt.Errorf("failed to write statement in type switch case body: %w", err)
+		}
+		c.writeGotoRegionsClose(clause, i, bodyStmt)
+	}
+	return nil
+}
+
+// shadowsSubject reports whether the variable of the type switch stmt has
+// the name of its subject, as in `switch x := x.(type)`.
+func shadowsSubject(stmt *ast.TypeSwitchStmt, subject *ast.Ident) bool {
+	assign, ok := stmt.Assign.(*ast.AssignStmt)
+	return ok && assign.Lhs[0].(*ast.Ident).Name == subject.Name
+}
+
+// usesObject reports whether obj is used in the statements.
+func usesObject(info *types.Info, stmts []ast.Stmt, obj types.Object) bool {
+	used := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && info.Uses[ident] == obj {
+				used = true
+			}
+			return !used
+		})
+	}
+	return used
+}
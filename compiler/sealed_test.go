@@ -0,0 +1,103 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompileSealedUnions(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"shape/shape.go": `package shape
+
+// Shape is a shape.
+type Shape interface {
+	Area() int
+	isShape()
+}
+
+// Circle is a circle.
+type Circle struct{ R int }
+
+func (c *Circle) Area() int { return 3 * c.R * c.R }
+func (*Circle) isShape()    {}
+
+// Area is implemented by any type.
+type Areaer interface {
+	Area() int
+}
+
+// Describe describes s.
+func Describe(s Shape) string {
+	switch v := s.(type) {
+	case *Circle:
+		if v.R == 0 {
+			return "dot"
+		}
+		return "circle"
+	case nil:
+		return "nil"
+	}
+	return "unknown"
+}
+
+// Measure measures a.
+func Measure(a Areaer) int {
+	switch v := a.(type) {
+	case *Circle:
+		return v.R
+	}
+	return 0
+}
+`,
+		"shape/square.go": `package shape
+
+// Square is a square.
+type Square struct{ S int }
+
+func (s *Square) Area() int { return s.S * s.S }
+func (*Square) isShape()    {}
+`,
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	comp, err := NewCompiler(&Config{
+		Dir:          dir,
+		OutputPath:   outputDir,
+		SealedUnions: true,
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.CompilePackages(context.Background(), "./shape"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "shape", "shape.gs.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		`import { Square } from "./square.gs.js"`,
+		"export type Shape = null | Circle | Square\n",
+		"\tpublic get __type(): \"Circle\" {\n\t\treturn \"Circle\"\n\t}\n",
+		"\tswitch (s?.__type) {\n" +
+			"\t\tcase \"Circle\": {\n" +
+			"\t\t\tlet v: Circle | null = $.isTypedNil(s) ? null : s\n",
+		"\t\tdefault: {\n\t\t\tif (s == null) {\n",
+		// Areaer is not sealed, so the switch on it is not narrowed
+		"$.typeSwitch(a,",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("shape.gs.ts does not contain %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "export type Areaer = null | {\n") {
+		t.Errorf("interface without unexported methods written as a union:\n%s", out)
+	}
+}
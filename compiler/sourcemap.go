@@ -0,0 +1,168 @@
+package compiler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceMap collects mappings from positions in a generated TypeScript file
+// back to positions in the Go source files and encodes them as a Source Map
+// v3 (https://tc39.es/ecma426/).
+//
+// Lines and columns are zero-based. Generated columns are counted in UTF-16
+// code units, like JavaScript engines and debuggers do.
+type SourceMap struct {
+	// file is the name of the generated file.
+	file string
+	// sources are the Go source file paths, in order of first use.
+	sources []string
+	// sourceIndex maps a Go source file path to its index in sources.
+	sourceIndex map[string]int
+	// mappings are the recorded mappings, in generated order.
+	mappings []sourceMapping
+}
+
+// sourceMapping maps a generated position to a source position.
+type sourceMapping struct {
+	genLine, genCol int
+	source          int
+	srcLine, srcCol int
+}
+
+// NewSourceMap creates an empty source map for the generated file.
+func NewSourceMap(file string) *SourceMap {
+	return &SourceMap{file: file, sourceIndex: make(map[string]int)}
+}
+
+// AddMapping records that the generated position genLine:genCol comes from
+// srcLine:srcCol of the Go file srcPath. A later mapping of the same
+// generated position replaces the earlier one.
+func (m *SourceMap) AddMapping(genLine, genCol int, srcPath string, srcLine, srcCol int) {
+	idx, ok := m.sourceIndex[srcPath]
+	if !ok {
+		idx = len(m.sources)
+		m.sources = append(m.sources, srcPath)
+		m.sourceIndex[srcPath] = idx
+	}
+	mapping := sourceMapping{genLine: genLine, genCol: genCol, source: idx, srcLine: srcLine, srcCol: srcCol}
+	if n := len(m.mappings); n != 0 {
+		last := &m.mappings[n-1]
+		if last.genLine == genLine && last.genCol == genCol {
+			*last = mapping
+			return
+		}
+	}
+	m.mappings = append(m.mappings, mapping)
+}
+
+// Sources returns the Go source file paths referenced by the mappings.
+func (m *SourceMap) Sources() []string {
+	return m.sources
+}
+
+// EncodeMappings returns the Base64 VLQ encoded "mappings" field.
+func (m *SourceMap) EncodeMappings() string {
+	var buf []byte
+	var prevGenCol, prevSource, prevSrcLine, prevSrcCol int
+	genLine := 0
+	for i, mapping := range m.mappings {
+		if mapping.genLine != genLine {
+			for genLine < mapping.genLine {
+				buf = append(buf, ';')
+				genLine++
+			}
+			prevGenCol = 0
+		} else if i != 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendVLQ(buf, mapping.genCol-prevGenCol)
+		buf = appendVLQ(buf, mapping.source-prevSource)
+		buf = appendVLQ(buf, mapping.srcLine-prevSrcLine)
+		buf = appendVLQ(buf, mapping.srcCol-prevSrcCol)
+		prevGenCol = mapping.genCol
+		prevSource = mapping.source
+		prevSrcLine = mapping.srcLine
+		prevSrcCol = mapping.srcCol
+	}
+	return string(buf)
+}
+
+// sourceMapJSON is the JSON representation of a Source Map v3.
+type sourceMapJSON struct {
+	Version        int       `json:"version"`
+	File           string    `json:"file"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent,omitempty"`
+	Names          []string  `json:"names"`
+	Mappings       string    `json:"mappings"`
+}
+
+// MarshalSourceMap encodes the source map as JSON.
+//
+// Source paths are written relative to outDir, the directory containing the
+// generated file. If includeSources is set the contents of the Go files are
+// embedded as well, so the map can be used without access to the sources.
+func (m *SourceMap) MarshalSourceMap(outDir string, includeSources bool) ([]byte, error) {
+	out := sourceMapJSON{
+		Version:  3,
+		File:     m.file,
+		Sources:  make([]string, len(m.sources)),
+		Names:    []string{},
+		Mappings: m.EncodeMappings(),
+	}
+	for i, src := range m.sources {
+		out.Sources[i] = sourceMapPath(outDir, src)
+	}
+	if includeSources {
+		out.SourcesContent = make([]*string, len(m.sources))
+		for i, src := range m.sources {
+			data, err := os.ReadFile(src)
+			if err != nil {
+				return nil, err
+			}
+			content := string(data)
+			out.SourcesContent[i] = &content
+		}
+	}
+	return json.Marshal(out)
+}
+
+// sourceMapPath returns the path of a Go source file as written to a source
+// map in outDir: relative if possible, otherwise a file URL.
+func sourceMapPath(outDir, src string) string {
+	if rel, err := filepath.Rel(outDir, src); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	src = filepath.ToSlash(src)
+	if !strings.HasPrefix(src, "/") {
+		src = "/" + src
+	}
+	return "file://" + src
+}
+
+// base64VLQChars are the digits of the Base64 VLQ encoding.
+const base64VLQChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// appendVLQ appends the Base64 VLQ encoding of v to buf.
+func appendVLQ(buf []byte, v int) []byte {
+	// The sign is stored in the least significant bit.
+	var vlq uint
+	if v < 0 {
+		vlq = uint(-v)<<1 | 1
+	} else {
+		vlq = uint(v) << 1
+	}
+	for {
+		digit := vlq & 0x1f
+		vlq >>= 5
+		if vlq != 0 {
+			digit |= 0x20
+		}
+		buf = append(buf, base64VLQChars[digit])
+		if vlq == 0 {
+			return buf
+		}
+	}
+}
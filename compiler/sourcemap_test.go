@@ -0,0 +1,129 @@
+package compiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAppendVLQ(t *testing.T) {
+	tests := []struct {
+		value int
+		want  string
+	}{
+		{0, "A"},
+		{1, "C"},
+		{-1, "D"},
+		{15, "e"},
+		{16, "gB"},
+		{-16, "hB"},
+		{1000, "w+B"},
+	}
+	for _, tt := range tests {
+		if got := string(appendVLQ(nil, tt.value)); got != tt.want {
+			t.Errorf("appendVLQ(%d) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSourceMapEncodeMappings(t *testing.T) {
+	m := NewSourceMap("main.gs.ts")
+	m.AddMapping(0, 0, "/src/main.go", 2, 0)
+	m.AddMapping(0, 4, "/src/main.go", 2, 5)
+	// Replaces the previous mapping of the same generated position.
+	m.AddMapping(0, 4, "/src/main.go", 3, 1)
+	m.AddMapping(2, 1, "/src/other.go", 0, 0)
+
+	if got, want := m.EncodeMappings(), "AAEA,IACC;;CCHD"; got != want {
+		t.Errorf("EncodeMappings() = %q, want %q", got, want)
+	}
+	if got := m.Sources(); len(got) != 2 || got[0] != "/src/main.go" || got[1] != "/src/other.go" {
+		t.Errorf("Sources() = %v", got)
+	}
+}
+
+func TestCodeWriterSourcePositions(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("/src/main.go", -1, 100)
+	file.SetLines([]int{0, 10, 20})
+
+	var buf bytes.Buffer
+	m := NewSourceMap("main.gs.ts")
+	w := NewTSCodeWriter(&buf)
+	w.SetSourceMap(fset, m)
+
+	w.WriteLine("// header")
+	w.Indent(1)
+	w.MarkPos(file.Pos(12))
+	w.WriteLiterally("let s = \"\U0001F600\"; ")
+	w.MarkPos(file.Pos(24))
+	w.WriteLine("f()")
+
+	// Line 1 column 1 (after the tab) maps to 1:2 and column 15 (after the
+	// surrogate pair) to 2:4, all zero-based.
+	if got, want := m.EncodeMappings(), ";CACE,cACE"; got != want {
+		t.Errorf("EncodeMappings() = %q, want %q", got, want)
+	}
+}
+
+func TestCompileSourceMap(t *testing.T) {
+	goSource := "package main\n\nfunc add(a, b int) int {\n\treturn a + b\n}\n\nfunc main() {\n\tprintln(add(1, 2))\n}\n"
+	dir := writeTestModule(t, map[string]string{
+		"go.mod":  "module example.com/sourcemap\n\ngo 1.24\n",
+		"main.go": goSource,
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	config := &Config{
+		Dir:                     dir,
+		OutputPath:              outputDir,
+		DisableEmitBuiltin:      true,
+		SourceMap:               true,
+		SourceMapSourcesContent: true,
+	}
+	le := logrus.NewEntry(logrus.New())
+	comp, err := NewCompiler(config, le, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.CompilePackages(context.Background(), "."); err != nil {
+		t.Fatal(err)
+	}
+
+	tsPath := filepath.Join(outputDir, "@goscript/example.com/sourcemap/main.gs.ts")
+	ts, err := os.ReadFile(tsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(ts), "//# sourceMappingURL=main.gs.ts.map\n") {
+		t.Errorf("generated file does not link the source map:\n%s", ts)
+	}
+
+	data, err := os.ReadFile(tsPath + ".map")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sm sourceMapJSON
+	if err := json.Unmarshal(data, &sm); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Version != 3 || sm.File != "main.gs.ts" {
+		t.Errorf("unexpected source map header: %s", data)
+	}
+	if len(sm.Sources) != 1 || sm.Sources[0] != "../../../../main.go" {
+		t.Errorf("unexpected sources: %v", sm.Sources)
+	}
+	if len(sm.SourcesContent) != 1 || sm.SourcesContent[0] == nil || *sm.SourcesContent[0] != goSource {
+		t.Errorf("unexpected sources content: %s", data)
+	}
+	if sm.Mappings == "" {
+		t.Errorf("source map has no mappings")
+	}
+}
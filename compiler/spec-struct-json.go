@@ -0,0 +1,474 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// With Config.JSONMethods, the class of each struct has methods converting it
+// to and from the plain objects of its encoding/json encoding, for
+// JSON.stringify, structuredClone, postMessage and RPC frameworks:
+//
+//   - toPlain() returns the plain object: the fields are named and skipped by
+//     their json tags with the omitempty, omitzero and string options, the
+//     fields of embedded structs are promoted, nested structs are converted
+//     with their toPlain method, slices are arrays, []byte base64 strings and
+//     maps objects with sorted keys. bigint values are kept as they are.
+//   - toJSON() returns toPlain() with the bigint values replaced by raw JSON
+//     numbers, so JSON.stringify writes what json.Marshal writes
+//   - the static fromJSON(obj) converts a plain object, like the result of
+//     JSON.parse, to an instance of the class. Like json.Unmarshal, fields are
+//     matched ignoring case and fields missing from obj are zero values.
+//
+// Fields of types encoding/json cannot encode, like channels and functions,
+// are left out, and fields of interfaces with methods are only encoded.
+// MarshalJSON and UnmarshalJSON methods are not called. Generic structs and
+// structs with a field or method named like these methods do not have them.
+
+// plainConversion converts the values of a Go type to and from plain values.
+type plainConversion struct {
+	// toPlain converts the value expr to a plain value, nil if they are the
+	// same.
+	toPlain func(expr string) string
+	// fromPlain converts the plain value expr to a value of the type, nil if
+	// they are the same.
+	fromPlain func(expr string) string
+	// encodeOnly is set if plain values cannot be converted to values of the
+	// type, like to interfaces with methods.
+	encodeOnly bool
+}
+
+// convertToPlain returns the expression converting the value expr.
+func (p *plainConversion) convertToPlain(expr string) string {
+	if p.toPlain == nil {
+		return expr
+	}
+	return p.toPlain(expr)
+}
+
+// convertFromPlain returns the expression converting the plain value expr.
+func (p *plainConversion) convertFromPlain(expr string) string {
+	if p.fromPlain == nil {
+		return expr
+	}
+	return p.fromPlain(expr)
+}
+
+// plainLambda returns the arrow function converting its parameter with
+// convert, or "" if convert is nil.
+func plainLambda(convert func(expr string) string) string {
+	if convert == nil {
+		return ""
+	}
+	return ", (e) => " + convert("e")
+}
+
+// plainField is a field of the plain object of a struct.
+type plainField struct {
+	jsonField
+	// path is the path of the field from the struct, like "Base.Name".
+	path string
+	// embedded are the paths of the embedded pointers to structs the field
+	// is promoted through, with the classes of these structs.
+	embedded [][2]string
+	// conv converts the values of the field.
+	conv *plainConversion
+	// nullable is set if JSON null is a value of the field.
+	nullable bool
+}
+
+// plainFields returns the fields of the plain objects of the struct t,
+// without the fields of types encoding/json cannot encode.
+func (c *GoToTSCompiler) plainFields(t types.Type) []plainField {
+	var fields []plainField
+	for _, jf := range jsonFields(t) {
+		field := plainField{jsonField: jf}
+		st := t.Underlying().(*types.Struct)
+		var path []string
+		var v *types.Var
+		supported := true
+		for i, idx := range jf.index {
+			v = st.Field(idx)
+			name := v.Name()
+			if v.Anonymous() {
+				name = c.getEmbeddedFieldKeyName(v.Type())
+			}
+			path = append(path, name)
+			if i == len(jf.index)-1 {
+				break
+			}
+
+			// Embedded struct the field is promoted through
+			ft := types.Unalias(v.Type())
+			if ptr, ok := ft.(*types.Pointer); ok {
+				ft = types.Unalias(ptr.Elem())
+				class := c.plainStructClass(ft)
+				if class == "" {
+					supported = false
+					break
+				}
+				field.embedded = append(field.embedded, [2]string{strings.Join(path, "."), class})
+			}
+			st = ft.Underlying().(*types.Struct)
+		}
+		if !supported {
+			continue
+		}
+		field.path = strings.Join(path, ".")
+		field.typ = v.Type()
+		if jf.quoted {
+			field.conv = c.quotedPlainConversion(field.typ)
+		} else {
+			field.conv = c.plainConversion(field.typ)
+		}
+		if field.conv == nil {
+			continue
+		}
+		switch types.Unalias(field.typ).Underlying().(type) {
+		case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+			field.nullable = true
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// plainStructClass returns the expression of the class of the struct type t,
+// or "" if it has none, like instances of generic structs.
+func (c *GoToTSCompiler) plainStructClass(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeArgs().Len() != 0 {
+		return ""
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return ""
+	}
+	return c.getTypeString(named)
+}
+
+// zeroValueString returns the zero value of type t.
+func (c *GoToTSCompiler) zeroValueString(t types.Type) string {
+	var zero strings.Builder
+	tempCompiler := NewGoToTSCompiler(NewTSCodeWriter(&zero), c.pkg, c.analysis, c.config, c.currentFilePath)
+	tempCompiler.WriteZeroValueForType(t)
+	return zero.String()
+}
+
+// plainConversion returns how values of type t are converted to and from
+// plain values, nil if encoding/json cannot encode them.
+func (c *GoToTSCompiler) plainConversion(t types.Type) *plainConversion {
+	return c.plainConversionOf(t, make(map[types.Type]bool))
+}
+
+// plainConversionOf returns the conversion of values of type t, the element
+// type of the named types in visiting.
+func (c *GoToTSCompiler) plainConversionOf(t types.Type, visiting map[types.Type]bool) *plainConversion {
+	t = types.Unalias(t)
+	if visiting[t] {
+		// Recursive type like type List []List, converted at run time
+		return &plainConversion{
+			toPlain:    func(expr string) string { return "$.toPlain(" + expr + ")" },
+			encodeOnly: true,
+		}
+	}
+	if _, ok := t.(*types.Named); ok {
+		visiting[t] = true
+		defer delete(visiting, t)
+	}
+	if _, ok := t.Underlying().(*types.Struct); ok {
+		class := c.plainStructClass(t)
+		if class == "" {
+			return nil
+		}
+		return &plainConversion{
+			toPlain:   func(expr string) string { return "$.toPlain(" + expr + ")" },
+			fromPlain: func(expr string) string { return "$.structFromPlain(" + class + ", " + expr + ")" },
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case info&(types.IsBoolean|types.IsString) != 0:
+			return &plainConversion{}
+		case info&types.IsComplex != 0:
+			return nil
+		case info&types.IsNumeric != 0:
+			if c.isBigIntType(t) {
+				// Plain values keep the bigint, toJSON writes it exactly
+				return &plainConversion{fromPlain: bigIntFromPlain(t)}
+			}
+			return &plainConversion{}
+		}
+	case *types.Pointer:
+		elemType := types.Unalias(u.Elem())
+		elem := c.plainConversionOf(elemType, visiting)
+		if elem == nil {
+			return nil
+		}
+		if _, ok := elemType.Underlying().(*types.Struct); ok {
+			// Pointers to structs are the instances of their classes
+			return &plainConversion{
+				toPlain:   elem.toPlain,
+				fromPlain: func(expr string) string { return expr + " === null ? null : " + elem.fromPlain(expr) },
+			}
+		}
+		return &plainConversion{
+			toPlain: func(expr string) string { return expr + " === null ? null : " + elem.convertToPlain(expr+".value") },
+			fromPlain: func(expr string) string {
+				return expr + " === null ? null : $.varRef(" + elem.convertFromPlain(expr) + ")"
+			},
+			encodeOnly: elem.encodeOnly,
+		}
+	case *types.Slice:
+		if isJSONBytes(u.Elem()) {
+			return &plainConversion{
+				toPlain:   func(expr string) string { return "$.bytesToBase64(" + expr + ")" },
+				fromPlain: func(expr string) string { return "$.base64ToBytes(" + expr + ")" },
+			}
+		}
+		elem := c.plainConversionOf(u.Elem(), visiting)
+		if elem == nil {
+			return nil
+		}
+		return &plainConversion{
+			toPlain:    func(expr string) string { return "$.sliceToPlain(" + expr + plainLambda(elem.toPlain) + ")" },
+			fromPlain:  func(expr string) string { return "$.sliceFromPlain(" + expr + plainLambda(elem.fromPlain) + ")" },
+			encodeOnly: elem.encodeOnly,
+		}
+	case *types.Array:
+		elem := c.plainConversionOf(u.Elem(), visiting)
+		if elem == nil {
+			return nil
+		}
+		zero := c.zeroValueString(u)
+		return &plainConversion{
+			toPlain: func(expr string) string { return "$.sliceToPlain(" + expr + plainLambda(elem.toPlain) + ")" },
+			fromPlain: func(expr string) string {
+				return "$.arrayFromPlain(" + expr + ", " + zero + plainLambda(elem.fromPlain) + ")"
+			},
+			encodeOnly: elem.encodeOnly,
+		}
+	case *types.Map:
+		key := "undefined"
+		basic, _ := types.Unalias(u.Key()).Underlying().(*types.Basic)
+		switch {
+		case basic != nil && basic.Info()&types.IsString != 0:
+		case basic != nil && basic.Info()&types.IsInteger != 0:
+			key = "Number"
+			if c.isBigIntType(u.Key()) {
+				key = "(k) => " + bigIntFromPlain(u.Key())("k")
+			}
+		default:
+			return nil
+		}
+		elem := c.plainConversionOf(u.Elem(), visiting)
+		if elem == nil {
+			return nil
+		}
+		return &plainConversion{
+			toPlain: func(expr string) string { return "$.mapToPlain(" + expr + plainLambda(elem.toPlain) + ")" },
+			fromPlain: func(expr string) string {
+				lambda := plainLambda(elem.fromPlain)
+				if key == "undefined" && lambda == "" {
+					return "$.mapFromPlain(" + expr + ")"
+				}
+				return "$.mapFromPlain(" + expr + ", " + key + lambda + ")"
+			},
+			encodeOnly: elem.encodeOnly,
+		}
+	case *types.Interface:
+		return &plainConversion{
+			toPlain:    func(expr string) string { return "$.toPlain(" + expr + ")" },
+			fromPlain:  func(expr string) string { return "$.plainToAny(" + expr + ")" },
+			encodeOnly: !u.Empty(),
+		}
+	}
+	// Channels, functions, complex numbers and unsafe pointers
+	return nil
+}
+
+// bigIntFromPlain returns the conversion of plain values to values of the
+// bigint type t, which checks that they are integers in the range of t.
+func bigIntFromPlain(t types.Type) func(expr string) string {
+	unsigned := strconv.FormatBool(isUnsignedType(t))
+	return func(expr string) string { return "$.bigIntFromPlain(" + expr + ", " + unsigned + ")" }
+}
+
+// quotedPlainConversion returns how values of the basic type t, or pointer to
+// one, are converted to and from the strings of the string option.
+func (c *GoToTSCompiler) quotedPlainConversion(t types.Type) *plainConversion {
+	if ptr, ok := types.Unalias(t).Underlying().(*types.Pointer); ok {
+		elem := c.quotedPlainConversion(ptr.Elem())
+		return &plainConversion{
+			toPlain:   func(expr string) string { return expr + " === null ? null : " + elem.toPlain(expr+".value") },
+			fromPlain: func(expr string) string { return expr + " === null ? null : $.varRef(" + elem.fromPlain(expr) + ")" },
+		}
+	}
+
+	info := t.Underlying().(*types.Basic).Info()
+	switch {
+	case info&types.IsString != 0:
+		return &plainConversion{
+			toPlain:   func(expr string) string { return "JSON.stringify(" + expr + ")" },
+			fromPlain: func(expr string) string { return "JSON.parse(" + expr + ")" },
+		}
+	case info&types.IsBoolean != 0:
+		return &plainConversion{
+			toPlain:   func(expr string) string { return "String(" + expr + ")" },
+			fromPlain: func(expr string) string { return expr + " === \"true\"" },
+		}
+	case c.isBigIntType(t):
+		return &plainConversion{
+			toPlain:   func(expr string) string { return "String(" + expr + ")" },
+			fromPlain: bigIntFromPlain(t),
+		}
+	}
+	return &plainConversion{
+		toPlain:   func(expr string) string { return "String(" + expr + ")" },
+		fromPlain: func(expr string) string { return "Number(" + expr + ")" },
+	}
+}
+
+// plainOmitCondition returns the condition under which the value expr of the
+// field is written to the plain object, "" if it is always written and
+// "false" if it never is.
+func (c *GoToTSCompiler) plainOmitCondition(field plainField, expr string) string {
+	t := types.Unalias(field.typ)
+	var conds []string
+	if field.omitEmpty {
+		switch u := t.Underlying().(type) {
+		case *types.Basic:
+			conds = append(conds, c.plainNonZeroBasic(t, u, expr))
+		case *types.Pointer, *types.Interface:
+			conds = append(conds, expr+" !== null")
+		case *types.Slice, *types.Map:
+			conds = append(conds, "$.len("+expr+") !== 0")
+		case *types.Array:
+			if u.Len() == 0 {
+				return "false"
+			}
+		}
+	}
+	if field.omitZero {
+		switch u := t.Underlying().(type) {
+		case *types.Basic:
+			conds = append(conds, c.plainNonZeroBasic(t, u, expr))
+		case *types.Struct, *types.Array:
+			conds = append(conds, "!$.isZero("+expr+")")
+		default:
+			conds = append(conds, expr+" !== null")
+		}
+	}
+	return strings.Join(conds, " && ")
+}
+
+// plainNonZeroBasic returns the condition under which the value expr of the
+// type t with the underlying type basic is not zero.
+func (c *GoToTSCompiler) plainNonZeroBasic(t types.Type, basic *types.Basic, expr string) string {
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return expr
+	case info&types.IsString != 0:
+		return expr + ` !== ""`
+	case c.isBigIntType(t):
+		return expr + " !== 0n"
+	}
+	return expr + " !== 0"
+}
+
+// writeStructPlainMethods writes the toJSON, toPlain and fromJSON methods of
+// the class of the struct t declared by a, if Config.JSONMethods is set.
+func (c *GoToTSCompiler) writeStructPlainMethods(a *ast.TypeSpec, t *types.Named) {
+	if !c.config.JSONMethods || a.TypeParams != nil {
+		return
+	}
+	for _, name := range []string{"toJSON", "toPlain", "fromJSON"} {
+		if obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, t.Obj().Pkg(), name); obj != nil {
+			return
+		}
+	}
+	className := sanitizeIdentifier(a.Name.Name)
+	fields := c.plainFields(t)
+
+	c.tsw.WriteLine("")
+	c.tsw.WriteLine("public toJSON(): Record<string, unknown> {")
+	c.tsw.Indent(1)
+	c.tsw.WriteLine("return $.plainToJSON(this.toPlain()) as Record<string, unknown>")
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("}")
+
+	c.tsw.WriteLine("")
+	c.tsw.WriteLine("public toPlain(): Record<string, unknown> {")
+	c.tsw.Indent(1)
+	c.tsw.WriteLine("const obj: Record<string, unknown> = {}")
+	for _, field := range fields {
+		expr := "this." + field.path
+		var conds []string
+		for _, embedded := range field.embedded {
+			conds = append(conds, "this."+embedded[0]+" !== null")
+		}
+		cond := c.plainOmitCondition(field, expr)
+		if cond == "false" {
+			continue
+		}
+		if cond != "" {
+			conds = append(conds, cond)
+		}
+		if len(conds) == 0 {
+			c.tsw.WriteLinef("obj[%s] = %s", strconv.Quote(field.name), field.conv.convertToPlain(expr))
+			continue
+		}
+		c.tsw.WriteLinef("if (%s) {", strings.Join(conds, " && "))
+		c.tsw.Indent(1)
+		c.tsw.WriteLinef("obj[%s] = %s", strconv.Quote(field.name), field.conv.convertToPlain(expr))
+		c.tsw.Indent(-1)
+		c.tsw.WriteLine("}")
+	}
+	c.tsw.WriteLine("return obj")
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("}")
+
+	c.tsw.WriteLine("")
+	c.tsw.WriteLinef("public static fromJSON(obj: unknown): %s {", className)
+	c.tsw.Indent(1)
+	c.tsw.WriteLinef("const value = new %s()", className)
+	var names []string
+	for _, field := range fields {
+		if !field.conv.encodeOnly {
+			names = append(names, strconv.Quote(field.name))
+		}
+	}
+	if len(names) != 0 {
+		c.tsw.WriteLinef("const props = $.plainObject(obj, [%s])", strings.Join(names, ", "))
+	}
+	for _, field := range fields {
+		if field.conv.encodeOnly {
+			continue
+		}
+		prop := "props[" + strconv.Quote(field.name) + "]"
+		if field.nullable {
+			c.tsw.WriteLinef("if (%s !== undefined) {", prop)
+		} else {
+			c.tsw.WriteLinef("if (%s != null) {", prop)
+		}
+		c.tsw.Indent(1)
+		for _, embedded := range field.embedded {
+			c.tsw.WriteLinef("if (value.%s === null) {", embedded[0])
+			c.tsw.Indent(1)
+			c.tsw.WriteLinef("value.%s = new %s()", embedded[0], embedded[1])
+			c.tsw.Indent(-1)
+			c.tsw.WriteLine("}")
+		}
+		c.tsw.WriteLinef("value.%s = %s", field.path, field.conv.convertFromPlain(prop))
+		c.tsw.Indent(-1)
+		c.tsw.WriteLine("}")
+	}
+	c.tsw.WriteLine("return value")
+	c.tsw.Indent(-1)
+	c.tsw.WriteLine("}")
+}
@@ -0,0 +1,103 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompileStructPlainMethods(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"todo/todo.go": `package todo
+
+import "io"
+
+// Meta is embedded in Todo.
+type Meta struct {
+	Owner string ` + "`json:\"owner\"`" + `
+}
+
+// Todo is a todo.
+type Todo struct {
+	*Meta
+	ID     int64             ` + "`json:\"id,string\"`" + `
+	Title  string            ` + "`json:\"title,omitempty\"`" + `
+	Tags   []string          ` + "`json:\"tags\"`" + `
+	Data   []byte            ` + "`json:\"data\"`" + `
+	Parent *Todo             ` + "`json:\"parent,omitempty\"`" + `
+	Extra  map[string]any    ` + "`json:\"extra\"`" + `
+	Body   io.Reader         ` + "`json:\"body\"`" + `
+	Done   chan struct{}
+	Hidden string            ` + "`json:\"-\"`" + `
+	Counts map[int]int
+}
+
+// Box is generic.
+type Box[T any] struct {
+	Value T
+}
+
+// Named has a method named toJSON.
+type Named struct {
+	Value int
+}
+
+func (Named) toJSON() string { return "" }
+`,
+	})
+
+	outputDir := filepath.Join(dir, "output")
+	comp, err := NewCompiler(&Config{
+		Dir:         dir,
+		OutputPath:  outputDir,
+		JSONMethods: true,
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.CompilePackages(context.Background(), "./todo"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "todo", "todo.gs.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"\tpublic toJSON(): Record<string, unknown> {\n\t\treturn $.plainToJSON(this.toPlain()) as Record<string, unknown>\n\t}\n",
+		"\tpublic static fromJSON(obj: unknown): Todo {\n",
+		`if (this.Meta !== null) {`,
+		`obj["owner"] = this.Meta.Owner`,
+		`obj["id"] = String(this.ID)`,
+		`if (this.Title !== "") {`,
+		`obj["tags"] = $.sliceToPlain(this.Tags)`,
+		`obj["data"] = $.bytesToBase64(this.Data)`,
+		`obj["body"] = $.toPlain(this.Body)`,
+		`obj["Counts"] = $.mapToPlain(this.Counts)`,
+		`const props = $.plainObject(obj, ["owner", "id", "title", "tags", "data", "parent", "extra", "Counts"])`,
+		"if (value.Meta === null) {",
+		`value.Parent = props["parent"] === null ? null : $.structFromPlain(Todo, props["parent"])`,
+		`value.Extra = $.mapFromPlain(props["extra"], undefined, (e) => $.plainToAny(e))`,
+		`value.Counts = $.mapFromPlain(props["Counts"], Number)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("todo.gs.ts does not contain %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{
+		`obj["Done"]`,
+		`obj["Hidden"]`,
+		`value.Body =`,
+		"public static fromJSON(obj: unknown): Box",
+		"public static fromJSON(obj: unknown): Named",
+	} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("todo.gs.ts contains %q:\n%s", unwanted, out)
+		}
+	}
+}
@@ -16,6 +16,9 @@ import (
 //     to maintain Go's value semantics.
 //   - A constructor that initializes the `_fields` and allows partial initialization.
 //   - A `clone` method for creating a deep copy of the struct instance.
+//   - With Config.JSONMethods, `toJSON`, `toPlain` and `fromJSON` methods
+//     converting the struct to and from the plain objects of its encoding/json
+//     encoding.
 //   - Methods defined directly on the struct.
 //   - Wrapper methods for promoted fields and methods from embedded structs,
 //     ensuring correct access and behavior.
@@ -220,6 +223,8 @@ func (c *GoToTSCompiler) WriteStructTypeSpec(a *ast.TypeSpec, t *ast.StructType)
 	c.tsw.WriteLine("return cloned")
 	c.tsw.Indent(-1)
 	c.tsw.WriteLine("}")
+	c.writeSealedDiscriminant(a)
+	c.writeStructPlainMethods(a, goStructType)
 
 	// Methods for this struct (direct methods)
 	for _, fileSyntax := range c.pkg.Syntax {
@@ -3,8 +3,10 @@ package compiler
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
+	"math"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -111,6 +113,10 @@ func (c *GoToTSCompiler) WriteValueSpec(a *ast.ValueSpec) error {
 		c.WriteDoc(a.Comment)
 	}
 
+	if c.isFoldedConstSpec(a) {
+		return c.writeConstSpec(a)
+	}
+
 	// Handle single variable declaration
 	if len(a.Names) == 1 {
 		name := a.Names[0]
@@ -259,6 +265,21 @@ func (c *GoToTSCompiler) WriteValueSpec(a *ast.ValueSpec) error {
 			}
 		}
 
+		// Variables with a //go:embed directive are initialized with the files
+		if files, isEmbed := c.analysis.EmbedFiles[a]; isEmbed && !hasInitializer {
+			if needsVarRef {
+				c.tsw.WriteLiterally("$.varRef(")
+			}
+			if err := c.writeEmbedValue(goType, files); err != nil {
+				return fmt.Errorf("%s: %w", name.Name, err)
+			}
+			if needsVarRef {
+				c.tsw.WriteLiterally(")")
+			}
+			c.tsw.WriteLine("")
+			return nil
+		}
+
 		if needsVarRef {
 			// VarRef variable: let v: VarRef<T> = $.varRef(init_or_zero);
 			c.tsw.WriteLiterally("$.varRef(")
@@ -516,6 +537,72 @@ func (c *GoToTSCompiler) WriteValueSpec(a *ast.ValueSpec) error {
 	return nil
 }
 
+// isFoldedConstSpec reports whether the constants of the specification a are
+// written with the values computed by the type checker instead of their
+// expressions. These are the constants using iota or repeating the previous
+// expression list, the specifications of several constants, the constants
+// of enum types, and the integer constants outside the 32-bit range, whose
+// expressions could overflow the 32-bit bitwise operators of JavaScript.
+func (c *GoToTSCompiler) isFoldedConstSpec(a *ast.ValueSpec) bool {
+	if len(a.Values) == 0 || len(a.Names) > 1 {
+		_, isConst := c.pkg.TypesInfo.Defs[a.Names[0]].(*types.Const)
+		return isConst
+	}
+	constObj, isConst := c.pkg.TypesInfo.Defs[a.Names[0]].(*types.Const)
+	if !isConst {
+		return false
+	}
+	if named, ok := constObj.Type().(*types.Named); ok && c.enumInfo(named.Obj()) != nil {
+		return true
+	}
+	if val := constObj.Val(); val.Kind() == constant.Int {
+		if v, exact := constant.Int64Val(val); !exact || v < math.MinInt32 || v > math.MaxUint32 {
+			return true
+		}
+	}
+	usesIota := false
+	ast.Inspect(a.Values[0], func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && c.pkg.TypesInfo.Uses[ident] == types.Universe.Lookup("iota") {
+			usesIota = true
+		}
+		return !usesIota
+	})
+	return usesIota
+}
+
+// writeConstSpec writes each constant of the specification a with its
+// value: `let Name: T = value`.
+func (c *GoToTSCompiler) writeConstSpec(a *ast.ValueSpec) error {
+	isInsideFunction := false
+	if nodeInfo := c.analysis.NodeData[a]; nodeInfo != nil {
+		isInsideFunction = nodeInfo.IsInsideFunction
+	}
+	for _, name := range a.Names {
+		if name.Name == "_" {
+			continue
+		}
+		constObj, ok := c.pkg.TypesInfo.Defs[name].(*types.Const)
+		if !ok {
+			return fmt.Errorf("could not resolve constant: %v", name)
+		}
+		if !isInsideFunction {
+			c.tsw.WriteLiterally("export ")
+		}
+		c.tsw.WriteLiterally("let ")
+		c.tsw.WriteLiterally(c.sanitizeIdentifier(name.Name))
+		c.tsw.WriteLiterally(": ")
+		if a.Type != nil {
+			c.WriteTypeExpr(a.Type)
+		} else {
+			c.WriteGoType(constObj.Type(), GoTypeContextGeneral)
+		}
+		c.tsw.WriteLiterally(" = ")
+		c.writeConstantValue(constObj)
+		c.tsw.WriteLine("")
+	}
+	return nil
+}
+
 // writeInitializerForInterface handles writing initializer expressions for interface variables,
 // with special handling for pointer variable assignments to avoid automatic .value dereferencing
 func (c *GoToTSCompiler) writeInitializerForInterface(initializerExpr ast.Expr, goType types.Type) error {
@@ -535,6 +622,6 @@ func (c *GoToTSCompiler) writeInitializerForInterface(initializerExpr ast.Expr,
 			}
 		}
 	}
-	// Default case: use regular WriteValueExpr
-	return c.WriteValueExpr(initializerExpr)
+	// Default case: pointers which may be nil become typed nils
+	return c.writeInterfaceValue(initializerExpr, goType)
 }
@@ -17,6 +17,7 @@ import (
 // - `ast.TypeSpec` (type definitions like structs, interfaces): Delegates to `WriteTypeSpec`.
 // If an unknown specification type is encountered, it returns an error.
 func (c *GoToTSCompiler) WriteSpec(a ast.Spec) error {
+	c.tsw.MarkPos(a.Pos())
 	switch d := a.(type) {
 	case *ast.ImportSpec:
 		c.WriteImportSpec(d)
@@ -326,18 +327,23 @@ func (c *GoToTSCompiler) WriteNamedTypeWithMethods(a *ast.TypeSpec) error {
 		isInsideFunction = nodeInfo.IsInsideFunction
 	}
 
-	if !isInsideFunction {
-		c.tsw.WriteLiterally("export ")
-	}
+	if enum := c.enumOf(a); enum != nil {
+		c.writeEnumTypeSpec(a, enum)
+		c.tsw.WriteLine("")
+	} else {
+		if !isInsideFunction {
+			c.tsw.WriteLiterally("export ")
+		}
 
-	// Generate type alias instead of class
-	c.tsw.WriteLiterally("type ")
-	c.tsw.WriteLiterally(className)
-	c.tsw.WriteLiterally(" = ")
-	// Use AST-based type writing to preserve qualified names like os.FileInfo
-	c.WriteTypeExpr(a.Type)
-	c.tsw.WriteLine(";")
-	c.tsw.WriteLine("")
+		// Generate type alias instead of class
+		c.tsw.WriteLiterally("type ")
+		c.tsw.WriteLiterally(className)
+		c.tsw.WriteLiterally(" = ")
+		// Use AST-based type writing to preserve qualified names like os.FileInfo
+		c.WriteTypeExpr(a.Type)
+		c.tsw.WriteLine(";")
+		c.tsw.WriteLine("")
+	}
 
 	// Generate function declarations and implementations for each method
 	for _, fileSyntax := range c.pkg.Syntax {
@@ -450,10 +456,14 @@ func (c *GoToTSCompiler) WriteNamedTypeWithMethods(a *ast.TypeSpec) error {
 func (c *GoToTSCompiler) writeWrapperFunctionBody(decl *ast.FuncDecl, typeName string) error {
 	// Write function body statements directly - identifier mapping is handled by pre-computed analysis
 	if decl.Body != nil {
-		for _, stmt := range decl.Body.List {
+		for i, stmt := range decl.Body.List {
+			if err := c.writeGotoRegionsOpen(decl.Body, i); err != nil {
+				return err
+			}
 			if err := c.WriteStmt(stmt); err != nil {
 				return err
 			}
+			c.writeGotoRegionsClose(decl.Body, i, stmt)
 		}
 	}
 	return nil
@@ -478,6 +488,10 @@ func (c *GoToTSCompiler) WriteTypeSpec(a *ast.TypeSpec) error {
 		if c.hasReceiverMethods(a.Name.Name) {
 			return c.WriteNamedTypeWithMethods(a)
 		}
+		if enum := c.enumOf(a); enum != nil {
+			c.writeEnumTypeSpec(a, enum)
+			return nil
+		}
 
 		// Always export types for cross-file imports within the same package (but not if inside a function)
 		isInsideFunction := false
@@ -539,7 +553,11 @@ func (c *GoToTSCompiler) WriteInterfaceTypeSpec(a *ast.TypeSpec, t *ast.Interfac
 	if !ok {
 		return errors.Errorf("expected *types.Interface, got %T for %s when processing interface literal", goType, a.Name.Name)
 	}
-	c.WriteInterfaceType(ifaceType, t) // Pass the *ast.InterfaceType for comment fetching
+	if iface := c.sealedInterfaceOf(a); iface != nil {
+		c.writeSealedUnion(iface)
+	} else {
+		c.WriteInterfaceType(ifaceType, t) // Pass the *ast.InterfaceType for comment fetching
+	}
 	c.tsw.WriteLine("")
 
 	// Add code to register the interface with the runtime system
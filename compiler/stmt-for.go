@@ -126,6 +126,15 @@ func (c *GoToTSCompiler) WriteStmtForInit(stmt ast.Stmt) error {
 		return c.WriteValueExpr(s.X)
 	case *ast.IncDecStmt:
 		// Handle increment/decrement in init (e.g., for i++; ...)
+		if c.isBigIntExpr(s.X) {
+			return c.writeBigIntIncDec(s)
+		}
+		if handled, err := c.writeStrictIntIncDec(s); handled {
+			return err
+		}
+		if handled, err := c.writeComplexIncDec(s); handled {
+			return err
+		}
 		if err := c.WriteValueExpr(s.X); err != nil { // The expression (e.g., i)
 			return err
 		}
@@ -214,6 +223,15 @@ func (c *GoToTSCompiler) WriteStmtForPost(stmt ast.Stmt) error {
 	switch s := stmt.(type) {
 	case *ast.IncDecStmt:
 		// Handle increment/decrement (e.g., i++)
+		if c.isBigIntExpr(s.X) {
+			return c.writeBigIntIncDec(s)
+		}
+		if handled, err := c.writeStrictIntIncDec(s); handled {
+			return err
+		}
+		if handled, err := c.writeComplexIncDec(s); handled {
+			return err
+		}
 		if err := c.WriteValueExpr(s.X); err != nil { // The expression (e.g., i)
 			return err
 		}
@@ -0,0 +1,748 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeScript has no goto. Go restricts goto so that the target label is always
+// in the same statement list as the goto or in an enclosing one, and a forward
+// goto may not skip over variable declarations of that list. This makes it
+// possible to lower goto into labeled blocks and loops:
+//
+//   - A forward goto becomes `break` out of a labeled block that ends just
+//     before the target label: `__goto_fwd_L: { ... break __goto_fwd_L ... }`.
+//   - A backward goto becomes `continue` of a labeled loop that starts at the
+//     target label: `__goto_back_L: for (;;) { ... continue __goto_back_L ... break }`.
+//   - When those regions cannot be nested properly (a forward goto jumps over a
+//     label that is targeted from below), the statement list is lowered into a
+//     dispatch loop: `for (;;) { switch (state) { case 0: ... case 1: ... } break }`
+//     and each goto assigns the state of its target and continues the loop.
+//
+// The loops introduced for backward gotos and dispatch capture unlabeled
+// break and continue statements. Those are rewritten to set an escape variable
+// and leave the region, which re-issues the branch once outside of it.
+
+// gotoRegionKind is the strategy used to lower a goto region.
+type gotoRegionKind int
+
+const (
+	// gotoRegionForward is a labeled block that is left with break.
+	gotoRegionForward gotoRegionKind = iota
+	// gotoRegionBackward is a labeled loop that is re-entered with continue.
+	gotoRegionBackward
+	// gotoRegionDispatch is a labeled loop around a switch over a state variable.
+	gotoRegionDispatch
+)
+
+// GotoRegion is a contiguous range of statements in a statement list which is
+// wrapped in a labeled block or loop to implement goto.
+type GotoRegion struct {
+	// Kind is the lowering strategy of the region.
+	Kind gotoRegionKind
+	// Label is the TypeScript label of the block or loop.
+	Label string
+	// Start is the index of the first statement in the region.
+	Start int
+	// End is the index of the last statement in the region (inclusive).
+	End int
+	// States maps statement indexes to dispatch states (dispatch regions only).
+	States map[int]int
+	// EscapeBreak is set if an unlabeled break escapes through this region.
+	EscapeBreak bool
+	// EscapeContinue is set if an unlabeled continue escapes through this region.
+	EscapeContinue bool
+	// BreakParent is the next region an escaping break must leave, if any.
+	BreakParent *GotoRegion
+	// ContinueParent is the next region an escaping continue must leave, if any.
+	ContinueParent *GotoRegion
+}
+
+// intercepts returns true if the region is a loop which captures unlabeled
+// break and continue statements.
+func (r *GotoRegion) intercepts() bool {
+	return r.Kind != gotoRegionForward
+}
+
+// stateVar returns the name of the dispatch state variable.
+func (r *GotoRegion) stateVar() string {
+	return r.Label + "_state"
+}
+
+// escapeVar returns the name of the variable recording an escaping branch.
+func (r *GotoRegion) escapeVar() string {
+	return r.Label + "_esc"
+}
+
+// GotoBlockPlan describes how goto targets in a single statement list are lowered.
+type GotoBlockPlan struct {
+	// Regions are sorted outermost first.
+	Regions []*GotoRegion
+	// Err is set if the statement list cannot be lowered.
+	Err error
+}
+
+// GotoBranch describes how a goto, or a break/continue escaping a goto
+// region, is written.
+type GotoBranch struct {
+	// Region is the region jumped to (goto) or escaped from (break/continue).
+	Region *GotoRegion
+	// State is the dispatch state of the target label (dispatch regions only).
+	State int
+	// Escape is set for unlabeled break/continue statements leaving Region.
+	Escape bool
+	// Err is set if the goto cannot be lowered.
+	Err error
+}
+
+// gotoStmtSite is the position of a statement within its statement list.
+type gotoStmtSite struct {
+	owner ast.Node
+	list  []ast.Stmt
+	index int
+}
+
+// gotoAnalyzer computes the goto lowering for a single function body.
+type gotoAnalyzer struct {
+	analysis *Analysis
+	pkg      *packages.Package
+
+	// sites maps statements to their position in the enclosing statement list.
+	sites map[ast.Stmt]gotoStmtSite
+	// labels maps goto target labels to the site of the labeled statement.
+	labels map[*types.Label]gotoStmtSite
+	// gotos lists the goto statements of the function in source order.
+	gotos []*ast.BranchStmt
+	// plans maps statement list owners to their lowering plan.
+	plans map[ast.Node]*GotoBlockPlan
+	// forward and backward map labels to the regions implementing gotos to them.
+	forward, backward map[*types.Label]*GotoRegion
+}
+
+// analyzeGotos computes the goto lowering plans for every function in the package.
+func (a *Analysis) analyzeGotos(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			var body *ast.BlockStmt
+			switch fn := n.(type) {
+			case *ast.FuncDecl:
+				body = fn.Body
+			case *ast.FuncLit:
+				body = fn.Body
+			}
+			if body != nil {
+				g := &gotoAnalyzer{analysis: a, pkg: pkg}
+				g.analyzeFunc(body)
+			}
+			return true
+		})
+	}
+}
+
+// analyzeFunc computes the goto lowering for a function body.
+// Nested function literals are analyzed separately.
+func (g *gotoAnalyzer) analyzeFunc(body *ast.BlockStmt) {
+	g.sites = make(map[ast.Stmt]gotoStmtSite)
+	g.labels = make(map[*types.Label]gotoStmtSite)
+	g.plans = make(map[ast.Node]*GotoBlockPlan)
+	g.forward = make(map[*types.Label]*GotoRegion)
+	g.backward = make(map[*types.Label]*GotoRegion)
+
+	// Record the site of every statement and collect goto statements.
+	targeted := make(map[*types.Label]bool)
+	branchLabels := make(map[*types.Label]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			g.recordSites(s, s.List)
+		case *ast.CaseClause:
+			g.recordSites(s, s.Body)
+		case *ast.CommClause:
+			g.recordSites(s, s.Body)
+		case *ast.BranchStmt:
+			if s.Label == nil {
+				break
+			}
+			label, _ := g.pkg.TypesInfo.Uses[s.Label].(*types.Label)
+			if label == nil {
+				break
+			}
+			if s.Tok == token.GOTO {
+				g.gotos = append(g.gotos, s)
+				targeted[label] = true
+			} else {
+				branchLabels[label] = true
+			}
+		}
+		return true
+	})
+	if len(g.gotos) == 0 {
+		return
+	}
+
+	for stmt, site := range g.sites {
+		labeled, ok := stmt.(*ast.LabeledStmt)
+		if !ok {
+			continue
+		}
+		label, _ := g.pkg.TypesInfo.Defs[labeled.Label].(*types.Label)
+		if label == nil || !targeted[label] {
+			continue
+		}
+		g.labels[label] = site
+		if !branchLabels[label] {
+			g.analysis.GotoOnlyLabels[label] = true
+		}
+	}
+
+	g.buildPlans()
+	for owner, plan := range g.plans {
+		g.analysis.GotoBlocks[owner] = plan
+	}
+	g.walkList(body, body.List, nil, nil, nil)
+}
+
+// recordSites records the position of each statement in a statement list.
+// Chains of labeled statements share the site of the outermost statement.
+func (g *gotoAnalyzer) recordSites(owner ast.Node, list []ast.Stmt) {
+	for i, stmt := range list {
+		site := gotoStmtSite{owner: owner, list: list, index: i}
+		for stmt != nil {
+			g.sites[stmt] = site
+			labeled, ok := stmt.(*ast.LabeledStmt)
+			if !ok {
+				break
+			}
+			stmt = labeled.Stmt
+		}
+	}
+}
+
+// gotoLabel returns the label targeted by a goto statement.
+func (g *gotoAnalyzer) gotoLabel(stmt *ast.BranchStmt) *types.Label {
+	label, _ := g.pkg.TypesInfo.Uses[stmt.Label].(*types.Label)
+	return label
+}
+
+// containingIndex returns the index of the statement in list which contains pos.
+func containingIndex(list []ast.Stmt, pos token.Pos) int {
+	for i, stmt := range list {
+		if stmt.Pos() <= pos && pos < stmt.End() {
+			return i
+		}
+	}
+	return -1
+}
+
+// buildPlans computes the regions of every statement list containing goto targets.
+func (g *gotoAnalyzer) buildPlans() {
+	type ownerInfo struct {
+		list    []ast.Stmt
+		regions []*GotoRegion
+		labels  map[int][]*types.Label
+	}
+	owners := make(map[ast.Node]*ownerInfo)
+	var ownerOrder []ast.Node
+
+	for _, stmt := range g.gotos {
+		label := g.gotoLabel(stmt)
+		site, ok := g.labels[label]
+		if !ok {
+			continue
+		}
+		info := owners[site.owner]
+		if info == nil {
+			info = &ownerInfo{list: site.list, labels: make(map[int][]*types.Label)}
+			owners[site.owner] = info
+			ownerOrder = append(ownerOrder, site.owner)
+		}
+		if !slices.Contains(info.labels[site.index], label) {
+			info.labels[site.index] = append(info.labels[site.index], label)
+		}
+
+		from := containingIndex(site.list, stmt.Pos())
+		if from < 0 {
+			continue
+		}
+		if from < site.index {
+			region := g.forward[label]
+			if region == nil {
+				region = &GotoRegion{
+					Kind:  gotoRegionForward,
+					Label: "__goto_fwd_" + label.Name(),
+					Start: from,
+					End:   site.index - 1,
+				}
+				g.forward[label] = region
+				info.regions = append(info.regions, region)
+			}
+			region.Start = min(region.Start, from)
+		} else {
+			region := g.backward[label]
+			if region == nil {
+				region = &GotoRegion{
+					Kind:  gotoRegionBackward,
+					Label: "__goto_back_" + label.Name(),
+					Start: site.index,
+					End:   from,
+				}
+				g.backward[label] = region
+				info.regions = append(info.regions, region)
+			}
+			region.End = max(region.End, from)
+		}
+	}
+
+	for _, owner := range ownerOrder {
+		info := owners[owner]
+		plan := &GotoBlockPlan{}
+		g.plans[owner] = plan
+		if !g.nestRegions(info.list, info.regions) {
+			region, err := g.dispatchRegion(info.list, info.regions, info.labels)
+			if err != nil {
+				plan.Err = err
+				continue
+			}
+			plan.Regions = []*GotoRegion{region}
+			continue
+		}
+		slices.SortStableFunc(info.regions, func(a, b *GotoRegion) int {
+			if a.Start != b.Start {
+				return a.Start - b.Start
+			}
+			return b.End - a.End
+		})
+		plan.Regions = info.regions
+	}
+}
+
+// nestRegions widens regions until every pair is either nested or disjoint.
+// Backward regions may always be extended towards the end of the list, and
+// forward regions may be extended towards the start of an overlapping forward
+// region. Returns false if the regions cannot be nested.
+func (g *gotoAnalyzer) nestRegions(list []ast.Stmt, regions []*GotoRegion) bool {
+	for changed := true; changed; {
+		changed = false
+		for _, r := range regions {
+			// Variables declared in a backward region must stay in scope for
+			// the statements following it.
+			if r.Kind == gotoRegionBackward && r.End < len(list)-1 && g.declsUsedAfter(list, r.Start, r.End) {
+				r.End = len(list) - 1
+				changed = true
+			}
+		}
+		for _, a := range regions {
+			for _, b := range regions {
+				if !(a.Start < b.Start && b.Start <= a.End && a.End < b.End) {
+					continue
+				}
+				switch {
+				case a.Kind == gotoRegionBackward:
+					a.End = b.End
+				case b.Kind == gotoRegionForward:
+					b.Start = a.Start
+				default:
+					// A backward region starts inside a forward region and
+					// extends past its end.
+					return false
+				}
+				changed = true
+			}
+		}
+	}
+	return true
+}
+
+// dispatchRegion builds a single dispatch region covering all regions.
+func (g *gotoAnalyzer) dispatchRegion(list []ast.Stmt, regions []*GotoRegion, labels map[int][]*types.Label) (*GotoRegion, error) {
+	start := len(list)
+	for _, r := range regions {
+		start = min(start, r.Start)
+	}
+
+	region := &GotoRegion{
+		Kind:   gotoRegionDispatch,
+		Start:  start,
+		End:    len(list) - 1,
+		States: map[int]int{start: 0},
+	}
+	var firstLabel *types.Label
+	var boundaries []int
+	for idx := start; idx < len(list); idx++ {
+		lbls := labels[idx]
+		if len(lbls) == 0 {
+			continue
+		}
+		if firstLabel == nil {
+			firstLabel = lbls[0]
+		}
+		if _, ok := region.States[idx]; !ok {
+			region.States[idx] = len(region.States)
+			boundaries = append(boundaries, idx)
+		}
+		// All gotos to labels of this list now jump through the dispatch loop.
+		for _, label := range lbls {
+			if g.forward[label] != nil {
+				g.forward[label] = region
+			}
+			if g.backward[label] != nil {
+				g.backward[label] = region
+			}
+		}
+	}
+	region.Label = "__goto_sm_" + firstLabel.Name()
+
+	// Each segment between two labels becomes a switch case. A variable
+	// declared in one segment and used in another would be read before its
+	// declaration ran when jumping directly into the later case.
+	segStart := start
+	for i := 0; i <= len(boundaries); i++ {
+		segEnd := len(list) - 1
+		if i < len(boundaries) {
+			segEnd = boundaries[i] - 1
+		}
+		if segEnd >= segStart && g.declsUsedAfter(list, segStart, segEnd) {
+			return nil, newDiagnosticError(firstLabel.Pos(), DiagnosticUnsupported,
+				"declare the variables before the first label",
+				"goto to label %s: variables declared between labels are used across labels, which is not supported", firstLabel.Name())
+		}
+		if i < len(boundaries) {
+			segStart = boundaries[i]
+		}
+	}
+	return region, nil
+}
+
+// declsUsedAfter returns true if an object declared by the statements
+// list[start:end+1] is referenced by the statements following end.
+func (g *gotoAnalyzer) declsUsedAfter(list []ast.Stmt, start, end int) bool {
+	declared := make(map[types.Object]bool)
+	for _, stmt := range list[start : end+1] {
+		for {
+			labeled, ok := stmt.(*ast.LabeledStmt)
+			if !ok {
+				break
+			}
+			stmt = labeled.Stmt
+		}
+		var idents []*ast.Ident
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE {
+				continue
+			}
+			for _, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					idents = append(idents, ident)
+				}
+			}
+		case *ast.DeclStmt:
+			genDecl, ok := s.Decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				switch sp := spec.(type) {
+				case *ast.ValueSpec:
+					idents = append(idents, sp.Names...)
+				case *ast.TypeSpec:
+					idents = append(idents, sp.Name)
+				}
+			}
+		}
+		for _, ident := range idents {
+			if obj := g.pkg.TypesInfo.Defs[ident]; obj != nil {
+				declared[obj] = true
+			}
+		}
+	}
+	if len(declared) == 0 {
+		return false
+	}
+
+	used := false
+	for _, stmt := range list[end+1:] {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && declared[g.pkg.TypesInfo.Uses[ident]] {
+				used = true
+			}
+			return !used
+		})
+		if used {
+			return true
+		}
+	}
+	return false
+}
+
+// walkList records how goto statements and escaping break/continue
+// statements in a statement list are written.
+//
+// brk and cont are the intercepting regions entered since the innermost
+// statement targeted by an unlabeled break or continue respectively, and
+// owners are the statement lists which can be reached without crossing a
+// function boundary in the generated code.
+func (g *gotoAnalyzer) walkList(owner ast.Node, list []ast.Stmt, brk, cont []*GotoRegion, owners []ast.Node) {
+	owners = append(slices.Clip(owners), owner)
+	plan := g.plans[owner]
+	for i, stmt := range list {
+		stmtBrk, stmtCont := brk, cont
+		if plan != nil {
+			for _, r := range plan.Regions {
+				if r.intercepts() && r.Start <= i && i <= r.End {
+					stmtBrk = append(slices.Clip(stmtBrk), r)
+					stmtCont = append(slices.Clip(stmtCont), r)
+				}
+			}
+		}
+		g.walkStmt(stmt, stmtBrk, stmtCont, owners)
+	}
+}
+
+// walkStmt implements walkList for a single statement.
+func (g *gotoAnalyzer) walkStmt(stmt ast.Stmt, brk, cont []*GotoRegion, owners []ast.Node) {
+	switch s := stmt.(type) {
+	case *ast.LabeledStmt:
+		g.walkStmt(s.Stmt, brk, cont, owners)
+	case *ast.BlockStmt:
+		g.walkList(s, s.List, brk, cont, owners)
+	case *ast.IfStmt:
+		g.walkStmt(s.Body, brk, cont, owners)
+		if s.Else != nil {
+			g.walkStmt(s.Else, brk, cont, owners)
+		}
+	case *ast.ForStmt:
+		g.walkList(s.Body, s.Body.List, nil, nil, owners)
+	case *ast.RangeStmt:
+		g.walkList(s.Body, s.Body.List, nil, nil, owners)
+	case *ast.SwitchStmt:
+		for _, clause := range s.Body.List {
+			if cc, ok := clause.(*ast.CaseClause); ok {
+				g.walkList(cc, cc.Body, nil, cont, owners)
+			}
+		}
+	case *ast.TypeSwitchStmt:
+		// Type switch cases are written as closures.
+		for _, clause := range s.Body.List {
+			if cc, ok := clause.(*ast.CaseClause); ok {
+				g.walkList(cc, cc.Body, nil, nil, nil)
+			}
+		}
+	case *ast.SelectStmt:
+		// Select cases are written as closures.
+		for _, clause := range s.Body.List {
+			if cc, ok := clause.(*ast.CommClause); ok {
+				g.walkList(cc, cc.Body, nil, nil, nil)
+			}
+		}
+	case *ast.BranchStmt:
+		g.walkBranch(s, brk, cont, owners)
+	}
+}
+
+// walkBranch records how a branch statement is written.
+func (g *gotoAnalyzer) walkBranch(stmt *ast.BranchStmt, brk, cont []*GotoRegion, owners []ast.Node) {
+	switch {
+	case stmt.Tok == token.GOTO:
+		label := g.gotoLabel(stmt)
+		site, ok := g.labels[label]
+		if !ok {
+			return
+		}
+		branch := &GotoBranch{}
+		g.analysis.GotoBranches[stmt] = branch
+		if !slices.Contains(owners, site.owner) {
+			branch.Err = newDiagnosticError(stmt.Pos(), DiagnosticUnsupported,
+				"set a variable and break out of the type switch or select, then goto after it",
+				"goto %s: jumping out of a type switch or select case is not supported", label.Name())
+			return
+		}
+		from := containingIndex(site.list, stmt.Pos())
+		if from < site.index {
+			branch.Region = g.forward[label]
+		} else {
+			branch.Region = g.backward[label]
+		}
+		if branch.Region == nil {
+			branch.Err = errors.Errorf("goto %s: no lowering found for label", label.Name())
+			return
+		}
+		if branch.Region.Kind == gotoRegionDispatch {
+			branch.State = branch.Region.States[site.index]
+		}
+	case stmt.Label == nil && (stmt.Tok == token.BREAK || stmt.Tok == token.CONTINUE):
+		stack := brk
+		if stmt.Tok == token.CONTINUE {
+			stack = cont
+		}
+		if len(stack) == 0 {
+			return
+		}
+		g.analysis.GotoBranches[stmt] = &GotoBranch{Region: stack[len(stack)-1], Escape: true}
+		for i := len(stack) - 1; i >= 0; i-- {
+			var parent *GotoRegion
+			if i > 0 {
+				parent = stack[i-1]
+			}
+			if stmt.Tok == token.BREAK {
+				stack[i].EscapeBreak = true
+				stack[i].BreakParent = parent
+			} else {
+				stack[i].EscapeContinue = true
+				stack[i].ContinueParent = parent
+			}
+		}
+	}
+}
+
+// writeGotoRegionsOpen opens the goto regions starting at the statement with
+// index idx in the statement list owned by owner. It must be called before
+// writing each statement of a statement list.
+func (c *GoToTSCompiler) writeGotoRegionsOpen(owner ast.Node, idx int) error {
+	plan := c.analysis.GotoBlocks[owner]
+	if plan == nil {
+		return nil
+	}
+	if plan.Err != nil {
+		return plan.Err
+	}
+	for _, r := range plan.Regions {
+		if r.Start == idx {
+			if r.EscapeBreak || r.EscapeContinue {
+				c.tsw.WriteLinef("let %s = 0", r.escapeVar())
+			}
+			switch r.Kind {
+			case gotoRegionForward:
+				c.tsw.WriteLinef("%s: {", r.Label)
+				c.tsw.Indent(1)
+			case gotoRegionBackward:
+				c.tsw.WriteLinef("%s: for (;;) {", r.Label)
+				c.tsw.Indent(1)
+			case gotoRegionDispatch:
+				c.tsw.WriteLinef("let %s: number = 0", r.stateVar())
+				c.tsw.WriteLinef("%s: for (;;) {", r.Label)
+				c.tsw.Indent(1)
+				c.tsw.WriteLinef("switch (%s) {", r.stateVar())
+				c.tsw.Indent(1)
+			}
+		}
+		if r.Kind == gotoRegionDispatch {
+			if state, ok := r.States[idx]; ok {
+				if idx != r.Start {
+					c.tsw.Indent(-1)
+				}
+				c.tsw.WriteLinef("case %d:", state)
+				c.tsw.Indent(1)
+			}
+		}
+	}
+	return nil
+}
+
+// writeGotoRegionsClose closes the goto regions ending at the statement stmt
+// with index idx in the statement list owned by owner. It must be called
+// after writing each statement of a statement list.
+func (c *GoToTSCompiler) writeGotoRegionsClose(owner ast.Node, idx int, stmt ast.Stmt) {
+	plan := c.analysis.GotoBlocks[owner]
+	if plan == nil {
+		return
+	}
+	for i := len(plan.Regions) - 1; i >= 0; i-- {
+		r := plan.Regions[i]
+		if r.End != idx {
+			continue
+		}
+		switch r.Kind {
+		case gotoRegionForward:
+			c.tsw.Indent(-1)
+			c.tsw.WriteLine("}")
+			continue
+		case gotoRegionBackward:
+			if !isTerminatingBranch(stmt) {
+				c.tsw.WriteLine("break")
+			}
+		case gotoRegionDispatch:
+			c.tsw.Indent(-2)
+			c.tsw.WriteLine("}")
+			c.tsw.WriteLine("break")
+		}
+		c.tsw.Indent(-1)
+		c.tsw.WriteLine("}")
+		if r.EscapeBreak {
+			c.tsw.WriteLinef("if (%s === 1) {", r.escapeVar())
+			c.tsw.Indent(1)
+			c.writeGotoEscape(r.BreakParent, token.BREAK)
+			c.tsw.Indent(-1)
+			c.tsw.WriteLine("}")
+		}
+		if r.EscapeContinue {
+			c.tsw.WriteLinef("if (%s === 2) {", r.escapeVar())
+			c.tsw.Indent(1)
+			c.writeGotoEscape(r.ContinueParent, token.CONTINUE)
+			c.tsw.Indent(-1)
+			c.tsw.WriteLine("}")
+		}
+	}
+}
+
+// writeGotoEscape writes an unlabeled break or continue which leaves the goto
+// region r first, or the plain branch statement if r is nil.
+func (c *GoToTSCompiler) writeGotoEscape(r *GotoRegion, tok token.Token) {
+	if r == nil {
+		c.tsw.WriteLine(tok.String())
+		return
+	}
+	code := 1
+	if tok == token.CONTINUE {
+		code = 2
+	}
+	c.tsw.WriteLinef("%s = %d", r.escapeVar(), code)
+	c.tsw.WriteLinef("break %s", r.Label)
+}
+
+// writeGoto writes a goto statement as a jump to its lowered target label.
+func (c *GoToTSCompiler) writeGoto(stmt *ast.BranchStmt) error {
+	branch := c.analysis.GotoBranches[stmt]
+	if branch == nil {
+		return fmt.Errorf("goto %s: target label not found", stmt.Label.Name)
+	}
+	if branch.Err != nil {
+		return branch.Err
+	}
+	switch branch.Region.Kind {
+	case gotoRegionForward:
+		c.tsw.WriteLinef("break %s", branch.Region.Label)
+	case gotoRegionBackward:
+		c.tsw.WriteLinef("continue %s", branch.Region.Label)
+	case gotoRegionDispatch:
+		c.tsw.WriteLinef("%s = %d", branch.Region.stateVar(), branch.State)
+		c.tsw.WriteLinef("continue %s", branch.Region.Label)
+	}
+	return nil
+}
+
+// isTerminatingBranch returns true if stmt unconditionally transfers control.
+func isTerminatingBranch(stmt ast.Stmt) bool {
+	for {
+		labeled, ok := stmt.(*ast.LabeledStmt)
+		if !ok {
+			break
+		}
+		stmt = labeled.Stmt
+	}
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	}
+	return false
+}
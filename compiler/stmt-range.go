@@ -91,7 +91,9 @@ func (c *GoToTSCompiler) WriteStmtRange(exp *ast.RangeStmt) error {
 		return c.writeChannelRange(exp)
 	}
 
-	return errors.Errorf("unsupported range loop type: %T for expression %v", underlying, exp)
+	return newDiagnosticError(exp.X.Pos(), DiagnosticUnsupported,
+		"range over a slice, array, string, map, channel, integer or iterator function instead",
+		"unsupported range loop type: %s", c.pkg.TypesInfo.TypeOf(exp.X))
 }
 
 // Helper functions
@@ -96,10 +96,14 @@ func (c *GoToTSCompiler) WriteStmtSelect(exp *ast.SelectStmt) error {
 				c.tsw.Indent(1)
 				c.tsw.WriteLine("")
 				// Write the case body
-				for _, bodyStmt := range commClause.Body {
+				for j, bodyStmt := range commClause.Body {
+					if err := c.writeGotoRegionsOpen(commClause, j); err != nil {
+						return err
+					}
 					if err := c.WriteStmt(bodyStmt); err != nil {
 						return fmt.Errorf("failed to write statement in select default case body (onSelected): %w", err)
 					}
+					c.writeGotoRegionsClose(commClause, j, bodyStmt)
 				}
 				c.tsw.Indent(-1)
 				c.tsw.WriteLine("}") // Close onSelected handler
@@ -166,7 +170,7 @@ func (c *GoToTSCompiler) WriteStmtSelect(exp *ast.SelectStmt) error {
 				c.tsw.WriteLiterally(",")
 				c.tsw.WriteLine("")
 				c.tsw.WriteLiterally("value: ")
-				if err := c.WriteValueExpr(comm.Value); err != nil { // The value expression
+				if err := c.writeInterfaceValue(comm.Value, c.chanElemType(comm.Chan)); err != nil { // The value expression
 					return fmt.Errorf("failed to write value expression in select send case: %w", err)
 				}
 				c.tsw.WriteLiterally(",")
@@ -216,10 +220,14 @@ func (c *GoToTSCompiler) WriteStmtSelect(exp *ast.SelectStmt) error {
 			// as the operation was already performed by selectReceive/selectSend and the result is in 'result'.
 
 			// Write the case body
-			for _, bodyStmt := range commClause.Body {
+			for j, bodyStmt := range commClause.Body {
+				if err := c.writeGotoRegionsOpen(commClause, j); err != nil {
+					return err
+				}
 				if err := c.WriteStmt(bodyStmt); err != nil {
 					return fmt.Errorf("failed to write statement in select case body (onSelected): %w", err)
 				}
+				c.writeGotoRegionsClose(commClause, j, bodyStmt)
 			}
 
 			c.tsw.Indent(-1)
@@ -54,6 +54,18 @@ func (c *GoToTSCompiler) WriteStmtTypeSwitch(stmt *ast.TypeSwitchStmt) error {
 		return errors.Errorf("unknown Assign type in TypeSwitchStmt: %T", stmt.Assign)
 	}
 
+	// Type switches over sealed interfaces narrow the union
+	if cases := c.sealedTypeSwitchCases(stmt, subjectExpr); cases != nil {
+		if err := c.writeSealedTypeSwitch(stmt, subjectExpr, cases); err != nil {
+			return err
+		}
+		if stmt.Init != nil {
+			c.tsw.Indent(-1)
+			c.tsw.WriteLine("}") // Close outer block
+		}
+		return nil
+	}
+
 	// Build the array of case configurations for $.typeSwitch
 	c.tsw.WriteLiterally("$.typeSwitch(")
 	if err := c.WriteValueExpr(subjectExpr); err != nil {
@@ -66,7 +78,7 @@ func (c *GoToTSCompiler) WriteStmtTypeSwitch(stmt *ast.TypeSwitchStmt) error {
 	c.tsw.WriteLiterally(", [")
 
 	stmtBodyList := stmt.Body.List
-	var defaultCaseBody []ast.Stmt
+	var defaultCase *ast.CaseClause
 
 	for i, caseClauseStmt := range stmtBodyList {
 		caseClause, ok := caseClauseStmt.(*ast.CaseClause)
@@ -75,7 +87,7 @@ func (c *GoToTSCompiler) WriteStmtTypeSwitch(stmt *ast.TypeSwitchStmt) error {
 		}
 
 		if len(caseClause.List) == 0 { // Default case
-			defaultCaseBody = caseClause.Body
+			defaultCase = caseClause
 			continue // Process default case after type cases
 		}
 
@@ -108,10 +120,14 @@ func (c *GoToTSCompiler) WriteStmtTypeSwitch(stmt *ast.TypeSwitchStmt) error {
 			c.tsw.WriteLine("")
 		}
 
-		for _, bodyStmt := range caseClauseBody {
+		for j, bodyStmt := range caseClauseBody {
+			if err := c.writeGotoRegionsOpen(caseClause, j); err != nil {
+				return err
+			}
 			if err := c.WriteStmt(bodyStmt); err != nil {
 				return fmt.Errorf("failed to write statement in type switch case body: %w", err)
 			}
+			c.writeGotoRegionsClose(caseClause, j, bodyStmt)
 		}
 
 		if len(caseClauseBody) != 0 {
@@ -124,14 +140,18 @@ func (c *GoToTSCompiler) WriteStmtTypeSwitch(stmt *ast.TypeSwitchStmt) error {
 	c.tsw.WriteLiterally("]") // Close cases array
 
 	// Add default case function if it exists
-	if len(defaultCaseBody) != 0 {
+	if defaultCase != nil && len(defaultCase.Body) != 0 {
 		c.tsw.WriteLiterally(", () => {")
 		c.tsw.Indent(1)
 		c.tsw.WriteLine("")
-		for _, bodyStmt := range defaultCaseBody {
+		for j, bodyStmt := range defaultCase.Body {
+			if err := c.writeGotoRegionsOpen(defaultCase, j); err != nil {
+				return err
+			}
 			if err := c.WriteStmt(bodyStmt); err != nil {
 				return fmt.Errorf("failed to write statement in type switch default case body: %w", err)
 			}
+			c.writeGotoRegionsClose(defaultCase, j, bodyStmt)
 		}
 		c.tsw.Indent(-1)
 		c.tsw.WriteLiterally("}") // Close default case function
@@ -32,8 +32,18 @@ import (
 //   - Type switch statements (`ast.TypeSwitchStmt`): `WriteStmtTypeSwitch`.
 //   - Labeled statements (`ast.LabeledStmt`): `WriteStmtLabeled`.
 //
-// If an unknown statement type is encountered, it returns an error.
+// If an unknown statement type is encountered, it returns an error. Errors
+// are positioned at the innermost statement which failed.
 func (c *GoToTSCompiler) WriteStmt(a ast.Stmt) error {
+	if err := c.writeStmt(a); err != nil {
+		return withDiagnosticPos(a.Pos(), err)
+	}
+	return nil
+}
+
+// writeStmt writes the statement a, see WriteStmt.
+func (c *GoToTSCompiler) writeStmt(a ast.Stmt) error {
+	c.tsw.MarkPos(a.Pos())
 	switch exp := a.(type) {
 	case *ast.BlockStmt:
 		if err := c.WriteStmtBlock(exp, false); err != nil {
@@ -151,6 +161,24 @@ func (c *GoToTSCompiler) WriteStmtDecl(stmt *ast.DeclStmt) error {
 // WriteStmtIncDec handles increment and decrement statements (`ast.IncDecStmt`).
 // It writes the expression followed by `++` or `--`.
 func (c *GoToTSCompiler) WriteStmtIncDec(stmt *ast.IncDecStmt) error {
+	if c.isBigIntExpr(stmt.X) {
+		if err := c.writeBigIntIncDec(stmt); err != nil {
+			return fmt.Errorf("failed to write increment/decrement expression: %w", err)
+		}
+		c.tsw.WriteLine("")
+		return nil
+	}
+	handled, err := c.writeStrictIntIncDec(stmt)
+	if !handled {
+		handled, err = c.writeComplexIncDec(stmt)
+	}
+	if handled {
+		if err != nil {
+			return fmt.Errorf("failed to write increment/decrement expression: %w", err)
+		}
+		c.tsw.WriteLine("")
+		return nil
+	}
 	if err := c.WriteValueExpr(stmt.X); err != nil { // The expression (e.g., i)
 		return fmt.Errorf("failed to write increment/decrement expression: %w", err)
 	}
@@ -163,8 +191,15 @@ func (c *GoToTSCompiler) WriteStmtIncDec(stmt *ast.IncDecStmt) error {
 	return nil
 }
 
-// WriteStmtBranch handles branch statements (`ast.BranchStmt`), such as `break` and `continue`.
+// WriteStmtBranch handles branch statements (`ast.BranchStmt`), such as `break`, `continue` and `goto`.
+// Goto statements are lowered as described in stmt-goto.go.
 func (c *GoToTSCompiler) WriteStmtBranch(stmt *ast.BranchStmt) error {
+	// Unlabeled break/continue inside a goto region must leave the region first
+	if branch := c.analysis.GotoBranches[stmt]; branch != nil && branch.Escape {
+		c.writeGotoEscape(branch.Region, stmt.Tok)
+		return nil
+	}
+
 	switch stmt.Tok {
 	case token.BREAK:
 		if stmt.Label != nil {
@@ -179,9 +214,9 @@ func (c *GoToTSCompiler) WriteStmtBranch(stmt *ast.BranchStmt) error {
 			c.tsw.WriteLine("continue")
 		}
 	case token.GOTO:
-		// TypeScript doesn't support goto, but we can handle it by skipping it
-		// since the labeled statement restructuring should handle the control flow
-		c.tsw.WriteCommentLinef("goto %s // goto statement skipped", stmt.Label.Name)
+		// TypeScript doesn't support goto, jump through the labeled block or loop
+		// which the analysis placed around the target label.
+		return c.writeGoto(stmt)
 	case token.FALLTHROUGH:
 		// Fallthrough is handled in switch statements, should not appear elsewhere
 		c.tsw.WriteCommentLinef("fallthrough // fallthrough statement skipped")
@@ -197,18 +232,20 @@ func (c *GoToTSCompiler) WriteStmtBranch(stmt *ast.BranchStmt) error {
 // It handles `go func(){...}()`, `go namedFunc(args)`, and `go x.Method(args)`.
 func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 	// Handle goroutine statement
-	// Translate 'go func() { ... }()' to 'queueMicrotask(() => { ... compiled body ... })'
+	// Translate 'go func() { ... }()' to '$.go(() => { ... compiled body ... })'
 	callExpr := exp.Call
 
 	switch fun := callExpr.Fun.(type) {
 	case *ast.FuncLit:
 		// For function literals, we need to check if the function literal itself is async
-		// This happens during analysis in analysisVisitor.Visit for FuncLit nodes
-		isAsync := c.analysis.IsFuncLitAsync(fun)
+		// This happens during analysis in analysisVisitor.Visit for FuncLit nodes.
+		// Goroutines with loops are made async so the loops can be preempted.
+		isAsync := c.analysis.IsFuncLitAsync(fun) || c.hasYieldLoops(fun)
+		c.markAsyncFunc(fun, isAsync)
 		if isAsync {
-			c.tsw.WriteLiterally("queueMicrotask(async () => ")
+			c.tsw.WriteLiterally("$.go(async () => ")
 		} else {
-			c.tsw.WriteLiterally("queueMicrotask(() => ")
+			c.tsw.WriteLiterally("$.go(() => ")
 		}
 
 		// Compile the function literal's body directly
@@ -216,7 +253,7 @@ func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 			return fmt.Errorf("failed to write goroutine function literal body: %w", err)
 		}
 
-		c.tsw.WriteLine(")") // Close the queueMicrotask statement
+		c.tsw.WriteLine(")") // Close the $.go statement
 
 	case *ast.Ident:
 		// Handle named functions: go namedFunc(args)
@@ -229,9 +266,9 @@ func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 		// Check if the function is async
 		isAsync := c.analysis.IsAsyncFunc(obj)
 		if isAsync {
-			c.tsw.WriteLiterally("queueMicrotask(async () => {")
+			c.tsw.WriteLiterally("$.go(async () => {")
 		} else {
-			c.tsw.WriteLiterally("queueMicrotask(() => {")
+			c.tsw.WriteLiterally("$.go(() => {")
 		}
 
 		c.tsw.Indent(1)
@@ -259,7 +296,7 @@ func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 		c.tsw.WriteLine("")
 
 		c.tsw.Indent(-1)
-		c.tsw.WriteLine("})") // Close the queueMicrotask callback and the statement
+		c.tsw.WriteLine("})") // Close the $.go callback and the statement
 	case *ast.SelectorExpr:
 		// Handle selector expressions: go x.Method(args)
 		// Get the object for the selected method
@@ -271,9 +308,9 @@ func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 		// Check if the function is async
 		isAsync := c.analysis.IsAsyncFunc(obj)
 		if isAsync {
-			c.tsw.WriteLiterally("queueMicrotask(async () => {")
+			c.tsw.WriteLiterally("$.go(async () => {")
 		} else {
-			c.tsw.WriteLiterally("queueMicrotask(() => {")
+			c.tsw.WriteLiterally("$.go(() => {")
 		}
 
 		c.tsw.Indent(1)
@@ -314,11 +351,11 @@ func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 		c.tsw.WriteLine("")
 
 		c.tsw.Indent(-1)
-		c.tsw.WriteLine("})") // Close the queueMicrotask callback and the statement
+		c.tsw.WriteLine("})") // Close the $.go callback and the statement
 	case *ast.TypeAssertExpr:
 		// Handle type assertion expressions: go x.(func())()
 		// We assume this is always synchronous (no async function returned by type assertion)
-		c.tsw.WriteLiterally("queueMicrotask(() => {")
+		c.tsw.WriteLiterally("$.go(() => {")
 
 		c.tsw.Indent(1)
 		c.tsw.WriteLine("")
@@ -345,7 +382,7 @@ func (c *GoToTSCompiler) WriteStmtGo(exp *ast.GoStmt) error {
 		c.tsw.WriteLine("")
 
 		c.tsw.Indent(-1)
-		c.tsw.WriteLine("})") // Close the queueMicrotask callback and the statement
+		c.tsw.WriteLine("})") // Close the $.go callback and the statement
 	default:
 		return errors.Errorf("unhandled goroutine function type: %T", callExpr.Fun)
 	}
@@ -428,7 +465,8 @@ func (c *GoToTSCompiler) WriteStmtExpr(exp *ast.ExprStmt) error {
 // which has the form `ch <- value`, into its asynchronous TypeScript equivalent.
 // The translation is `await ch_ts.send(value_ts)`.
 // Both the channel expression (`exp.Chan`) and the value expression (`exp.Value`)
-// are translated using `WriteValueExpr`. The `await` keyword is used because
+// are translated using `WriteValueExpr`, wrapping pointers which may be nil
+// sent on a channel of interfaces with `$.typedNilOf`. The `await` keyword is used because
 // channel send operations are asynchronous in the TypeScript model.
 // The statement is terminated with a newline.
 func (c *GoToTSCompiler) WriteStmtSend(exp *ast.SendStmt) error {
@@ -438,7 +476,7 @@ func (c *GoToTSCompiler) WriteStmtSend(exp *ast.SendStmt) error {
 		return fmt.Errorf("failed to write channel expression in send statement: %w", err)
 	}
 	c.tsw.WriteLiterally(", ")
-	if err := c.WriteValueExpr(exp.Value); err != nil { // The value expression
+	if err := c.writeInterfaceValue(exp.Value, c.chanElemType(exp.Chan)); err != nil { // The value expression
 		return fmt.Errorf("failed to write value expression in send statement: %w", err)
 	}
 	c.tsw.WriteLiterally(")")
@@ -607,7 +645,7 @@ func (c *GoToTSCompiler) WriteStmtReturn(exp *ast.ReturnStmt) error {
 				continue
 			}
 
-			if err := c.WriteValueExpr(res); err != nil { // Return results are values
+			if err := c.writeInterfaceValue(res, c.returnResultType(exp, i)); err != nil { // Return results are values
 				return err
 			}
 		}
@@ -732,6 +770,9 @@ func (c *GoToTSCompiler) WriteStmtBlock(exp *ast.BlockStmt, suppressNewline bool
 	c.tsw.WriteLine("{")
 	c.tsw.Indent(1)
 
+	// Give way to other goroutines at each iteration of a loop
+	c.writeYieldPoint(exp)
+
 	// Determine if there is any defer to an async function literal in this block
 	hasAsyncDefer := false
 	for _, stmt := range exp.List {
@@ -783,7 +824,12 @@ func (c *GoToTSCompiler) WriteStmtBlock(exp *ast.BlockStmt, suppressNewline bool
 	}
 
 	// 1. For each statement: write its leading comments, blank space, then the stmt
-	for _, stmt := range exp.List {
+	for i, stmt := range exp.List {
+		// Open goto regions starting at this statement
+		if err := c.writeGotoRegionsOpen(exp, i); err != nil {
+			return err
+		}
+
 		// Get statement's end line and position for inline comment check
 		stmtEndLine := 0
 		stmtEndPos := token.NoPos
@@ -828,9 +874,12 @@ func (c *GoToTSCompiler) WriteStmtBlock(exp *ast.BlockStmt, suppressNewline bool
 		writeBlank(lastLine, stmtStart)
 		// Call the specific statement writer (e.g., WriteStmtAssign).
 		// It is responsible for handling its own inline comment.
-		if err := c.WriteStmt(stmt); err != nil {
-			return fmt.Errorf("failed to write statement in block: %w", err)
-		}
+		// A statement which cannot be written is reported and skipped.
+		c.writeRecovering(stmt.Pos(), func() error {
+			return c.WriteStmt(stmt)
+		})
+
+		c.writeGotoRegionsClose(exp, i, stmt)
 
 		if file != nil && stmt.End().IsValid() {
 			// Update lastLine based on the statement's end, *including* potential inline comment handled by WriteStmt*
@@ -968,10 +1017,15 @@ func (c *GoToTSCompiler) WriteStmtDefer(exp *ast.DeferStmt) error {
 	// function literal (defer func(){ ... }()).
 	if funcLit, ok := exp.Call.Fun.(*ast.FuncLit); ok && len(exp.Call.Args) == 0 {
 		// Inline the function literal's body to avoid nested arrow invocation.
-		for _, stmt := range funcLit.Body.List {
+		c.markAsyncFunc(funcLit, isAsyncDeferred)
+		for i, stmt := range funcLit.Body.List {
+			if err := c.writeGotoRegionsOpen(funcLit.Body, i); err != nil {
+				return err
+			}
 			if err := c.WriteStmt(stmt); err != nil {
 				return fmt.Errorf("failed to write statement in deferred function body: %w", err)
 			}
+			c.writeGotoRegionsClose(funcLit.Body, i, stmt)
 		}
 	} else {
 		// Write the call expression as-is.
@@ -990,6 +1044,14 @@ func (c *GoToTSCompiler) WriteStmtDefer(exp *ast.DeferStmt) error {
 // WriteStmtLabeled handles labeled statements (ast.LabeledStmt), such as "label: statement".
 // In TypeScript, labels cannot be used with variable declarations, so we need to handle this case specially.
 func (c *GoToTSCompiler) WriteStmtLabeled(stmt *ast.LabeledStmt) error {
+	// Labels only targeted by goto are implemented by the enclosing goto regions
+	if label, ok := c.pkg.TypesInfo.Defs[stmt.Label].(*types.Label); ok && c.analysis.GotoOnlyLabels[label] {
+		if _, isEmpty := stmt.Stmt.(*ast.EmptyStmt); isEmpty {
+			return nil
+		}
+		return c.WriteStmt(stmt.Stmt)
+	}
+
 	// Check if the labeled statement is a declaration statement or assignment with :=
 	needsBlock := false
 	if _, ok := stmt.Stmt.(*ast.DeclStmt); ok {
@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testModuleGoMod is the go.mod written by writeTestModule if the files have
+// none.
+const testModuleGoMod = "module example.com/app\n\ngo 1.24\n"
+
+// writeTestModule writes files, keyed by their slash-separated path, to a new
+// temporary directory and returns the directory. The module is example.com/app
+// unless the files include a go.mod.
+func writeTestModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		writeTestFile(t, dir, "go.mod", testModuleGoMod)
+	}
+	for name, content := range files {
+		writeTestFile(t, dir, name, content)
+	}
+	return dir
+}
+
+// writeTestFile writes content to the file name, a slash-separated path
+// relative to dir, creating its directory.
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
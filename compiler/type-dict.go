@@ -0,0 +1,539 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeScript generics are erased, so a generic function cannot tell the zero
+// value, the equality or the methods of its type arguments at runtime. Like
+// Go's dictionaries, a generic function which needs them takes a $.TypeDict
+// for each such type parameter before its other parameters:
+//
+//	func Zero[T any]() T {       function Zero<T extends any>($T: $.TypeDict<T>): T {
+//		var zero T                   let zero: T = $T.zero()
+//		return zero                  return zero
+//	}                            }
+//
+//	Zero[int]()                  Zero<number>($.typeDict<number>(() => 0))
+//	Zero[Point]()                Zero<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value' } }))
+//
+// A type parameter takes a dictionary if the function writes its zero value
+// (var declarations, named results, new, make, map reads), uses it as a map
+// key, compares its values, calls its methods or passes it on to another
+// generic function whose type parameter takes a dictionary. Only functions of
+// compiled packages take dictionaries: handwritten packages and methods of
+// generic types do not.
+
+// typeDictRefs returns the type parameters whose dictionaries are used to
+// write the zero value or the map key descriptor of t.
+func typeDictRefs(t types.Type) []*types.TypeParam {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return []*types.TypeParam{t}
+	case *types.Array:
+		return typeDictRefs(t.Elem())
+	}
+	return nil
+}
+
+// genericFuncInstance returns the generic function instantiated by expr,
+// which is F, pkg.F, F[T] or pkg.F[T, U], and its type arguments.
+func genericFuncInstance(info *types.Info, expr ast.Expr) (*types.Func, *types.TypeList) {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	case *ast.IndexExpr:
+		return genericFuncInstance(info, e.X)
+	case *ast.IndexListExpr:
+		return genericFuncInstance(info, e.X)
+	default:
+		return nil, nil
+	}
+	inst, ok := info.Instances[ident]
+	if !ok {
+		return nil, nil
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	fn = fn.Origin()
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil || sig.TypeParams().Len() != inst.TypeArgs.Len() {
+		return nil, nil
+	}
+	return fn, inst.TypeArgs
+}
+
+// takesTypeDicts reports whether any type parameter of the generic function
+// fn takes a dictionary.
+func (a *Analysis) takesTypeDicts(fn *types.Func) bool {
+	tparams := fn.Type().(*types.Signature).TypeParams()
+	for i := range tparams.Len() {
+		if a.TypeDictParams[tparams.At(i).Obj()] {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeTypeDicts finds the type parameters of the generic functions of pkg
+// which take a dictionary, see Analysis.TypeDictParams. The compiled packages
+// imported by pkg are analyzed first, as calls to their generic functions
+// must pass dictionaries too.
+func (a *Analysis) analyzeTypeDicts(pkg *packages.Package) {
+	if pkg == nil || pkg.Types == nil || pkg.TypesInfo == nil || a.typeDictPackages[pkg.PkgPath] {
+		return
+	}
+	a.typeDictPackages[pkg.PkgPath] = true
+	for _, imp := range pkg.Types.Imports() {
+		if dep := a.AllPackages[imp.Path()]; dep != nil && !a.hasGsOverride(imp.Path()) {
+			a.analyzeTypeDicts(dep)
+		}
+	}
+
+	info := pkg.TypesInfo
+
+	// typeDictEdge records that param takes a dictionary if the index-th type
+	// parameter of callee does, as the dictionary of param is passed on.
+	type typeDictEdge struct {
+		callee *types.Func
+		index  int
+		param  *types.TypeName
+	}
+	var edges []typeDictEdge
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || funcDecl.Type.TypeParams == nil || funcDecl.Body == nil {
+				continue
+			}
+			fn, ok := info.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			own := make(map[*types.TypeParam]bool)
+			tparams := fn.Type().(*types.Signature).TypeParams()
+			for i := range tparams.Len() {
+				own[tparams.At(i)] = true
+			}
+			need := func(t types.Type) {
+				for _, tp := range typeDictRefs(t) {
+					if own[tp] {
+						a.TypeDictParams[tp.Obj()] = true
+					}
+				}
+			}
+
+			// Named results start out as zero values
+			if funcDecl.Type.Results != nil {
+				for _, field := range funcDecl.Type.Results.List {
+					if len(field.Names) != 0 {
+						need(info.TypeOf(field.Type))
+					}
+				}
+			}
+
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.ValueSpec:
+					if len(n.Values) == 0 {
+						for _, name := range n.Names {
+							need(info.TypeOf(name))
+						}
+					}
+				case *ast.CallExpr:
+					ident, ok := ast.Unparen(n.Fun).(*ast.Ident)
+					if !ok || len(n.Args) == 0 {
+						break
+					}
+					builtin, ok := info.Uses[ident].(*types.Builtin)
+					if !ok {
+						break
+					}
+					switch builtin.Name() {
+					case "new":
+						need(info.TypeOf(n.Args[0]))
+					case "make":
+						switch u := info.TypeOf(n.Args[0]).Underlying().(type) {
+						case *types.Slice:
+							need(u.Elem())
+						case *types.Chan:
+							need(u.Elem())
+						case *types.Map:
+							need(u.Key())
+						}
+					}
+				case *ast.IndexExpr:
+					// Reading a missing map entry yields the zero value
+					if t := info.TypeOf(n.X); t != nil {
+						if m, ok := t.Underlying().(*types.Map); ok {
+							need(m.Elem())
+						}
+					}
+				case *ast.CompositeLit:
+					if t := info.TypeOf(n); t != nil {
+						switch u := t.Underlying().(type) {
+						case *types.Map:
+							need(u.Key())
+						case *types.Array:
+							need(u.Elem())
+						}
+					}
+				case *ast.SelectorExpr:
+					if sel := info.Selections[n]; sel != nil && sel.Kind() == types.MethodVal {
+						need(info.TypeOf(n.X))
+					}
+				case *ast.BinaryExpr:
+					if n.Op == token.EQL || n.Op == token.NEQ {
+						if _, isTypeParam := info.TypeOf(n.X).(*types.TypeParam); isTypeParam {
+							need(info.TypeOf(n.X))
+						}
+					}
+				case *ast.Ident:
+					callee, targs := genericFuncInstance(info, n)
+					if callee == nil {
+						break
+					}
+					for i := range targs.Len() {
+						for _, tp := range typeDictRefs(targs.At(i)) {
+							if own[tp] {
+								edges = append(edges, typeDictEdge{callee: callee, index: i, param: tp.Obj()})
+							}
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	// Propagate dictionaries passed on to other generic functions
+	for changed := true; changed; {
+		changed = false
+		for _, edge := range edges {
+			if a.TypeDictParams[edge.param] {
+				continue
+			}
+			calleeParam := edge.callee.Type().(*types.Signature).TypeParams().At(edge.index)
+			if a.TypeDictParams[calleeParam.Obj()] {
+				a.TypeDictParams[edge.param] = true
+				changed = true
+			}
+		}
+	}
+
+	// Find the generic functions used as values, which get their
+	// dictionaries bound. Called and instantiated functions are not values.
+	notValues := make(map[ast.Expr]bool)
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				notValues[ast.Unparen(n.Fun)] = true
+			case *ast.IndexExpr:
+				notValues[n.X] = true
+			case *ast.IndexListExpr:
+				notValues[n.X] = true
+			case *ast.SelectorExpr:
+				notValues[n.Sel] = true
+			}
+			return true
+		})
+	}
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if !ok || notValues[expr] {
+				return true
+			}
+			if fn, _ := genericFuncInstance(info, expr); fn != nil && a.takesTypeDicts(fn) {
+				switch expr.(type) {
+				case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+					a.TypeDictFuncValues[expr] = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// typeDictName returns the name of the dictionary parameter of tp, if tp
+// takes a dictionary.
+func (c *GoToTSCompiler) typeDictName(tp *types.TypeParam) (string, bool) {
+	if !c.analysis.TypeDictParams[tp.Obj()] {
+		return "", false
+	}
+	return "$" + tp.Obj().Name(), true
+}
+
+// typeDictParamName returns the name of the dictionary parameter of the
+// type parameter t, if t is a type parameter which takes a dictionary.
+func (c *GoToTSCompiler) typeDictParamName(t types.Type) (string, bool) {
+	tp, ok := t.(*types.TypeParam)
+	if !ok {
+		return "", false
+	}
+	return c.typeDictName(tp)
+}
+
+// writeTypeDictParams writes the dictionary parameters of the generic
+// function type ft. It returns false if no type parameter takes a dictionary.
+func (c *GoToTSCompiler) writeTypeDictParams(ft *ast.FuncType) bool {
+	if ft.TypeParams == nil {
+		return false
+	}
+	wrote := false
+	for _, field := range ft.TypeParams.List {
+		for _, name := range field.Names {
+			typeName, ok := c.pkg.TypesInfo.Defs[name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+			tp, ok := typeName.Type().(*types.TypeParam)
+			if !ok {
+				continue
+			}
+			dictName, ok := c.typeDictName(tp)
+			if !ok {
+				continue
+			}
+			if wrote {
+				c.tsw.WriteLiterally(", ")
+			}
+			c.tsw.WriteLiterallyf("%s: $.TypeDict<%s>", dictName, c.sanitizeIdentifier(name.Name))
+			wrote = true
+		}
+	}
+	return wrote
+}
+
+// writeTypeDictArgs writes the dictionaries passed to the generic function
+// instantiated by fun. It returns false if the function takes none.
+func (c *GoToTSCompiler) writeTypeDictArgs(fun ast.Expr) bool {
+	fn, targs := genericFuncInstance(c.pkg.TypesInfo, fun)
+	if fn == nil {
+		return false
+	}
+	tparams := fn.Type().(*types.Signature).TypeParams()
+	wrote := false
+	for i := range tparams.Len() {
+		if !c.analysis.TypeDictParams[tparams.At(i).Obj()] {
+			continue
+		}
+		if wrote {
+			c.tsw.WriteLiterally(", ")
+		}
+		c.writeTypeDict(targs.At(i))
+		wrote = true
+	}
+	return wrote
+}
+
+// writeTypeDict writes the dictionary of the type argument t.
+func (c *GoToTSCompiler) writeTypeDict(t types.Type) {
+	if dictName, ok := c.typeDictParamName(t); ok {
+		c.tsw.WriteLiterally(dictName)
+		return
+	}
+	c.tsw.WriteLiterally("$.typeDict<")
+	c.WriteGoType(t, GoTypeContextGeneral)
+	c.tsw.WriteLiterally(">(() => ")
+	// An object literal must be parenthesized to not be read as a block
+	_, isAnonStruct := t.(*types.Struct)
+	if isAnonStruct {
+		c.tsw.WriteLiterally("(")
+	}
+	c.WriteZeroValueForType(t)
+	if isAnonStruct {
+		c.tsw.WriteLiterally(")")
+	}
+
+	key := c.mapKeyDescriptor(t)
+	methods := c.typeDictMethods(t)
+	if key != "'value'" || len(methods) != 0 {
+		c.tsw.WriteLiterally(", ")
+		c.tsw.WriteLiterally(key)
+	}
+	if len(methods) != 0 {
+		c.tsw.WriteLiterally(", { ")
+		for i, method := range methods {
+			if i > 0 {
+				c.tsw.WriteLiterally(", ")
+			}
+			c.tsw.WriteLiterally(method)
+		}
+		c.tsw.WriteLiterally(" }")
+	}
+	c.tsw.WriteLiterally(")")
+}
+
+// typeDictMethods returns the method table entries of the dictionary of t.
+// Only named basic types have methods written as free functions, values of
+// other types have their methods.
+func (c *GoToTSCompiler) typeDictMethods(t types.Type) []string {
+	named, ok := t.(*types.Named)
+	if !ok || !c.isWrapperType(named) {
+		return nil
+	}
+	typeName := c.getQualifiedTypeName(named)
+	if typeName == "" {
+		return nil
+	}
+	mset := types.NewMethodSet(named)
+	methods := make([]string, 0, mset.Len())
+	for i := range mset.Len() {
+		name := mset.At(i).Obj().Name()
+		methods = append(methods, fmt.Sprintf("%s: %s_%s", name, typeName, name))
+	}
+	return methods
+}
+
+// writeTypeDictCallee writes the generic function instantiated by fun, which
+// takes type dictionaries, with its type arguments. Named basic types do not
+// satisfy constraints with methods in TypeScript, as they are primitives, so
+// they are passed as any.
+func (c *GoToTSCompiler) writeTypeDictCallee(fun ast.Expr) error {
+	fn, targs := genericFuncInstance(c.pkg.TypesInfo, fun)
+	switch e := ast.Unparen(fun).(type) {
+	case *ast.IndexExpr:
+		return c.writeTypeDictCallee(e.X)
+	case *ast.IndexListExpr:
+		return c.writeTypeDictCallee(e.X)
+	case *ast.Ident:
+		c.WriteIdent(e, true)
+	case *ast.SelectorExpr:
+		if err := c.WriteSelectorExpr(e); err != nil {
+			return fmt.Errorf("failed to write generic function: %w", err)
+		}
+	}
+
+	tparams := fn.Type().(*types.Signature).TypeParams()
+	c.tsw.WriteLiterally("<")
+	for i := range targs.Len() {
+		if i > 0 {
+			c.tsw.WriteLiterally(", ")
+		}
+		targ := targs.At(i)
+		if iface, ok := tparams.At(i).Constraint().Underlying().(*types.Interface); ok && iface.NumMethods() != 0 && c.isWrapperType(targ) {
+			c.tsw.WriteLiterally("any")
+			continue
+		}
+		c.WriteGoType(targ, GoTypeContextGeneral)
+	}
+	c.tsw.WriteLiterally(">")
+	return nil
+}
+
+// writeTypeDictCall writes a call of a generic function which takes type
+// dictionaries. It returns false if exp is not such a call.
+func (c *GoToTSCompiler) writeTypeDictCall(exp *ast.CallExpr) (bool, error) {
+	fn, _ := genericFuncInstance(c.pkg.TypesInfo, exp.Fun)
+	if fn == nil || !c.analysis.takesTypeDicts(fn) {
+		return false, nil
+	}
+	c.writeAsyncCallIfNeeded(exp)
+	if err := c.writeTypeDictCallee(exp.Fun); err != nil {
+		return true, err
+	}
+	return true, c.writeCallArguments(exp)
+}
+
+// writeTypeDictFuncValue writes a generic function used as a value with its
+// dictionaries bound. It returns false if expr is not such a function.
+func (c *GoToTSCompiler) writeTypeDictFuncValue(expr ast.Expr) (bool, error) {
+	if !c.analysis.TypeDictFuncValues[expr] {
+		return false, nil
+	}
+	c.tsw.WriteLiterally("(")
+	if err := c.writeTypeDictCallee(expr); err != nil {
+		return true, err
+	}
+	c.tsw.WriteLiterally(").bind(null, ")
+	c.writeTypeDictArgs(expr)
+	c.tsw.WriteLiterally(")")
+	return true, nil
+}
+
+// writeTypeDictMethodCall writes a call of a method on a value whose type is
+// a type parameter, which is looked up through the dictionary:
+//
+//	v.String()   $.callMethod($T, v, "String")
+//
+// It returns false if exp is not such a call.
+func (c *GoToTSCompiler) writeTypeDictMethodCall(exp *ast.CallExpr) (bool, error) {
+	selectorExpr, ok := ast.Unparen(exp.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false, nil
+	}
+	if sel := c.pkg.TypesInfo.Selections[selectorExpr]; sel == nil || sel.Kind() != types.MethodVal {
+		return false, nil
+	}
+	dictName, ok := c.typeDictParamName(c.pkg.TypesInfo.TypeOf(selectorExpr.X))
+	if !ok {
+		return false, nil
+	}
+
+	c.writeAsyncCallIfNeeded(exp)
+	c.tsw.WriteLiterallyf("$.callMethod(%s, ", dictName)
+	if err := c.WriteValueExpr(selectorExpr.X); err != nil {
+		return true, fmt.Errorf("failed to write method receiver: %w", err)
+	}
+	c.tsw.WriteLiterallyf(", %q", selectorExpr.Sel.Name)
+	for i, arg := range exp.Args {
+		c.tsw.WriteLiterally(", ")
+		if exp.Ellipsis != token.NoPos && i == len(exp.Args)-1 {
+			c.tsw.WriteLiterally("...(")
+			if err := c.WriteValueExpr(arg); err != nil {
+				return true, fmt.Errorf("failed to write method argument: %w", err)
+			}
+			c.tsw.WriteLiterally(" ?? [])")
+			continue
+		}
+		if err := c.WriteValueExpr(arg); err != nil {
+			return true, fmt.Errorf("failed to write method argument: %w", err)
+		}
+	}
+	c.tsw.WriteLiterally(")")
+	return true, nil
+}
+
+// writeTypeDictEquality writes the comparison of two values whose type is a
+// type parameter, which compares them like the type argument does:
+//
+//	a == b   $.dictEquals($T, a, b)
+//
+// It returns false if exp is not such a comparison.
+func (c *GoToTSCompiler) writeTypeDictEquality(exp *ast.BinaryExpr) (bool, error) {
+	if exp.Op != token.EQL && exp.Op != token.NEQ {
+		return false, nil
+	}
+	dictName, ok := c.typeDictParamName(c.pkg.TypesInfo.TypeOf(exp.X))
+	if !ok {
+		return false, nil
+	}
+	if ident, isIdent := ast.Unparen(exp.Y).(*ast.Ident); isIdent && ident.Name == "nil" {
+		return false, nil
+	}
+	if exp.Op == token.NEQ {
+		c.tsw.WriteLiterally("!")
+	}
+	c.tsw.WriteLiterallyf("$.dictEquals(%s, ", dictName)
+	if err := c.WriteValueExpr(exp.X); err != nil {
+		return true, fmt.Errorf("failed to write comparison operand: %w", err)
+	}
+	c.tsw.WriteLiterally(", ")
+	if err := c.WriteValueExpr(exp.Y); err != nil {
+		return true, fmt.Errorf("failed to write comparison operand: %w", err)
+	}
+	c.tsw.WriteLiterally(")")
+	return true, nil
+}
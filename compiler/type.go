@@ -170,6 +170,14 @@ func (c *GoToTSCompiler) WriteZeroValueForType(typ any) {
 		case types.String:
 			c.tsw.WriteLiterally(`""`)
 		default:
+			if c.isBigIntType(t) {
+				c.tsw.WriteLiterally("0n")
+				return
+			}
+			if isComplexType(t) {
+				c.tsw.WriteLiterally("$.complex(0, 0)")
+				return
+			}
 			c.tsw.WriteLiterally("0")
 		}
 	case *types.Named:
@@ -182,6 +190,11 @@ func (c *GoToTSCompiler) WriteZeroValueForType(typ any) {
 			c.tsw.WriteLiterally("()")
 			return
 		}
+		// Named types of handwritten packages keep number for int64 underlying types
+		if _, isBasic := t.Underlying().(*types.Basic); isBasic && c.isBigIntType(t.Underlying()) && !c.isBigIntType(t) {
+			c.tsw.WriteLiterally("0")
+			return
+		}
 		// For other named types, use the zero value of the underlying type
 		c.WriteZeroValueForType(t.Underlying())
 	case *types.Slice:
@@ -196,6 +209,11 @@ func (c *GoToTSCompiler) WriteZeroValueForType(typ any) {
 		// For anonymous struct types, initialize with {}
 		c.tsw.WriteLiterally("{}")
 	case *types.TypeParam:
+		// The zero value of the type argument is given by its dictionary, if any
+		if dictName, ok := c.typeDictName(t); ok {
+			c.tsw.WriteLiterallyf("%s.zero()", dictName)
+			return
+		}
 		// For type parameters, use null with type assertion to work around TypeScript's strict checking
 		// This allows null to be assigned to generic type parameters even when the constraint doesn't explicitly include null
 		c.tsw.WriteLiterally("null as any")
@@ -217,9 +235,12 @@ func (c *GoToTSCompiler) WriteBasicType(t *types.Basic) {
 		case types.UntypedBool:
 			c.tsw.WriteLiterally("boolean")
 			return
-		case types.UntypedInt, types.UntypedFloat, types.UntypedComplex, types.UntypedRune:
+		case types.UntypedInt, types.UntypedFloat, types.UntypedRune:
 			c.tsw.WriteLiterally("number")
 			return
+		case types.UntypedComplex:
+			c.tsw.WriteLiterally("$.Complex")
+			return
 		case types.UntypedString:
 			c.tsw.WriteLiterally("string")
 			return
@@ -229,6 +250,12 @@ func (c *GoToTSCompiler) WriteBasicType(t *types.Basic) {
 		}
 	}
 
+	// int64, uint64 and uintptr are bigint in bigint mode
+	if c.isBigIntType(t) {
+		c.tsw.WriteLiterally("bigint")
+		return
+	}
+
 	// For typed basic types, use the existing mapping
 	if tsType, ok := GoBuiltinToTypescript(name); ok {
 		c.tsw.WriteLiterally(tsType)
@@ -390,6 +417,10 @@ func (c *GoToTSCompiler) WriteChannelType(t *types.Chan) {
 //     the return type is wrapped in `Promise<>` (e.g., `Promise<void>`, `Promise<number>`).
 func (c *GoToTSCompiler) WriteFuncType(exp *ast.FuncType, isAsync bool) {
 	c.tsw.WriteLiterally("(")
+	// Generic functions take the dictionaries of their type parameters first
+	if c.writeTypeDictParams(exp) && exp.Params != nil && len(exp.Params.List) != 0 {
+		c.tsw.WriteLiterally(", ")
+	}
 	c.WriteFieldList(exp.Params, true) // true = arguments
 	c.tsw.WriteLiterally(")")
 	if exp.Results != nil && len(exp.Results.List) > 0 {
@@ -718,7 +749,7 @@ func (c *GoToTSCompiler) writeInterfaceStructure(iface *types.Interface, astNode
 func (c *GoToTSCompiler) getTypeString(goType types.Type) string {
 	var typeStr strings.Builder
 	writer := NewTSCodeWriter(&typeStr)
-	tempCompiler := NewGoToTSCompiler(writer, c.pkg, c.analysis, c.currentFilePath)
+	tempCompiler := NewGoToTSCompiler(writer, c.pkg, c.analysis, c.config, c.currentFilePath)
 	tempCompiler.WriteGoType(goType, GoTypeContextGeneral)
 	return typeStr.String()
 }
@@ -730,7 +761,7 @@ func (c *GoToTSCompiler) getTypeString(goType types.Type) string {
 func (c *GoToTSCompiler) getASTTypeString(astType ast.Expr, goType types.Type) string {
 	var typeStr strings.Builder
 	writer := NewTSCodeWriter(&typeStr)
-	tempCompiler := NewGoToTSCompiler(writer, c.pkg, c.analysis, c.currentFilePath)
+	tempCompiler := NewGoToTSCompiler(writer, c.pkg, c.analysis, c.config, c.currentFilePath)
 
 	if astType != nil {
 		// Use AST-based type writing to preserve qualified names
@@ -107,7 +107,7 @@ func (c *FileCompiler) CompileToWriter(w io.Writer) error {
 	c.codeWriter = NewTSCodeWriter(w)
 
 	// Pass analysis to compiler
-	goWriter := NewGoToTSCompiler(c.codeWriter, c.pkg, c.Analysis, c.fullPath)
+	goWriter := NewGoToTSCompiler(c.codeWriter, c.pkg, c.Analysis, c.compilerConfig, c.fullPath)
 
 	// Add import for the goscript runtime using namespace import and alias
 	c.codeWriter.WriteLinef("import * as $ from %q", "@goscript/builtin/index.js")
@@ -118,7 +118,8 @@ func (c *FileCompiler) CompileToWriter(w io.Writer) error {
 		return fmt.Errorf("failed to write declarations: %w", err)
 	}
 
-	return nil
+	c.Diagnostics = goWriter.diagnostics
+	return diagnosticsError(c.Diagnostics)
 }
 
 // wasmImporter provides import resolution for WASM compilation.
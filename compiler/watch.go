@@ -0,0 +1,269 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"go/build"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// WatchEvent describes a compile done by Compiler.Watch.
+type WatchEvent struct {
+	// Changed are the files and directories which changed since the
+	// previous compile, empty for the first compile.
+	Changed []string
+	// Result is the result of the compile, with the diagnostics of the
+	// packages. It is nil if the packages could not be loaded.
+	Result *CompilationResult
+	// Err is the error of the compile, a *DiagnosticsError if it failed with
+	// error diagnostics.
+	Err error
+	// Duration is the time the compile took.
+	Duration time.Duration
+}
+
+// watchedFile is the state of a watched file or directory.
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchedPackage is the directory and files of a watched package.
+type watchedPackage struct {
+	dir   string
+	files []string
+}
+
+// Watch compiles the packages matched by patterns, then polls their files
+// every interval and compiles them again when they change, calling handler
+// after each compile until ctx is done.
+//
+// Watch always compiles incrementally (see Config.Incremental), so only the
+// packages affected by a change are compiled again. The loaded packages are
+// kept between compiles and only loaded again when Go files, go.mod or the
+// package directories change: changes of embedded files, TypeScript modules
+// and override directories reuse them. The handwritten packages are listed
+// again when the override directories change.
+func (c *Compiler) Watch(ctx context.Context, interval time.Duration, handler func(*WatchEvent), patterns ...string) error {
+	wc := &Compiler{le: c.le, config: c.config, opts: c.opts, overrides: c.overrides}
+	wc.config.Incremental = true
+	return wc.watch(ctx, interval, handler, patterns)
+}
+
+// watch implements Watch with a compiler compiling incrementally.
+func (c *Compiler) watch(ctx context.Context, interval time.Duration, handler func(*WatchEvent), patterns []string) error {
+	// watchedPkgs caches the files of the packages by import path, until
+	// the files of their directory change.
+	watchedPkgs := make(map[string]*watchedPackage)
+	var pkgs []*packages.Package
+	var patternPkgPaths []string
+	var watched map[string]watchedFile
+	var changed []string
+	reload := true
+	for {
+		event := &WatchEvent{Changed: changed}
+		start := time.Now()
+		if reload {
+			loadedPkgs, loadedPatternPkgPaths, err := c.loadPackages(ctx, patterns)
+			if err != nil {
+				event.Err = err
+			} else {
+				pkgs, patternPkgPaths = loadedPkgs, loadedPatternPkgPaths
+			}
+		}
+		if event.Err == nil && pkgs != nil {
+			result := &CompilationResult{OriginalPackages: patternPkgPaths}
+			event.Err = c.compileLoadedPackages(ctx, pkgs, patternPkgPaths, result)
+			var diagErr *DiagnosticsError
+			if event.Err == nil || errors.As(event.Err, &diagErr) {
+				event.Result = result
+			}
+		}
+		event.Duration = time.Since(start)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		handler(event)
+
+		// Watch the files of the last loaded packages, keeping the state
+		// of the files before the compile so that no change is missed.
+		current := c.watchSnapshot(pkgs, watchedPkgs)
+		if watched != nil {
+			for name, state := range watched {
+				if _, ok := current[name]; ok {
+					current[name] = state
+				}
+			}
+		}
+		watched = current
+
+		// Wait for changes, until the files stop changing.
+		changed = nil
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+			next := c.watchSnapshot(pkgs, watchedPkgs)
+			diff := diffWatchSnapshots(watched, next)
+			watched = next
+			if len(diff) == 0 && len(changed) != 0 {
+				break
+			}
+			for _, name := range diff {
+				if !slices.Contains(changed, name) {
+					changed = append(changed, name)
+				}
+			}
+		}
+		slices.Sort(changed)
+		forgetWatchedPackages(watchedPkgs, changed)
+
+		// The handwritten packages are listed once per overrides, so list
+		// them again when the override directories change.
+		if changesOverrideDirs(c.config.OverrideDirs, changed) {
+			c.overrides = newOverrides(c.config.OverrideDirs)
+		}
+
+		reload = pkgs == nil
+		for _, name := range changed {
+			if requiresReload(name) {
+				reload = true
+			}
+		}
+	}
+}
+
+// requiresReload reports whether a change of the file or directory name
+// requires loading the packages again.
+func requiresReload(name string) bool {
+	switch base := filepath.Base(name); {
+	case strings.HasSuffix(base, ".go"), base == "go.mod", base == "go.sum", base == "go.work":
+		return true
+	}
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
+
+// changesOverrideDirs reports whether one of the changed files or
+// directories is in one of the override directories overrideDirs.
+func changesOverrideDirs(overrideDirs, changed []string) bool {
+	for _, name := range changed {
+		for _, dir := range overrideDirs {
+			rel, err := filepath.Rel(dir, name)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forgetWatchedPackages removes the packages with changed files or
+// directories from watchedPkgs, so that their files are listed again.
+func forgetWatchedPackages(watchedPkgs map[string]*watchedPackage, changed []string) {
+	for pkgPath, pkg := range watchedPkgs {
+		for _, name := range changed {
+			if name == pkg.dir || filepath.Dir(name) == pkg.dir || slices.Contains(pkg.files, name) {
+				delete(watchedPkgs, pkgPath)
+				break
+			}
+		}
+	}
+}
+
+// watchSnapshot returns the state of the files to watch for the packages
+// pkgs: the files and directories of the packages outside of GOROOT, the
+// go.mod of the working directory and the override directories. The files
+// of the packages are listed once and kept in watchedPkgs.
+func (c *Compiler) watchSnapshot(pkgs []*packages.Package, watchedPkgs map[string]*watchedPackage) map[string]watchedFile {
+	snapshot := make(map[string]watchedFile)
+	add := func(name string) {
+		if info, err := os.Stat(name); err == nil {
+			snapshot[name] = watchedFile{modTime: info.ModTime(), size: info.Size()}
+		} else {
+			snapshot[name] = watchedFile{size: -1}
+		}
+	}
+
+	dir := c.config.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	for _, name := range []string{"go.mod", "go.sum", "go.work"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			add(filepath.Join(dir, name))
+		}
+	}
+
+	// Without packages, watch the Go files under the working directory until
+	// they load.
+	if pkgs == nil {
+		_ = filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+			switch {
+			case err != nil:
+				return nil
+			case d.IsDir() && name != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules"):
+				return filepath.SkipDir
+			case d.IsDir() || strings.HasSuffix(name, ".go"):
+				add(name)
+			}
+			return nil
+		})
+	}
+
+	goroot := filepath.Clean(build.Default.GOROOT) + string(filepath.Separator)
+	for _, pkg := range pkgs {
+		watchedPkg := watchedPkgs[pkg.PkgPath]
+		if watchedPkg == nil {
+			watchedPkg = &watchedPackage{}
+			if files := slices.Concat(pkg.GoFiles, pkg.IgnoredFiles, pkg.OtherFiles); len(files) != 0 && !strings.HasPrefix(files[0], goroot) {
+				watchedPkg.dir = filepath.Dir(files[0])
+				watchedPkg.files = slices.Concat(files, packageInputFiles(pkg))
+			}
+			watchedPkgs[pkg.PkgPath] = watchedPkg
+		}
+		if watchedPkg.dir == "" {
+			continue
+		}
+		add(watchedPkg.dir)
+		for _, name := range watchedPkg.files {
+			add(name)
+		}
+	}
+
+	for _, overrideDir := range c.config.OverrideDirs {
+		_ = filepath.WalkDir(overrideDir, func(name string, d fs.DirEntry, err error) error {
+			if err == nil {
+				add(name)
+			}
+			return nil
+		})
+	}
+	return snapshot
+}
+
+// diffWatchSnapshots returns the names of the files which are different in
+// the snapshots before and after.
+func diffWatchSnapshots(before, after map[string]watchedFile) []string {
+	var changed []string
+	for name, state := range after {
+		if prev, ok := before[name]; !ok || !prev.modTime.Equal(state.modTime) || prev.size != state.size {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
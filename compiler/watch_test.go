@@ -0,0 +1,205 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Now().Add(-time.Hour)
+	writeFile := func(name, content string) {
+		writeTestFile(t, dir, name, content)
+		path := filepath.Join(dir, name)
+		// Change the modification time even if the file system is coarse
+		modTime = modTime.Add(time.Second)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("go.mod", "module example.com/app\n\ngo 1.24\n")
+	writeFile("lib/lib.go", "package lib\n\n// Answer returns the answer.\nfunc Answer() int { return 42 }\n")
+	writeFile("main.go", `package main
+
+import "example.com/app/lib"
+
+func main() {
+	println(lib.Answer())
+}
+`)
+
+	comp, err := NewCompiler(&Config{
+		Dir:        dir,
+		OutputPath: filepath.Join(dir, "output"),
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *WatchEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- comp.Watch(ctx, 10*time.Millisecond, func(event *WatchEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		}, "./...")
+	}()
+	next := func() *WatchEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case err := <-done:
+			t.Fatalf("Watch() returned %v", err)
+		case <-time.After(time.Minute):
+			t.Fatal("timed out waiting for a compile")
+		}
+		return nil
+	}
+
+	event := next()
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if len(event.Changed) != 0 || len(event.Result.CompiledPackages) != 2 {
+		t.Fatalf("first compile: Changed = %v, CompiledPackages = %v", event.Changed, event.Result.CompiledPackages)
+	}
+
+	writeFile("main.go", `package main
+
+import "example.com/app/lib"
+
+func main() {
+	println(lib.Answer() + 1)
+}
+`)
+	event = next()
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if !slices.Equal(event.Changed, []string{filepath.Join(dir, "main.go")}) {
+		t.Errorf("Changed = %v, want main.go", event.Changed)
+	}
+	if !slices.Equal(event.Result.CompiledPackages, []string{"example.com/app"}) || !slices.Equal(event.Result.ReusedPackages, []string{"example.com/app/lib"}) {
+		t.Errorf("CompiledPackages = %v, ReusedPackages = %v", event.Result.CompiledPackages, event.Result.ReusedPackages)
+	}
+
+	writeFile("lib/lib.go", "package lib\n\nfunc Answer() int { return }\n")
+	event = next()
+	var diagErr *DiagnosticsError
+	if !errors.As(event.Err, &diagErr) || event.Result == nil {
+		t.Fatalf("compile with a type error: Err = %v", event.Err)
+	}
+	if diag := event.Result.Diagnostics[0]; diag.Code != DiagnosticType || diag.File != filepath.Join(dir, "lib", "lib.go") || diag.Line != 3 {
+		t.Errorf("Diagnostics = %+v", event.Result.Diagnostics)
+	}
+
+	writeFile("lib/lib.go", "package lib\n\n// Answer returns the answer.\nfunc Answer() int { return 41 }\n")
+	event = next()
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if !slices.Contains(event.Result.CompiledPackages, "example.com/app/lib") {
+		t.Errorf("CompiledPackages = %v, want example.com/app/lib", event.Result.CompiledPackages)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch() = %v, want context.Canceled", err)
+	}
+	if comp.config.Incremental {
+		t.Error("Watch() enabled Incremental in the config of the compiler")
+	}
+}
+
+func TestWatchOverrides(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"lib/lib.go": "package lib\n\n// Answer returns the answer.\nfunc Answer() int { return 42 }\n",
+		"main.go": `package main
+
+import "example.com/app/lib"
+
+func main() {
+	println(lib.Answer())
+}
+`,
+	})
+	overrideDir := filepath.Join(dir, "overrides")
+	if err := os.Mkdir(overrideDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := filepath.Join(dir, "output")
+	comp, err := NewCompiler(&Config{
+		Dir:          dir,
+		OutputPath:   outputDir,
+		OverrideDirs: []string{overrideDir},
+	}, logrus.NewEntry(logrus.New()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *WatchEvent)
+	go func() {
+		_ = comp.Watch(ctx, 10*time.Millisecond, func(event *WatchEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		}, ".")
+	}()
+	readMain := func() string {
+		t.Helper()
+		select {
+		case event := <-events:
+			if event.Err != nil {
+				t.Fatal(event.Err)
+			}
+		case <-time.After(time.Minute):
+			t.Fatal("timed out waiting for a compile")
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, "@goscript", "example.com", "app", "main.gs.ts"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if out := readMain(); !strings.Contains(out, "$.println(lib.Answer())") {
+		t.Fatalf("first compile:\n%s", out)
+	}
+
+	// A handwritten package with an async function is added while watching
+	writeTestFile(t, overrideDir, "example.com/app/lib/index.ts", "export async function Answer(): Promise<number> {\n  return 42\n}\n")
+	writeTestFile(t, overrideDir, "example.com/app/lib/meta.json", `{"asyncMethods": {"Answer": true}}`)
+	if out := readMain(); !strings.Contains(out, "$.println(await lib.Answer())") {
+		t.Errorf("call of the async handwritten function is not awaited:\n%s", out)
+	}
+}
+
+func TestForgetWatchedPackages(t *testing.T) {
+	watchedPkgs := map[string]*watchedPackage{
+		"example.com/app":     {dir: "/app", files: []string{"/app/main.go", "/app/host.ts"}},
+		"example.com/app/lib": {dir: "/app/lib", files: []string{"/app/lib/lib.go"}},
+		"example.com/app/web": {dir: "/app/web", files: []string{"/app/web/web.go", "/app/assets/logo.png"}},
+	}
+	forgetWatchedPackages(watchedPkgs, []string{"/app/host.ts", "/app/assets/logo.png"})
+	if len(watchedPkgs) != 1 || watchedPkgs["example.com/app/lib"] == nil {
+		t.Errorf("watched packages after the change: %v, want example.com/app/lib", slices.Collect(maps.Keys(watchedPkgs)))
+	}
+}
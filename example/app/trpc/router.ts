@@ -8,15 +8,11 @@ import {
   Validate,
   ValidateDescription,
   PriorityString,
-  type Priority,
+  PriorityLow,
+  PriorityMedium,
+  PriorityHigh,
 } from '@goscript/github.com/aperturerobotics/goscript/example/app/todo/index.js'
 
-// Priority constants (matching Go's iota values)
-// Note: GoScript iota support is being improved - for now we define these manually
-const PriorityLow: Priority = 0
-const PriorityMedium: Priority = 1
-const PriorityHigh: Priority = 2
-
 // Initialize tRPC
 const t = initTRPC.create()
 
@@ -1,5 +1,6 @@
 import type { Slice, SliceProxy } from './slice.js'
 import { isSliceProxy } from './slice.js'
+import { Complex } from './complex.js'
 
 /**
  * Implementation of Go's built-in println function
@@ -10,7 +11,12 @@ export function println(...args: any[]): void {
     // Bun's console.log() with no args doesn't print a newline, so we explicitly print an empty string
     console.log('')
   } else {
-    console.log(...args)
+    // console.log prints bigint values with an n suffix and complex values as objects
+    console.log(
+      ...args.map((arg) =>
+        typeof arg === 'bigint' || arg instanceof Complex ? arg.toString() : arg,
+      ),
+    )
   }
 }
 
@@ -61,6 +67,116 @@ export function int(value: number): number {
   return Math.trunc(value)
 }
 
+// int64 converts a value to a Go int64 represented as bigint, wrapping it
+// around to the 64-bit range. Used when int64 values are emitted as bigint.
+export function int64(value: number | bigint): bigint {
+  if (typeof value === 'bigint') {
+    return BigInt.asIntN(64, value)
+  }
+  if (!Number.isFinite(value)) {
+    return 0n
+  }
+  return BigInt.asIntN(64, BigInt(Math.trunc(value)))
+}
+
+// uint64 converts a value to a Go uint64 represented as bigint, wrapping it
+// around to the 64-bit range. Used when uint64 values are emitted as bigint.
+export function uint64(value: number | bigint): bigint {
+  if (typeof value === 'bigint') {
+    return BigInt.asUintN(64, value)
+  }
+  if (!Number.isFinite(value)) {
+    return 0n
+  }
+  return BigInt.asUintN(64, BigInt(Math.trunc(value)))
+}
+
+// idiv implements Go's integer division, which truncates towards zero and
+// panics on a zero divisor. Used in strict integer mode.
+export function idiv(x: number, y: number): number {
+  if (y === 0) {
+    throw new Error('runtime error: integer divide by zero')
+  }
+  return Math.trunc(x / y)
+}
+
+// imod implements Go's integer remainder, which has the sign of the dividend
+// and panics on a zero divisor. Used in strict integer mode.
+export function imod(x: number, y: number): number {
+  if (y === 0) {
+    throw new Error('runtime error: integer divide by zero')
+  }
+  return x % y
+}
+
+// shlInt shifts the signed integer x of the given bit width left by n,
+// discarding the bits shifted out of the type like Go.
+export function shlInt(x: number, n: number, bits: number): number {
+  if (n < 0) {
+    throw new Error('runtime error: negative shift amount')
+  }
+  if (n >= bits) {
+    return 0
+  }
+  return ((x << n) << (32 - bits)) >> (32 - bits)
+}
+
+// shlUint shifts the unsigned integer x of the given bit width left by n,
+// discarding the bits shifted out of the type like Go.
+export function shlUint(x: number, n: number, bits: number): number {
+  if (n < 0) {
+    throw new Error('runtime error: negative shift amount')
+  }
+  if (n >= bits) {
+    return 0
+  }
+  if (bits === 32) {
+    return (x << n) >>> 0
+  }
+  return (x << n) & ((1 << bits) - 1)
+}
+
+// shrInt shifts the signed integer x right by n. Unlike JavaScript, Go does
+// not take the shift count modulo 32, so large counts yield 0 or -1.
+export function shrInt(x: number, n: number): number {
+  if (n < 0) {
+    throw new Error('runtime error: negative shift amount')
+  }
+  return x >> Math.min(n, 31)
+}
+
+// shrUint shifts the unsigned integer x right by n, yielding 0 for counts
+// of 32 and above like Go.
+export function shrUint(x: number, n: number): number {
+  if (n < 0) {
+    throw new Error('runtime error: negative shift amount')
+  }
+  return n >= 32 ? 0 : x >>> n
+}
+
+/**
+ * bigintResults converts the int64 and uint64 elements of a result tuple
+ * returned as number by a handwritten package to bigint.
+ * @param results The result tuple
+ * @param kinds The kind of each tuple element, null for elements to keep
+ * @returns The converted result tuple
+ */
+export function bigintResults<T extends any[]>(
+  results: T,
+  kinds: Array<'int64' | 'uint64' | null>,
+): any {
+  return results.map((value, i) => {
+    switch (kinds[i]) {
+      case 'int64':
+        return int64(value)
+      case 'uint64':
+        return uint64(value)
+      default:
+        return value
+    }
+  })
+}
+
 /**
  * Normalizes various byte representations into a `Uint8Array` for protobuf compatibility.
  *
@@ -1,3 +1,5 @@
+import { parkForever, parkWhile } from './scheduler.js'
+
 /**
  * Represents the result of a channel receive operation with 'ok' value
  */
@@ -105,8 +107,7 @@ export async function selectStatement<T, V = void>(
 ): Promise<[boolean, V]> {
   if (cases.length === 0 && !hasDefault) {
     // Go spec: If there are no cases, the select statement blocks forever.
-    // Emulate blocking forever with a promise that never resolves.
-    return new Promise<[boolean, V]>(() => {}) // Promise never resolves
+    return parkForever<[boolean, V]>('select (no cases)')
   }
 
   // 1. Check for ready (non-blocking) operations
@@ -195,10 +196,10 @@ export async function selectStatement<T, V = void>(
   // If all non-default cases have nil channels, we effectively block forever
   if (blockingPromises.length === 0) {
     // No valid channels to operate on, block forever (unless there's a default)
-    return new Promise<[boolean, V]>(() => {}) // Promise never resolves
+    return parkForever<[boolean, V]>('select')
   }
 
-  const result = await Promise.race(blockingPromises)
+  const result = await parkWhile('select', Promise.race(blockingPromises))
   // Execute onSelected handler for the selected case
   const selectedCase = cases.find((c) => c.id === result.id)
   if (selectedCase && selectedCase.onSelected) {
@@ -223,7 +224,10 @@ export async function chanSend<T>(
 ): Promise<void> {
   if (channel === null) {
     // In Go, sending to a nil channel blocks forever
-    return new Promise<void>(() => {}) // Promise that never resolves
+    return parkForever<void>('chan send (nil chan)')
+  }
+  if (!channel.canSendNonBlocking()) {
+    return parkWhile('chan send', channel.send(value))
   }
   return channel.send(value)
 }
@@ -239,7 +243,10 @@ export async function chanRecv<T>(
 ): Promise<T> {
   if (channel === null) {
     // In Go, receiving from a nil channel blocks forever
-    return new Promise<T>(() => {}) // Promise that never resolves
+    return parkForever<T>('chan receive (nil chan)')
+  }
+  if (!channel.canReceiveNonBlocking()) {
+    return parkWhile('chan receive', channel.receive())
   }
   return channel.receive()
 }
@@ -255,7 +262,10 @@ export async function chanRecvWithOk<T>(
 ): Promise<ChannelReceiveResult<T>> {
   if (channel === null) {
     // In Go, receiving from a nil channel blocks forever
-    return new Promise<ChannelReceiveResult<T>>(() => {}) // Promise that never resolves
+    return parkForever<ChannelReceiveResult<T>>('chan receive (nil chan)')
+  }
+  if (!channel.canReceiveNonBlocking()) {
+    return parkWhile('chan receive', channel.receiveWithOk())
   }
   return channel.receiveWithOk()
 }
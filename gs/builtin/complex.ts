@@ -0,0 +1,151 @@
+/**
+ * Complex represents a Go complex64 or complex128 value.
+ * Complex values are immutable, so they can be shared like numbers.
+ */
+export class Complex {
+  constructor(
+    public readonly real: number,
+    public readonly imag: number,
+  ) {}
+
+  // toString formats the value like Go's %v verb, e.g. (1+2i).
+  public toString(): string {
+    let im = formatFloat(this.imag)
+    if (im[0] !== '-' && im[0] !== '+') {
+      im = '+' + im
+    }
+    return `(${formatFloat(this.real)}${im}i)`
+  }
+}
+
+/**
+ * Implementation of Go's built-in complex function.
+ * @param real The real part
+ * @param imag The imaginary part
+ */
+export function complex(real: number, imag: number): Complex {
+  return new Complex(real, imag)
+}
+
+/**
+ * Implementation of Go's built-in real function.
+ * @param c The complex value
+ */
+export function real(c: Complex): number {
+  return c.real
+}
+
+/**
+ * Implementation of Go's built-in imag function.
+ * @param c The complex value
+ */
+export function imag(c: Complex): number {
+  return c.imag
+}
+
+// complex64 rounds both parts of c to float32 precision.
+export function complex64(c: Complex): Complex {
+  return new Complex(Math.fround(c.real), Math.fround(c.imag))
+}
+
+// complexAdd implements the + operator on complex values.
+export function complexAdd(x: Complex, y: Complex): Complex {
+  return new Complex(x.real + y.real, x.imag + y.imag)
+}
+
+// complexSub implements the - operator on complex values.
+export function complexSub(x: Complex, y: Complex): Complex {
+  return new Complex(x.real - y.real, x.imag - y.imag)
+}
+
+// complexMul implements the * operator on complex values.
+export function complexMul(x: Complex, y: Complex): Complex {
+  return new Complex(
+    x.real * y.real - x.imag * y.imag,
+    x.real * y.imag + x.imag * y.real,
+  )
+}
+
+// complexDiv implements the / operator on complex values. Like Go it uses
+// Smith's algorithm and yields infinities instead of panicking on zero.
+export function complexDiv(n: Complex, m: Complex): Complex {
+  let e: number
+  let f: number
+  if (Math.abs(m.real) >= Math.abs(m.imag)) {
+    const ratio = m.imag / m.real
+    const denom = m.real + ratio * m.imag
+    e = (n.real + n.imag * ratio) / denom
+    f = (n.imag - n.real * ratio) / denom
+  } else {
+    const ratio = m.real / m.imag
+    const denom = m.imag + ratio * m.real
+    e = (n.real * ratio + n.imag) / denom
+    f = (n.imag * ratio - n.real) / denom
+  }
+
+  if (Number.isNaN(e) && Number.isNaN(f)) {
+    // Correct the result to infinities and zeros like C99 G.5.1
+    let a = n.real
+    let b = n.imag
+    let c = m.real
+    let d = m.imag
+    if (c === 0 && d === 0 && (!Number.isNaN(a) || !Number.isNaN(b))) {
+      e = copysign(Infinity, c) * a
+      f = copysign(Infinity, c) * b
+    } else if (
+      (isInf(a) || isInf(b)) &&
+      Number.isFinite(c) &&
+      Number.isFinite(d)
+    ) {
+      a = copysign(isInf(a) ? 1 : 0, a)
+      b = copysign(isInf(b) ? 1 : 0, b)
+      e = Infinity * (a * c + b * d)
+      f = Infinity * (b * c - a * d)
+    } else if (
+      (isInf(c) || isInf(d)) &&
+      Number.isFinite(a) &&
+      Number.isFinite(b)
+    ) {
+      c = copysign(isInf(c) ? 1 : 0, c)
+      d = copysign(isInf(d) ? 1 : 0, d)
+      e = 0 * (a * c + b * d)
+      f = 0 * (b * c - a * d)
+    }
+  }
+  return new Complex(e, f)
+}
+
+// complexNeg implements the unary - operator on complex values.
+export function complexNeg(x: Complex): Complex {
+  return new Complex(-x.real, -x.imag)
+}
+
+// complexEquals implements the == operator on complex values.
+export function complexEquals(x: Complex, y: Complex): boolean {
+  return x.real === y.real && x.imag === y.imag
+}
+
+function isInf(x: number): boolean {
+  return x === Infinity || x === -Infinity
+}
+
+function copysign(x: number, sign: number): number {
+  const negative = sign < 0 || Object.is(sign, -0)
+  return negative ? -Math.abs(x) : Math.abs(x)
+}
+
+// formatFloat formats x like Go's %v verb for floats, the shortest %g.
+function formatFloat(x: number): string {
+  if (Number.isNaN(x)) return 'NaN'
+  if (x === Infinity) return '+Inf'
+  if (x === -Infinity) return '-Inf'
+  if (x === 0) return Object.is(x, -0) ? '-0' : '0'
+
+  const [mantissa, expStr] = x.toExponential().split('e')
+  const exp = Number(expStr)
+  if (exp < -4 || exp >= 6) {
+    const digits = Math.abs(exp).toString().padStart(2, '0')
+    return `${mantissa}e${exp < 0 ? '-' : '+'}${digits}`
+  }
+  return x.toString()
+}
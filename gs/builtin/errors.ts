@@ -15,6 +15,9 @@ export function newError(text: string): GoError {
 // toGoError converts a JavaScript Error to a Go error
 // if the error is already a Go error, it returns it unchanged
 export function toGoError(err: Error): GoError {
+  if (err instanceof GoErrorException) {
+    return err.goError
+  }
   if ('Error' in err) {
     return err as GoError
   }
@@ -24,6 +27,19 @@ export function toGoError(err: Error): GoError {
   } as GoError
 }
 
+// GoErrorException is a JavaScript Error carrying a Go error. The facades of
+// compiled packages (facade.ts) throw it when a function returns an error.
+export class GoErrorException extends Error {
+  // goError is the error returned by the Go function.
+  public readonly goError: Exclude<GoError, null>
+
+  constructor(goError: Exclude<GoError, null>) {
+    super(goError.Error())
+    this.name = 'GoErrorException'
+    this.goError = goError
+  }
+}
+
 // wrapPrimitiveError wraps a primitive value that implements the error interface
 // by creating an object with an Error() method that calls the type's Error function.
 // This is needed for types like `type MyError int` with `func (e MyError) Error() string`
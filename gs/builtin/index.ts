@@ -1,4 +1,5 @@
 export * from './builtin.js'
+export * from './complex.js'
 export * from './slice.js'
 export * from './channel.js'
 export * from './map.js'
@@ -6,3 +7,6 @@ export * from './type.js'
 export * from './varRef.js'
 export * from './defer.js'
 export * from './errors.js'
+export * from './typeDict.js'
+export * from './scheduler.js'
+export * from './json.js'
@@ -0,0 +1,383 @@
+import { Bytes, bytesToUint8Array } from './builtin.js'
+import { Slice, arrayToSlice, asArray, isSliceProxy } from './slice.js'
+import { isVarRef } from './varRef.js'
+
+// The toPlain, toJSON and fromJSON methods of the compiled struct classes
+// convert them to and from the plain values of their encoding/json encoding.
+// The compiler writes the conversions of their fields, knowing their types;
+// the functions here convert the values of the fields. Plain values keep the
+// bigint values of 64-bit integers compiled with --int64-bigint, toJSON
+// replaces them with values JSON.stringify can write.
+
+/**
+ * toPlain converts a value of unknown type, like the value of an interface, to
+ * a plain value: structs with their toPlain or toJSON method, slices to
+ * arrays, []byte to base64 strings and maps to objects. bigint values are
+ * kept as they are.
+ */
+export function toPlain(value: unknown): unknown {
+  if (value === null || value === undefined) {
+    return null
+  }
+  switch (typeof value) {
+    case 'function':
+    case 'symbol':
+      return null
+    case 'object':
+      break
+    default:
+      return value
+  }
+
+  const v = value as any
+  if (typeof v.toPlain === 'function') {
+    return v.toPlain()
+  }
+  if (typeof v.toJSON === 'function') {
+    return v.toJSON()
+  }
+  if (value instanceof Uint8Array) {
+    return bytesToBase64(value)
+  }
+  if (Array.isArray(value) || isSliceProxy(value as Slice<unknown>)) {
+    return asArray(value as Slice<unknown>).map(toPlain)
+  }
+  if (value instanceof Map) {
+    return mapToPlain(value, toPlain)
+  }
+  if (isVarRef(value)) {
+    return toPlain(value.value)
+  }
+  if (v.constructor !== Object) {
+    // A class without exported fields
+    return {}
+  }
+  const obj: Record<string, unknown> = {}
+  for (const [key, elem] of Object.entries(v)) {
+    obj[key] = toPlain(elem)
+  }
+  return obj
+}
+
+/**
+ * plainToJSON returns the plain value with its bigint values replaced by
+ * values JSON.stringify writes exactly: raw JSON numbers where JSON.rawJSON
+ * is available, and else numbers if they are safe integers and strings if
+ * they are not.
+ */
+export function plainToJSON(value: unknown): unknown {
+  if (typeof value === 'bigint') {
+    return bigIntToJSON(value)
+  }
+  if (Array.isArray(value)) {
+    return value.map(plainToJSON)
+  }
+  if (
+    value === null ||
+    typeof value !== 'object' ||
+    Object.getPrototypeOf(value) !== Object.prototype
+  ) {
+    return value
+  }
+  const obj: Record<string, unknown> = {}
+  for (const [key, elem] of Object.entries(value)) {
+    obj[key] = plainToJSON(elem)
+  }
+  return obj
+}
+
+// rawJSON is JSON.rawJSON, which is missing from older runtimes.
+const rawJSON = (JSON as { rawJSON?: (text: string) => unknown }).rawJSON
+
+/**
+ * bigIntToJSON returns the value JSON.stringify writes for the bigint n.
+ */
+function bigIntToJSON(n: bigint): unknown {
+  if (rawJSON) {
+    return rawJSON(n.toString())
+  }
+  if (
+    n <= BigInt(Number.MAX_SAFE_INTEGER) &&
+    n >= BigInt(Number.MIN_SAFE_INTEGER)
+  ) {
+    return Number(n)
+  }
+  return n.toString()
+}
+
+/**
+ * bigIntFromPlain converts the plain value of an int64, or of a uint64 if
+ * unsigned is set, to a bigint. The value is a bigint, an integer number or
+ * a string of decimal digits, as written by toJSON without JSON.rawJSON. It
+ * throws if the value is not an integer in the range of the type, like
+ * encoding/json fails to decode it.
+ */
+export function bigIntFromPlain(value: unknown, unsigned: boolean): bigint {
+  let n: bigint | undefined
+  switch (typeof value) {
+    case 'bigint':
+      n = value
+      break
+    case 'number':
+      if (Number.isInteger(value)) {
+        n = BigInt(value)
+      }
+      break
+    case 'string':
+      if (/^-?[0-9]+$/.test(value)) {
+        n = BigInt(value)
+      }
+      break
+  }
+  if (
+    n === undefined ||
+    n !== (unsigned ? BigInt.asUintN(64, n) : BigInt.asIntN(64, n))
+  ) {
+    const text =
+      typeof value === 'string' ? JSON.stringify(value) : String(value)
+    throw new Error(
+      `json: cannot unmarshal ${text} into Go value of type ${unsigned ? 'uint64' : 'int64'}`,
+    )
+  }
+  return n
+}
+
+/**
+ * plainToAny converts a plain value to the value encoding/json decodes into an
+ * interface: objects to maps and arrays to slices.
+ */
+export function plainToAny(value: unknown): any {
+  if (value === null || value === undefined) {
+    return null
+  }
+  if (Array.isArray(value)) {
+    return arrayToSlice(value.map(plainToAny))
+  }
+  if (typeof value === 'object') {
+    return new Map(
+      Object.entries(value).map(([key, elem]) => [key, plainToAny(elem)]),
+    )
+  }
+  return value
+}
+
+/**
+ * plainObject returns the properties of the plain object obj with the names
+ * of the fields of a struct. Like encoding/json, a property matches a field
+ * with the same name, or else with the same name ignoring case. Values which
+ * are not objects have no properties.
+ */
+export function plainObject(
+  obj: unknown,
+  names: string[],
+): Record<string, any> {
+  const result: Record<string, any> = {}
+  if (obj === null || typeof obj !== 'object' || Array.isArray(obj)) {
+    return result
+  }
+  const props = obj as Record<string, unknown>
+  for (const name of names) {
+    if (Object.hasOwn(props, name)) {
+      result[name] = props[name]
+      continue
+    }
+    const folded = name.toLowerCase()
+    for (const key of Object.keys(props)) {
+      if (key.toLowerCase() === folded) {
+        result[name] = props[key]
+        break
+      }
+    }
+  }
+  return result
+}
+
+/**
+ * structFromPlain converts the plain object obj to an instance of the struct
+ * class ctor with its fromJSON method. Classes without it are zero values.
+ */
+export function structFromPlain<T>(
+  ctor: (new () => T) & { fromJSON?: (obj: unknown) => T },
+  obj: unknown,
+): T {
+  if (typeof ctor.fromJSON === 'function') {
+    return ctor.fromJSON(obj)
+  }
+  return new ctor()
+}
+
+/**
+ * sliceToPlain converts the slice or array s to an array of the values
+ * converted with conv, null for a nil slice.
+ */
+export function sliceToPlain<T>(
+  s: Slice<T> | T[],
+  conv?: (elem: T) => unknown,
+): unknown[] | null {
+  if (s === null || s === undefined) {
+    return null
+  }
+  const arr = asArray(s as Slice<T>)
+  return conv ? arr.map((elem) => conv(elem)) : arr
+}
+
+/**
+ * sliceFromPlain converts the array value to a slice of the values converted
+ * with conv. null is a nil slice.
+ */
+export function sliceFromPlain(
+  value: unknown,
+  conv?: (elem: any) => unknown,
+): any {
+  if (!Array.isArray(value)) {
+    return null
+  }
+  return arrayToSlice(conv ? value.map((elem) => conv(elem)) : value.slice())
+}
+
+/**
+ * arrayFromPlain converts the array value to an array of the length of the
+ * zero array, of the values converted with conv. Like encoding/json, extra
+ * values are dropped and missing values are the values of zero.
+ */
+export function arrayFromPlain<T>(
+  value: unknown,
+  zero: T[],
+  conv?: (elem: any) => T,
+): T[] {
+  if (!Array.isArray(value)) {
+    return zero
+  }
+  return zero.map((elem, i) =>
+    i < value.length ?
+      conv ? conv(value[i])
+      : value[i]
+    : elem,
+  )
+}
+
+/**
+ * mapToPlain converts the map m to an object with the values converted with
+ * conv, null for a nil map. Keys are converted to strings and sorted, like
+ * encoding/json writes them. JavaScript objects keep integer keys first in
+ * numeric order whatever the order they are added in.
+ */
+export function mapToPlain<K, V>(
+  m: Map<K, V> | null,
+  conv?: (elem: V) => unknown,
+): Record<string, unknown> | null {
+  if (m === null || m === undefined) {
+    return null
+  }
+  const entries = Array.from(m, ([key, elem]): [string, V] => [
+    String(key),
+    elem,
+  ])
+  entries.sort(([a], [b]) => (a < b ? -1 : a > b ? 1 : 0))
+  const obj: Record<string, unknown> = {}
+  for (const [key, elem] of entries) {
+    obj[key] = conv ? conv(elem) : elem
+  }
+  return obj
+}
+
+/**
+ * mapFromPlain converts the object value to a map with the keys and values
+ * converted with key and conv. null is a nil map.
+ */
+export function mapFromPlain(
+  value: unknown,
+  key?: (key: string) => unknown,
+  conv?: (elem: any) => unknown,
+): any {
+  if (value === null || typeof value !== 'object' || Array.isArray(value)) {
+    return null
+  }
+  return new Map(
+    Object.entries(value).map(([k, elem]) => [
+      key ? key(k) : k,
+      conv ? conv(elem) : elem,
+    ]),
+  )
+}
+
+/**
+ * isZero reports whether the struct or array value holds the zero value, for
+ * the omitzero option: a struct with an IsZero method is zero if it says so,
+ * and other structs and arrays if all their fields or elements are zero.
+ */
+export function isZero(value: unknown): boolean {
+  if (value === null || value === undefined) {
+    return true
+  }
+  switch (typeof value) {
+    case 'number':
+      return value === 0
+    case 'bigint':
+      return value === 0n
+    case 'string':
+      return value === ''
+    case 'boolean':
+      return !value
+    case 'object':
+      break
+    default:
+      return false
+  }
+
+  const v = value as any
+  if (typeof v.IsZero === 'function') {
+    return v.IsZero()
+  }
+  if (value instanceof Uint8Array) {
+    return value.every((b) => b === 0)
+  }
+  if (Array.isArray(value)) {
+    return value.every(isZero)
+  }
+  if (typeof v._fields === 'object' && v._fields !== null) {
+    return Object.values(v._fields).every((field) =>
+      isZero((field as { value: unknown }).value),
+    )
+  }
+  if (typeof v.toPlain === 'function' && typeof v.constructor === 'function') {
+    // Classes written by hand, like time.Time
+    return (
+      JSON.stringify(plainToJSON(v.toPlain())) ===
+      JSON.stringify(plainToJSON(new v.constructor().toPlain()))
+    )
+  }
+  return false
+}
+
+/**
+ * bytesToBase64 encodes the bytes b in standard base64, like encoding/json
+ * writes []byte. A nil slice is null.
+ */
+export function bytesToBase64(b: Bytes | null): string | null {
+  if (b === null || b === undefined) {
+    return null
+  }
+  const bytes = bytesToUint8Array(b)
+  let binary = ''
+  for (let i = 0; i < bytes.length; i++) {
+    binary += String.fromCharCode(bytes[i])
+  }
+  return btoa(binary)
+}
+
+/**
+ * base64ToBytes decodes the standard base64 string s, like encoding/json
+ * reads []byte. null is a nil slice.
+ */
+export function base64ToBytes(s: unknown): Uint8Array | null {
+  if (typeof s !== 'string') {
+    return null
+  }
+  const binary = atob(s)
+  const bytes = new Uint8Array(binary.length)
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i)
+  }
+  return bytes
+}
@@ -1,8 +1,298 @@
+import { Complex } from './complex.js'
+import { isMarkedAsStructValue, TypeKind } from './type.js'
+
+/**
+ * MapKeyType describes how the keys of a HashMap are compared.
+ *
+ * - 'float' compares numbers like Go: NaN never matches and -0 equals +0.
+ * - 'complex' compares $.Complex values by their parts.
+ * - 'pointer' compares objects by identity.
+ * - 'interface' compares by the dynamic value: struct values structurally,
+ *   other objects by identity.
+ * - { fields } compares struct values field by field.
+ * - { elem } compares arrays element by element.
+ */
+export type MapKeyType =
+  | 'value'
+  | 'float'
+  | 'complex'
+  | 'pointer'
+  | 'interface'
+  | { fields: Record<string, MapKeyType> }
+  | { elem: MapKeyType }
+
+/**
+ * HashMap is a Map whose keys are compared with Go's == semantics instead of
+ * JavaScript identity. It is used for struct, array, interface and float keys.
+ * Entries are stored under a string hash of the key.
+ */
+export class HashMap<K, V> extends Map<K, V> {
+  private readonly entriesByHash = new Map<unknown, [K, V]>()
+
+  constructor(
+    public readonly keyType: MapKeyType,
+    entries?: Iterable<readonly [K, V]> | null,
+  ) {
+    super()
+    if (entries) {
+      for (const [k, v] of entries) {
+        this.set(k, v)
+      }
+    }
+  }
+
+  public get size(): number {
+    return this.entriesByHash.size
+  }
+
+  public get(key: K): V | undefined {
+    return this.entriesByHash.get(hashMapKey(key, this.keyType))?.[1]
+  }
+
+  public has(key: K): boolean {
+    return this.entriesByHash.has(hashMapKey(key, this.keyType))
+  }
+
+  public set(key: K, value: V): this {
+    // Like Go, an assignment also replaces the stored key, e.g. -0 with +0.
+    const hash = hashMapKey(key, this.keyType)
+    const existing = this.entriesByHash.get(hash)
+    if (existing) {
+      existing[0] = copyMapKey(key, this.keyType)
+      existing[1] = value
+    } else {
+      this.entriesByHash.set(hash, [copyMapKey(key, this.keyType), value])
+    }
+    return this
+  }
+
+  public delete(key: K): boolean {
+    return this.entriesByHash.delete(hashMapKey(key, this.keyType))
+  }
+
+  public clear(): void {
+    this.entriesByHash.clear()
+  }
+
+  public forEach(
+    callbackfn: (value: V, key: K, map: Map<K, V>) => void,
+    thisArg?: any,
+  ): void {
+    for (const [k, v] of this.entriesByHash.values()) {
+      callbackfn.call(thisArg, v, k, this)
+    }
+  }
+
+  public entries(): MapIterator<[K, V]> {
+    return this.entriesByHash.values()
+  }
+
+  public keys(): MapIterator<K> {
+    return mapIterator(this.entriesByHash.values(), (e) => e[0])
+  }
+
+  public values(): MapIterator<V> {
+    return mapIterator(this.entriesByHash.values(), (e) => e[1])
+  }
+
+  public [Symbol.iterator](): MapIterator<[K, V]> {
+    return this.entries()
+  }
+}
+
+function mapIterator<T, U>(
+  it: Iterator<T>,
+  fn: (value: T) => U,
+): MapIterator<U> {
+  function* gen(): Generator<U> {
+    for (let r = it.next(); !r.done; r = it.next()) {
+      yield fn(r.value)
+    }
+  }
+  return gen() as unknown as MapIterator<U>
+}
+
+// objectIds assigns stable ids to objects compared by identity.
+const objectIds = new WeakMap<object, number>()
+let nextObjectId = 1
+
+function objectId(obj: object): number {
+  let id = objectIds.get(obj)
+  if (id === undefined) {
+    id = nextObjectId++
+    objectIds.set(obj, id)
+  }
+  return id
+}
+
+// hashMapKey returns a value that is === for keys that are == in Go.
+// A NaN float yields a fresh object, so it can never be found again.
+function hashMapKey(key: unknown, keyType: MapKeyType): unknown {
+  if (keyType === 'value' || keyType === 'pointer') {
+    return key
+  }
+  if (keyType === 'float' && typeof key === 'number') {
+    if (Number.isNaN(key)) {
+      return {}
+    }
+    return key === 0 ? 0 : key
+  }
+  let nan = false
+  const hash = encodeMapKey(key, keyType, () => {
+    nan = true
+  })
+  return nan ? {} : hash
+}
+
+// encodeMapKey encodes key as a string with type tags.
+function encodeMapKey(
+  key: unknown,
+  keyType: MapKeyType,
+  onNaN: () => void,
+): string {
+  if (keyType === 'interface') {
+    return encodeDynamicKey(key, onNaN)
+  }
+  if (keyType === 'float') {
+    return encodeFloat(key as number, onNaN)
+  }
+  if (keyType === 'complex') {
+    const c = key as Complex
+    return `c(${encodeFloat(c.real, onNaN)},${encodeFloat(c.imag, onNaN)})`
+  }
+  if (keyType === 'pointer') {
+    return key === null || key === undefined
+      ? 'nil'
+      : `p${objectId(key as object)}`
+  }
+  if (keyType === 'value') {
+    return encodePrimitive(key)
+  }
+  if ('elem' in keyType) {
+    const arr = key as ArrayLike<unknown>
+    const parts: string[] = []
+    for (let i = 0; i < arr.length; i++) {
+      parts.push(encodeMapKey(arr[i], keyType.elem, onNaN))
+    }
+    return `[${parts.join(',')}]`
+  }
+  const fields = (key as any)._fields
+  const parts: string[] = []
+  for (const [name, fieldType] of Object.entries(keyType.fields)) {
+    parts.push(encodeMapKey(fields[name].value, fieldType, onNaN))
+  }
+  return `{${parts.join(',')}}`
+}
+
+// encodeDynamicKey encodes the dynamic value of an interface key.
+function encodeDynamicKey(key: unknown, onNaN: () => void): string {
+  if (key === null || key === undefined) {
+    return 'nil'
+  }
+  if (typeof key === 'number') {
+    return encodeFloat(key, onNaN)
+  }
+  if (key instanceof Complex) {
+    return encodeMapKey(key, 'complex', onNaN)
+  }
+  if (typeof key !== 'object') {
+    return encodePrimitive(key)
+  }
+  // Struct values are compared structurally, everything else by identity.
+  const typeInfo = (key as any).constructor?.__typeInfo
+  if (typeInfo?.fields && isMarkedAsStructValue(key)) {
+    const fields = (key as any)._fields
+    const parts: string[] = []
+    for (const name of Object.keys(typeInfo.fields)) {
+      const value = fields[name]?.value
+      const fieldInfo = typeInfo.fields[name]
+      const fieldType = fieldInfo?.type ?? fieldInfo
+      parts.push(
+        isPointerTypeInfo(fieldType) ?
+          encodeMapKey(value, 'pointer', onNaN)
+        : encodeDynamicKey(value, onNaN),
+      )
+    }
+    return `${typeInfo.name}{${parts.join(',')}}`
+  }
+  if (Array.isArray(key) || (key as any).__meta__ !== undefined) {
+    return encodeMapKey(key, { elem: 'interface' }, onNaN)
+  }
+  return `p${objectId(key)}`
+}
+
+function encodeFloat(x: number, onNaN: () => void): string {
+  if (Number.isNaN(x)) {
+    onNaN()
+    return 'NaN'
+  }
+  return x === 0 ? 'n0' : `n${x}`
+}
+
+function encodePrimitive(x: unknown): string {
+  switch (typeof x) {
+    case 'string':
+      return JSON.stringify(x)
+    case 'number':
+      return `n${x}`
+    case 'bigint':
+      return `b${x}`
+    case 'boolean':
+      return x ? 'true' : 'false'
+    default:
+      return x === null || x === undefined ? 'nil' : `p${objectId(x as object)}`
+  }
+}
+
+function isPointerTypeInfo(t: unknown): boolean {
+  return (
+    typeof t === 'object' && t !== null && (t as any).kind === TypeKind.Pointer
+  )
+}
+
+// copyMapKey copies struct and array keys so that later mutation of the
+// original value does not change the stored key.
+function copyMapKey<K>(key: K, keyType: MapKeyType): K {
+  if (typeof keyType !== 'object' || key === null || key === undefined) {
+    return key
+  }
+  if ('elem' in keyType) {
+    return Array.from(key as ArrayLike<unknown>) as K
+  }
+  return typeof (key as any).clone === 'function' ? (key as any).clone() : key
+}
+
 /**
  * Creates a new map (TypeScript Map).
+ * @param keyType Describes how keys are compared if they need Go equality
+ *   semantics. If omitted, 'value' or 'pointer', a native Map comparing keys
+ *   by identity is returned.
  * @returns A new TypeScript Map.
  */
-export const makeMap = <K, V>(): Map<K, V> => {
+export const makeMap = <K, V>(keyType?: MapKeyType): Map<K, V> => {
+  if (keyType !== undefined && keyType !== 'value' && keyType !== 'pointer') {
+    return new HashMap<K, V>(keyType)
+  }
+  return new Map<K, V>()
+}
+
+/**
+ * Reports whether a and b are equal keys of a map with the given key type,
+ * i.e. whether a == b in Go.
+ */
+export function mapKeysEqual(a: unknown, b: unknown, keyType: MapKeyType): boolean {
+  return hashMapKey(a, keyType) === hashMapKey(b, keyType)
+}
+
+/**
+ * Creates a new empty map comparing keys the same way as m.
+ * @param m The map whose key comparison is copied.
+ * @returns A new empty map.
+ */
+export const makeMapLike = <K, V>(m: Map<K, V> | null): Map<K, V> => {
+  if (m instanceof HashMap) {
+    return new HashMap<K, V>(m.keyType)
+  }
   return new Map<K, V>()
 }
 
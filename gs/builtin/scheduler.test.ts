@@ -0,0 +1,46 @@
+import { afterEach, describe, expect, it } from 'vitest'
+import * as $ from './index.js'
+
+describe('runMain', () => {
+  afterEach(() => {
+    process.exitCode = undefined
+  })
+
+  it('reports a deadlock like the Go runtime', async () => {
+    const results = $.makeChannel<number>(0, 0)
+    const work = $.makeChannel<number>(0, 0)
+
+    async function sendResult(): Promise<void> {
+      await $.chanSend(work, 1)
+    }
+    async function waitResult(): Promise<void> {
+      $.go(sendResult)
+      await $.chanRecv(results)
+    }
+
+    const err = await $.runMain(waitResult).then(
+      () => null,
+      (err: unknown) => err,
+    )
+    expect(err).toBeInstanceOf($.DeadlockError)
+    const msg = (err as Error).message
+    expect(msg).toMatch(
+      /^fatal error: all goroutines are asleep - deadlock!\n\ngoroutine \[/,
+    )
+    expect(msg).toMatch(/goroutine \[chan receive\]:\n\tat waitResult /)
+    expect(msg).toMatch(/goroutine \[chan send\]:\n\tat sendResult /)
+    expect((err as $.DeadlockError).goroutines).toHaveLength(2)
+    expect(process.exitCode).toBe(2)
+  })
+
+  it('returns when main returns', async () => {
+    const done = $.makeChannel<boolean>(1, false)
+    await $.runMain(async () => {
+      $.go(async () => {
+        await $.chanSend(done, true)
+      })
+      expect(await $.chanRecv(done)).toBe(true)
+    })
+    expect(process.exitCode).toBeUndefined()
+  })
+})
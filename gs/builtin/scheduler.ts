@@ -0,0 +1,372 @@
+/**
+ * Goroutines run cooperatively: a goroutine only gives way to the others when
+ * it awaits. When compiled with preemption enabled, the loops of async
+ * functions check at each iteration whether the running goroutine used up its
+ * time slice, and if so yield to the event loop, letting other goroutines,
+ * timers, I/O and rendering run:
+ *
+ *   for (;;) {
+ *     if ($.shouldYield()) await $.yieldSlice()
+ *     ...
+ *   }
+ */
+
+/** Length of a time slice in milliseconds. */
+let timeSliceMs = 10
+
+/** Number of shouldYield calls between two reads of the clock. */
+const checkInterval = 1000
+
+let checksLeft = checkInterval
+
+const now: () => number =
+  typeof performance !== 'undefined' ?
+    () => performance.now()
+  : () => Date.now()
+
+/** End of the current time slice, as returned by now(). */
+let sliceEnd = now() + timeSliceMs
+
+/**
+ * Sets the length of the time slice after which looping goroutines yield.
+ * @param ms Length of the time slice in milliseconds.
+ */
+export function setTimeSlice(ms: number): void {
+  timeSliceMs = ms
+  sliceEnd = now() + timeSliceMs
+}
+
+/**
+ * Reports whether the running goroutine used up its time slice and should
+ * yield with yieldSlice. It is cheap enough to be called at each iteration
+ * of a loop: the clock is only read every few calls.
+ */
+export function shouldYield(): boolean {
+  if (--checksLeft > 0) {
+    return false
+  }
+  checksLeft = checkInterval
+  return now() >= sliceEnd
+}
+
+/**
+ * Yields to the event loop and starts a new time slice once the running
+ * goroutine is resumed.
+ */
+export function yieldSlice(): Promise<void> {
+  return new Promise((resolve) => {
+    scheduleMacrotask(() => {
+      sliceEnd = now() + timeSliceMs
+      checksLeft = checkInterval
+      resolve()
+    })
+  })
+}
+
+/** Callbacks waiting for the message channel, see scheduleMacrotask. */
+const macrotasks: (() => void)[] = []
+let macrotaskChannel: MessageChannel | null = null
+
+/**
+ * Runs fn in a new task of the event loop, after pending microtasks, timers
+ * and I/O. Unlike setTimeout(fn, 0), it is not throttled by browsers.
+ */
+function scheduleMacrotask(fn: () => void): void {
+  const setImmediateFn = (globalThis as any).setImmediate
+  if (typeof setImmediateFn === 'function') {
+    setImmediateFn(fn)
+    return
+  }
+  if (typeof MessageChannel === 'undefined') {
+    setTimeout(fn, 0)
+    return
+  }
+  if (macrotaskChannel === null) {
+    macrotaskChannel = new MessageChannel()
+    const port = macrotaskChannel.port1
+    port.onmessage = () => {
+      // Close the idle channel, an open one keeps Node.js and Deno running.
+      if (macrotasks.length === 1) {
+        port.close()
+        macrotaskChannel = null
+      }
+      macrotasks.shift()?.()
+    }
+  }
+  macrotasks.push(fn)
+  macrotaskChannel.port2.postMessage(null)
+}
+
+/**
+ * The scheduler also keeps track of the goroutines to detect deadlocks. A
+ * goroutine started with go is live until its function returns, and parked
+ * while it waits in a blocking operation of the runtime: a channel operation,
+ * a select or a sync primitive. While the main function runs under runMain,
+ * the program is deadlocked when all live goroutines, main included, are
+ * parked and no timer or external event may wake any of them.
+ *
+ * JavaScript cannot tell which goroutine an await resumes, so the state is
+ * counted rather than kept per goroutine: an async operation which is not a
+ * park, such as fetch or a stream read, keeps its goroutine running. Code
+ * which wakes goroutines from JavaScript callbacks, such as event listeners,
+ * must hold keepAlive while it may do so.
+ */
+
+/** Parked is a goroutine blocked in the runtime, see park. */
+export interface Parked {
+  /** reason is the blocking operation, as in Go tracebacks. */
+  readonly reason: string
+  /** site records the stack of the blocking operation. */
+  readonly site: Error | null
+  /** ready marks the goroutine as runnable again. */
+  ready(): void
+}
+
+/**
+ * DeadlockError is thrown by runMain when all goroutines are asleep. Its
+ * message is the crash report of the Go runtime, starting with
+ * "fatal error: ".
+ */
+export class DeadlockError extends Error {
+  /** goroutines are the blocked goroutines. */
+  declare readonly goroutines: Parked[]
+
+  constructor(goroutines: Parked[]) {
+    const sites = goroutines.map(formatParked).join('\n\n')
+    super('fatal error: all goroutines are asleep - deadlock!\n\n' + sites)
+    this.name = 'DeadlockError'
+    // Not enumerable, the message already lists them when the error is logged.
+    Object.defineProperty(this, 'goroutines', { value: goroutines })
+  }
+}
+
+/** Number of goroutines started with go which did not return yet. */
+let goroutines = 0
+
+/** The parked goroutines. */
+const parked = new Set<Parked>()
+
+/** Number of pending timers, see setTimer. */
+let pendingTimers = 0
+
+/** Number of holds taken with keepAlive and not released yet. */
+let holds = 0
+
+/** Rejects the promise of the running main function, see runMain. */
+let abortMain: ((err: DeadlockError) => void) | null = null
+
+let checkScheduled = false
+
+/**
+ * Starts fn in a new goroutine, for the go statement.
+ * @param fn The body of the goroutine.
+ */
+export function go(fn: () => void | Promise<void>): void {
+  goroutines++
+  queueMicrotask(() => {
+    let result: void | Promise<void>
+    try {
+      result = fn()
+    } catch (err) {
+      exitGoroutine()
+      throw err
+    }
+    if (result instanceof Promise) {
+      // A goroutine which panics still crashes the program with an unhandled
+      // rejection.
+      void result.finally(exitGoroutine)
+    } else {
+      exitGoroutine()
+    }
+  })
+}
+
+function exitGoroutine(): void {
+  goroutines--
+  checkDeadlock()
+}
+
+/**
+ * Returns the number of goroutines that currently exist, including the
+ * calling one.
+ */
+export function numGoroutine(): number {
+  return goroutines + 1
+}
+
+/**
+ * Runs the main function of a program and detects deadlocks while it runs.
+ * @param main The main function.
+ * @returns A promise resolved when main returns, or rejected with a
+ * DeadlockError when all goroutines are asleep. The exit code of the process
+ * is then set to 2, like the Go runtime does.
+ */
+export async function runMain(main: () => void | Promise<void>): Promise<void> {
+  const deadlock = new Promise<never>((_, reject) => {
+    abortMain = reject
+  })
+  try {
+    await Promise.race([main(), deadlock])
+  } catch (err) {
+    if (err instanceof DeadlockError) {
+      const proc = (globalThis as any).process
+      if (proc) {
+        proc.exitCode = 2
+      }
+    }
+    throw err
+  } finally {
+    abortMain = null
+  }
+}
+
+/**
+ * Parks the calling goroutine in a blocking operation until ready is called.
+ * @param reason The blocking operation, such as 'chan receive'.
+ */
+export function park(reason: string): Parked {
+  const g: Parked = {
+    reason,
+    // Only pay for the stack while deadlocks are detected.
+    site: abortMain !== null ? new Error(reason) : null,
+    ready: () => {
+      parked.delete(g)
+    },
+  }
+  parked.add(g)
+  checkDeadlock()
+  return g
+}
+
+/**
+ * Parks the calling goroutine while it awaits promise.
+ * @param reason The blocking operation, such as 'chan receive'.
+ * @param promise The operation.
+ */
+export async function parkWhile<T>(
+  reason: string,
+  promise: Promise<T>,
+): Promise<T> {
+  const g = park(reason)
+  try {
+    return await promise
+  } finally {
+    g.ready()
+  }
+}
+
+/**
+ * Parks the calling goroutine forever, like an operation on a nil channel or
+ * an empty select.
+ * @param reason The blocking operation, such as 'select (no cases)'.
+ */
+export function parkForever<T>(reason: string): Promise<T> {
+  park(reason)
+  return new Promise<T>(() => {})
+}
+
+/** A timer started with setTimer. */
+export interface TimerHandle {
+  id: ReturnType<typeof setTimeout>
+  pending: boolean
+}
+
+/**
+ * Calls fn after ms milliseconds like setTimeout. A pending timer may wake a
+ * goroutine, so the program is not deadlocked while it waits.
+ */
+export function setTimer(fn: () => void, ms: number): TimerHandle {
+  pendingTimers++
+  const t: TimerHandle = {
+    pending: true,
+    id: setTimeout(() => {
+      t.pending = false
+      pendingTimers--
+      try {
+        fn()
+      } finally {
+        checkDeadlock()
+      }
+    }, ms),
+  }
+  return t
+}
+
+/**
+ * Stops a timer started with setTimer.
+ * @returns Whether the timer was stopped before it fired.
+ */
+export function clearTimer(t: TimerHandle): boolean {
+  if (!t.pending) {
+    return false
+  }
+  t.pending = false
+  pendingTimers--
+  clearTimeout(t.id)
+  checkDeadlock()
+  return true
+}
+
+/** Returns a promise resolved after ms milliseconds, see setTimer. */
+export function sleep(ms: number): Promise<void> {
+  return new Promise((resolve) => setTimer(resolve, ms))
+}
+
+/**
+ * Reports that goroutines may be woken by JavaScript, such as an event
+ * listener sending on a channel, which prevents deadlock detection until the
+ * returned function is called.
+ */
+export function keepAlive(): () => void {
+  let released = false
+  holds++
+  return () => {
+    if (!released) {
+      released = true
+      holds--
+      checkDeadlock()
+    }
+  }
+}
+
+/** Reports whether nothing can wake the parked goroutines any more. */
+function allAsleep(): boolean {
+  return (
+    abortMain !== null &&
+    parked.size >= goroutines + 1 &&
+    pendingTimers === 0 &&
+    holds === 0
+  )
+}
+
+/**
+ * Checks for a deadlock once the pending microtasks ran: a goroutine being
+ * woken is still parked until its wake-up callback runs.
+ */
+function checkDeadlock(): void {
+  if (checkScheduled || !allAsleep()) {
+    return
+  }
+  checkScheduled = true
+  setTimeout(() => {
+    checkScheduled = false
+    if (allAsleep()) {
+      abortMain!(new DeadlockError([...parked]))
+    }
+  }, 0)
+}
+
+/** Matches the stack frames of the runtime modules, but not of their tests. */
+const runtimeFrame = /[\\/]builtin[\\/]\w+\.[jt]s\b/
+
+/** Formats a parked goroutine like a Go traceback. */
+function formatParked(g: Parked): string {
+  const frames = (g.site?.stack ?? '')
+    .split('\n')
+    .slice(1)
+    .map((line) => line.trim())
+    .filter((line) => line !== '' && !runtimeFrame.test(line))
+  return [`goroutine [${g.reason}]:`, ...frames.map((f) => '\t' + f)].join(
+    '\n',
+  )
+}
@@ -129,12 +129,14 @@ export function isSliceProxy<T>(slice: Slice<T>): slice is SliceProxy<T> {
  * Creates a new slice with the specified length and capacity.
  * @param length The length of the slice.
  * @param capacity The capacity of the slice (optional).
+ * @param typeHint The kind of element, or a function returning a new zero
+ *   element, used to initialize the elements (optional).
  * @returns A new slice.
  */
 export const makeSlice = <T>(
   length: number,
   capacity?: number,
-  typeHint?: string,
+  typeHint?: string | (() => T),
 ): Slice<T> => {
   if (typeHint === 'byte') {
     const actualCapacity = capacity === undefined ? length : capacity
@@ -181,6 +183,9 @@ export const makeSlice = <T>(
     case 'number':
       zeroVal = 0
       break
+    case 'bigint':
+      zeroVal = 0n
+      break
     case 'boolean':
       zeroVal = false
       break
@@ -194,7 +199,7 @@ export const makeSlice = <T>(
   const backingArr = new Array<T>(actualCapacity)
   // Initialize the relevant part of the backing array
   for (let i = 0; i < length; i++) {
-    backingArr[i] = zeroVal
+    backingArr[i] = typeof typeHint === 'function' ? typeHint() : zeroVal
   }
   // The rest of backingArr (from length to actualCapacity-1) remains uninitialized (undefined),
   // representing available capacity.
@@ -1,3 +1,5 @@
+import { Complex } from './complex.js'
+
 /**
  * Represents the kinds of Go types that can be registered at runtime.
  */
@@ -461,6 +463,16 @@ function matchesBasicType(value: any, info: TypeInfo): boolean {
     return typeof value === 'number'
   if (info.name === 'boolean' || info.name === 'bool')
     return typeof value === 'boolean'
+  // int64, uint64 and uintptr are only named like this when emitted as bigint
+  if (
+    info.name === 'int64' ||
+    info.name === 'uint64' ||
+    info.name === 'uintptr' ||
+    info.name === 'bigint'
+  )
+    return typeof value === 'bigint'
+  if (info.name === 'complex64' || info.name === 'complex128')
+    return value instanceof Complex
   return false
 }
 
@@ -704,7 +716,7 @@ export function markAsStructValue<T>(value: T): T {
 }
 
 // Check if a struct instance is marked as a value
-function isMarkedAsStructValue(value: any): boolean {
+export function isMarkedAsStructValue(value: any): boolean {
   return (
     typeof value === 'object' &&
     value !== null &&
@@ -854,7 +866,8 @@ function matchesChannelType(value: any, info: ChannelTypeInfo): boolean {
  */
 function matchesType(value: any, info: TypeInfo): boolean {
   if (value === null || value === undefined) {
-    return false
+    // Only the nil case of a type switch matches a nil interface
+    return info.kind === TypeKind.Basic && info.name === 'nil'
   }
 
   switch (info.kind) {
@@ -1022,6 +1035,10 @@ export function typeAssert<T>(
       }
       return { value: null as unknown as T, ok: false }
     }
+    // A typed nil implements the interfaces of its pointer type
+    if (matchesInterfaceType(value, normalizedType)) {
+      return { value: value as T, ok: true }
+    }
     return { value: null as unknown as T, ok: false }
   }
 
@@ -1151,6 +1168,9 @@ export function mustTypeAssert<T>(value: any, typeInfo: string | TypeInfo): T {
  * @returns True if the value matches the type, false otherwise
  */
 export function is(value: any, typeInfo: string | TypeInfo): boolean {
+  if (isTypedNil(value)) {
+    return typeAssert(value, typeInfo).ok
+  }
   return matchesType(value, normalizeTypeInfo(typeInfo))
 }
 
@@ -1205,17 +1225,75 @@ export function typeSwitch(
   }
 }
 
+// typedNils caches the typed nil of each pointer type, so that two typed nils
+// of the same type compare equal like in Go.
+const typedNils = new Map<string, any>()
+
 /**
  * Creates a typed nil pointer with type metadata for reflection.
- * This is used for type conversions like (*Interface)(nil) where we need
- * to preserve the pointer type information even though the value is null.
+ * This is used for type conversions like (*Interface)(nil) and for nil
+ * pointers stored in interfaces, where the interface is not nil in Go.
+ * Methods of the pointer type can be called on the typed nil, and are
+ * invoked with a null receiver.
  *
  * @param typeName The full Go type name (e.g., "*main.Stringer")
  * @returns An object that represents a typed nil with reflection metadata
  */
 export function typedNil(typeName: string): any {
-  return Object.assign(Object.create(null), {
-    __goType: typeName,
-    __isTypedNil: true,
-  })
+  let nil = typedNils.get(typeName)
+  if (nil === undefined) {
+    const target = Object.assign(Object.create(null), {
+      __goType: typeName,
+      __isTypedNil: true,
+    })
+    nil = new Proxy(target, {
+      get(target, prop) {
+        if (prop in target || typeof prop !== 'string') {
+          return target[prop]
+        }
+        // The __type discriminant of sealed unions is the name of the struct.
+        if (prop === '__type') {
+          return typeName.startsWith('*') ?
+              typeName.slice(typeName.lastIndexOf('.') + 1)
+            : undefined
+        }
+        // Types are registered lazily, so resolve the method on each access.
+        // Generic types are registered without their type arguments.
+        const registered: any =
+          typeName.startsWith('*') ?
+            typeRegistry.get(typeName.slice(1).replace(/\[.*\]$/, ''))
+          : null
+        const method = registered?.ctor?.prototype?.[prop]
+        return typeof method === 'function' ? method.bind(null) : undefined
+      },
+      has(target, prop) {
+        return prop in target
+      },
+    })
+    typedNils.set(typeName, nil)
+  }
+  return nil
+}
+
+/**
+ * Returns value, or the typed nil of typeName if value is a nil pointer.
+ * This is used when a pointer that may be nil is converted to an interface.
+ *
+ * @param value The pointer value
+ * @param typeName The full Go type name of the pointer (e.g., "*main.Dog")
+ */
+export function typedNilOf<T>(value: T | null, typeName: string): T {
+  if (value === null || value === undefined) {
+    return typedNil(typeName)
+  }
+  return value
+}
+
+/**
+ * Reports whether value is a typed nil created by typedNil.
+ *
+ * @param value The value to check
+ */
+export function isTypedNil(value: any): boolean {
+  return typeof value === 'object' && value !== null && value.__isTypedNil === true
 }
@@ -0,0 +1,80 @@
+import { MapKeyType, mapKeysEqual } from './map.js'
+import { varRef } from './varRef.js'
+
+/**
+ * TypeDict describes the type argument of a generic function at runtime.
+ *
+ * TypeScript generics are erased, so generic functions which need to know
+ * their type arguments take a dictionary for each of them:
+ *
+ *   func Zero[T any]() T     function Zero<T>($T: $.TypeDict<T>): T
+ *   Zero[IntVal]()           Zero($.typeDict<IntVal>(() => 0, 'value', { String: IntVal_String }))
+ */
+export interface TypeDict<T = any> {
+  /** Returns a new zero value of the type. */
+  zero: () => T
+  /** Describes how values of the type are compared, see MapKeyType. */
+  key: MapKeyType
+  /**
+   * Methods of types which are not represented by classes, like named basic
+   * types, taking the receiver as their first argument.
+   */
+  methods?: Record<string, (recv: T, ...args: any[]) => any>
+}
+
+/**
+ * Creates the dictionary of a type argument.
+ * @param zero Returns a new zero value of the type.
+ * @param key Describes how values of the type are compared.
+ * @param methods Methods of the type called as free functions.
+ */
+export function typeDict<T>(
+  zero: () => T,
+  key: MapKeyType = 'value',
+  methods?: Record<string, (recv: T, ...args: any[]) => any>,
+): TypeDict<T> {
+  return { zero, key, methods }
+}
+
+/**
+ * Returns a pointer to a new zero value of the type described by dict, like
+ * new(T) in Go. Pointers to structs are the struct instance itself, other
+ * pointers are a VarRef.
+ */
+export function newOf<T>(dict: TypeDict<T>): any {
+  const zero = dict.zero()
+  if (typeof dict.key === 'object' && 'fields' in dict.key) {
+    return zero
+  }
+  return varRef(zero)
+}
+
+/**
+ * Calls the method name on recv, a value of the type described by dict.
+ * Methods of named basic types are looked up in the dictionary, other
+ * methods are called on the value itself.
+ */
+export function callMethod<T>(
+  dict: TypeDict<T>,
+  recv: T,
+  name: string,
+  ...args: any[]
+): any {
+  const method = dict.methods?.[name]
+  if (method) {
+    return method(recv, ...args)
+  }
+  if (recv === null || recv === undefined) {
+    throw new Error(
+      'runtime error: invalid memory address or nil pointer dereference',
+    )
+  }
+  return (recv as any)[name](...args)
+}
+
+/**
+ * Reports whether a == b for values of the type described by dict.
+ */
+export function dictEquals<T>(dict: TypeDict<T>, a: T, b: T): boolean {
+  return mapKeysEqual(a, b, dict.key)
+}
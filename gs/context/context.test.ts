@@ -0,0 +1,29 @@
+import { describe, it, expect } from 'vitest'
+import * as $ from '@goscript/builtin/index.js'
+import { Background, Canceled, Cause, fromAbortSignal } from './context.js'
+
+describe('fromAbortSignal', () => {
+  it('returns Background without a signal', () => {
+    expect(fromAbortSignal()).toBe(Background())
+  })
+
+  it('cancels the context when the signal aborts', async () => {
+    const controller = new AbortController()
+    const ctx = fromAbortSignal(controller.signal)
+    expect(ctx.Err()).toBe(null)
+
+    controller.abort(new Error('stopped'))
+    await ctx.Done().receive()
+    expect(ctx.Err()).toBe(Canceled)
+    expect(Cause(ctx)!.Error()).toBe('stopped')
+  })
+
+  it('unwraps the Go error of a GoErrorException', () => {
+    const err = $.newError('shutdown')!
+    const ctx = fromAbortSignal(
+      AbortSignal.abort(new $.GoErrorException(err)),
+    )
+    expect(ctx.Err()).toBe(Canceled)
+    expect(Cause(ctx)).toBe(err)
+  })
+})
@@ -196,7 +196,7 @@ class cancelContext extends baseContext {
 // Timer context with deadline
 class timerContext extends cancelContext {
   private deadline: Date
-  private timer: any
+  private timer: $.TimerHandle | null = null
 
   constructor(parent: ContextNonNil, deadline: Date) {
     super(parent)
@@ -217,7 +217,7 @@ class timerContext extends cancelContext {
       return
     }
 
-    this.timer = setTimeout(() => {
+    this.timer = $.setTimer(() => {
       this.cancel(true, DeadlineExceeded, null)
     }, duration)
   }
@@ -225,7 +225,7 @@ class timerContext extends cancelContext {
   cancel(removeFromParent: boolean, err: $.GoError, cause: $.GoError): void {
     super.cancel(removeFromParent, err, cause)
     if (this.timer) {
-      clearTimeout(this.timer)
+      $.clearTimer(this.timer)
       this.timer = null
     }
   }
@@ -421,3 +421,28 @@ export function AfterFunc(ctx: Context, f: () => void): () => boolean {
     return false
   }
 }
+
+// fromAbortSignal returns a context which is canceled when signal aborts, with
+// the abort reason as the cause. It is used by the facades of compiled
+// packages (facade.ts) to fill context.Context parameters. Without a signal,
+// it returns Background.
+export function fromAbortSignal(signal?: AbortSignal | null): ContextNonNil {
+  if (!signal) {
+    return background
+  }
+  const [ctx, cancel] = WithCancelCause(background)
+  const abort = () => {
+    const reason: unknown = signal.reason
+    cancel(
+      reason instanceof Error ?
+        $.toGoError(reason)
+      : $.newError(String(reason ?? 'aborted')),
+    )
+  }
+  if (signal.aborted) {
+    abort()
+  } else {
+    signal.addEventListener('abort', abort, { once: true })
+  }
+  return ctx
+}
@@ -0,0 +1,86 @@
+import { describe, it, expect } from 'vitest'
+import * as $ from '@goscript/builtin/index.js'
+import * as io from '@goscript/io/index.js'
+import * as fs from '@goscript/io/fs/index.js'
+import { FS } from './embed.js'
+
+const files = new FS([
+  { name: 'static/index.html', data: '<h1>index</h1>\n' },
+  { name: 'static/css/site.css', data: 'body {}\n' },
+  { name: 'static/logo.bin', data: new Uint8Array([0, 1, 254, 255]) },
+])
+
+describe('embed.FS', () => {
+  it('reads files', () => {
+    const [data, err] = files.ReadFile('static/index.html')
+    expect(err).toBe(null)
+    expect($.bytesToString(data)).toBe('<h1>index</h1>\n')
+
+    const [bin] = files.ReadFile('static/logo.bin')
+    expect(Array.from(bin)).toEqual([0, 1, 254, 255])
+    // ReadFile returns a copy of the contents.
+    bin[0] = 42
+    expect(files.ReadFile('static/logo.bin')[0][0]).toBe(0)
+  })
+
+  it('lists directories in name order', () => {
+    const [root, rootErr] = files.ReadDir('.')
+    expect(rootErr).toBe(null)
+    expect($.asArray(root).map((e) => e!.Name())).toEqual(['static'])
+
+    const [entries] = files.ReadDir('static')
+    expect($.asArray(entries).map((e) => [e!.Name(), e!.IsDir()])).toEqual([
+      ['css', true],
+      ['index.html', false],
+      ['logo.bin', false],
+    ])
+  })
+
+  it('reports errors like Go', () => {
+    expect(files.ReadFile('missing')[1]!.Error()).toBe(
+      'open missing: file does not exist',
+    )
+    expect(files.ReadFile('/static/index.html')[1]!.Error()).toBe(
+      'open /static/index.html: file does not exist',
+    )
+    expect(files.ReadFile('static')[1]!.Error()).toBe(
+      'read static: is a directory',
+    )
+    expect(files.ReadDir('static/index.html')[1]!.Error()).toBe(
+      'read static/index.html: not a directory',
+    )
+    expect(new FS().Open('static')[1]!.Error()).toBe(
+      'open static: file does not exist',
+    )
+  })
+
+  it('opens files for reading and seeking', () => {
+    const [f, err] = files.Open('static/index.html')
+    expect(err).toBe(null)
+    const [info] = f!.Stat()
+    expect(info!.Name()).toBe('index.html')
+    expect(info!.Size()).toBe(15)
+    expect(fs.FileMode_String(info!.Mode())).toBe('-r--r--r--')
+
+    const buf = new Uint8Array(4)
+    expect(f!.Read(buf)).toEqual([4, null])
+    expect($.bytesToString(buf)).toBe('<h1>')
+
+    const seeker = f as unknown as io.Seeker
+    expect(seeker!.Seek(-4, io.SeekEnd)).toEqual([11, null])
+    const rest = new Uint8Array(8)
+    expect(f!.Read(rest)).toEqual([4, null])
+    expect(f!.Read(rest)).toEqual([0, io.EOF])
+  })
+
+  it('reads directories in chunks', () => {
+    const [d] = files.Open('static')
+    const dir = d as fs.ReadDirFile
+    expect(dir!.Read(new Uint8Array(1))[1]!.Error()).toBe(
+      'read static: is a directory',
+    )
+    expect($.len(dir!.ReadDir(2)[0])).toBe(2)
+    expect($.len(dir!.ReadDir(2)[0])).toBe(1)
+    expect(dir!.ReadDir(2)).toEqual([null, io.EOF])
+  })
+})
@@ -0,0 +1,340 @@
+import * as $ from '@goscript/builtin/index.js'
+
+import * as io from '@goscript/io/index.js'
+
+import * as fs from '@goscript/io/fs/index.js'
+
+import * as time from '@goscript/time/index.js'
+
+// EmbedFile is a file embedded in an FS by the compiler, with its contents
+// as a string when they are valid UTF-8.
+export interface EmbedFile {
+  // name is the slash-separated path of the file, like "static/index.html".
+  name: string
+  data: string | Uint8Array
+}
+
+// file is a file or directory of an FS.
+class file {
+  // name is the full slash-separated path of the file, "." for the root.
+  public name: string
+  public data: Uint8Array
+  public isDir: boolean
+  // entries are the files of a directory, sorted by name.
+  public entries: file[] = []
+
+  constructor(name: string, data: Uint8Array | null) {
+    this.name = name
+    this.data = data ?? new Uint8Array(0)
+    this.isDir = data === null
+  }
+
+  public Name(): string {
+    const i = this.name.lastIndexOf('/')
+    return i < 0 ? this.name : this.name.slice(i + 1)
+  }
+
+  public Size(): number {
+    return this.data.length
+  }
+
+  public ModTime(): time.Time {
+    return new time.Time()
+  }
+
+  public IsDir(): boolean {
+    return this.isDir
+  }
+
+  public Sys(): null | any {
+    return null
+  }
+
+  public Type(): fs.FileMode {
+    return fs.FileMode_Type(this.Mode())
+  }
+
+  public Info(): [fs.FileInfo, $.GoError] {
+    return [this, null]
+  }
+
+  public Mode(): fs.FileMode {
+    if (this.isDir) {
+      return fs.ModeDir | 0o555
+    }
+    return 0o444
+  }
+
+  public String(): string {
+    return fs.FormatFileInfo(this)
+  }
+}
+
+// FS is a read-only collection of files, usually initialized with a
+// //go:embed directive. The compiler passes the embedded files to the
+// constructor; the zero FS is empty.
+//
+// FS implements [fs.FS], [fs.ReadDirFS] and [fs.ReadFileFS].
+export class FS {
+  // files maps the names of the files and directories to them.
+  private _files: Map<string, file>
+
+  constructor(files?: EmbedFile[] | Partial<{}>) {
+    const root = new file('.', null)
+    this._files = new Map([['.', root]])
+    if (!Array.isArray(files)) {
+      return
+    }
+    for (const f of files) {
+      const data =
+        typeof f.data === 'string' ? $.stringToBytes(f.data) : f.data
+      this.add(new file(f.name, data))
+    }
+    for (const dir of this._files.values()) {
+      dir.entries.sort((a, b) =>
+        a.name < b.name ? -1
+        : a.name > b.name ? 1
+        : 0,
+      )
+    }
+  }
+
+  // add adds f and the directories containing it.
+  private add(f: file): void {
+    if (this._files.has(f.name)) {
+      return
+    }
+    this._files.set(f.name, f)
+    const i = f.name.lastIndexOf('/')
+    const dirName = i < 0 ? '.' : f.name.slice(0, i)
+    let dir = this._files.get(dirName)
+    if (!dir) {
+      dir = new file(dirName, null)
+      this.add(dir)
+    }
+    dir.entries.push(f)
+  }
+
+  public clone(): FS {
+    const cloned = new FS()
+    cloned._files = this._files
+    return cloned
+  }
+
+  // Open opens the named file for reading and returns it as an [fs.File].
+  //
+  // The returned file implements [io.Seeker] and [io.ReaderAt] when the file
+  // is not a directory.
+  public Open(name: string): [fs.File, $.GoError] {
+    const f = fs.ValidPath(name) ? this._files.get(name) : undefined
+    if (!f) {
+      return [
+        null,
+        new fs.PathError({ Op: 'open', Path: name, Err: fs.ErrNotExist }),
+      ]
+    }
+    if (f.isDir) {
+      return [new openDir(f), null]
+    }
+    return [new openFile(f), null]
+  }
+
+  // ReadDir reads and returns the entire named directory.
+  public ReadDir(name: string): [$.Slice<fs.DirEntry>, $.GoError] {
+    const [file, err] = this.Open(name)
+    if (err !== null) {
+      return [null, err]
+    }
+    if (!(file instanceof openDir)) {
+      return [
+        null,
+        new fs.PathError({
+          Op: 'read',
+          Path: name,
+          Err: $.newError('not a directory'),
+        }),
+      ]
+    }
+    return [$.arrayToSlice<fs.DirEntry>([...file.f.entries]), null]
+  }
+
+  // ReadFile reads and returns the content of the named file.
+  public ReadFile(name: string): [Uint8Array, $.GoError] {
+    const [file, err] = this.Open(name)
+    if (err !== null) {
+      return [new Uint8Array(0), err]
+    }
+    if (!(file instanceof openFile)) {
+      return [
+        new Uint8Array(0),
+        new fs.PathError({
+          Op: 'read',
+          Path: name,
+          Err: $.newError('is a directory'),
+        }),
+      ]
+    }
+    return [new Uint8Array(file.f.data), null]
+  }
+
+  // Register this type with the runtime type system
+  static __typeInfo = $.registerStructType(
+    'FS',
+    new FS(),
+    [
+      {
+        name: 'Open',
+        args: [
+          { name: 'name', type: { kind: $.TypeKind.Basic, name: 'string' } },
+        ],
+        returns: [
+          { type: 'File' },
+          {
+            type: { kind: $.TypeKind.Interface, name: 'GoError', methods: [] },
+          },
+        ],
+      },
+      {
+        name: 'ReadDir',
+        args: [
+          { name: 'name', type: { kind: $.TypeKind.Basic, name: 'string' } },
+        ],
+        returns: [
+          { type: { kind: $.TypeKind.Slice, elemType: 'DirEntry' } },
+          {
+            type: { kind: $.TypeKind.Interface, name: 'GoError', methods: [] },
+          },
+        ],
+      },
+      {
+        name: 'ReadFile',
+        args: [
+          { name: 'name', type: { kind: $.TypeKind.Basic, name: 'string' } },
+        ],
+        returns: [
+          {
+            type: {
+              kind: $.TypeKind.Slice,
+              elemType: { kind: $.TypeKind.Basic, name: 'number' },
+            },
+          },
+          {
+            type: { kind: $.TypeKind.Interface, name: 'GoError', methods: [] },
+          },
+        ],
+      },
+    ],
+    FS,
+    {},
+  )
+}
+
+// openFile is a regular file opened for reading.
+class openFile {
+  public f: file
+  private offset = 0
+
+  constructor(f: file) {
+    this.f = f
+  }
+
+  public Close(): $.GoError {
+    return null
+  }
+
+  public Stat(): [fs.FileInfo, $.GoError] {
+    return [this.f, null]
+  }
+
+  public Read(b: $.Bytes): [number, $.GoError] {
+    if (this.offset >= this.f.data.length) {
+      return [0, io.EOF]
+    }
+    if (this.offset < 0) {
+      return [
+        0,
+        new fs.PathError({ Op: 'read', Path: this.f.name, Err: fs.ErrInvalid }),
+      ]
+    }
+    const n = $.copy(b as Uint8Array, this.f.data.subarray(this.offset))
+    this.offset += n
+    return [n, null]
+  }
+
+  public Seek(offset: number, whence: number): [number, $.GoError] {
+    switch (whence) {
+      case io.SeekStart:
+        break
+      case io.SeekCurrent:
+        offset += this.offset
+        break
+      case io.SeekEnd:
+        offset += this.f.data.length
+        break
+    }
+    if (offset < 0 || offset > this.f.data.length) {
+      return [
+        0,
+        new fs.PathError({ Op: 'seek', Path: this.f.name, Err: fs.ErrInvalid }),
+      ]
+    }
+    this.offset = offset
+    return [offset, null]
+  }
+
+  public ReadAt(b: $.Bytes, offset: number): [number, $.GoError] {
+    if (offset < 0 || offset > this.f.data.length) {
+      return [
+        0,
+        new fs.PathError({ Op: 'read', Path: this.f.name, Err: fs.ErrInvalid }),
+      ]
+    }
+    const n = $.copy(b as Uint8Array, this.f.data.subarray(offset))
+    if (n < $.len(b)) {
+      return [n, io.EOF]
+    }
+    return [n, null]
+  }
+}
+
+// openDir is a directory opened for reading.
+class openDir {
+  public f: file
+  private offset = 0
+
+  constructor(f: file) {
+    this.f = f
+  }
+
+  public Close(): $.GoError {
+    return null
+  }
+
+  public Stat(): [fs.FileInfo, $.GoError] {
+    return [this.f, null]
+  }
+
+  public Read(_b: $.Bytes): [number, $.GoError] {
+    return [
+      0,
+      new fs.PathError({
+        Op: 'read',
+        Path: this.f.name,
+        Err: $.newError('is a directory'),
+      }),
+    ]
+  }
+
+  public ReadDir(count: number): [$.Slice<fs.DirEntry>, $.GoError] {
+    let n = this.f.entries.length - this.offset
+    if (n === 0 && count > 0) {
+      return [null, io.EOF]
+    }
+    if (count > 0 && n > count) {
+      n = count
+    }
+    const list = this.f.entries.slice(this.offset, this.offset + n)
+    this.offset += n
+    return [$.arrayToSlice<fs.DirEntry>(list), null]
+  }
+}
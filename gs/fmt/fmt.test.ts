@@ -1,4 +1,5 @@
 import { describe, it, expect } from 'vitest'
+import * as $ from '@goscript/builtin/index.js'
 import * as fmt from './fmt.js'
 
 // Helper to capture stdout via internal stdout.write
@@ -174,3 +175,11 @@ describe('fmt parseFormat basic cases', () => {
     expect(fmt.Sprintf('%c', 65)).toBe('A')
   })
 })
+
+describe('fmt typed nil pointers', () => {
+  it('formats a typed nil without methods as <nil>', () => {
+    const nil = $.typedNil('*main.Plain')
+    expect(fmt.Sprint(nil)).toBe('<nil>')
+    expect(fmt.Sprintf('%v %T', nil, nil)).toBe('<nil> *main.Plain')
+  })
+})
@@ -30,10 +30,19 @@ function formatValue(value: any, verb: string): string {
     return '<nil>'
   }
 
+  if ($.isTypedNil(value)) {
+    return formatTypedNil(value, verb)
+  }
+
+  if (value instanceof $.Complex && 'feEgG'.includes(verb)) {
+    return formatComplex(value, (part) => formatValue(part, verb))
+  }
+
   switch (verb) {
     case 'v': // default format
       return defaultFormat(value)
     case 'd': // decimal integer
+      if (typeof value === 'bigint') return value.toString()
       return String(Math.trunc(Number(value)))
     case 'f': // decimal point, no exponent
       return Number(value).toString()
@@ -45,19 +54,21 @@ function formatValue(value: any, verb: string): string {
       if (typeof value === 'number') {
         return Number.isInteger(value) ? 'int' : 'float64'
       }
+      if (typeof value === 'bigint') return 'int64'
+      if (value instanceof $.Complex) return 'complex128'
       if (typeof value === 'boolean') return 'bool'
       if (typeof value === 'string') return 'string'
       return typeof value
     case 'c': // character (Unicode code point)
       return String.fromCharCode(Number(value))
     case 'x': // hexadecimal lowercase
-      return Number(value).toString(16)
+      return integerValue(value).toString(16)
     case 'X': // hexadecimal uppercase
-      return Number(value).toString(16).toUpperCase()
+      return integerValue(value).toString(16).toUpperCase()
     case 'o': // octal
-      return Number(value).toString(8)
+      return integerValue(value).toString(8)
     case 'b': // binary
-      return Number(value).toString(2)
+      return integerValue(value).toString(2)
     case 'e': // scientific notation lowercase
       return Number(value).toExponential()
     case 'E': // scientific notation uppercase
@@ -84,11 +95,54 @@ function formatValue(value: any, verb: string): string {
   }
 }
 
+// formatTypedNil formats a nil pointer stored in an interface. Like Go, the
+// Error or String method is called with the nil receiver, and <nil> is
+// written if it panics.
+function formatTypedNil(value: any, verb: string): string {
+  if (verb === 'T') {
+    return value.__goType
+  }
+  if (verb === 'p') {
+    return '0x0'
+  }
+  for (const method of ['Error', 'String']) {
+    if (typeof value[method] === 'function') {
+      try {
+        return value[method]()
+      } catch {
+        return '<nil>'
+      }
+    }
+  }
+  return '<nil>'
+}
+
+// formatComplex formats both parts of a complex number with formatPart,
+// always writing the sign of the imaginary part like Go.
+function formatComplex(
+  value: $.Complex,
+  formatPart: (part: number) => string,
+): string {
+  let imag = formatPart(value.imag)
+  if (imag[0] !== '-' && imag[0] !== '+') {
+    imag = '+' + imag
+  }
+  return `(${formatPart(value.real)}${imag}i)`
+}
+
+// integerValue keeps bigint values (int64 and uint64 in bigint mode) exact.
+function integerValue(value: any): number | bigint {
+  return typeof value === 'bigint' ? value : Number(value)
+}
+
 function defaultFormat(value: any): string {
   if (value === null || value === undefined) return '<nil>'
+  if ($.isTypedNil(value)) return formatTypedNil(value, 'v')
   if (typeof value === 'boolean') return value ? 'true' : 'false'
-  if (typeof value === 'number') return value.toString()
+  if (typeof value === 'number' || typeof value === 'bigint')
+    return value.toString()
   if (typeof value === 'string') return value
+  if (value instanceof $.Complex) return value.toString()
   if (Array.isArray(value))
     return '[' + value.map(defaultFormat).join(' ') + ']'
   if (typeof value === 'object') {
@@ -216,14 +270,19 @@ function parseFormat(format: string, args: any[]): string {
               (verb === 'f' || verb === 'e' || verb === 'g')
             ) {
               const p = parseInt(precision)
-              const num = Number(args[argIndex])
-              if (verb === 'f') {
-                formatted = num.toFixed(p)
-              } else if (verb === 'e') {
-                formatted = num.toExponential(p)
-              } else if (verb === 'g') {
-                formatted = num.toPrecision(p)
+              const formatPart = (num: number): string => {
+                if (verb === 'f') {
+                  return num.toFixed(p)
+                } else if (verb === 'e') {
+                  return num.toExponential(p)
+                }
+                return num.toPrecision(p)
               }
+              const arg = args[argIndex]
+              formatted =
+                arg instanceof $.Complex
+                  ? formatComplex(arg, formatPart)
+                  : formatPart(Number(arg))
 
               if (width) {
                 const w = parseInt(width)
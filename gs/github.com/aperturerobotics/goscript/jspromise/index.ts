@@ -0,0 +1 @@
+export { Await } from './jspromise.js'
@@ -0,0 +1,19 @@
+import * as $ from '@goscript/builtin/index.js'
+import * as js from '@goscript/syscall/js/index.js'
+
+// Await waits for the promise p to settle. It returns the value of a
+// fulfilled promise, or a js.Error holding the reason of a rejected one.
+// Values which are not promises or other thenables are returned as they are.
+//
+// The Go implementation blocks on a channel until a callback passed to
+// p.then is called, here the promise is awaited directly.
+export async function Await(p: js.Value): Promise<[js.Value, $.GoError]> {
+  try {
+    return [js.wrap(await p.ref), null]
+  } catch (err) {
+    return [
+      new js.Value(),
+      $.markAsStructValue(new js.Error({ Value: js.wrap(err) })),
+    ]
+  }
+}
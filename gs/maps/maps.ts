@@ -46,7 +46,7 @@ export function clone<K extends $.Comparable, V>(
   if (m == null) {
     return null
   }
-  const result = $.makeMap<K, V>()
+  const result = $.makeMapLike<K, V>(m)
   for (const [k, v] of m.entries()) {
     $.mapSet(result, k, v)
   }
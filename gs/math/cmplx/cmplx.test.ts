@@ -0,0 +1,75 @@
+import { describe, it, expect } from 'vitest'
+import * as $ from '@goscript/builtin/index.js'
+import {
+  Abs,
+  Acos,
+  Asin,
+  Atan,
+  Conj,
+  Cos,
+  Exp,
+  Inf,
+  IsInf,
+  IsNaN,
+  Log,
+  NaN,
+  Phase,
+  Polar,
+  Pow,
+  Rect,
+  Sin,
+  Sqrt,
+  Tan,
+} from './cmplx.js'
+
+function expectClose(actual: $.Complex, real: number, imag: number): void {
+  expect(actual.real).toBeCloseTo(real, 12)
+  expect(actual.imag).toBeCloseTo(imag, 12)
+}
+
+describe('math/cmplx', () => {
+  it('computes the modulus and phase', () => {
+    expect(Abs($.complex(3, 4))).toBe(5)
+    expect(Phase($.complex(0, 1))).toBeCloseTo(Math.PI / 2, 15)
+    const [r, θ] = Polar($.complex(-2, 0))
+    expect(r).toBe(2)
+    expect(θ).toBeCloseTo(Math.PI, 15)
+    expectClose(Rect(2, Math.PI / 2), 0, 2)
+  })
+
+  it('computes the conjugate', () => {
+    expect(Conj($.complex(1, 2)).toString()).toBe('(1-2i)')
+  })
+
+  it('computes square roots', () => {
+    expect(Sqrt($.complex(-4, 0)).toString()).toBe('(0+2i)')
+    expect(Sqrt($.complex(-4, -0)).toString()).toBe('(0-2i)')
+    expectClose(Sqrt($.complex(3, 4)), 2, 1)
+    expectClose(Sqrt($.complex(0, 2)), 1, 1)
+  })
+
+  it('computes exponentials and logarithms', () => {
+    expectClose(Exp($.complex(0, Math.PI)), -1, 0)
+    expectClose(Log($.complex(-1, 0)), 0, Math.PI)
+    expectClose(Pow($.complex(0, 1), $.complex(2, 0)), -1, 0)
+    expect(Pow($.complex(0, 0), $.complex(0, 0)).toString()).toBe('(1+0i)')
+    expect(Pow($.complex(0, 0), $.complex(-1, 0)).toString()).toBe('(+Inf+0i)')
+  })
+
+  it('computes trigonometric functions', () => {
+    expectClose(Sin($.complex(1, 2)), 3.165778513216168, 1.959601041421606)
+    expectClose(Cos($.complex(1, 2)), 2.0327230070196656, -3.0518977991518)
+    expectClose(Tan($.complex(1, 2)), 0.0338128260798967, 1.0147936161466335)
+    expectClose(Asin(Sin($.complex(0.5, 0.25))), 0.5, 0.25)
+    expectClose(Acos(Cos($.complex(0.5, 0.25))), 0.5, 0.25)
+    expectClose(Atan(Tan($.complex(0.5, 0.25))), 0.5, 0.25)
+  })
+
+  it('classifies infinities and NaN', () => {
+    expect(IsInf(Inf())).toBe(true)
+    expect(IsInf($.complex(1, -Infinity))).toBe(true)
+    expect(IsNaN(NaN())).toBe(true)
+    expect(IsNaN($.complex(Infinity, Number.NaN))).toBe(false)
+    expect(IsNaN($.complex(1, 2))).toBe(false)
+  })
+})
@@ -0,0 +1,267 @@
+import * as $ from '@goscript/builtin/index.js'
+
+// Abs returns the absolute value (also called the modulus) of x.
+export function Abs(x: $.Complex): number {
+  return Math.hypot(x.real, x.imag)
+}
+
+// Phase returns the phase (also called the argument) of x.
+// The returned value is in the range [-Pi, Pi].
+export function Phase(x: $.Complex): number {
+  return Math.atan2(x.imag, x.real)
+}
+
+// Polar returns the absolute value r and phase θ of x,
+// such that x = r * e**θi.
+export function Polar(x: $.Complex): [number, number] {
+  return [Abs(x), Phase(x)]
+}
+
+// Rect returns the complex number x with polar coordinates r, θ.
+export function Rect(r: number, θ: number): $.Complex {
+  return $.complex(r * Math.cos(θ), r * Math.sin(θ))
+}
+
+// Conj returns the complex conjugate of x.
+export function Conj(x: $.Complex): $.Complex {
+  return $.complex(x.real, -x.imag)
+}
+
+// Inf returns a complex infinity, complex(+Inf, +Inf).
+export function Inf(): $.Complex {
+  return $.complex(Infinity, Infinity)
+}
+
+// NaN returns a complex “not-a-number” value.
+export function NaN(): $.Complex {
+  return $.complex(Number.NaN, Number.NaN)
+}
+
+// IsInf reports whether either real(x) or imag(x) is an infinity.
+export function IsInf(x: $.Complex): boolean {
+  return isInf(x.real) || isInf(x.imag)
+}
+
+// IsNaN reports whether either real(x) or imag(x) is NaN
+// and neither is an infinity.
+export function IsNaN(x: $.Complex): boolean {
+  if (IsInf(x)) {
+    return false
+  }
+  return Number.isNaN(x.real) || Number.isNaN(x.imag)
+}
+
+// Sqrt returns the square root of x.
+// The result r is chosen so that real(r) ≥ 0 and imag(r) has the same sign as imag(x).
+export function Sqrt(x: $.Complex): $.Complex {
+  if (x.imag === 0) {
+    // Ensure that imag(r) has the same sign as imag(x) for imag(x) == signed zero.
+    if (x.real === 0) {
+      return $.complex(0, x.imag)
+    }
+    if (x.real < 0) {
+      return $.complex(0, copysign(Math.sqrt(-x.real), x.imag))
+    }
+    return $.complex(Math.sqrt(x.real), x.imag)
+  } else if (isInf(x.imag)) {
+    return $.complex(Infinity, x.imag)
+  }
+  if (x.real === 0) {
+    if (x.imag < 0) {
+      const r = Math.sqrt(-0.5 * x.imag)
+      return $.complex(r, -r)
+    }
+    const r = Math.sqrt(0.5 * x.imag)
+    return $.complex(r, r)
+  }
+  let a = x.real
+  let b = x.imag
+  let scale: number
+  // Rescale to avoid internal overflow or underflow.
+  if (Math.abs(a) > 4 || Math.abs(b) > 4) {
+    a *= 0.25
+    b *= 0.25
+    scale = 2
+  } else {
+    a *= 1.8014398509481984e16 // 2**54
+    b *= 1.8014398509481984e16
+    scale = 7.450580596923828125e-9 // 2**-27
+  }
+  let r = Math.hypot(a, b)
+  let t: number
+  if (a > 0) {
+    t = Math.sqrt(0.5 * r + 0.5 * a)
+    r = scale * Math.abs((0.5 * b) / t)
+    t *= scale
+  } else {
+    r = Math.sqrt(0.5 * r - 0.5 * a)
+    t = scale * Math.abs((0.5 * b) / r)
+    r *= scale
+  }
+  if (b < 0) {
+    return $.complex(t, -r)
+  }
+  return $.complex(t, r)
+}
+
+// Exp returns e**x, the base-e exponential of x.
+export function Exp(x: $.Complex): $.Complex {
+  const r = Math.exp(x.real)
+  if (x.imag === 0) {
+    return $.complex(r, x.imag)
+  }
+  return $.complex(r * Math.cos(x.imag), r * Math.sin(x.imag))
+}
+
+// Log returns the natural logarithm of x.
+export function Log(x: $.Complex): $.Complex {
+  return $.complex(Math.log(Abs(x)), Phase(x))
+}
+
+// Log10 returns the decimal logarithm of x.
+export function Log10(x: $.Complex): $.Complex {
+  const z = Log(x)
+  return $.complex(Math.LOG10E * z.real, Math.LOG10E * z.imag)
+}
+
+// Pow returns x**y, the base-x exponential of y.
+// For generalized compatibility with math.Pow:
+//
+//	Pow(0, ±0) returns 1+0i
+//	Pow(0, c) for real(c)<0 returns Inf+0i if imag(c) is zero, otherwise Inf+Inf i.
+export function Pow(x: $.Complex, y: $.Complex): $.Complex {
+  if (x.real === 0 && x.imag === 0) {
+    if (IsNaN(y)) {
+      return NaN()
+    }
+    if (y.real === 0) {
+      return $.complex(1, 0)
+    }
+    if (y.real < 0) {
+      if (y.imag === 0) {
+        return $.complex(Infinity, 0)
+      }
+      return Inf()
+    }
+    return $.complex(0, 0)
+  }
+  const modulus = Abs(x)
+  let r = Math.pow(modulus, y.real)
+  const arg = Phase(x)
+  let theta = y.real * arg
+  if (y.imag !== 0) {
+    r *= Math.exp(-y.imag * arg)
+    theta += y.imag * Math.log(modulus)
+  }
+  return $.complex(r * Math.cos(theta), r * Math.sin(theta))
+}
+
+// Sin returns the sine of x.
+export function Sin(x: $.Complex): $.Complex {
+  return $.complex(
+    Math.sin(x.real) * Math.cosh(x.imag),
+    Math.cos(x.real) * Math.sinh(x.imag),
+  )
+}
+
+// Cos returns the cosine of x.
+export function Cos(x: $.Complex): $.Complex {
+  return $.complex(
+    Math.cos(x.real) * Math.cosh(x.imag),
+    -Math.sin(x.real) * Math.sinh(x.imag),
+  )
+}
+
+// Tan returns the tangent of x.
+export function Tan(x: $.Complex): $.Complex {
+  const d = Math.cos(2 * x.real) + Math.cosh(2 * x.imag)
+  return $.complex(Math.sin(2 * x.real) / d, Math.sinh(2 * x.imag) / d)
+}
+
+// Cot returns the cotangent of x.
+export function Cot(x: $.Complex): $.Complex {
+  const d = Math.cosh(2 * x.imag) - Math.cos(2 * x.real)
+  return $.complex(Math.sin(2 * x.real) / d, -Math.sinh(2 * x.imag) / d)
+}
+
+// Sinh returns the hyperbolic sine of x.
+export function Sinh(x: $.Complex): $.Complex {
+  return $.complex(
+    Math.cos(x.imag) * Math.sinh(x.real),
+    Math.sin(x.imag) * Math.cosh(x.real),
+  )
+}
+
+// Cosh returns the hyperbolic cosine of x.
+export function Cosh(x: $.Complex): $.Complex {
+  return $.complex(
+    Math.cos(x.imag) * Math.cosh(x.real),
+    Math.sin(x.imag) * Math.sinh(x.real),
+  )
+}
+
+// Tanh returns the hyperbolic tangent of x.
+export function Tanh(x: $.Complex): $.Complex {
+  const d = Math.cosh(2 * x.real) + Math.cos(2 * x.imag)
+  return $.complex(Math.sinh(2 * x.real) / d, Math.sin(2 * x.imag) / d)
+}
+
+// Asin returns the inverse sine of x.
+export function Asin(x: $.Complex): $.Complex {
+  const ct = $.complex(-x.imag, x.real) // i * x
+  const xx = $.complexMul(x, x)
+  const x1 = $.complex(1 - xx.real, -xx.imag) // 1 - x*x
+  const x2 = Sqrt(x1) // x2 = sqrt(1 - x*x)
+  const w = Log($.complexAdd(ct, x2))
+  return $.complex(w.imag, -w.real) // -i * w
+}
+
+// Asinh returns the inverse hyperbolic sine of x.
+export function Asinh(x: $.Complex): $.Complex {
+  const xx = $.complexMul(x, x)
+  const x1 = $.complex(1 + xx.real, xx.imag) // 1 + x*x
+  return Log($.complexAdd(x, Sqrt(x1))) // log(x + sqrt(1 + x*x))
+}
+
+// Acos returns the inverse cosine of x.
+export function Acos(x: $.Complex): $.Complex {
+  const w = Asin(x)
+  return $.complex(Math.PI / 2 - w.real, -w.imag)
+}
+
+// Acosh returns the inverse hyperbolic cosine of x.
+export function Acosh(x: $.Complex): $.Complex {
+  if (x.real === 0 && x.imag === 0) {
+    return $.complex(0, copysign(Math.PI / 2, x.imag))
+  }
+  const w = Acos(x)
+  if (w.imag <= 0) {
+    return $.complex(-w.imag, w.real) // i * w
+  }
+  return $.complex(w.imag, -w.real) // -i * w
+}
+
+// Atan returns the inverse tangent of x.
+export function Atan(x: $.Complex): $.Complex {
+  // atan(x) = i/2 * (log(1 - i*x) - log(1 + i*x))
+  const ix = $.complex(-x.imag, x.real)
+  const a = Log($.complex(1 - ix.real, -ix.imag))
+  const b = Log($.complex(1 + ix.real, ix.imag))
+  const d = $.complexSub(a, b)
+  return $.complex(-d.imag / 2, d.real / 2)
+}
+
+// Atanh returns the inverse hyperbolic tangent of x.
+export function Atanh(x: $.Complex): $.Complex {
+  const z = Atan($.complex(-x.imag, x.real)) // z = atan(i * x)
+  return $.complex(z.imag, -z.real) // -i * z
+}
+
+function isInf(x: number): boolean {
+  return x === Infinity || x === -Infinity
+}
+
+function copysign(x: number, sign: number): number {
+  const negative = sign < 0 || Object.is(sign, -0)
+  return negative ? -Math.abs(x) : Math.abs(x)
+}
@@ -5,14 +5,19 @@ import * as runtime from "@goscript/runtime/index.js"
 
 import * as syscall from "@goscript/syscall/index.js"
 
-export let Args: $.Slice<string> = null
+export let Args: $.Slice<string> = runtime_args()
 
 export function init(): void {
-	// In JavaScript environment, just initialize with empty args
 	Args = runtime_args()
 }
 
 export function runtime_args(): $.Slice<string> {
+	// process.argv starts with the path of the JavaScript runtime, drop it so
+	// that Args[0] is the program.
+	if (typeof process !== 'undefined' && process.argv) {
+		return $.arrayToSlice<string>(process.argv.slice(1))
+	}
+	// In the browser there are no arguments
 	return $.arrayToSlice<string>([])
 }
 
@@ -1,3 +1,5 @@
+import * as $ from '@goscript/builtin/index.js'
+
 // Runtime constants for the JavaScript/WebAssembly target
 export const GOOS = 'js'
 export const GOARCH = 'wasm'
@@ -48,23 +50,8 @@ export function Gosched(): Promise<void> {
 }
 
 // NumGoroutine returns the number of goroutines that currently exist.
-// In goscript, this is informational only
-let goroutineCount = 1 // Start with main goroutine
-
 export function NumGoroutine(): number {
-  return goroutineCount
-}
-
-// Internal function to track goroutine creation (called by goscript runtime)
-export function _incrementGoroutineCount(): void {
-  goroutineCount++
-}
-
-// Internal function to track goroutine completion (called by goscript runtime)
-export function _decrementGoroutineCount(): void {
-  if (goroutineCount > 0) {
-    goroutineCount--
-  }
+  return $.numGoroutine()
 }
 
 // Caller returns details about the calling goroutine's stack.
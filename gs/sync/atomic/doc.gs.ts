@@ -2,7 +2,11 @@ import * as $ from "@goscript/builtin/index.js";
 
 import * as unsafe from "@goscript/unsafe/index.js"
 
-// Type alias for uintptr (pointer-sized unsigned integer)
+import { wrap64 } from "./doc_64.gs.js";
+
+// Type alias for uintptr (pointer-sized unsigned integer). The Uintptr
+// functions also take the bigint values of uintptr variables compiled with
+// --int64-bigint, like the 64-bit functions.
 export type uintptr = number;
 export type Pointer = any;
 
@@ -32,7 +36,9 @@ export function SwapUint32(addr: $.VarRef<number> | null, _new: number): number
 // Consider using the more ergonomic and less error-prone [Uintptr.Swap] instead.
 //
 //go:noescape
-export function SwapUintptr(addr: $.VarRef<uintptr> | null, _new: uintptr): uintptr {
+export function SwapUintptr(addr: $.VarRef<uintptr> | null, _new: uintptr): uintptr;
+export function SwapUintptr(addr: $.VarRef<bigint> | null, _new: bigint): bigint;
+export function SwapUintptr(addr: $.VarRef<any> | null, _new: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = _new;
@@ -78,7 +84,9 @@ export function CompareAndSwapUint32(addr: $.VarRef<number> | null, old: number,
 // Consider using the more ergonomic and less error-prone [Uintptr.CompareAndSwap] instead.
 //
 //go:noescape
-export function CompareAndSwapUintptr(addr: $.VarRef<uintptr> | null, old: uintptr, _new: uintptr): boolean {
+export function CompareAndSwapUintptr(addr: $.VarRef<uintptr> | null, old: uintptr, _new: uintptr): boolean;
+export function CompareAndSwapUintptr(addr: $.VarRef<bigint> | null, old: bigint, _new: bigint): boolean;
+export function CompareAndSwapUintptr(addr: $.VarRef<any> | null, old: any, _new: any): boolean {
 	if (!addr) return false;
 	if (addr.value === old) {
 		addr.value = _new;
@@ -124,9 +132,11 @@ export function AddUint32(addr: $.VarRef<number> | null, delta: number): number
 // Consider using the more ergonomic and less error-prone [Uintptr.Add] instead.
 //
 //go:noescape
-export function AddUintptr(addr: $.VarRef<uintptr> | null, delta: uintptr): uintptr {
+export function AddUintptr(addr: $.VarRef<uintptr> | null, delta: uintptr): uintptr;
+export function AddUintptr(addr: $.VarRef<bigint> | null, delta: bigint): bigint;
+export function AddUintptr(addr: $.VarRef<any> | null, delta: any): any {
 	if (!addr) return 0;
-	addr.value = (addr.value + delta) >>> 0; // Use unsigned right shift for uintptr
+	addr.value = typeof addr.value === 'bigint' ? wrap64(addr.value + delta, true) : (addr.value + delta) >>> 0; // Use unsigned right shift for uintptr
 	return addr.value;
 }
 
@@ -159,10 +169,12 @@ export function AndUint32(addr: $.VarRef<number> | null, mask: number): number {
 // Consider using the more ergonomic and less error-prone [Uintptr.And] instead.
 //
 //go:noescape
-export function AndUintptr(addr: $.VarRef<uintptr> | null, mask: uintptr): uintptr {
+export function AndUintptr(addr: $.VarRef<uintptr> | null, mask: uintptr): uintptr;
+export function AndUintptr(addr: $.VarRef<bigint> | null, mask: bigint): bigint;
+export function AndUintptr(addr: $.VarRef<any> | null, mask: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
-	addr.value = (addr.value & mask) >>> 0; // Use unsigned right shift for uintptr
+	addr.value = typeof addr.value === 'bigint' ? addr.value & mask : (addr.value & mask) >>> 0; // Use unsigned right shift for uintptr
 	return old;
 }
 
@@ -195,10 +207,12 @@ export function OrUint32(addr: $.VarRef<number> | null, mask: number): number {
 // Consider using the more ergonomic and less error-prone [Uintptr.Or] instead.
 //
 //go:noescape
-export function OrUintptr(addr: $.VarRef<uintptr> | null, mask: uintptr): uintptr {
+export function OrUintptr(addr: $.VarRef<uintptr> | null, mask: uintptr): uintptr;
+export function OrUintptr(addr: $.VarRef<bigint> | null, mask: bigint): bigint;
+export function OrUintptr(addr: $.VarRef<any> | null, mask: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
-	addr.value = (addr.value | mask) >>> 0; // Use unsigned right shift for uintptr
+	addr.value = typeof addr.value === 'bigint' ? addr.value | mask : (addr.value | mask) >>> 0; // Use unsigned right shift for uintptr
 	return old;
 }
 
@@ -224,7 +238,9 @@ export function LoadUint32(addr: $.VarRef<number> | null): number {
 // Consider using the more ergonomic and less error-prone [Uintptr.Load] instead.
 //
 //go:noescape
-export function LoadUintptr(addr: $.VarRef<uintptr> | null): uintptr {
+export function LoadUintptr(addr: $.VarRef<uintptr> | null): uintptr;
+export function LoadUintptr(addr: $.VarRef<bigint> | null): bigint;
+export function LoadUintptr(addr: $.VarRef<any> | null): any {
 	if (!addr) return 0;
 	return addr.value;
 }
@@ -260,9 +276,11 @@ export function StoreUint32(addr: $.VarRef<number> | null, val: number): void {
 // Consider using the more ergonomic and less error-prone [Uintptr.Store] instead.
 //
 //go:noescape
-export function StoreUintptr(addr: $.VarRef<uintptr> | null, val: uintptr): void {
+export function StoreUintptr(addr: $.VarRef<uintptr> | null, val: uintptr): void;
+export function StoreUintptr(addr: $.VarRef<bigint> | null, val: bigint): void;
+export function StoreUintptr(addr: $.VarRef<any> | null, val: any): void {
 	if (addr) {
-		addr.value = val >>> 0; // Use unsigned right shift for uintptr
+		addr.value = typeof val === 'bigint' ? val : val >>> 0; // Use unsigned right shift for uintptr
 	}
 }
 
@@ -1,11 +1,24 @@
 import * as $ from "@goscript/builtin/index.js";
 
+// The 64-bit functions take the values of int64 and uint64 variables as
+// numbers, or as bigints when the code is compiled with --int64-bigint: they
+// are listed under bigintParams in meta.json, so their arguments are passed
+// as they are, and the sums of bigints are wrapped to 64 bits.
+
+// wrap64 wraps the bigint v to a 64-bit integer. Numbers are not wrapped.
+export function wrap64(v: number | bigint, unsigned: boolean): number | bigint {
+	if (typeof v !== 'bigint') return v;
+	return unsigned ? BigInt.asUintN(64, v) : BigInt.asIntN(64, v);
+}
+
 // SwapInt64 atomically stores new into *addr and returns the previous *addr value.
 // Consider using the more ergonomic and less error-prone [Int64.Swap] instead
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function SwapInt64(addr: $.VarRef<number> | null, _new: number): number {
+export function SwapInt64(addr: $.VarRef<number> | null, _new: number): number;
+export function SwapInt64(addr: $.VarRef<bigint> | null, _new: bigint): bigint;
+export function SwapInt64(addr: $.VarRef<any> | null, _new: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = _new;
@@ -17,7 +30,9 @@ export function SwapInt64(addr: $.VarRef<number> | null, _new: number): number {
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function SwapUint64(addr: $.VarRef<number> | null, _new: number): number {
+export function SwapUint64(addr: $.VarRef<number> | null, _new: number): number;
+export function SwapUint64(addr: $.VarRef<bigint> | null, _new: bigint): bigint;
+export function SwapUint64(addr: $.VarRef<any> | null, _new: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = _new;
@@ -29,7 +44,9 @@ export function SwapUint64(addr: $.VarRef<number> | null, _new: number): number
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function CompareAndSwapInt64(addr: $.VarRef<number> | null, old: number, _new: number): boolean {
+export function CompareAndSwapInt64(addr: $.VarRef<number> | null, old: number, _new: number): boolean;
+export function CompareAndSwapInt64(addr: $.VarRef<bigint> | null, old: bigint, _new: bigint): boolean;
+export function CompareAndSwapInt64(addr: $.VarRef<any> | null, old: any, _new: any): boolean {
 	if (!addr) return false;
 	if (addr.value === old) {
 		addr.value = _new;
@@ -43,7 +60,9 @@ export function CompareAndSwapInt64(addr: $.VarRef<number> | null, old: number,
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function CompareAndSwapUint64(addr: $.VarRef<number> | null, old: number, _new: number): boolean {
+export function CompareAndSwapUint64(addr: $.VarRef<number> | null, old: number, _new: number): boolean;
+export function CompareAndSwapUint64(addr: $.VarRef<bigint> | null, old: bigint, _new: bigint): boolean;
+export function CompareAndSwapUint64(addr: $.VarRef<any> | null, old: any, _new: any): boolean {
 	if (!addr) return false;
 	if (addr.value === old) {
 		addr.value = _new;
@@ -57,9 +76,11 @@ export function CompareAndSwapUint64(addr: $.VarRef<number> | null, old: number,
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function AddInt64(addr: $.VarRef<number> | null, delta: number): number {
+export function AddInt64(addr: $.VarRef<number> | null, delta: number): number;
+export function AddInt64(addr: $.VarRef<bigint> | null, delta: bigint): bigint;
+export function AddInt64(addr: $.VarRef<any> | null, delta: any): any {
 	if (!addr) return 0;
-	addr.value = addr.value + delta;
+	addr.value = wrap64(addr.value + delta, false);
 	return addr.value;
 }
 
@@ -70,9 +91,11 @@ export function AddInt64(addr: $.VarRef<number> | null, delta: number): number {
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function AddUint64(addr: $.VarRef<number> | null, delta: number): number {
+export function AddUint64(addr: $.VarRef<number> | null, delta: number): number;
+export function AddUint64(addr: $.VarRef<bigint> | null, delta: bigint): bigint;
+export function AddUint64(addr: $.VarRef<any> | null, delta: any): any {
 	if (!addr) return 0;
-	addr.value = addr.value + delta;
+	addr.value = wrap64(addr.value + delta, true);
 	return addr.value;
 }
 
@@ -81,7 +104,9 @@ export function AddUint64(addr: $.VarRef<number> | null, delta: number): number
 // Consider using the more ergonomic and less error-prone [Int64.And] instead.
 //
 //go:noescape
-export function AndInt64(addr: $.VarRef<number> | null, mask: number): number {
+export function AndInt64(addr: $.VarRef<number> | null, mask: number): number;
+export function AndInt64(addr: $.VarRef<bigint> | null, mask: bigint): bigint;
+export function AndInt64(addr: $.VarRef<any> | null, mask: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = addr.value & mask;
@@ -93,7 +118,9 @@ export function AndInt64(addr: $.VarRef<number> | null, mask: number): number {
 // Consider using the more ergonomic and less error-prone [Uint64.And] instead.
 //
 //go:noescape
-export function AndUint64(addr: $.VarRef<number> | null, mask: number): number {
+export function AndUint64(addr: $.VarRef<number> | null, mask: number): number;
+export function AndUint64(addr: $.VarRef<bigint> | null, mask: bigint): bigint;
+export function AndUint64(addr: $.VarRef<any> | null, mask: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = addr.value & mask;
@@ -105,7 +132,9 @@ export function AndUint64(addr: $.VarRef<number> | null, mask: number): number {
 // Consider using the more ergonomic and less error-prone [Int64.Or] instead.
 //
 //go:noescape
-export function OrInt64(addr: $.VarRef<number> | null, mask: number): number {
+export function OrInt64(addr: $.VarRef<number> | null, mask: number): number;
+export function OrInt64(addr: $.VarRef<bigint> | null, mask: bigint): bigint;
+export function OrInt64(addr: $.VarRef<any> | null, mask: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = addr.value | mask;
@@ -117,7 +146,9 @@ export function OrInt64(addr: $.VarRef<number> | null, mask: number): number {
 // Consider using the more ergonomic and less error-prone [Uint64.Or] instead.
 //
 //go:noescape
-export function OrUint64(addr: $.VarRef<number> | null, mask: number): number {
+export function OrUint64(addr: $.VarRef<number> | null, mask: number): number;
+export function OrUint64(addr: $.VarRef<bigint> | null, mask: bigint): bigint;
+export function OrUint64(addr: $.VarRef<any> | null, mask: any): any {
 	if (!addr) return 0;
 	let old = addr.value;
 	addr.value = addr.value | mask;
@@ -129,7 +160,9 @@ export function OrUint64(addr: $.VarRef<number> | null, mask: number): number {
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function LoadInt64(addr: $.VarRef<number> | null): number {
+export function LoadInt64(addr: $.VarRef<number> | null): number;
+export function LoadInt64(addr: $.VarRef<bigint> | null): bigint;
+export function LoadInt64(addr: $.VarRef<any> | null): any {
 	if (!addr) return 0;
 	return addr.value;
 }
@@ -139,7 +172,9 @@ export function LoadInt64(addr: $.VarRef<number> | null): number {
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function LoadUint64(addr: $.VarRef<number> | null): number {
+export function LoadUint64(addr: $.VarRef<number> | null): number;
+export function LoadUint64(addr: $.VarRef<bigint> | null): bigint;
+export function LoadUint64(addr: $.VarRef<any> | null): any {
 	if (!addr) return 0;
 	return addr.value;
 }
@@ -149,7 +184,9 @@ export function LoadUint64(addr: $.VarRef<number> | null): number {
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function StoreInt64(addr: $.VarRef<number> | null, val: number): void {
+export function StoreInt64(addr: $.VarRef<number> | null, val: number): void;
+export function StoreInt64(addr: $.VarRef<bigint> | null, val: bigint): void;
+export function StoreInt64(addr: $.VarRef<any> | null, val: any): void {
 	if (addr) {
 		addr.value = val;
 	}
@@ -160,7 +197,9 @@ export function StoreInt64(addr: $.VarRef<number> | null, val: number): void {
 // (particularly if you target 32-bit platforms; see the bugs section).
 //
 //go:noescape
-export function StoreUint64(addr: $.VarRef<number> | null, val: number): void {
+export function StoreUint64(addr: $.VarRef<number> | null, val: number): void;
+export function StoreUint64(addr: $.VarRef<bigint> | null, val: bigint): void;
+export function StoreUint64(addr: $.VarRef<any> | null, val: any): void {
 	if (addr) {
 		addr.value = val;
 	}
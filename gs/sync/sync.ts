@@ -3,6 +3,8 @@
 // low-level library routines. Higher-level synchronization is better done via
 // channels and communication.
 
+import * as $ from '@goscript/builtin/index.js'
+
 // Locker represents an object that can be locked and unlocked
 export interface Locker {
   Lock(): Promise<void>
@@ -26,10 +28,13 @@ export class Mutex implements Locker {
       return
     }
 
-    // In a real implementation, this would block the goroutine
-    // Use Promise to simulate blocking behavior like channels
+    // Park the goroutine until Unlock hands the mutex over
+    const g = $.park('sync.Mutex.Lock')
     return new Promise<void>((resolve) => {
-      this._waitQueue.push(resolve)
+      this._waitQueue.push(() => {
+        g.ready()
+        resolve()
+      })
     })
   }
 
@@ -83,8 +88,12 @@ export class RWMutex {
       return
     }
 
+    const g = $.park('sync.RWMutex.Lock')
     return new Promise<void>((resolve) => {
-      this._writerWaitQueue.push(resolve)
+      this._writerWaitQueue.push(() => {
+        g.ready()
+        resolve()
+      })
     })
   }
 
@@ -114,9 +123,11 @@ export class RWMutex {
       return
     }
 
+    const g = $.park('sync.RWMutex.RLock')
     return new Promise<void>((resolve) => {
       this._readerWaitQueue.push(() => {
         this._readers++
+        g.ready()
         resolve()
       })
     })
@@ -199,8 +210,12 @@ export class WaitGroup {
       return
     }
 
+    const g = $.park('sync.WaitGroup.Wait')
     return new Promise<void>((resolve) => {
-      this._waiters.push(resolve)
+      this._waiters.push(() => {
+        g.ready()
+        resolve()
+      })
     })
   }
 
@@ -271,8 +286,11 @@ export class Cond {
   public async Wait(): Promise<void> {
     this._l.Unlock()
 
+    const g = $.park('sync.Cond.Wait')
     return new Promise<void>((resolve) => {
       this._waiters.push(async () => {
+        // Locking parks the goroutine again if c.L is held
+        g.ready()
         await this._l.Lock()
         resolve()
       })
@@ -293,7 +311,10 @@ export function NewCond(l: Locker): Cond {
 // Map is like a Go map[interface{}]interface{} but is safe for concurrent use by multiple goroutines
 export class Map {
   private _m: RWMutex = new RWMutex()
-  private _data: globalThis.Map<any, any> = new globalThis.Map()
+  // Keys are compared like Go interface values.
+  private _data: globalThis.Map<any, any> = new $.HashMap<any, any>(
+    'interface',
+  )
 
   constructor(_init?: Partial<{}>) {
     // Map has no public fields to initialize
@@ -0,0 +1 @@
+export * from './js.js'
@@ -0,0 +1,52 @@
+import { describe, it, expect } from 'vitest'
+import * as $ from '@goscript/builtin/index.js'
+import * as js from './js.js'
+
+describe('syscall/js', () => {
+  it('accesses properties and calls methods', () => {
+    const obj = js.ValueOf(new Map<string, any>([['a', 1]]))
+    obj.Set('b', $.arrayToSlice<any>(['x', true, null]))
+    expect(obj.Get('a').Int()).toBe(1)
+    expect(obj.Get('b').Length()).toBe(3)
+    expect(obj.Get('b').Index(0).String()).toBe('x')
+    expect(js.Global().Get('JSON').Call('stringify', obj).String()).toBe(
+      '{"a":1,"b":["x",true,null]}',
+    )
+    obj.Delete('a')
+    expect(obj.Get('a').IsUndefined()).toBe(true)
+  })
+
+  it('reports types like Go', () => {
+    expect(new js.Value().Type()).toBe(js.TypeUndefined)
+    expect(js.Null().Type()).toBe(js.TypeNull)
+    expect(js.ValueOf(1.5).String()).toBe('<number: 1.5>')
+    expect(js.Type_String(js.ValueOf('s').Type())).toBe('string')
+    expect(() => js.ValueOf('s').Int()).toThrow()
+    expect(() => js.ValueOf(() => {})).toThrow()
+    expect(
+      new js.ValueError({ Method: 'Value.Int', Type: js.TypeString }).Error(),
+    ).toBe('syscall/js: call of Value.Int on string')
+  })
+
+  it('calls Go functions from JavaScript', async () => {
+    const double = js.FuncOf((_this, args) => args![0].Int() * 2)
+    expect(double.Invoke(21).Int()).toBe(42)
+    expect(double.ref(4)).toBe(8)
+    double.Release()
+
+    const later = js.FuncOf(async (_this, args) => {
+      await Promise.resolve()
+      return args![0]
+    })
+    expect(await later.ref('done')).toBe('done')
+    later.Release()
+  })
+
+  it('copies bytes', () => {
+    const u8 = js.Global().Get('Uint8Array').New(2)
+    expect(js.CopyBytesToJS(u8, new Uint8Array([1, 2, 3]))).toBe(2)
+    const dst = new Uint8Array(4)
+    expect(js.CopyBytesToGo(dst, u8)).toBe(2)
+    expect(Array.from(dst)).toEqual([1, 2, 0, 0])
+  })
+})
@@ -0,0 +1,488 @@
+import * as $ from '@goscript/builtin/index.js'
+
+// Package js gives access to the JavaScript host environment, like
+// syscall/js does under GOOS=js GOARCH=wasm.
+//
+// A Value holds the JavaScript value itself in ref. The compiler lowers most
+// calls of Value methods to direct JavaScript property accesses and calls on
+// ref, using wrap and unwrap to convert the results and the arguments, so the
+// methods below mostly run for method values and calls from other packages.
+
+// Type represents the JavaScript type of a Value.
+export type Type = number
+
+export const TypeUndefined: Type = 0
+export const TypeNull: Type = 1
+export const TypeBoolean: Type = 2
+export const TypeNumber: Type = 3
+export const TypeString: Type = 4
+export const TypeSymbol: Type = 5
+export const TypeObject: Type = 6
+export const TypeFunction: Type = 7
+
+const typeNames = [
+  'undefined',
+  'null',
+  'boolean',
+  'number',
+  'string',
+  'symbol',
+  'object',
+  'function',
+]
+
+export function Type_String(t: Type): string {
+  if (t < 0 || t >= typeNames.length) {
+    $.panic('bad type')
+  }
+  return typeNames[t]
+}
+
+// typeOf returns the Type of the JavaScript value ref.
+function typeOf(ref: any): Type {
+  switch (typeof ref) {
+    case 'undefined':
+      return TypeUndefined
+    case 'boolean':
+      return TypeBoolean
+    case 'number':
+    case 'bigint':
+      return TypeNumber
+    case 'string':
+      return TypeString
+    case 'symbol':
+      return TypeSymbol
+    case 'function':
+      return TypeFunction
+    default:
+      return ref === null ? TypeNull : TypeObject
+  }
+}
+
+// Value represents a JavaScript value. The zero value is the JavaScript
+// value "undefined".
+export class Value {
+  // ref is the JavaScript value.
+  public ref: any = undefined
+
+  constructor(_init?: Partial<{}>) {}
+
+  public clone(): Value {
+    return wrap(this.ref)
+  }
+
+  // Bool returns the value v as a bool.
+  // It panics if v is not a JavaScript boolean.
+  public Bool(): boolean {
+    this.expect(TypeBoolean, 'Value.Bool')
+    return this.ref
+  }
+
+  // Call does a JavaScript call to the method m of value v with the given
+  // arguments.
+  public Call(m: string, ...args: any[]): Value {
+    const fn = this.ref[m]
+    if (typeof fn !== 'function') {
+      $.panic('syscall/js: Value.Call: property ' + m + ' is not a function')
+    }
+    return wrap(fn.apply(this.ref, args.map(unwrap)))
+  }
+
+  // Delete deletes the JavaScript property p of value v.
+  public Delete(p: string): void {
+    this.expectObject('Value.Delete')
+    delete this.ref[p]
+  }
+
+  // Equal reports whether v and w are equal according to JavaScript's ===
+  // operator.
+  public Equal(w: Value): boolean {
+    return this.ref === w.ref
+  }
+
+  // Float returns the value v as a float64.
+  // It panics if v is not a JavaScript number.
+  public Float(): number {
+    this.expect(TypeNumber, 'Value.Float')
+    return Number(this.ref)
+  }
+
+  // Get returns the JavaScript property p of value v.
+  public Get(p: string): Value {
+    this.expectObject('Value.Get')
+    return wrap(this.ref[p])
+  }
+
+  // Index returns JavaScript index i of value v.
+  public Index(i: number): Value {
+    this.expectObject('Value.Index')
+    return wrap(this.ref[i])
+  }
+
+  // InstanceOf reports whether v is an instance of type t according to
+  // JavaScript's instanceof operator.
+  public InstanceOf(t: Value): boolean {
+    return this.ref instanceof t.ref
+  }
+
+  // Int returns the value v truncated to an int.
+  // It panics if v is not a JavaScript number.
+  public Int(): number {
+    this.expect(TypeNumber, 'Value.Int')
+    return Math.trunc(Number(this.ref))
+  }
+
+  // Invoke does a JavaScript call of the value v with the given arguments.
+  public Invoke(...args: any[]): Value {
+    this.expect(TypeFunction, 'Value.Invoke')
+    return wrap(this.ref(...args.map(unwrap)))
+  }
+
+  public IsNaN(): boolean {
+    return Number.isNaN(this.ref)
+  }
+
+  public IsNull(): boolean {
+    return this.ref === null
+  }
+
+  public IsUndefined(): boolean {
+    return this.ref === undefined
+  }
+
+  // Length returns the JavaScript property "length" of v.
+  public Length(): number {
+    this.expectObject('Value.Length')
+    return this.ref.length
+  }
+
+  // New uses JavaScript's "new" operator with value v as constructor and the
+  // given arguments.
+  public New(...args: any[]): Value {
+    this.expect(TypeFunction, 'Value.New')
+    return wrap(new this.ref(...args.map(unwrap)))
+  }
+
+  // Set sets the JavaScript property p of value v to ValueOf(x).
+  public Set(p: string, x: any): void {
+    this.expectObject('Value.Set')
+    this.ref[p] = unwrap(x)
+  }
+
+  // SetIndex sets the JavaScript index i of value v to ValueOf(x).
+  public SetIndex(i: number, x: any): void {
+    this.expectObject('Value.SetIndex')
+    this.ref[i] = unwrap(x)
+  }
+
+  // String returns the value v as a string. Unlike the other getters, it
+  // does not panic if v is not a JavaScript string, but returns "<T>" or
+  // "<T: V>" where T is the type of v and V its value.
+  public String(): string {
+    switch (this.Type()) {
+      case TypeString:
+        return this.ref
+      case TypeBoolean:
+      case TypeNumber:
+        return '<' + Type_String(this.Type()) + ': ' + String(this.ref) + '>'
+      default:
+        return '<' + Type_String(this.Type()) + '>'
+    }
+  }
+
+  // Truthy returns the JavaScript "truthiness" of the value v.
+  public Truthy(): boolean {
+    return !!this.ref
+  }
+
+  // Type returns the JavaScript type of the value v, like JavaScript's typeof
+  // operator except that it returns TypeNull for null.
+  public Type(): Type {
+    return typeOf(this.ref)
+  }
+
+  // expect panics with a ValueError if v is not of type t.
+  private expect(t: Type, method: string): void {
+    const vt = this.Type()
+    if (vt !== t) {
+      $.panic(new ValueError({ Method: method, Type: vt }))
+    }
+  }
+
+  // expectObject panics with a ValueError if v has no properties.
+  private expectObject(method: string): void {
+    const vt = this.Type()
+    if (vt !== TypeObject && vt !== TypeFunction) {
+      $.panic(new ValueError({ Method: method, Type: vt }))
+    }
+  }
+
+  // Register this type with the runtime type system
+  static __typeInfo = $.registerStructType(
+    'Value',
+    new Value(),
+    [
+      'Bool',
+      'Call',
+      'Delete',
+      'Equal',
+      'Float',
+      'Get',
+      'Index',
+      'InstanceOf',
+      'Int',
+      'Invoke',
+      'IsNaN',
+      'IsNull',
+      'IsUndefined',
+      'Length',
+      'New',
+      'Set',
+      'SetIndex',
+      'String',
+      'Truthy',
+      'Type',
+    ].map((name) => ({ name, args: [], returns: [] })),
+    Value,
+    {},
+  )
+}
+
+// wrap returns the JavaScript value ref as a Value.
+export function wrap(ref: any): Value {
+  const v = new Value()
+  v.ref = ref
+  return v
+}
+
+// unwrap returns the JavaScript value of x, see ValueOf.
+export function unwrap(x: any): any {
+  if (x instanceof Value) {
+    return x.ref
+  }
+  switch (typeof x) {
+    case 'boolean':
+    case 'number':
+    case 'string':
+      return x
+    case 'bigint':
+      return Number(x)
+    case 'undefined':
+      return null
+  }
+  if (x === null) {
+    return null
+  }
+  if (Array.isArray(x) || $.isSliceProxy(x)) {
+    return $.asArray(x).map(unwrap)
+  }
+  if (x instanceof Map) {
+    const obj: Record<string, any> = {}
+    for (const [k, v] of x) {
+      obj[k] = unwrap(v)
+    }
+    return obj
+  }
+  $.panic('ValueOf: invalid value')
+}
+
+// Global returns the JavaScript global object, usually "window" or "global".
+export function Global(): Value {
+  return wrap(globalThis)
+}
+
+// Null returns the JavaScript value "null".
+export function Null(): Value {
+  return wrap(null)
+}
+
+// Undefined returns the JavaScript value "undefined".
+export function Undefined(): Value {
+  return wrap(undefined)
+}
+
+// ValueOf returns x as a JavaScript value:
+//
+//	| Go                     | JavaScript             |
+//	| ---------------------- | ---------------------- |
+//	| js.Value               | [its value]            |
+//	| js.Func                | function               |
+//	| nil                    | null                   |
+//	| bool                   | boolean                |
+//	| integers and floats    | number                 |
+//	| string                 | string                 |
+//	| []interface{}          | new array              |
+//	| map[string]interface{} | new object             |
+//
+// Panics if x is not one of the expected types.
+export function ValueOf(x: any): Value {
+  if (x instanceof Value) {
+    return wrap(x.ref)
+  }
+  return wrap(unwrap(x))
+}
+
+// Func is a wrapped Go function to be called by JavaScript.
+export class Func extends Value {
+  // Value is the JavaScript function that invokes the Go function.
+  public get Value(): Value {
+    return wrap(this.ref)
+  }
+  public set Value(value: Value) {
+    this.ref = value.ref
+  }
+
+  // release releases the hold on the scheduler, see FuncOf.
+  private release: () => void = () => {}
+
+  constructor(init?: Partial<{ Value?: Value }>) {
+    super()
+    this.ref = init?.Value?.ref
+  }
+
+  public clone(): Func {
+    const cloned = new Func()
+    cloned.ref = this.ref
+    cloned.release = this.release
+    return cloned
+  }
+
+  // Release frees up resources allocated for the function.
+  // The function must not be invoked after calling Release.
+  public Release(): void {
+    this.release()
+  }
+
+  static __typeInfo = $.registerStructType(
+    'Func',
+    new Func(),
+    [{ name: 'Release', args: [], returns: [] }],
+    Func,
+    { Value: 'Value' },
+  )
+}
+
+// FuncOf returns a function to be used by JavaScript.
+//
+// The Go function fn is called with the value of JavaScript's "this" keyword
+// and the arguments of the invocation. The return value of the invocation is
+// the result of the Go function mapped back to JavaScript according to
+// ValueOf. If fn blocks, such as on a channel, the invocation returns a
+// promise of the result instead.
+//
+// Goroutines waiting for fn to be called are not reported as deadlocked until
+// Func.Release is called.
+export function FuncOf(fn: (_this: Value, args: $.Slice<Value>) => any): Func {
+  const f = new Func()
+  f.ref = function (this: any, ...args: any[]): any {
+    const result = fn(wrap(this), $.arrayToSlice(args.map(wrap)))
+    if (result instanceof Promise) {
+      return result.then(unwrap)
+    }
+    return unwrap(result)
+  }
+  ;(f as any).release = $.keepAlive()
+  return f
+}
+
+// Error wraps a JavaScript error.
+export class Error extends Value {
+  // Value is the underlying JavaScript error value.
+  public get Value(): Value {
+    return wrap(this.ref)
+  }
+  public set Value(value: Value) {
+    this.ref = value.ref
+  }
+
+  constructor(init?: Partial<{ Value?: Value }>) {
+    super()
+    this.ref = init?.Value?.ref
+  }
+
+  public clone(): Error {
+    const cloned = new Error()
+    cloned.ref = this.ref
+    return cloned
+  }
+
+  // Error implements the error interface.
+  public Error(): string {
+    return 'JavaScript error: ' + String(this.ref?.message)
+  }
+
+  static __typeInfo = $.registerStructType(
+    'Error',
+    new Error(),
+    [{ name: 'Error', args: [], returns: [] }],
+    Error,
+    { Value: 'Value' },
+  )
+}
+
+// A ValueError occurs when a Value method is invoked on a Value that does not
+// support it.
+export class ValueError {
+  public Method: string
+  public Type: Type
+
+  constructor(init?: Partial<{ Method?: string; Type?: Type }>) {
+    this.Method = init?.Method ?? ''
+    this.Type = init?.Type ?? TypeUndefined
+  }
+
+  public clone(): ValueError {
+    return new ValueError({ Method: this.Method, Type: this.Type })
+  }
+
+  public Error(): string {
+    return (
+      'syscall/js: call of ' + this.Method + ' on ' + Type_String(this.Type)
+    )
+  }
+
+  static __typeInfo = $.registerStructType(
+    'ValueError',
+    new ValueError(),
+    [{ name: 'Error', args: [], returns: [] }],
+    ValueError,
+    { Method: { kind: $.TypeKind.Basic, name: 'string' }, Type: 'Type' },
+  )
+}
+
+// CopyBytesToGo copies bytes from src to dst. It panics if src is not a
+// Uint8Array or Uint8ClampedArray. It returns the number of bytes copied,
+// which will be the minimum of the lengths of src and dst.
+export function CopyBytesToGo(dst: $.Bytes, src: Value): number {
+  if (!isByteArray(src.ref)) {
+    $.panic(
+      'syscall/js: CopyBytesToGo: expected src to be a Uint8Array or Uint8ClampedArray',
+    )
+  }
+  const bytes = new Uint8Array(
+    src.ref.buffer,
+    src.ref.byteOffset,
+    src.ref.length,
+  )
+  return $.copy(dst as Uint8Array, bytes)
+}
+
+// CopyBytesToJS copies bytes from src to dst. It panics if dst is not a
+// Uint8Array or Uint8ClampedArray. It returns the number of bytes copied,
+// which will be the minimum of the lengths of src and dst.
+export function CopyBytesToJS(dst: Value, src: $.Bytes): number {
+  if (!isByteArray(dst.ref)) {
+    $.panic(
+      'syscall/js: CopyBytesToJS: expected dst to be a Uint8Array or Uint8ClampedArray',
+    )
+  }
+  const n = Math.min(dst.ref.length, $.len(src))
+  for (let i = 0; i < n; i++) {
+    dst.ref[i] = (src as Uint8Array)[i]
+  }
+  return n
+}
+
+function isByteArray(ref: any): boolean {
+  return ref instanceof Uint8Array || ref instanceof Uint8ClampedArray
+}
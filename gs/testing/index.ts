@@ -0,0 +1 @@
+export * from './testing.js'
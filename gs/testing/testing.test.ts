@@ -0,0 +1,165 @@
+import { describe, it, expect } from 'vitest'
+import * as testing from './testing.js'
+
+// run runs the tests with the test flags args and returns the exit code and
+// the output of the runner.
+async function run(
+  tests: testing.InternalTest[],
+  args: string[] = [],
+  benchmarks: testing.InternalBenchmark[] = [],
+): Promise<[number, string]> {
+  const proc = process as any
+  const origArgv = proc.argv
+  const origWrite = proc.stdout.write
+  const origExitCode = proc.exitCode
+  let out = ''
+  proc.argv = ['node', 'testmain.ts', ...args]
+  proc.stdout.write = (chunk: any) => {
+    out += String(chunk)
+    return true
+  }
+  try {
+    const m = testing.MainStart('example.com/pkg', tests, benchmarks)
+    const code = await m.Run()
+    return [code, out]
+  } finally {
+    proc.argv = origArgv
+    proc.stdout.write = origWrite
+    proc.exitCode = origExitCode
+  }
+}
+
+describe('testing', () => {
+  it('reports passing tests', async () => {
+    const [code, out] = await run([{ Name: 'TestOk', F: () => {} }])
+    expect(code).toBe(0)
+    expect(out).toBe('PASS\n')
+  })
+
+  it('reports failures with their logs', async () => {
+    const [code, out] = await run([
+      {
+        Name: 'TestBad',
+        F: (t) => {
+          t!.Log('first\nsecond')
+          t!.Errorf('got %d', 2)
+        },
+      },
+    ])
+    expect(code).toBe(1)
+    expect(out).toMatch(
+      /^--- FAIL: TestBad \(\d+\.\d\ds\)\n {4}first\n {8}second\n {4}got 2\nFAIL\n$/,
+    )
+  })
+
+  it('stops the test on Fatal and runs cleanups in reverse order', async () => {
+    const events: string[] = []
+    const [code] = await run([
+      {
+        Name: 'TestFatal',
+        F: (t) => {
+          t!.Cleanup(() => {
+            events.push('cleanup 1')
+          })
+          t!.Cleanup(() => {
+            events.push('cleanup 2')
+          })
+          t!.Fatal('stop')
+          events.push('after Fatal')
+        },
+      },
+    ])
+    expect(code).toBe(1)
+    expect(events).toEqual(['cleanup 2', 'cleanup 1'])
+  })
+
+  it('runs subtests with unique names', async () => {
+    const names: string[] = []
+    const [code, out] = await run(
+      [
+        {
+          Name: 'TestSub',
+          F: async (t) => {
+            for (const name of ['a', 'a', 'b c']) {
+              await t!.Run(name, (t) => {
+                names.push(t!.Name())
+                if (t!.Name() === 'TestSub/b_c') {
+                  t!.Skip('skipped')
+                }
+              })
+            }
+          },
+        },
+      ],
+      ['-test.v'],
+    )
+    expect(code).toBe(0)
+    expect(names).toEqual(['TestSub/a', 'TestSub/a#01', 'TestSub/b_c'])
+    expect(out).toContain('    --- SKIP: TestSub/b_c')
+  })
+
+  it('runs parallel subtests after their parent returns', async () => {
+    const events: string[] = []
+    const [code] = await run([
+      {
+        Name: 'TestParallel',
+        F: async (t) => {
+          for (const name of ['x', 'y']) {
+            await t!.Run(name, async (t) => {
+              await t!.Parallel()
+              events.push(name)
+            })
+          }
+          events.push('parent done')
+        },
+      },
+    ])
+    expect(code).toBe(0)
+    expect(events).toEqual(['parent done', 'x', 'y'])
+  })
+
+  it('filters tests with -run and -skip', async () => {
+    const ran: string[] = []
+    const test = (name: string) => ({
+      Name: name,
+      F: () => {
+        ran.push(name)
+      },
+    })
+    await run([test('TestA'), test('TestB'), test('TestAB')], [
+      '-test.run=A',
+      '-test.skip=B$',
+    ])
+    expect(ran).toEqual(['TestA'])
+  })
+
+  it('warns when no tests match', async () => {
+    const [code, out] = await run([{ Name: 'TestA', F: () => {} }], [
+      '-run',
+      'Nothing',
+    ])
+    expect(code).toBe(0)
+    expect(out).toBe('testing: warning: no tests to run\nPASS\n')
+  })
+
+  it('runs benchmarks N times', async () => {
+    let total = 0
+    const [code, out] = await run(
+      [],
+      ['-test.bench=.', '-test.benchtime=10x'],
+      [
+        {
+          Name: 'BenchmarkLoop',
+          F: (b) => {
+            for (let i = 0; i < b!.N; i++) {
+              total++
+            }
+          },
+        },
+      ],
+    )
+    expect(code).toBe(0)
+    expect(out).toMatch(/BenchmarkLoop\t\s+10\t\s+[\d.]+ ns\/op\n/)
+    expect(total).toBe(11)
+  })
+})
@@ -0,0 +1,971 @@
+// Package testing provides support for automated testing of Go packages
+// compiled to TypeScript with goscript test.
+//
+// goscript test generates a runner which passes the Test and Benchmark
+// functions of the package to MainStart and runs them with runTestMain. The
+// runner prints Go style output and exits with status 1 if any test failed.
+
+import * as $ from '@goscript/builtin/index.js'
+import * as context from '@goscript/context/index.js'
+import * as fmt from '@goscript/fmt/index.js'
+import * as syscall from '@goscript/syscall/index.js'
+
+// InternalTest is a Test function and its name.
+export interface InternalTest {
+  Name: string
+  F: (t: T | null) => void | Promise<void>
+}
+
+// InternalBenchmark is a Benchmark function and its name.
+export interface InternalBenchmark {
+  Name: string
+  F: (b: B | null) => void | Promise<void>
+}
+
+// flags are the command line flags of the test runner, see parseFlags.
+const flags = {
+  verbose: false,
+  run: '',
+  skip: '',
+  bench: '',
+  benchtime: '1s',
+  short: false,
+  failfast: false,
+}
+
+let testingMode = false
+
+// Short reports whether the -test.short flag is set.
+export function Short(): boolean {
+  return flags.short
+}
+
+// Verbose reports whether the -test.v flag is set.
+export function Verbose(): boolean {
+  return flags.verbose
+}
+
+// Testing reports whether the current code is being run in a test.
+export function Testing(): boolean {
+  return testingMode
+}
+
+// parseFlags parses the Go test flags, accepted both as -test.run=x and as
+// -run x.
+function parseFlags(args: string[]): void {
+  for (let i = 0; i < args.length; i++) {
+    let name = args[i]
+    if (!name.startsWith('-')) {
+      continue
+    }
+    name = name.replace(/^--?/, '').replace(/^test\./, '')
+    let value: string | undefined
+    const eq = name.indexOf('=')
+    if (eq !== -1) {
+      value = name.slice(eq + 1)
+      name = name.slice(0, eq)
+    }
+    switch (name) {
+      case 'v':
+      case 'short':
+      case 'failfast': {
+        const on = value === undefined || value === 'true'
+        if (name === 'v') {
+          flags.verbose = on
+        } else if (name === 'short') {
+          flags.short = on
+        } else {
+          flags.failfast = on
+        }
+        break
+      }
+      case 'run':
+      case 'skip':
+      case 'bench':
+      case 'benchtime':
+        if (value === undefined) {
+          value = args[++i] ?? ''
+        }
+        flags[name] = value
+        break
+    }
+  }
+}
+
+const now: () => number =
+  typeof performance !== 'undefined' ?
+    () => performance.now()
+  : () => Date.now()
+
+// writeOut writes s to the standard output.
+function writeOut(s: string): void {
+  const proc = (globalThis as any).process
+  if (proc?.stdout?.write) {
+    proc.stdout.write(s)
+  } else {
+    console.log(s.endsWith('\n') ? s.slice(0, -1) : s)
+  }
+}
+
+// indent prefixes each line of s with prefix.
+function indent(s: string, prefix: string): string {
+  return s
+    .split('\n')
+    .map((line) => (line === '' ? line : prefix + line))
+    .join('\n')
+}
+
+// formatDuration formats a test duration in milliseconds like Go: (0.01s).
+function formatDuration(ms: number): string {
+  return `(${(ms / 1000).toFixed(2)}s)`
+}
+
+// matchesFilter reports whether the test name matches the -run or -bench
+// pattern: each element of the slash-separated pattern must match the
+// corresponding element of the name.
+function matchesFilter(name: string, pattern: string): boolean {
+  if (pattern === '') {
+    return true
+  }
+  const patterns = splitPattern(pattern)
+  const elems = name.split('/')
+  for (let i = 0; i < elems.length && i < patterns.length; i++) {
+    if (!new RegExp(patterns[i]).test(elems[i])) {
+      return false
+    }
+  }
+  return true
+}
+
+// matchesSkip reports whether the test name matches the -skip pattern, which
+// only skips a test if all elements of the pattern match.
+function matchesSkip(name: string, pattern: string): boolean {
+  if (pattern === '') {
+    return false
+  }
+  const patterns = splitPattern(pattern)
+  const elems = name.split('/')
+  if (elems.length < patterns.length) {
+    return false
+  }
+  return patterns.every((p, i) => new RegExp(p).test(elems[i]))
+}
+
+// splitPattern splits a pattern on the slashes outside of brackets and
+// parentheses.
+function splitPattern(pattern: string): string[] {
+  const parts: string[] = []
+  let depth = 0
+  let start = 0
+  for (let i = 0; i < pattern.length; i++) {
+    switch (pattern[i]) {
+      case '\\':
+        i++
+        break
+      case '[':
+      case '(':
+        depth++
+        break
+      case ']':
+      case ')':
+        depth--
+        break
+      case '/':
+        if (depth === 0) {
+          parts.push(pattern.slice(start, i))
+          start = i + 1
+        }
+        break
+    }
+  }
+  parts.push(pattern.slice(start))
+  return parts
+}
+
+// rewriteName rewrites a subtest name like Go: spaces become underscores and
+// unprintable characters are escaped.
+function rewriteName(name: string): string {
+  let out = ''
+  for (const ch of name) {
+    if (/\s/.test(ch)) {
+      out += '_'
+    } else if (/[\p{C}]/u.test(ch)) {
+      out += JSON.stringify(ch).slice(1, -1)
+    } else {
+      out += ch
+    }
+  }
+  return out
+}
+
+// goexit is thrown by FailNow and SkipNow to stop the test function, like
+// runtime.Goexit does in Go.
+class goexit {}
+
+// common holds the state and methods shared by T and B.
+class common {
+  protected _name = ''
+  protected _parent: common | null = null
+  protected _failed = false
+  protected _skipped = false
+  protected _finished = false
+  // _output holds the report lines of the test and its subtests, printed
+  // when the test finishes.
+  protected _output = ''
+  protected _cleanups: (() => void | Promise<void>)[] = []
+  protected _subNames = new Map<string, number>()
+  protected _ctx: context.Context | null = null
+  protected _cancelCtx: context.CancelFunc | null = null
+
+  // Name returns the name of the running test or benchmark.
+  public Name(): string {
+    return this._name
+  }
+
+  // Fail marks the function as having failed but continues execution.
+  public Fail(): void {
+    if (this._finished) {
+      $.panic('Fail in goroutine after ' + this._name + ' has completed')
+    }
+    this._failed = true
+    this._parent?.Fail()
+  }
+
+  // Failed reports whether the function has failed.
+  public Failed(): boolean {
+    return this._failed
+  }
+
+  // FailNow marks the function as having failed and stops its execution.
+  public FailNow(): void {
+    this.Fail()
+    throw new goexit()
+  }
+
+  // Log formats its arguments like Println and records the text in the
+  // test output.
+  public Log(...args: any[]): void {
+    this.log(fmt.Sprintln(...args))
+  }
+
+  // Logf formats its arguments like Printf and records the text in the
+  // test output.
+  public Logf(format: string, ...args: any[]): void {
+    this.log(fmt.Sprintf(format, ...args))
+  }
+
+  // Error is equivalent to Log followed by Fail.
+  public Error(...args: any[]): void {
+    this.Log(...args)
+    this.Fail()
+  }
+
+  // Errorf is equivalent to Logf followed by Fail.
+  public Errorf(format: string, ...args: any[]): void {
+    this.Logf(format, ...args)
+    this.Fail()
+  }
+
+  // Fatal is equivalent to Log followed by FailNow.
+  public Fatal(...args: any[]): void {
+    this.Log(...args)
+    this.FailNow()
+  }
+
+  // Fatalf is equivalent to Logf followed by FailNow.
+  public Fatalf(format: string, ...args: any[]): void {
+    this.Logf(format, ...args)
+    this.FailNow()
+  }
+
+  // Skip is equivalent to Log followed by SkipNow.
+  public Skip(...args: any[]): void {
+    this.Log(...args)
+    this.SkipNow()
+  }
+
+  // Skipf is equivalent to Logf followed by SkipNow.
+  public Skipf(format: string, ...args: any[]): void {
+    this.Logf(format, ...args)
+    this.SkipNow()
+  }
+
+  // SkipNow marks the test as having been skipped and stops its execution.
+  public SkipNow(): void {
+    this._skipped = true
+    throw new goexit()
+  }
+
+  // Skipped reports whether the test was skipped.
+  public Skipped(): boolean {
+    return this._skipped
+  }
+
+  // Helper marks the calling function as a test helper function. Log
+  // messages carry no source positions, so it has no effect.
+  public Helper(): void {}
+
+  // Cleanup registers a function to be called when the test and all its
+  // subtests complete, in last added, first called order.
+  public Cleanup(f: () => void | Promise<void>): void {
+    this._cleanups.push(f)
+  }
+
+  // Setenv sets an environment variable and restores it during cleanup.
+  public Setenv(key: string, value: string): void {
+    const [prev, ok] = syscall.Getenv(key)
+    const err = syscall.Setenv(key, value)
+    if (err !== null) {
+      this.Fatalf('cannot set environment variable: %v', err)
+    }
+    this.Cleanup(() => {
+      if (ok) {
+        syscall.Setenv(key, prev)
+      } else {
+        syscall.Unsetenv(key)
+      }
+    })
+  }
+
+  // TempDir would return a temporary directory for the test, but goscript
+  // has no file system to create it in.
+  public TempDir(): string {
+    this.Fatalf('TempDir is not supported by goscript')
+    return ''
+  }
+
+  // Context returns a context which is canceled just before the cleanup
+  // functions are called.
+  public Context(): context.Context {
+    if (this._ctx === null) {
+      ;[this._ctx, this._cancelCtx] = context.WithCancel(context.Background())
+    }
+    return this._ctx
+  }
+
+  // log records s in the output of the test, or prints it right away in
+  // verbose mode.
+  protected log(s: string): void {
+    if (s.endsWith('\n')) {
+      s = s.slice(0, -1)
+    }
+    const text = '    ' + s.split('\n').join('\n        ') + '\n'
+    if (flags.verbose) {
+      writeOut(text)
+    } else {
+      this._output += text
+    }
+  }
+
+  // subName returns the unique full name of the subtest name.
+  protected subName(name: string): string {
+    let sub = rewriteName(name)
+    const n = this._subNames.get(sub) ?? 0
+    this._subNames.set(sub, n + 1)
+    if (n > 0) {
+      sub += '#' + String(n).padStart(2, '0')
+    }
+    return this._name === '' ? sub : this._name + '/' + sub
+  }
+
+  // runCleanups cancels the context and calls the cleanup functions.
+  protected async runCleanups(): Promise<void> {
+    this._cancelCtx?.()
+    while (this._cleanups.length > 0) {
+      const f = this._cleanups.pop()!
+      try {
+        await f()
+      } catch (err) {
+        this.recordPanic(err)
+      }
+    }
+  }
+
+  // recordPanic fails the test with the panic err, unless err stops the
+  // test like FailNow.
+  protected recordPanic(err: unknown): void {
+    if (err instanceof goexit) {
+      return
+    }
+    let msg = err instanceof Error ? err.message : String(err)
+    if (!msg.startsWith('panic: ')) {
+      msg = 'panic: ' + msg
+    }
+    const stack = err instanceof Error ? (err.stack ?? '') : ''
+    // Leave out the frames of the runtime and of the test runner.
+    const frames = stack
+      .split('\n')
+      .filter(
+        (l) =>
+          l.trim().startsWith('at ') &&
+          !/[\\/](builtin|testing)[\\/]/.test(l),
+      )
+    this.log([msg, ...frames.map((l) => l.trim())].join('\n'))
+    this.Fail()
+  }
+
+  // report flushes the result line and output of a finished test to its
+  // parent, or to the standard output for top-level tests.
+  protected report(kind: 'test' | 'bench', durationMs: number): void {
+    let status = ''
+    if (this._failed) {
+      status = 'FAIL'
+    } else if (this._skipped && (kind === 'bench' || flags.verbose)) {
+      status = 'SKIP'
+    } else if (kind === 'bench') {
+      status = this._output !== '' ? 'BENCH' : ''
+    } else if (flags.verbose) {
+      status = 'PASS'
+    }
+    let report = ''
+    if (status !== '') {
+      // Benchmark reports have no duration, like in Go.
+      const duration = kind === 'test' ? ' ' + formatDuration(durationMs) : ''
+      report = `--- ${status}: ${this._name}${duration}\n` + this._output
+    }
+    this._output = ''
+    if (this._parent !== null && this._parent._parent !== null) {
+      this._parent._output += indent(report, '    ')
+    } else {
+      writeOut(report)
+    }
+  }
+}
+
+// T is a type passed to Test functions to manage test state and support
+// formatted test logs.
+export class T extends common {
+  // _parallel is resolved when the test calls Parallel, _done when it
+  // finished.
+  private _parallelSignal: Promise<void> = Promise.resolve()
+  private _signalParallel: () => void = () => {}
+  // _release is resolved when the parent finished its sequential part and
+  // its parallel subtests may run.
+  private _released: Promise<void> = Promise.resolve()
+  private _release: () => void = () => {}
+  private _parallelSubtests: Promise<void>[] = []
+  private _done: Promise<void> = Promise.resolve()
+  private _isParallel = false
+  private _start = 0
+
+  constructor(_init?: Partial<{}>) {
+    super()
+  }
+
+  // Parallel signals that this test is to be run in parallel with (and only
+  // with) other parallel tests. It returns once the parent test finished
+  // its sequential part.
+  public async Parallel(): Promise<void> {
+    if (this._isParallel) {
+      $.panic('testing: t.Parallel called multiple times')
+    }
+    const parent = this._parent as T | null
+    if (parent === null) {
+      return
+    }
+    this._isParallel = true
+    parent._parallelSubtests.push(this._done)
+    if (flags.verbose) {
+      writeOut(`=== PAUSE ${this._name}\n`)
+    }
+    this._signalParallel()
+    await parent._released
+    if (flags.verbose) {
+      writeOut(`=== CONT  ${this._name}\n`)
+    }
+    this._start = now()
+  }
+
+  // Run runs f as a subtest of t called name and reports whether f
+  // succeeded. Run returns early if f calls Parallel.
+  public async Run(
+    name: string,
+    f: (t: T | null) => void | Promise<void>,
+  ): Promise<boolean> {
+    const fullName = this.subName(name)
+    if (
+      !matchesFilter(fullName, flags.run) ||
+      matchesSkip(fullName, flags.skip)
+    ) {
+      return true
+    }
+    const t = newT(fullName, this)
+    await t.start(f)
+    return !t._failed
+  }
+
+  // start runs the test function and resolves once it finished or called
+  // Parallel.
+  private async start(f: (t: T | null) => void | Promise<void>): Promise<void> {
+    this._parallelSignal = new Promise((resolve) => {
+      this._signalParallel = resolve
+    })
+    this._released = new Promise((resolve) => {
+      this._release = resolve
+    })
+    if (flags.verbose) {
+      writeOut(`=== RUN   ${this._name}\n`)
+    }
+    this._start = now()
+    this._done = this.runBody(f)
+    await Promise.race([this._done, this._parallelSignal])
+  }
+
+  private async runBody(
+    f: (t: T | null) => void | Promise<void>,
+  ): Promise<void> {
+    // Let Parallel register the returned promise before the body starts.
+    await Promise.resolve()
+    try {
+      await f(this)
+    } catch (err) {
+      this.recordPanic(err)
+    }
+    this._release()
+    await Promise.all(this._parallelSubtests)
+    await this.runCleanups()
+    this._finished = true
+    this.report('test', now() - this._start)
+  }
+
+  // runTests runs the top-level tests and their parallel subtests.
+  public async runTests(tests: InternalTest[]): Promise<[boolean, boolean]> {
+    let ran = false
+    for (const test of tests) {
+      if (
+        !matchesFilter(test.Name, flags.run) ||
+        matchesSkip(test.Name, flags.skip)
+      ) {
+        continue
+      }
+      ran = true
+      const t = newT(test.Name, this)
+      await t.start(test.F)
+      if (flags.failfast && this._failed) {
+        break
+      }
+    }
+    this._release()
+    await Promise.all(this._parallelSubtests)
+    return [ran, !this._failed]
+  }
+}
+
+// newT creates the test name, a subtest of parent.
+function newT(name: string, parent: T | null): T {
+  const t = new T()
+  t['_name'] = name
+  t['_parent'] = parent
+  return t
+}
+
+// TB is the interface common to T and B.
+export interface TB {
+  Cleanup(f: () => void | Promise<void>): void
+  Context(): context.Context
+  Error(...args: any[]): void
+  Errorf(format: string, ...args: any[]): void
+  Fail(): void
+  FailNow(): void
+  Failed(): boolean
+  Fatal(...args: any[]): void
+  Fatalf(format: string, ...args: any[]): void
+  Helper(): void
+  Log(...args: any[]): void
+  Logf(format: string, ...args: any[]): void
+  Name(): string
+  Setenv(key: string, value: string): void
+  Skip(...args: any[]): void
+  SkipNow(): void
+  Skipf(format: string, ...args: any[]): void
+  Skipped(): boolean
+  TempDir(): string
+}
+
+$.registerInterfaceType(
+  'testing.TB',
+  null,
+  [
+    'Cleanup',
+    'Context',
+    'Error',
+    'Errorf',
+    'Fail',
+    'FailNow',
+    'Failed',
+    'Fatal',
+    'Fatalf',
+    'Helper',
+    'Log',
+    'Logf',
+    'Name',
+    'Setenv',
+    'Skip',
+    'SkipNow',
+    'Skipf',
+    'Skipped',
+    'TempDir',
+  ].map((name) => ({ name, args: [], returns: [] })),
+)
+
+// BenchmarkResult contains the results of a benchmark run.
+export class BenchmarkResult {
+  public N: number = 0
+  public T: number = 0
+  public Bytes: number = 0
+  public Extra: Map<string, number> = new Map()
+
+  constructor(init?: Partial<BenchmarkResult>) {
+    Object.assign(this, init)
+  }
+
+  // NsPerOp returns the nanoseconds per iteration.
+  public NsPerOp(): number {
+    return this.N <= 0 ? 0 : Math.trunc(this.T / this.N)
+  }
+
+  // String returns a summary of the benchmark results.
+  public String(): string {
+    let s = `${String(this.N).padStart(8)}\t${prettyPrint(this.T / Math.max(this.N, 1))} ns/op`
+    if (this.Bytes > 0 && this.T > 0) {
+      const mbs = (this.Bytes * this.N) / 1e6 / (this.T / 1e9)
+      s += `\t${mbs.toFixed(2).padStart(7)} MB/s`
+    }
+    for (const [unit, value] of this.Extra) {
+      s += `\t${prettyPrint(value)} ${unit}`
+    }
+    return s
+  }
+}
+
+// prettyPrint formats a benchmark metric with 4 significant digits like Go.
+function prettyPrint(x: number): string {
+  let s: string
+  const y = Math.abs(x)
+  if (y === 0 || y >= 999.95) {
+    s = x.toFixed(0)
+  } else if (y >= 99.995) {
+    s = x.toFixed(1)
+  } else if (y >= 9.9995) {
+    s = x.toFixed(2)
+  } else if (y >= 0.99995) {
+    s = x.toFixed(3)
+  } else if (y >= 0.099995) {
+    s = x.toFixed(4)
+  } else if (y >= 0.0099995) {
+    s = x.toFixed(5)
+  } else {
+    s = x.toExponential(3)
+  }
+  return s.padStart(10)
+}
+
+// parseBenchtime parses the -benchtime flag: a duration such as 1s or
+// 500ms, or an iteration count such as 100x.
+function parseBenchtime(s: string): { ms: number; n: number } {
+  const m = /^(\d+(?:\.\d+)?)(x|ns|us|µs|ms|s|m)$/.exec(s)
+  if (m === null) {
+    return { ms: 1000, n: 0 }
+  }
+  const v = Number(m[1])
+  switch (m[2]) {
+    case 'x':
+      return { ms: 0, n: Math.trunc(v) }
+    case 'ns':
+      return { ms: v / 1e6, n: 0 }
+    case 'us':
+    case 'µs':
+      return { ms: v / 1e3, n: 0 }
+    case 'ms':
+      return { ms: v, n: 0 }
+    case 'm':
+      return { ms: v * 60000, n: 0 }
+    default:
+      return { ms: v * 1000, n: 0 }
+  }
+}
+
+// B is a type passed to Benchmark functions to manage benchmark timing and
+// control the number of iterations.
+export class B extends common {
+  // N is the number of iterations to run.
+  public N: number = 0
+
+  private _benchFunc: (b: B | null) => void | Promise<void> = () => {}
+  private _timerOn = false
+  private _timerStart = 0
+  private _duration = 0
+  private _bytes = 0
+  private _extra = new Map<string, number>()
+  private _hasSub = false
+  private _loopN = 0
+  private _result: BenchmarkResult | null = null
+
+  constructor(_init?: Partial<{ N?: number }>) {
+    super()
+    this.N = _init?.N ?? 0
+  }
+
+  // ResetTimer zeroes the elapsed benchmark time.
+  public ResetTimer(): void {
+    if (this._timerOn) {
+      this._timerStart = now()
+    }
+    this._duration = 0
+  }
+
+  // StartTimer starts timing a test.
+  public StartTimer(): void {
+    if (!this._timerOn) {
+      this._timerStart = now()
+      this._timerOn = true
+    }
+  }
+
+  // StopTimer stops timing a test.
+  public StopTimer(): void {
+    if (this._timerOn) {
+      this._duration += now() - this._timerStart
+      this._timerOn = false
+    }
+  }
+
+  // Elapsed returns the measured elapsed time of the benchmark in
+  // nanoseconds.
+  public Elapsed(): number {
+    let d = this._duration
+    if (this._timerOn) {
+      d += now() - this._timerStart
+    }
+    return Math.round(d * 1e6)
+  }
+
+  // ReportAllocs has no effect: JavaScript does not expose allocations.
+  public ReportAllocs(): void {}
+
+  // SetBytes records the number of bytes processed in a single operation.
+  public SetBytes(n: number): void {
+    this._bytes = n
+  }
+
+  // ReportMetric adds n unit to the reported benchmark results.
+  public ReportMetric(n: number, unit: string): void {
+    this._extra.set(unit, n)
+  }
+
+  // Loop returns true as long as the benchmark should continue running.
+  public Loop(): boolean {
+    if (this._loopN === 0) {
+      this.ResetTimer()
+    }
+    if (this._loopN < this.N) {
+      this._loopN++
+      return true
+    }
+    this.StopTimer()
+    return false
+  }
+
+  // Run benchmarks f as a subbenchmark with the given name and reports
+  // whether there were no failures.
+  public async Run(
+    name: string,
+    f: (b: B | null) => void | Promise<void>,
+  ): Promise<boolean> {
+    this._hasSub = true
+    const fullName = this.subName(name)
+    if (
+      !matchesFilter(fullName, flags.bench) ||
+      matchesSkip(fullName, flags.skip)
+    ) {
+      return true
+    }
+    const sub = newB(fullName, this, f)
+    await sub.runBenchmark()
+    return !sub._failed
+  }
+
+  // runN runs the benchmark function for n iterations.
+  private async runN(n: number): Promise<void> {
+    this.N = n
+    this._loopN = 0
+    this._duration = 0
+    this._timerOn = false
+    this.StartTimer()
+    try {
+      await this._benchFunc(this)
+    } catch (err) {
+      this.recordPanic(err)
+    }
+    this.StopTimer()
+  }
+
+  // runBenchmark runs the benchmark once to find out whether it has
+  // subbenchmarks, then with increasing b.N until it ran for -benchtime,
+  // and prints the result.
+  public async runBenchmark(): Promise<void> {
+    const start = now()
+    await this.runN(1)
+    if (!this._hasSub && !this._failed && !this._skipped) {
+      const benchtime = parseBenchtime(flags.benchtime)
+      if (benchtime.n > 0) {
+        if (benchtime.n > 1) {
+          await this.runN(benchtime.n)
+        }
+      } else {
+        while (
+          !this._failed &&
+          this._duration < benchtime.ms &&
+          this.N < 1e9
+        ) {
+          const last = this.N
+          const prevNs = Math.max(this._duration * 1e6, 1)
+          let n = Math.trunc((benchtime.ms * 1e6 * last) / prevNs)
+          n += Math.trunc(n / 5)
+          n = Math.min(n, 100 * last)
+          n = Math.max(n, last + 1)
+          n = Math.min(n, 1e9)
+          await this.runN(n)
+        }
+      }
+      if (!this._failed) {
+        this._result = new BenchmarkResult({
+          N: this.N,
+          T: Math.round(this._duration * 1e6),
+          Bytes: this._bytes,
+          Extra: this._extra,
+        })
+      }
+    }
+    await this.runCleanups()
+    this._finished = true
+    if (this._result !== null) {
+      writeOut(`${this._name}\t${this._result.String()}\n`)
+    }
+    if (this._failed || this._skipped || this._output !== '') {
+      // Benchmark reports are printed right away, not nested.
+      const parent = this._parent
+      this._parent = null
+      this.report('bench', now() - start)
+      this._parent = parent
+    }
+  }
+}
+
+// newB creates the benchmark name running f, a subbenchmark of parent.
+function newB(
+  name: string,
+  parent: B | null,
+  f: (b: B | null) => void | Promise<void>,
+): B {
+  const b = new B()
+  b['_name'] = name
+  b['_parent'] = parent
+  b['_benchFunc'] = f
+  return b
+}
+
+// AllocsPerRun calls f runs times and returns the average number of
+// allocations per run, always 0 as JavaScript does not expose allocations.
+export async function AllocsPerRun(
+  runs: number,
+  f: () => void | Promise<void>,
+): Promise<number> {
+  await f()
+  for (let i = 0; i < runs; i++) {
+    await f()
+  }
+  return 0
+}
+
+// M is a type passed to a TestMain function to run the actual tests.
+export class M {
+  private _pkgPath = ''
+  private _tests: InternalTest[] = []
+  private _benchmarks: InternalBenchmark[] = []
+  private _exitCode = 0
+  private _ran = false
+
+  constructor(_init?: Partial<{}>) {}
+
+  // Run runs the tests and benchmarks and returns an exit code to pass to
+  // os.Exit.
+  public async Run(): Promise<number> {
+    this._ran = true
+    const root = newT('', null)
+    const [testRan, testOk] = await root.runTests(this._tests)
+    if (!testRan && flags.bench === '') {
+      writeOut('testing: warning: no tests to run\n')
+    }
+    let ok = testOk
+    if (ok) {
+      ok = await this.runBenchmarks()
+    }
+    if (!ok) {
+      writeOut('FAIL\n')
+      this._exitCode = 1
+      return 1
+    }
+    writeOut('PASS\n')
+    this._exitCode = 0
+    return 0
+  }
+
+  private async runBenchmarks(): Promise<boolean> {
+    if (flags.bench === '') {
+      return true
+    }
+    const root = newB('', null, () => {})
+    let printedHeader = false
+    for (const bench of this._benchmarks) {
+      if (
+        !matchesFilter(bench.Name, flags.bench) ||
+        matchesSkip(bench.Name, flags.skip)
+      ) {
+        continue
+      }
+      if (!printedHeader) {
+        writeOut(`goos: js\ngoarch: wasm\npkg: ${this._pkgPath}\n`)
+        printedHeader = true
+      }
+      await newB(bench.Name, root, bench.F).runBenchmark()
+    }
+    return !root.Failed()
+  }
+}
+
+// MainStart creates the M for the tests and benchmarks of the package
+// pkgPath, reading the test flags from the command line.
+export function MainStart(
+  pkgPath: string,
+  tests: InternalTest[],
+  benchmarks: InternalBenchmark[],
+): M {
+  testingMode = true
+  const proc = (globalThis as any).process
+  parseFlags(proc?.argv?.slice(2) ?? [])
+  const m = new M()
+  m['_pkgPath'] = pkgPath
+  m['_tests'] = tests
+  m['_benchmarks'] = benchmarks
+  return m
+}
+
+// runTestMain runs the tests of m with the TestMain function of the package
+// if there is one, and sets the exit code of the process.
+export async function runTestMain(
+  m: M,
+  testMain?: (m: M | null) => void | Promise<void>,
+): Promise<number> {
+  if (testMain) {
+    await testMain(m)
+  } else {
+    await m.Run()
+  }
+  const code = m['_ran'] ? m['_exitCode'] : 0
+  const proc = (globalThis as any).process
+  if (proc && code !== 0) {
+    proc.exitCode = code
+  }
+  return code
+}
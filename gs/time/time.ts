@@ -1,4 +1,11 @@
 import { makeChannel, ChannelRef, makeChannelRef } from '../builtin/channel.js'
+import {
+  clearTimer,
+  keepAlive,
+  setTimer,
+  sleep,
+  TimerHandle,
+} from '../builtin/scheduler.js'
 
 // Time represents a time instant with nanosecond precision
 export class Time {
@@ -574,6 +581,24 @@ export class Time {
 
     return result
   }
+
+  // toJSON returns the time in RFC 3339 format with nanoseconds, like MarshalJSON
+  public toJSON(): string {
+    return this.Format(RFC3339Nano)
+  }
+
+  // toPlain returns the time as encoding/json writes it, like the toPlain method of compiled structs
+  public toPlain(): string {
+    return this.toJSON()
+  }
+
+  // fromJSON parses a time in RFC 3339 format, like UnmarshalJSON
+  public static fromJSON(value: unknown): Time {
+    if (typeof value !== 'string') {
+      return new Time()
+    }
+    return Parse(RFC3339, value)
+  }
 }
 
 // Duration represents a span of time (nanoseconds)
@@ -689,7 +714,7 @@ export class ParseError extends Error {
 
 // Timer represents a single event timer
 export class Timer {
-  private _timeout: NodeJS.Timeout | number
+  private _timeout: TimerHandle
   private _duration: Duration
   private _callback?: () => void
 
@@ -699,32 +724,27 @@ export class Timer {
     const ms = duration / 1000000 // Convert nanoseconds to milliseconds
 
     if (callback) {
-      this._timeout = setTimeout(callback, ms)
+      this._timeout = setTimer(callback, ms)
     } else {
-      this._timeout = setTimeout(() => {}, ms)
+      this._timeout = setTimer(() => {}, ms)
     }
   }
 
   // Stop prevents the Timer from firing
   public Stop(): boolean {
-    if (typeof this._timeout === 'number') {
-      clearTimeout(this._timeout)
-    } else {
-      clearTimeout(this._timeout)
-    }
-    return true
+    return clearTimer(this._timeout)
   }
 
   // Reset changes the timer to expire after duration d
   public Reset(d: Duration): boolean {
-    this.Stop()
+    const active = this.Stop()
     const ms = d / 1000000
     if (this._callback) {
-      this._timeout = setTimeout(this._callback, ms)
+      this._timeout = setTimer(this._callback, ms)
     } else {
-      this._timeout = setTimeout(() => {}, ms)
+      this._timeout = setTimer(() => {}, ms)
     }
-    return true
+    return active
   }
 }
 
@@ -733,16 +753,20 @@ export class Ticker {
   private _interval: NodeJS.Timeout | number
   private _duration: Duration
   private _stopped: boolean = false
+  // A running ticker may wake goroutines, see $.keepAlive
+  private _release: () => void
 
   constructor(duration: Duration) {
     this._duration = duration
     const ms = duration / 1000000 // Convert nanoseconds to milliseconds
     this._interval = setInterval(() => {}, ms)
+    this._release = keepAlive()
   }
 
   // Stop turns off a ticker
   public Stop(): void {
     this._stopped = true
+    this._release()
     if (typeof this._interval === 'number') {
       clearInterval(this._interval)
     } else {
@@ -757,13 +781,14 @@ export class Ticker {
     this._duration = d
     const ms = d / 1000000
     this._interval = setInterval(() => {}, ms)
+    this._release = keepAlive()
   }
 
   // Channel returns an async iterator that yields time values
   public async *Channel(): AsyncIterableIterator<Time> {
     const ms = this._duration / 1000000
     while (!this._stopped) {
-      await new Promise((resolve) => setTimeout(resolve, ms))
+      await sleep(ms)
       if (!this._stopped) {
         yield Now()
       }
@@ -862,7 +887,7 @@ export function Until(t: Time): Duration {
 // Sleep pauses the current execution for at least the duration d
 export async function Sleep(d: Duration): Promise<void> {
   const ms = d / 1000000 // Convert nanoseconds to milliseconds
-  return new Promise((resolve) => setTimeout(resolve, ms))
+  return sleep(ms)
 }
 
 // Export month constants
@@ -872,6 +897,7 @@ export const May = Month.May
 export const DateTime = '2006-01-02 15:04:05'
 export const Layout = "01/02 03:04:05PM '06 -0700"
 export const RFC3339 = '2006-01-02T15:04:05Z07:00'
+export const RFC3339Nano = '2006-01-02T15:04:05.999999999Z07:00'
 export const Kitchen = '3:04PM'
 
 // Unix returns the local Time corresponding to the given Unix time,
@@ -1012,7 +1038,7 @@ export function After(d: Duration): ChannelRef<Time> {
   const channel = makeChannel(1, new Time(), 'both')
 
   // Start a timer that will send the current time after the duration
-  setTimeout(async () => {
+  setTimer(() => {
     channel.send(Now()).catch(() => {})
   }, ms)
 
@@ -0,0 +1,32 @@
+package jspromise
+
+import "syscall/js"
+
+// Await waits for the promise p to settle. It returns the value of a
+// fulfilled promise, or a js.Error holding the reason of a rejected one.
+// Values which are not promises or other thenables are returned as they are.
+func Await(p js.Value) (js.Value, error) {
+	if t := p.Type(); (t != js.TypeObject && t != js.TypeFunction) || p.Get("then").Type() != js.TypeFunction {
+		return p, nil
+	}
+
+	type result struct {
+		value js.Value
+		err   error
+	}
+	done := make(chan result, 1)
+	onFulfilled := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{value: args[0]}
+		return nil
+	})
+	defer onFulfilled.Release()
+	onRejected := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{err: js.Error{Value: args[0]}}
+		return nil
+	})
+	defer onRejected.Release()
+
+	p.Call("then", onFulfilled, onRejected)
+	res := <-done
+	return res.value, res.err
+}
@@ -0,0 +1,9 @@
+// Package jspromise waits for JavaScript promises from Go code using
+// syscall/js, both when it is compiled with GOOS=js GOARCH=wasm and when it
+// is compiled to TypeScript with goscript.
+//
+// syscall/js has no way to wait for a promise, so a Go program passes
+// callbacks to its then method and blocks on a channel until one of them is
+// called. Await does this, and goscript replaces it with an await of the
+// promise.
+package jspromise
@@ -132,7 +132,7 @@ func ReadExpectedLog(t *testing.T, testDir string) string {
 //
 // The function walks the testDir to find all .go files, determines their package structure,
 // and then invokes the goscript compiler.
-// After compilation, it copies the generated .gs.ts files and any index.ts files
+// After compilation, it copies the generated .gs.ts files and any index.ts and facade.ts files
 // from the outputDir back into the original testDir, adding a header comment to the .gs.ts files.
 // This allows the generated TypeScript to be reviewed and committed alongside the Go source.
 func CompileGoToTypeScript(t *testing.T, parentModulePath, testDir, tempDir, outputDir string, le *logrus.Entry) {
@@ -160,11 +160,81 @@ func CompileGoToTypeScript(t *testing.T, parentModulePath, testDir, tempDir, out
 		t.Fatalf("failed to check for no-all-deps file in %s: %v", testDir, err)
 	}
 
+	// Check if int64 and uint64 should be emitted as bigint for this test
+	int64AsBigInt := false
+	if _, err := os.Stat(filepath.Join(testDir, "int64-bigint")); err == nil {
+		int64AsBigInt = true
+		t.Logf("Enabling Int64AsBigInt for %s: int64-bigint file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for int64-bigint file in %s: %v", testDir, err)
+	}
+
+	// Check if fixed-width integer arithmetic should wrap for this test
+	strictIntegers := false
+	if _, err := os.Stat(filepath.Join(testDir, "strict-integers")); err == nil {
+		strictIntegers = true
+		t.Logf("Enabling StrictIntegers for %s: strict-integers file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for strict-integers file in %s: %v", testDir, err)
+	}
+
+	// Check if loops should yield to other goroutines for this test
+	preemptive := false
+	if _, err := os.Stat(filepath.Join(testDir, "preemptive")); err == nil {
+		preemptive = true
+		t.Logf("Enabling Preemptive for %s: preemptive file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for preemptive file in %s: %v", testDir, err)
+	}
+
+	// Check if enum types should be written as literal unions for this test
+	enumUnions := false
+	if _, err := os.Stat(filepath.Join(testDir, "enum-unions")); err == nil {
+		enumUnions = true
+		t.Logf("Enabling EnumUnions for %s: enum-unions file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for enum-unions file in %s: %v", testDir, err)
+	}
+
+	// Check if struct classes should have JSON methods for this test
+	jsonMethods := false
+	if _, err := os.Stat(filepath.Join(testDir, "json-methods")); err == nil {
+		jsonMethods = true
+		t.Logf("Enabling JSONMethods for %s: json-methods file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for json-methods file in %s: %v", testDir, err)
+	}
+
+	// Check if sealed interfaces should be written as unions for this test
+	sealedUnions := false
+	if _, err := os.Stat(filepath.Join(testDir, "sealed-unions")); err == nil {
+		sealedUnions = true
+		t.Logf("Enabling SealedUnions for %s: sealed-unions file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for sealed-unions file in %s: %v", testDir, err)
+	}
+
+	// Check if a facade.ts should be written for this test
+	facade := false
+	if _, err := os.Stat(filepath.Join(testDir, "facade")); err == nil {
+		facade = true
+		t.Logf("Enabling Facade for %s: facade file found", filepath.Base(testDir))
+	} else if !os.IsNotExist(err) {
+		t.Fatalf("failed to check for facade file in %s: %v", testDir, err)
+	}
+
 	conf := &compiler.Config{
 		Dir:                testDir,
 		OutputPath:         outputDir,
 		AllDependencies:    allDependencies,
 		DisableEmitBuiltin: true, // We want to use the handwritten gs/ packages in compliance tests
+		Int64AsBigInt:      int64AsBigInt,
+		StrictIntegers:     strictIntegers,
+		Preemptive:         preemptive,
+		EnumUnions:         enumUnions,
+		JSONMethods:        jsonMethods,
+		SealedUnions:       sealedUnions,
+		Facade:             facade,
 	}
 	if err := conf.Validate(); err != nil {
 		t.Fatalf("invalid compiler config: %v", err)
@@ -306,9 +376,9 @@ func CompileGoToTypeScript(t *testing.T, parentModulePath, testDir, tempDir, out
 			return err // Stop walking on error
 		}
 
-		if fileName == "index.ts" {
+		if fileName == "index.ts" || fileName == "facade.ts" {
 			if err := copyFile(path, destPath); err != nil {
-				t.Logf("failed to copy index.ts from %s to %s: %v", path, destPath, err)
+				t.Logf("failed to copy %s from %s to %s: %v", fileName, path, destPath, err)
 				return err
 			}
 		} else if strings.HasSuffix(fileName, ".gs.ts") {
@@ -606,7 +676,7 @@ func copyFile(src, dst string) error {
 //     written here, and paths within it will be relative to this directory.
 //
 // The generated tsconfig.json extends the root tsconfig.json from the workspace.
-// It includes all "*.gs.ts" and "index.ts" files found recursively within testDir.
+// It includes all "*.gs.ts", "index.ts" and "facade.ts" files found recursively within testDir.
 // It sets up "paths" aliases for:
 //   - The test's own generated package: "@goscript/PARENT_MODULE/tests/tests/TEST_NAME/*" -> "./*"
 //   - The goscript builtin types: "@goscript/builtin" -> relative path to "workspaceDir/gs/builtin/index.ts"
@@ -620,7 +690,7 @@ func WriteTypeCheckConfig(t *testing.T, parentModulePath, workspaceDir, testDir
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && (strings.HasSuffix(d.Name(), ".gs.ts") || d.Name() == "index.ts") {
+		if !d.IsDir() && (strings.HasSuffix(d.Name(), ".gs.ts") || d.Name() == "index.ts" || d.Name() == "facade.ts") {
 			gsTsFiles = append(gsTsFiles, path)
 		}
 		if d.IsDir() && d.Name() == "run" {
@@ -724,10 +794,11 @@ func RunTypeScriptTypeCheck(t *testing.T, workspaceDir, testDir string, tsconfig
 	})
 }
 
-const runnerContentTemplate = `import { main } from %q;
+const runnerContentTemplate = `import * as $ from "@goscript/builtin/index.js";
+import { main } from %q;
 // NOTE: To debug: add a breakpoint, open a JavaScript Debug Terminal, and bun runner.ts
 await (async () => {
-  await main();
+  await $.runMain(main);
   await new Promise(resolve => setTimeout(resolve, 100)); // Allow microtasks to settle
 })();
 `
@@ -741,7 +812,7 @@ await (async () => {
 // 3. Preparing a test run directory within testDir.
 // 4. Setting up a "tsconfig.json" and "package.json" in the "run" directory for executing the compiled TypeScript.
 // 5. Compiling Go source files from testDir to TypeScript, placing them in "run/output/...".
-//   - Generated .gs.ts and index.ts files are copied back to testDir.
+//   - Generated .gs.ts, index.ts and facade.ts files are copied back to testDir.
 //     6. Writing a "runner.ts" script in the "run" directory to execute the compiled test.
 //     7. If an "expect-fail" file is not present in testDir:
 //     a. Running the "runner.ts" script using `bun`.
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+const rotation = 1i
+
+type point struct {
+	pos complex128
+}
+
+func mandelbrot(c complex128) int {
+	z := complex(0, 0)
+	for i := 0; i < 50; i++ {
+		z = z*z + c
+		if cmplx.Abs(z) > 2 {
+			return i
+		}
+	}
+	return -1
+}
+
+func main() {
+	a := 1 + 2i
+	b := complex(3.0, -4.0)
+	fmt.Println("a:", a, "b:", b)
+	fmt.Println("sum:", a+b, "diff:", a-b)
+	fmt.Println("product:", a*b, "quotient:", a/b)
+	fmt.Println("neg:", -a)
+	fmt.Println("real:", real(b), "imag:", imag(b))
+	fmt.Println("equal:", a == 1+2i, "not equal:", a != b)
+
+	// Compound assignment and rotation
+	c := a
+	c *= rotation
+	c += 1
+	fmt.Println("rotated:", c)
+
+	// Zero values
+	var z complex128
+	var p point
+	fmt.Println("zero:", z, p.pos == 0)
+
+	// complex64 rounds to float32
+	var f complex64 = complex(0.1, 0.5)
+	f = f * 2
+	fmt.Println("complex64:", f == complex(0.2, 1))
+
+	// Division by zero yields infinities
+	fmt.Println("div zero:", a/z)
+
+	// Formatting
+	fmt.Printf("%v %.2f %T\n", a, b, a)
+
+	// math/cmplx
+	fmt.Println("abs:", cmplx.Abs(b))
+	fmt.Println("conj:", cmplx.Conj(a))
+	fmt.Println("sqrt:", cmplx.Sqrt(-4))
+	fmt.Println("inf:", cmplx.IsInf(cmplx.Inf()), cmplx.IsNaN(cmplx.NaN()))
+	r, theta := cmplx.Polar(2i)
+	fmt.Printf("polar: %.3f %.3f\n", r, theta)
+
+	// Composite literal elements, keys and fields
+	roots := []complex128{1 + 2i, 3, rotation}
+	fmt.Println("slice:", roots[0]+roots[1], roots[2])
+	small := [3]complex64{0.1, 2: 1}
+	fmt.Println("array:", small[0] == complex(0.1, 0), small[1], small[2])
+	names := map[complex128]string{1: "one", 1i: "i"}
+	fmt.Println("map:", names[1+0i], names[rotation], len(names))
+	scaled := map[string]complex128{"half": 0.5}
+	fmt.Println("map value:", scaled["half"]*2)
+	q := point{2}
+	fmt.Println("struct:", q.pos*rotation, point{pos: -1}.pos)
+
+	// Interfaces
+	var v any = a
+	if x, ok := v.(complex128); ok {
+		fmt.Println("type assertion:", x)
+	}
+
+	fmt.Println("mandelbrot:", mandelbrot(0), mandelbrot(1+1i), mandelbrot(-0.75+0.1i))
+}
@@ -0,0 +1,122 @@
+// Generated file based on complex_numbers.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+import * as cmplx from "@goscript/math/cmplx/index.js"
+
+export let rotation: $.Complex = $.complex(0, 1)
+
+export class point {
+	public get pos(): $.Complex {
+		return this._fields.pos.value
+	}
+	public set pos(value: $.Complex) {
+		this._fields.pos.value = value
+	}
+
+	public _fields: {
+		pos: $.VarRef<$.Complex>;
+	}
+
+	constructor(init?: Partial<{pos?: $.Complex}>) {
+		this._fields = {
+			pos: $.varRef(init?.pos ?? $.complex(0, 0))
+		}
+	}
+
+	public clone(): point {
+		const cloned = new point()
+		cloned._fields = {
+			pos: $.varRef(this._fields.pos.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.point',
+	  new point(),
+	  [],
+	  point,
+	  {"pos": { kind: $.TypeKind.Basic, name: "complex128" }}
+	);
+}
+
+export function mandelbrot(c: $.Complex): number {
+	let z = $.complex(0, 0)
+	for (let i = 0; i < 50; i++) {
+		z = $.complexAdd($.complexMul(z, z), c)
+		if (cmplx.Abs(z) > 2) {
+			return i
+		}
+	}
+	return -1
+}
+
+export async function main(): Promise<void> {
+	let a = $.complex(1, 2)
+	let b = $.complex(3, -4)
+	fmt.Println("a:", a, "b:", b)
+	fmt.Println("sum:", $.complexAdd(a, b), "diff:", $.complexSub(a, b))
+	fmt.Println("product:", $.complexMul(a, b), "quotient:", $.complexDiv(a, b))
+	fmt.Println("neg:", $.complexNeg(a))
+	fmt.Println("real:", $.real(b), "imag:", $.imag(b))
+	fmt.Println("equal:", $.complexEquals(a, $.complex(1, 2)), "not equal:", !$.complexEquals(a, b))
+
+	// Compound assignment and rotation
+	let c = a
+	c = $.complexMul(c, $.complex(0, 1))
+	c = $.complexAdd(c, $.complex(1, 0))
+	fmt.Println("rotated:", c)
+
+	// Zero values
+	let z: $.Complex = $.complex(0, 0)
+	let p: point = new point()
+	fmt.Println("zero:", z, $.complexEquals(p.pos, $.complex(0, 0)))
+
+	// complex64 rounds to float32
+	let f: $.Complex = $.complex(0.10000000149011612, 0.5)
+	f = $.complex64($.complexMul(f, $.complex(2, 0)))
+	fmt.Println("complex64:", $.complexEquals(f, $.complex(0.20000000298023224, 1)))
+
+	// Division by zero yields infinities
+	fmt.Println("div zero:", $.complexDiv(a, z))
+
+	// Formatting
+	fmt.Printf("%v %.2f %T\n", a, b, a)
+
+	// math/cmplx
+	fmt.Println("abs:", cmplx.Abs(b))
+	fmt.Println("conj:", cmplx.Conj(a))
+	fmt.Println("sqrt:", cmplx.Sqrt($.complex(-4, 0)))
+	fmt.Println("inf:", cmplx.IsInf(cmplx.Inf()), cmplx.IsNaN(cmplx.NaN()))
+	let [r, theta] = cmplx.Polar($.complex(0, 2))
+	fmt.Printf("polar: %.3f %.3f\n", r, theta)
+
+	// Composite literal elements, keys and fields
+	let roots = $.arrayToSlice<$.Complex>([$.complex(1, 2), $.complex(3, 0), $.complex(0, 1)])
+	fmt.Println("slice:", $.complexAdd(roots![0], roots![1]), roots![2])
+	let small = $.arrayToSlice<$.Complex>([$.complex(0.10000000149011612, 0), $.complex(0, 0), $.complex(1, 0)])
+	fmt.Println("array:", $.complexEquals(small![0], $.complex(0.10000000149011612, 0)), small![1], small![2])
+	let names = new $.HashMap<$.Complex, string>('complex', [[$.complex(1, 0), "one"], [$.complex(0, 1), "i"]])
+	fmt.Println("map:", $.mapGet(names, $.complex(1, 0), "")[0], $.mapGet(names, $.complex(0, 1), "")[0], $.len(names))
+	let scaled = new Map([["half", $.complex(0.5, 0)]])
+	fmt.Println("map value:", $.complexMul($.mapGet(scaled, "half", $.complex(0, 0))[0], $.complex(2, 0)))
+	let q = $.markAsStructValue(new point({pos: $.complex(2, 0)}))
+	fmt.Println("struct:", $.complexMul(q.pos, $.complex(0, 1)), $.markAsStructValue(new point({pos: $.complex(-1, 0)})).pos)
+
+	// Interfaces
+	let v: null | any = a
+	{
+		let { value: x, ok: ok } = $.typeAssert<$.Complex>(v, {kind: $.TypeKind.Basic, name: 'complex128'})
+		if (ok) {
+			fmt.Println("type assertion:", x)
+		}
+	}
+
+	fmt.Println("mandelbrot:", mandelbrot($.complex(0, 0)), mandelbrot($.complex(1, 1)), mandelbrot($.complex(-0.75, 0.1)))
+}
+
@@ -5,53 +5,53 @@ import * as $ from "@goscript/builtin/index.js"
 
 // ignore first value by assigning to blank identifier
 
-export let KB: ByteSize = (1 << (10 * 0))
+export let KB: ByteSize = 1024
 
-export let MB: ByteSize = 0
+export let MB: ByteSize = 1048576
 
-export let GB: ByteSize = 0
+export let GB: ByteSize = 1073741824
 
-export let TB: ByteSize = 0
+export let TB: ByteSize = 1099511627776
 
 export let North: Direction = 0
 
-export let East: Direction = 0
+export let East: Direction = 1
 
-export let South: Direction = 0
+export let South: Direction = 2
 
-export let West: Direction = 0
+export let West: Direction = 3
 
 export let Red: number = 0
 
-export let Green: number = 0
+export let Green: number = 1
 
-export let Blue: number = 0
+export let Blue: number = 2
 
 export let Sunday: number = 0
 
-export let Monday: number = 0
+export let Monday: number = 1
 
-export let Tuesday: number = 0
+export let Tuesday: number = 2
 
-export let Wednesday: number = 0
+export let Wednesday: number = 3
 
-export let Thursday: number = 0
+export let Thursday: number = 4
 
-export let Friday: number = 0
+export let Friday: number = 5
 
-export let Saturday: number = 0
+export let Saturday: number = 6
 
-export let First: number = 0 + 1
+export let First: number = 1
 
-export let Second: number = 0 + 1
+export let Second: number = 2
 
-export let Third: number = 0 + 1
+export let Third: number = 3
 
-export let A: number = 0 * 2
+export let A: number = 0
 
-export let B: number = 0
+export let B: number = 2
 
-export let C: number = 0
+export let C: number = 4
 
 export type ByteSize = number;
 
@@ -33,7 +33,7 @@ export class AsyncResource {
 	public async Release(): Promise<void> {
 		const r = this
 		let ch = $.makeChannel<boolean>(1, false, 'both')
-		queueMicrotask(async () => {
+		$.go(async () => {
 			await $.chanSend(ch, true)
 		})
 		await $.chanRecv(ch)
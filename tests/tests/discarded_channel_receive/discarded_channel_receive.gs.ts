@@ -7,7 +7,7 @@ export async function main(): Promise<void> {
 	let ch = $.makeChannel<number>(0, 0, 'both')
 
 	// Close the channel to allow the main goroutine to exit
-	queueMicrotask(async () => {
+	$.go(async () => {
 		await $.chanSend(ch, 1)
 		ch.close() // Close the channel to allow the main goroutine to exit
 	})
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+//go:embed greeting.txt
+var greeting string
+
+//go:embed static/logo.bin
+var logo []byte
+
+//go:embed static
+var static embed.FS
+
+var (
+	// hidden includes the files starting with '.' or '_'.
+	//go:embed all:static
+	hidden embed.FS
+)
+
+func main() {
+	fmt.Print("greeting: ", greeting)
+	fmt.Println("logo:", len(logo), logo[0], logo[3])
+
+	entries, err := static.ReadDir("static")
+	if err != nil {
+		fmt.Println("ReadDir error:", err)
+	}
+	for _, e := range entries {
+		fmt.Println("entry:", e.Name(), e.IsDir())
+	}
+
+	data, err := static.ReadFile("static/css/site.css")
+	fmt.Printf("site.css: %q %v\n", string(data), err)
+
+	_, err = static.ReadFile("static/missing.txt")
+	fmt.Println("missing:", err)
+
+	_, err = static.ReadFile("static/.cache/state")
+	fmt.Println("hidden file:", err)
+
+	data, err = hidden.ReadFile("static/.cache/state")
+	fmt.Printf("all: %q %v\n", string(data), err)
+
+	_, err = static.ReadFile("static/css")
+	fmt.Println("read dir:", err)
+
+	f, err := static.Open("static/index.html")
+	if err != nil {
+		fmt.Println("Open error:", err)
+		return
+	}
+	info, _ := f.Stat()
+	fmt.Println("stat:", info.Name(), info.Size(), info.IsDir(), info.Mode().String())
+	buf := make([]byte, 5)
+	n, err := f.Read(buf)
+	fmt.Printf("read: %q %v\n", string(buf[:n]), err)
+	rest, err := io.ReadAll(f)
+	fmt.Printf("rest: %q %v\n", string(rest), err)
+	f.Close()
+
+	var fsys fs.FS = static
+	root, err := fs.ReadDir(fsys, ".")
+	fmt.Println("root:", len(root), root[0].Name(), err)
+
+	var empty embed.FS
+	_, err = empty.Open("greeting.txt")
+	fmt.Println("empty:", err)
+}
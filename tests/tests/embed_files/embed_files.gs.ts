@@ -0,0 +1,90 @@
+// Generated file based on embed_files.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as embed from "@goscript/embed/index.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+import * as io from "@goscript/io/index.js"
+
+import * as fs from "@goscript/io/fs/index.js"
+
+export let greeting: string = "Hello, embed!\n"
+
+// hidden includes the files starting with '.' or '_'.
+//go:embed all:static
+export let hidden: embed.FS = new embed.FS([
+	{ name: "static/.cache/state", data: "hidden\n" },
+	{ name: "static/css/site.css", data: "body { color: red; }\n" },
+	{ name: "static/index.html", data: "<h1>index</h1>\n" },
+	{ name: "static/logo.bin", data: new Uint8Array([0, 1, 254, 255]) },
+])
+
+export let logo: $.Bytes = new Uint8Array([0, 1, 254, 255])
+
+export let _static: embed.FS = new embed.FS([
+	{ name: "static/css/site.css", data: "body { color: red; }\n" },
+	{ name: "static/index.html", data: "<h1>index</h1>\n" },
+	{ name: "static/logo.bin", data: new Uint8Array([0, 1, 254, 255]) },
+])
+
+export async function main(): Promise<void> {
+	fmt.Print("greeting: ", greeting)
+	fmt.Println("logo:", $.len(logo), logo![0], logo![3])
+
+	let [entries, err] = _static.ReadDir("static")
+	if (err != null) {
+		fmt.Println("ReadDir error:", err)
+	}
+	for (let _i = 0; _i < $.len(entries); _i++) {
+		let e = entries![_i]
+		{
+			fmt.Println("entry:", e!.Name(), e!.IsDir())
+		}
+	}
+
+	let data: $.Bytes
+	[data, err] = _static.ReadFile("static/css/site.css")
+	fmt.Printf("site.css: %q %v\n", $.bytesToString(data), err)
+
+	;[, err] = _static.ReadFile("static/missing.txt")
+	fmt.Println("missing:", err)
+
+	;[, err] = _static.ReadFile("static/.cache/state")
+	fmt.Println("hidden file:", err)
+
+	;[data, err] = hidden.ReadFile("static/.cache/state")
+	fmt.Printf("all: %q %v\n", $.bytesToString(data), err)
+
+	;[, err] = _static.ReadFile("static/css")
+	fmt.Println("read dir:", err)
+
+	let f: fs.File
+	[f, err] = _static.Open("static/index.html")
+	if (err != null) {
+		fmt.Println("Open error:", err)
+		return 
+	}
+	let [info, ] = f!.Stat()
+	fmt.Println("stat:", info!.Name(), info!.Size(), info!.IsDir(), fs.FileMode_String(info!.Mode()))
+	let buf = new Uint8Array(5)
+	let n: number
+	[n, err] = f!.Read(buf)
+	fmt.Printf("read: %q %v\n", $.bytesToString($.goSlice(buf, undefined, n)), err)
+	let rest: $.Bytes
+	[rest, err] = io.ReadAll(f)
+	fmt.Printf("rest: %q %v\n", $.bytesToString(rest), err)
+	f!.Close()
+
+	let fsys: null | fs.FS = $.markAsStructValue(_static.clone())
+	let root: $.Slice<fs.DirEntry>
+	[root, err] = fs.ReadDir(fsys, ".")
+	fmt.Println("root:", $.len(root), root![0]!.Name(), err)
+
+	let empty: embed.FS = new embed.FS()
+	;[, err] = empty.Open("greeting.txt")
+	fmt.Println("empty:", err)
+}
+
@@ -0,0 +1,90 @@
+package main
+
+// Color is written as the literal union type 0 | 1 | 2 with a const object
+// of its constants, and maps between the values and the names of String.
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func (c Color) String() string {
+	switch c {
+	case Red:
+		return "red"
+	case Green:
+		return "green"
+	case Blue:
+		return "blue"
+	}
+	return "unknown"
+}
+
+// Mode is a string enum.
+type Mode string
+
+const (
+	ModeRead  Mode = "r"
+	ModeWrite Mode = "w"
+)
+
+// Level is computed with arithmetic, so it stays a number.
+type Level int
+
+const (
+	Low Level = iota + 1
+	High
+)
+
+func next(l Level) Level {
+	return l + 1
+}
+
+// Weekday is converted from computed values, so it stays a number.
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+)
+
+func weekday(n int) Weekday {
+	return Weekday(n % 7)
+}
+
+func describe(c Color, m Mode) string {
+	switch m {
+	case ModeRead:
+		return "read " + c.String()
+	case ModeWrite:
+		return "write " + c.String()
+	}
+	return "none"
+}
+
+func main() {
+	c := Blue
+	println(c.String())
+	c = Color(1)
+	println(c.String())
+
+	var zero Color
+	println(zero.String())
+
+	names := map[Color]string{Red: "R", Green: "G"}
+	println(names[Red], names[Green], len(names))
+
+	colors := []Color{Red, Blue}
+	for _, color := range colors {
+		println(describe(color, ModeRead))
+	}
+	println(describe(Green, ModeWrite))
+
+	var m Mode
+	println(m == "", string(ModeWrite))
+
+	println(int(next(High)), int(Low))
+	println(int(weekday(8)), int(Monday))
+}
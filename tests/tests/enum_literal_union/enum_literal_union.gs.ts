@@ -0,0 +1,123 @@
+// Generated file based on enum_literal_union.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+export let Red: Color = 0
+
+export let Green: Color = 1
+
+export let Blue: Color = 2
+
+export let ModeRead: Mode = "r"
+
+export let ModeWrite: Mode = "w"
+
+export let Low: Level = 1
+
+export let High: Level = 2
+
+export let Sunday: Weekday = 0
+
+export let Monday: Weekday = 1
+
+export type Color = 0 | 1 | 2;
+
+export const Color = {
+	Red: 0,
+	Green: 1,
+	Blue: 2,
+} as const;
+
+export const Color_name: ReadonlyMap<Color, string> = new Map<Color, string>([
+	[0, "red"],
+	[1, "green"],
+	[2, "blue"],
+]);
+
+export const Color_value: ReadonlyMap<string, Color> = new Map<string, Color>([
+	["red", 0],
+	["green", 1],
+	["blue", 2],
+]);
+
+export function Color_String(c: Color): string {
+	switch (c) {
+		case 0: {
+			return "red"
+			break
+		}
+		case 1: {
+			return "green"
+			break
+		}
+		case 2: {
+			return "blue"
+			break
+		}
+	}
+	return "unknown"
+}
+
+
+export type Level = number;
+
+export type Mode = "" | "r" | "w";
+
+export const Mode = {
+	ModeRead: "r",
+	ModeWrite: "w",
+} as const;
+
+export type Weekday = number;
+
+export function next(l: Level): Level {
+	return l + 1
+}
+
+export function weekday(n: number): Weekday {
+	return (n % 7 as Weekday)
+}
+
+export function describe(c: Color, m: Mode): string {
+	switch (m) {
+		case "r": {
+			return "read " + Color_String(c)
+			break
+		}
+		case "w": {
+			return "write " + Color_String(c)
+			break
+		}
+	}
+	return "none"
+}
+
+export async function main(): Promise<void> {
+	let c = (2 as Color)
+	$.println(Color_String(c))
+	c = (1 as Color)
+	$.println(Color_String(c))
+
+	let zero: Color = 0
+	$.println(Color_String(zero))
+
+	let names = new Map<Color, string>([[(0 as Color), "R"], [(1 as Color), "G"]])
+	$.println($.mapGet(names, (0 as Color), "")[0], $.mapGet(names, (1 as Color), "")[0], $.len(names))
+
+	let colors = $.arrayToSlice<Color>([(0 as Color), (2 as Color)])
+	for (let _i = 0; _i < $.len(colors); _i++) {
+		let color = colors![_i]
+		{
+			$.println(describe(color, ("r" as Mode)))
+		}
+	}
+	$.println(describe((1 as Color), ("w" as Mode)))
+
+	let m: Mode = ""
+	$.println(m == "", ("w" as Mode))
+
+	$.println(next(2), 1)
+	$.println(weekday(8), 1)
+}
+
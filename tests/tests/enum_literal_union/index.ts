@@ -0,0 +1,3 @@
+export { Blue, Color_String, Color_name, Color_value, Green, High, Low, ModeRead, ModeWrite, Monday, Red, Sunday } from "./enum_literal_union.gs.js"
+export { Color, Mode } from "./enum_literal_union.gs.js"
+export type { Level, Weekday } from "./enum_literal_union.gs.js"
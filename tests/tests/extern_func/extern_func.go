@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The functions below are implemented in host.ts when compiled by goscript.
+// Their Go bodies behave the same when the package is built by the go command.
+
+//goscript:extern "./host.ts" greet
+func Greet(name string) string {
+	return "Hello, " + name + "!"
+}
+
+//goscript:extern "./host.ts" sum
+func Sum(nums ...int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+//goscript:extern "./host.ts" divMod
+func DivMod(a, b int) (q, r int) {
+	return a / b, a % b
+}
+
+//goscript:extern "./host.ts" shout
+func Shout(msg string) {
+	fmt.Println(strings.ToUpper(msg))
+}
+
+//goscript:extern "./host.ts" delay async
+func Delay(ms int) string {
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return fmt.Sprintf("waited %dms", ms)
+}
+
+// wait calls the async Delay, so it is async too.
+func wait() {
+	fmt.Println(Delay(5))
+}
+
+func main() {
+	fmt.Println(Greet("gopher"))
+	fmt.Println(Sum(1, 2, 3, 4))
+	fmt.Println(Sum([]int{10, 20}...))
+
+	q, r := DivMod(17, 5)
+	fmt.Println("divmod:", q, r)
+
+	Shout("extern functions")
+	wait()
+}
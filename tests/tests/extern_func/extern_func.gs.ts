@@ -0,0 +1,54 @@
+// Generated file based on extern_func.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+import * as __extern_host from "./host.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+import * as strings from "@goscript/strings/index.js"
+
+import * as time from "@goscript/time/index.js"
+
+//goscript:extern "./host.ts" greet
+export function Greet(name: string): string {
+	return __extern_host.greet(name)
+}
+
+//goscript:extern "./host.ts" sum
+export function Sum(...nums: number[]): number {
+	return __extern_host.sum(...nums)
+}
+
+//goscript:extern "./host.ts" divMod
+export function DivMod(a: number, b: number): [number, number] {
+	return __extern_host.divMod(a, b)
+}
+
+//goscript:extern "./host.ts" shout
+export function Shout(msg: string): void {
+	__extern_host.shout(msg)
+}
+
+//goscript:extern "./host.ts" delay async
+export async function Delay(ms: number): Promise<string> {
+	return __extern_host.delay(ms)
+}
+
+// wait calls the async Delay, so it is async too.
+export async function wait(): Promise<void> {
+	fmt.Println(await Delay(5))
+}
+
+export async function main(): Promise<void> {
+	fmt.Println(Greet("gopher"))
+	fmt.Println(Sum(1, 2, 3, 4))
+	fmt.Println(Sum(...($.arrayToSlice<number>([10, 20]) ?? [])))
+
+	let [q, r] = DivMod(17, 5)
+	fmt.Println("divmod:", q, r)
+
+	Shout("extern functions")
+	await wait()
+}
+
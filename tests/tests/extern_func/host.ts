@@ -0,0 +1,22 @@
+// Implementations of the //goscript:extern functions of extern_func.go.
+
+export function greet(name: string): string {
+  return `Hello, ${name}!`
+}
+
+export function sum(...nums: number[]): number {
+  return nums.reduce((total, n) => total + n, 0)
+}
+
+export function divMod(a: number, b: number): [number, number] {
+  return [Math.trunc(a / b), a % b]
+}
+
+export function shout(msg: string): void {
+  console.log(msg.toUpperCase())
+}
+
+export async function delay(ms: number): Promise<string> {
+  await new Promise((resolve) => setTimeout(resolve, ms))
+  return `waited ${ms}ms`
+}
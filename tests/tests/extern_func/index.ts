@@ -0,0 +1 @@
+export { Delay, DivMod, Greet, Shout, Sum } from "./extern_func.gs.js"
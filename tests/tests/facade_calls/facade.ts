@@ -0,0 +1,113 @@
+// Facade of the Go package github.com/aperturerobotics/goscript/tests/tests/facade_calls using plain TypeScript values.
+// Errors returned by the functions are thrown as a $.GoErrorException.
+
+import * as $ from "@goscript/builtin/index.js"
+import * as context from "@goscript/context/index.js"
+import * as main from "./index.js"
+
+// Path is a main.Path as a plain object.
+export interface Path {
+	Name: string
+	Points: Point[]
+	Tags: Map<string, number>
+}
+
+// toGoPath converts the plain object v to a main.Path.
+export function toGoPath(v: Path): main.Path {
+	return new main.Path({
+		Name: v.Name,
+		Points: $.arrayToSlice(v.Points.map((x) => $.markAsStructValue(toGoPoint(x)))),
+		Tags: v.Tags,
+	})
+}
+
+// fromGoPath converts v to a plain object.
+export function fromGoPath(v: main.Path): Path {
+	return {
+		Name: v.Name,
+		Points: $.asArray(v.Points).map((x) => fromGoPoint(x)),
+		Tags: v.Tags ?? new Map(),
+	}
+}
+
+// Point is a main.Point as a plain object.
+export interface Point {
+	X: number
+	Y: number
+}
+
+// toGoPoint converts the plain object v to a main.Point.
+export function toGoPoint(v: Point): main.Point {
+	return new main.Point({
+		X: v.X,
+		Y: v.Y,
+	})
+}
+
+// fromGoPoint converts v to a plain object.
+export function fromGoPoint(v: main.Point): Point {
+	return {
+		X: v.X,
+		Y: v.Y,
+	}
+}
+
+export function Add64(a: number, b: number): number {
+	return main.Add64(a, b)
+}
+
+export function Bump(c: main.Counter): number {
+	return main.Bump($.markAsStructValue(c.clone()))
+}
+
+export function Centroid(...points: Point[]): Point {
+	const [_r0, _err] = main.Centroid(...points.map((x) => $.markAsStructValue(toGoPoint(x))))
+	if (_err !== null) {
+		throw new $.GoErrorException(_err)
+	}
+	return fromGoPoint(_r0)
+}
+
+export function Check(name: string, signal?: AbortSignal): void {
+	const _err = main.Check(context.fromAbortSignal(signal), name)
+	if (_err !== null) {
+		throw new $.GoErrorException(_err)
+	}
+}
+
+export function DivMod(a: number, b: number): [number, number] {
+	const [_r0, _r1, _err] = main.DivMod(a, b)
+	if (_err !== null) {
+		throw new $.GoErrorException(_err)
+	}
+	return [_r0, _r1]
+}
+
+export function Find(paths: Path[], name: string): Path | null {
+	const _r0 = main.Find($.arrayToSlice(paths.map((x) => $.markAsStructValue(toGoPath(x)))), name)
+	return _r0 === null ? null : fromGoPath(_r0)
+}
+
+export function Grow(p: Path): Path {
+	const _r0 = main.Grow($.markAsStructValue(toGoPath(p)))
+	return fromGoPath(_r0)
+}
+
+export function NewCounter(start: number): main.Counter | null {
+	return main.NewCounter(start)
+}
+
+export function Reverse(b: Uint8Array): Uint8Array {
+	const _r0 = main.Reverse(b)
+	return $.bytesToUint8Array(_r0)
+}
+
+export function Scale(p: Point, f: number): Point {
+	const _r0 = main.Scale($.markAsStructValue(toGoPoint(p)), f)
+	return fromGoPoint(_r0)
+}
+
+export function Words(s: string): string[] {
+	const _r0 = main.Words(s)
+	return $.asArray(_r0)
+}
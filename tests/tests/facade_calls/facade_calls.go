@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Point is converted to a plain object by the facade.
+type Point struct {
+	X int
+	Y int
+}
+
+// Path has a slice of plain structs and a map.
+type Path struct {
+	Name   string
+	Points []Point
+	Tags   map[string]int
+}
+
+// Counter has an unexported field: the facade passes instances of its class.
+type Counter struct {
+	n int
+}
+
+// Add adds n to the counter and returns its value.
+func (c *Counter) Add(n int) int {
+	c.n += n
+	return c.n
+}
+
+// Scale multiplies the coordinates of p by f.
+func Scale(p Point, f int) Point {
+	return Point{X: p.X * f, Y: p.Y * f}
+}
+
+// Centroid returns the centroid of the points.
+func Centroid(points ...Point) (Point, error) {
+	if len(points) == 0 {
+		return Point{}, errors.New("no points")
+	}
+	var c Point
+	for _, p := range points {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	return Point{X: c.X / len(points), Y: c.Y / len(points)}, nil
+}
+
+// DivMod returns the quotient and remainder of a divided by b.
+func DivMod(a, b int) (int, int, error) {
+	if b == 0 {
+		return 0, 0, errors.New("division by zero")
+	}
+	return a / b, a % b, nil
+}
+
+// Reverse returns the bytes of b in reverse order.
+func Reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, c := range b {
+		r[len(b)-1-i] = c
+	}
+	return r
+}
+
+// Words splits s around spaces.
+func Words(s string) []string {
+	return strings.Fields(s)
+}
+
+// Find returns the path with the name, nil if there is none.
+func Find(paths []Path, name string) *Path {
+	for i := range paths {
+		if paths[i].Name == name {
+			return &paths[i]
+		}
+	}
+	return nil
+}
+
+// Grow appends a point to the path and moves its first point.
+func Grow(p Path) Path {
+	p.Points = append(p.Points, Point{X: 9, Y: 9})
+	p.Points[0].X = 100
+	return p
+}
+
+// NewCounter returns a counter starting at start.
+func NewCounter(start int) *Counter {
+	return &Counter{n: start}
+}
+
+// Bump adds one to a copy of the counter.
+func Bump(c Counter) int {
+	c.n++
+	return c.n
+}
+
+// Check returns an error if the context is done.
+func Check(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return errors.New(name + ": " + err.Error())
+	}
+	return nil
+}
+
+// Add64 adds two int64 values, numbers in TypeScript without --int64-bigint.
+func Add64(a, b int64) int64 {
+	return a + b
+}
+
+func main() {
+	fmt.Println(scale())
+	fmt.Println(centroid())
+	fmt.Println(divMod())
+	fmt.Println(bytes())
+	fmt.Println(words())
+	fmt.Println(paths())
+	fmt.Println(grow())
+	fmt.Println(counter())
+	fmt.Println(check())
+	fmt.Println(add64())
+}
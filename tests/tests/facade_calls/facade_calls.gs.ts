@@ -0,0 +1,264 @@
+// Generated file based on facade_calls.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+import { add64, bytes, centroid, check, counter, divMod, grow, paths, scale, words } from "./facade_calls_js.gs.js";
+
+import * as context from "@goscript/context/index.js"
+
+import * as errors from "@goscript/errors/index.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+import * as strings from "@goscript/strings/index.js"
+
+export class Counter {
+	public get n(): number {
+		return this._fields.n.value
+	}
+	public set n(value: number) {
+		this._fields.n.value = value
+	}
+
+	public _fields: {
+		n: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{n?: number}>) {
+		this._fields = {
+			n: $.varRef(init?.n ?? 0)
+		}
+	}
+
+	public clone(): Counter {
+		const cloned = new Counter()
+		cloned._fields = {
+			n: $.varRef(this._fields.n.value)
+		}
+		return cloned
+	}
+
+	// Add adds n to the counter and returns its value.
+	public Add(n: number): number {
+		const c = this
+		c.n += n
+		return c.n
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Counter',
+	  new Counter(),
+	  [{ name: "Add", args: [{ name: "n", type: { kind: $.TypeKind.Basic, name: "int" } }], returns: [{ type: { kind: $.TypeKind.Basic, name: "int" } }] }],
+	  Counter,
+	  {"n": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export class Point {
+	public get X(): number {
+		return this._fields.X.value
+	}
+	public set X(value: number) {
+		this._fields.X.value = value
+	}
+
+	public get Y(): number {
+		return this._fields.Y.value
+	}
+	public set Y(value: number) {
+		this._fields.Y.value = value
+	}
+
+	public _fields: {
+		X: $.VarRef<number>;
+		Y: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{X?: number, Y?: number}>) {
+		this._fields = {
+			X: $.varRef(init?.X ?? 0),
+			Y: $.varRef(init?.Y ?? 0)
+		}
+	}
+
+	public clone(): Point {
+		const cloned = new Point()
+		cloned._fields = {
+			X: $.varRef(this._fields.X.value),
+			Y: $.varRef(this._fields.Y.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Point',
+	  new Point(),
+	  [],
+	  Point,
+	  {"X": { kind: $.TypeKind.Basic, name: "int" }, "Y": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export class Path {
+	public get Name(): string {
+		return this._fields.Name.value
+	}
+	public set Name(value: string) {
+		this._fields.Name.value = value
+	}
+
+	public get Points(): $.Slice<Point> {
+		return this._fields.Points.value
+	}
+	public set Points(value: $.Slice<Point>) {
+		this._fields.Points.value = value
+	}
+
+	public get Tags(): Map<string, number> | null {
+		return this._fields.Tags.value
+	}
+	public set Tags(value: Map<string, number> | null) {
+		this._fields.Tags.value = value
+	}
+
+	public _fields: {
+		Name: $.VarRef<string>;
+		Points: $.VarRef<$.Slice<Point>>;
+		Tags: $.VarRef<Map<string, number> | null>;
+	}
+
+	constructor(init?: Partial<{Name?: string, Points?: $.Slice<Point>, Tags?: Map<string, number> | null}>) {
+		this._fields = {
+			Name: $.varRef(init?.Name ?? ""),
+			Points: $.varRef(init?.Points ?? null),
+			Tags: $.varRef(init?.Tags ?? null)
+		}
+	}
+
+	public clone(): Path {
+		const cloned = new Path()
+		cloned._fields = {
+			Name: $.varRef(this._fields.Name.value),
+			Points: $.varRef(this._fields.Points.value),
+			Tags: $.varRef(this._fields.Tags.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Path',
+	  new Path(),
+	  [],
+	  Path,
+	  {"Name": { kind: $.TypeKind.Basic, name: "string" }, "Points": { kind: $.TypeKind.Slice, elemType: "Point" }, "Tags": { kind: $.TypeKind.Map, keyType: { kind: $.TypeKind.Basic, name: "string" }, elemType: { kind: $.TypeKind.Basic, name: "int" } }}
+	);
+}
+
+// Scale multiplies the coordinates of p by f.
+export function Scale(p: Point, f: number): Point {
+	return $.markAsStructValue(new Point({X: p.X * f, Y: p.Y * f}))
+}
+
+// Centroid returns the centroid of the points.
+export function Centroid(...points: Point[]): [Point, $.GoError] {
+	if ($.len(points) == 0) {
+		return [$.markAsStructValue(new Point({})), errors.New("no points")]
+	}
+	let c: Point = new Point()
+	for (let _i = 0; _i < $.len(points); _i++) {
+		let p = points![_i]
+		{
+			c.X += p.X
+			c.Y += p.Y
+		}
+	}
+	return [$.markAsStructValue(new Point({X: Math.trunc(c.X / $.len(points)), Y: Math.trunc(c.Y / $.len(points))})), null]
+}
+
+// DivMod returns the quotient and remainder of a divided by b.
+export function DivMod(a: number, b: number): [number, number, $.GoError] {
+	if (b == 0) {
+		return [0, 0, errors.New("division by zero")]
+	}
+	return [Math.trunc(a / b), a % b, null]
+}
+
+// Reverse returns the bytes of b in reverse order.
+export function Reverse(b: $.Bytes): $.Bytes {
+	let r = new Uint8Array($.len(b))
+	for (let i = 0; i < $.len(b); i++) {
+		let c = b![i]
+		{
+			r![$.len(b) - 1 - i] = c
+		}
+	}
+	return r
+}
+
+// Words splits s around spaces.
+export function Words(s: string): $.Slice<string> {
+	return strings.Fields(s)
+}
+
+// Find returns the path with the name, nil if there is none.
+export function Find(paths: $.Slice<Path>, name: string): Path | null {
+	for (let i = 0; i < $.len(paths); i++) {
+		{
+			if (paths![i].Name == name) {
+				return paths![i]
+			}
+		}
+	}
+	return null
+}
+
+// Grow appends a point to the path and moves its first point.
+export function Grow(p: Path): Path {
+	p.Points = $.append(p.Points, $.markAsStructValue(new Point({X: 9, Y: 9})))
+	p.Points![0].X = 100
+	return p
+}
+
+// NewCounter returns a counter starting at start.
+export function NewCounter(start: number): Counter | null {
+	return new Counter({n: start})
+}
+
+// Bump adds one to a copy of the counter.
+export function Bump(c: Counter): number {
+	c.n++
+	return c.n
+}
+
+// Check returns an error if the context is done.
+export function Check(ctx: null | context.Context, name: string): $.GoError {
+	{
+		let err = ctx!.Err()
+		if (err != null) {
+			return errors.New(name + ": " + err!.Error())
+		}
+	}
+	return null
+}
+
+// Add64 adds two int64 values, numbers in TypeScript without --int64-bigint.
+export function Add64(a: number, b: number): number {
+	return a + b
+}
+
+export async function main(): Promise<void> {
+	fmt.Println(scale())
+	fmt.Println(centroid())
+	fmt.Println(divMod())
+	fmt.Println(bytes())
+	fmt.Println(words())
+	fmt.Println(paths())
+	fmt.Println(grow())
+	fmt.Println(counter())
+	fmt.Println(check())
+	fmt.Println(add64())
+}
+
@@ -0,0 +1,35 @@
+package main
+
+// The functions below call the facade of the package in host.ts and describe
+// the results. facade_calls_native.go describes the results of the Go
+// functions the same way.
+
+//goscript:extern "./host.ts"
+func scale() string
+
+//goscript:extern "./host.ts"
+func centroid() string
+
+//goscript:extern "./host.ts"
+func divMod() string
+
+//goscript:extern "./host.ts"
+func bytes() string
+
+//goscript:extern "./host.ts"
+func words() string
+
+//goscript:extern "./host.ts"
+func paths() string
+
+//goscript:extern "./host.ts"
+func grow() string
+
+//goscript:extern "./host.ts"
+func counter() string
+
+//goscript:extern "./host.ts"
+func check() string
+
+//goscript:extern "./host.ts"
+func add64() string
@@ -0,0 +1,56 @@
+// Generated file based on facade_calls_js.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+import * as __extern_host from "./host.js"
+
+//goscript:extern "./host.ts"
+export function scale(): string {
+	return __extern_host.scale()
+}
+
+//goscript:extern "./host.ts"
+export function centroid(): string {
+	return __extern_host.centroid()
+}
+
+//goscript:extern "./host.ts"
+export function divMod(): string {
+	return __extern_host.divMod()
+}
+
+//goscript:extern "./host.ts"
+export function bytes(): string {
+	return __extern_host.bytes()
+}
+
+//goscript:extern "./host.ts"
+export function words(): string {
+	return __extern_host.words()
+}
+
+//goscript:extern "./host.ts"
+export function paths(): string {
+	return __extern_host.paths()
+}
+
+//goscript:extern "./host.ts"
+export function grow(): string {
+	return __extern_host.grow()
+}
+
+//goscript:extern "./host.ts"
+export function counter(): string {
+	return __extern_host.counter()
+}
+
+//goscript:extern "./host.ts"
+export function check(): string {
+	return __extern_host.check()
+}
+
+//goscript:extern "./host.ts"
+export function add64(): string {
+	return __extern_host.add64()
+}
+
@@ -0,0 +1,87 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// show encodes v in JSON like JSON.stringify writes the plain values of the
+// facade.
+func show(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// thrown describes the error like the $.GoErrorException the facade throws.
+func thrown(err error) string {
+	return "GoErrorException: " + err.Error()
+}
+
+func scale() string {
+	return show(Scale(Point{X: 1, Y: 2}, 3))
+}
+
+func centroid() string {
+	c, _ := Centroid(Point{X: 0, Y: 0}, Point{X: 4, Y: 2}, Point{X: 2, Y: 7})
+	_, err := Centroid()
+	return show(c) + " " + thrown(err)
+}
+
+func divMod() string {
+	q, r, _ := DivMod(17, 5)
+	_, _, err := DivMod(1, 0)
+	return show([]int{q, r}) + " " + thrown(err)
+}
+
+func bytes() string {
+	r := Reverse([]byte{1, 2, 3})
+	parts := make([]string, len(r))
+	for i, b := range r {
+		parts[i] = fmt.Sprint(b)
+	}
+	return "Uint8Array " + strings.Join(parts, ",")
+}
+
+func words() string {
+	return show(Words("  go script  facade "))
+}
+
+func paths() string {
+	ps := []Path{
+		{Name: "a", Points: []Point{{X: 1, Y: 1}}, Tags: map[string]int{"k": 1}},
+		{Name: "b", Points: []Point{{X: 2, Y: 2}, {X: 3, Y: 3}}, Tags: map[string]int{}},
+	}
+	return show(Find(ps, "b")) + " " + show(Find(ps, "c"))
+}
+
+func grow() string {
+	p := Path{Name: "p", Points: []Point{{X: 1, Y: 2}}, Tags: map[string]int{"k": 1}}
+	before := show(p)
+	// The facade passes a copy of the points
+	g := Grow(Path{Name: p.Name, Points: append([]Point(nil), p.Points...), Tags: p.Tags})
+	return before + " " + show(g) + " " + show(p)
+}
+
+func counter() string {
+	c := NewCounter(1)
+	added := c.Add(2)
+	bumped := Bump(*c)
+	return fmt.Sprint(added, bumped, c.Add(0))
+}
+
+func check() string {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return fmt.Sprint(Check(context.Background(), "running") == nil) + " " + thrown(Check(ctx, "stopped"))
+}
+
+func add64() string {
+	return "number " + fmt.Sprint(Add64(1<<40, 1))
+}
@@ -0,0 +1,89 @@
+// Implementations of the //goscript:extern functions of facade_calls_js.go,
+// calling the functions of the package through its facade.
+
+import * as facade from './facade.js'
+
+// show encodes v in JSON, with maps as objects.
+function show(v: unknown): string {
+  return JSON.stringify(v, (_key, value) =>
+    value instanceof Map ? Object.fromEntries(value) : value,
+  )
+}
+
+// thrown describes the exception thrown by fn.
+function thrown(fn: () => unknown): string {
+  try {
+    fn()
+    return 'no exception'
+  } catch (e) {
+    return `${(e as Error).name}: ${(e as Error).message}`
+  }
+}
+
+export function scale(): string {
+  return show(facade.Scale({ X: 1, Y: 2 }, 3))
+}
+
+export function centroid(): string {
+  const c = facade.Centroid({ X: 0, Y: 0 }, { X: 4, Y: 2 }, { X: 2, Y: 7 })
+  return show(c) + ' ' + thrown(() => facade.Centroid())
+}
+
+export function divMod(): string {
+  return show(facade.DivMod(17, 5)) + ' ' + thrown(() => facade.DivMod(1, 0))
+}
+
+export function bytes(): string {
+  const r = facade.Reverse(new Uint8Array([1, 2, 3]))
+  return r.constructor.name + ' ' + Array.from(r).join(',')
+}
+
+export function words(): string {
+  const w = facade.Words('  go script  facade ')
+  return Array.isArray(w) ? show(w) : 'not an array'
+}
+
+export function paths(): string {
+  const ps: facade.Path[] = [
+    { Name: 'a', Points: [{ X: 1, Y: 1 }], Tags: new Map([['k', 1]]) },
+    {
+      Name: 'b',
+      Points: [
+        { X: 2, Y: 2 },
+        { X: 3, Y: 3 },
+      ],
+      Tags: new Map(),
+    },
+  ]
+  return show(facade.Find(ps, 'b')) + ' ' + show(facade.Find(ps, 'c'))
+}
+
+export function grow(): string {
+  const p: facade.Path = {
+    Name: 'p',
+    Points: [{ X: 1, Y: 2 }],
+    Tags: new Map([['k', 1]]),
+  }
+  const before = show(p)
+  return before + ' ' + show(facade.Grow(p)) + ' ' + show(p)
+}
+
+export function counter(): string {
+  const c = facade.NewCounter(1)!
+  const added = c.Add(2)
+  const bumped = facade.Bump(c)
+  return `${added} ${bumped} ${c.Add(0)}`
+}
+
+export function check(): string {
+  facade.Check('running')
+  return (
+    'true ' +
+    thrown(() => facade.Check('stopped', AbortSignal.abort()))
+  )
+}
+
+export function add64(): string {
+  const n = facade.Add64(2 ** 40, 1)
+  return `${typeof n} ${n}`
+}
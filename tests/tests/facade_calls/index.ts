@@ -0,0 +1,2 @@
+export { Add64, Bump, Centroid, Check, DivMod, Find, Grow, NewCounter, Reverse, Scale, Words } from "./facade_calls.gs.js"
+export { Counter, Path, Point } from "./facade_calls.gs.js"
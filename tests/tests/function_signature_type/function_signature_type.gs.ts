@@ -70,7 +70,7 @@ export async function main(): Promise<void> {
 		if (a > 0) {
 			return [true, null]
 		}
-		return [false, NewMyError("a was not positive")]
+		return [false, $.typedNilOf(NewMyError("a was not positive"), "*main.MyError")]
 	}
 
 	fn2 = (p0: number, p1: string): boolean => {
@@ -0,0 +1,126 @@
+package main
+
+type Point struct {
+	X, Y int
+}
+
+type Celsius int
+
+func (c Celsius) String() string {
+	if c < 0 {
+		return "cold"
+	}
+	return "warm"
+}
+
+type Stringer interface {
+	String() string
+}
+
+// Zero returns the zero value of T.
+func Zero[T any]() T {
+	var zero T
+	return zero
+}
+
+// NewOf returns a pointer to a new zero value of T.
+func NewOf[T any]() *T {
+	return new(T)
+}
+
+// Filled returns a slice of n zero values of T.
+func Filled[T any](n int) []T {
+	return make([]T, n)
+}
+
+// Equal compares two values of T with Go semantics.
+func Equal[T comparable](a, b T) bool {
+	return a == b
+}
+
+// Index returns the index of v in s or -1.
+func Index[T comparable](s []T, v T) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Count counts the occurrences of each value in s.
+func Count[K comparable](s []K) map[K]int {
+	m := make(map[K]int)
+	for _, k := range s {
+		m[k] = m[k] + 1
+	}
+	return m
+}
+
+// Lookup returns the value stored under k, or the zero value.
+func Lookup[K comparable, V any](m map[K]V, k K) V {
+	return m[k]
+}
+
+// Describe calls String on v.
+func Describe[T Stringer](v T) string {
+	return v.String()
+}
+
+// DescribeAll calls Describe on each element of s.
+func DescribeAll[T Stringer](s []T) []string {
+	var out []string
+	for _, v := range s {
+		out = append(out, Describe(v))
+	}
+	return out
+}
+
+// ZeroPair returns the zero values of both type parameters through Zero.
+func ZeroPair[A, B any]() (A, B) {
+	return Zero[A](), Zero[B]()
+}
+
+func main() {
+	println("Zero[int]:", Zero[int]())
+	println("Zero[string]:", Zero[string]() == "")
+	p := Zero[Point]()
+	println("Zero[Point]:", p.X, p.Y)
+	println("Zero[Celsius]:", Zero[Celsius]().String())
+
+	ip := NewOf[int]()
+	*ip = 5
+	println("NewOf[int]:", *ip)
+	pp := NewOf[Point]()
+	pp.X = 3
+	println("NewOf[Point]:", pp.X, pp.Y)
+
+	ss := Filled[string](3)
+	println("Filled[string]:", len(ss), ss[0] == "")
+	ps := Filled[Point](2)
+	ps[0].X = 1
+	println("Filled[Point]:", ps[0].X, ps[1].X)
+
+	println("Equal ints:", Equal(1, 1), Equal(1, 2))
+	println("Equal points:", Equal(Point{1, 2}, Point{1, 2}), Equal(Point{1, 2}, Point{2, 1}))
+	println("Index point:", Index([]Point{{1, 1}, {2, 2}, {3, 3}}, Point{2, 2}))
+
+	counts := Count([]Point{{1, 1}, {2, 2}, {1, 1}})
+	println("Count points:", len(counts), counts[Point{1, 1}], counts[Point{2, 2}])
+
+	println("Lookup missing:", Lookup(map[string]int{"a": 1}, "b"))
+	lp := Lookup(map[int]Point{1: {4, 5}}, 2)
+	println("Lookup missing point:", lp.X, lp.Y)
+
+	println("Describe:", Describe(Celsius(-3)), Describe(Celsius(20)))
+	descs := DescribeAll([]Celsius{-1, 1})
+	for _, s := range descs {
+		println("DescribeAll:", s)
+	}
+
+	a, b := ZeroPair[int, string]()
+	println("ZeroPair:", a, b == "")
+
+	eq := Equal[Point]
+	println("func value:", eq(Point{1, 1}, Point{1, 1}))
+}
@@ -0,0 +1,191 @@
+// Generated file based on generic_type_dicts.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+export type Celsius = number;
+
+export function Celsius_String(c: Celsius): string {
+	if (c < 0) {
+		return "cold"
+	}
+	return "warm"
+}
+
+
+export class Point {
+	public get X(): number {
+		return this._fields.X.value
+	}
+	public set X(value: number) {
+		this._fields.X.value = value
+	}
+
+	public get Y(): number {
+		return this._fields.Y.value
+	}
+	public set Y(value: number) {
+		this._fields.Y.value = value
+	}
+
+	public _fields: {
+		X: $.VarRef<number>;
+		Y: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{X?: number, Y?: number}>) {
+		this._fields = {
+			X: $.varRef(init?.X ?? 0),
+			Y: $.varRef(init?.Y ?? 0)
+		}
+	}
+
+	public clone(): Point {
+		const cloned = new Point()
+		cloned._fields = {
+			X: $.varRef(this._fields.X.value),
+			Y: $.varRef(this._fields.Y.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Point',
+	  new Point(),
+	  [],
+	  Point,
+	  {"X": { kind: $.TypeKind.Basic, name: "int" }, "Y": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export type Stringer = null | {
+	String(): string
+}
+
+$.registerInterfaceType(
+  'main.Stringer',
+  null, // Zero value for interface is null
+  [{ name: "String", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }]
+);
+
+// Zero returns the zero value of T.
+export function Zero<T extends any>($T: $.TypeDict<T>): T {
+	let zero: T = $T.zero()
+	return zero
+}
+
+// NewOf returns a pointer to a new zero value of T.
+export function NewOf<T extends any>($T: $.TypeDict<T>): T | null {
+	return $.newOf($T)
+}
+
+// Filled returns a slice of n zero values of T.
+export function Filled<T extends any>($T: $.TypeDict<T>, n: number): $.Slice<T> {
+	return $.makeSlice<T>(n, undefined, $T.zero)
+}
+
+// Equal compares two values of T with Go semantics.
+export function Equal<T extends $.Comparable>($T: $.TypeDict<T>, a: T, b: T): boolean {
+	return $.dictEquals($T, a, b)
+}
+
+// Index returns the index of v in s or -1.
+export function Index<T extends $.Comparable>($T: $.TypeDict<T>, s: $.Slice<T>, v: T): number {
+	for (let i = 0; i < $.len(s); i++) {
+		let e = s![i]
+		{
+			if ($.dictEquals($T, e, v)) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Count counts the occurrences of each value in s.
+export function Count<K extends $.Comparable>($K: $.TypeDict<K>, s: $.Slice<K>): Map<K, number> | null {
+	let m = $.makeMap<K, number>($K.key)
+	for (let _i = 0; _i < $.len(s); _i++) {
+		let k = s![_i]
+		{
+			$.mapSet(m, k, $.mapGet(m, k, 0)[0] + 1)
+		}
+	}
+	return m
+}
+
+// Lookup returns the value stored under k, or the zero value.
+export function Lookup<K extends $.Comparable, V extends any>($V: $.TypeDict<V>, m: Map<K, V> | null, k: K): V {
+	return $.mapGet(m, k, $V.zero())[0]
+}
+
+// Describe calls String on v.
+export function Describe<T extends Stringer>($T: $.TypeDict<T>, v: T): string {
+	return $.callMethod($T, v, "String")
+}
+
+// DescribeAll calls Describe on each element of s.
+export function DescribeAll<T extends Stringer>($T: $.TypeDict<T>, s: $.Slice<T>): $.Slice<string> {
+	let out: $.Slice<string> = null
+	for (let _i = 0; _i < $.len(s); _i++) {
+		let v = s![_i]
+		{
+			out = $.append(out, Describe<T>($T, v))
+		}
+	}
+	return out
+}
+
+// ZeroPair returns the zero values of both type parameters through Zero.
+export function ZeroPair<A extends any, B extends any>($A: $.TypeDict<A>, $B: $.TypeDict<B>): [A, B] {
+	return [Zero<A>($A), Zero<B>($B)]
+}
+
+export async function main(): Promise<void> {
+	$.println("Zero[int]:", Zero<number>($.typeDict<number>(() => 0)))
+	$.println("Zero[string]:", Zero<string>($.typeDict<string>(() => "")) == "")
+	let p = $.markAsStructValue(Zero<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } })).clone())
+	$.println("Zero[Point]:", p.X, p.Y)
+	$.println("Zero[Celsius]:", Celsius_String(Zero<Celsius>($.typeDict<Celsius>(() => 0, 'value', { String: Celsius_String }))))
+
+	let ip = NewOf<number>($.typeDict<number>(() => 0))
+	ip!.value = 5
+	$.println("NewOf[int]:", ip!.value)
+	let pp = NewOf<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }))
+	pp!.X = 3
+	$.println("NewOf[Point]:", pp!.X, pp!.Y)
+
+	let ss = Filled<string>($.typeDict<string>(() => ""), 3)
+	$.println("Filled[string]:", $.len(ss), ss![0] == "")
+	let ps = Filled<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }), 2)
+	ps![0].X = 1
+	$.println("Filled[Point]:", ps![0].X, ps![1].X)
+
+	$.println("Equal ints:", Equal<number>($.typeDict<number>(() => 0), 1, 1), Equal<number>($.typeDict<number>(() => 0), 1, 2))
+	$.println("Equal points:", Equal<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }), $.markAsStructValue(new Point({X: 1, Y: 2})), $.markAsStructValue(new Point({X: 1, Y: 2}))), Equal<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }), $.markAsStructValue(new Point({X: 1, Y: 2})), $.markAsStructValue(new Point({X: 2, Y: 1}))))
+	$.println("Index point:", Index<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }), $.arrayToSlice<Point>([$.markAsStructValue(new Point({X: 1, Y: 1})), $.markAsStructValue(new Point({X: 2, Y: 2})), $.markAsStructValue(new Point({X: 3, Y: 3}))]), $.markAsStructValue(new Point({X: 2, Y: 2}))))
+
+	let counts = Count<Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }), $.arrayToSlice<Point>([$.markAsStructValue(new Point({X: 1, Y: 1})), $.markAsStructValue(new Point({X: 2, Y: 2})), $.markAsStructValue(new Point({X: 1, Y: 1}))]))
+	$.println("Count points:", $.len(counts), $.mapGet(counts, $.markAsStructValue(new Point({X: 1, Y: 1})), 0)[0], $.mapGet(counts, $.markAsStructValue(new Point({X: 2, Y: 2})), 0)[0])
+
+	$.println("Lookup missing:", Lookup<string, number>($.typeDict<number>(() => 0), new Map([["a", 1]]), "b"))
+	let lp = $.markAsStructValue(Lookup<number, Point>($.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }), new Map([[1, $.markAsStructValue(new Point({X: 4, Y: 5}))]]), 2).clone())
+	$.println("Lookup missing point:", lp.X, lp.Y)
+
+	$.println("Describe:", Describe<any>($.typeDict<Celsius>(() => 0, 'value', { String: Celsius_String }), (-3 as Celsius)), Describe<any>($.typeDict<Celsius>(() => 0, 'value', { String: Celsius_String }), (20 as Celsius)))
+	let descs = DescribeAll<any>($.typeDict<Celsius>(() => 0, 'value', { String: Celsius_String }), $.arrayToSlice<Celsius>([-1, 1]))
+	for (let _i = 0; _i < $.len(descs); _i++) {
+		let s = descs![_i]
+		{
+			$.println("DescribeAll:", s)
+		}
+	}
+
+	let [a, b] = ZeroPair<number, string>($.typeDict<number>(() => 0), $.typeDict<string>(() => ""))
+	$.println("ZeroPair:", a, b == "")
+
+	let eq = (Equal<Point>).bind(null, $.typeDict<Point>(() => new Point(), { fields: { "X": 'value', "Y": 'value' } }))
+	$.println("func value:", eq!($.markAsStructValue(new Point({X: 1, Y: 1})), $.markAsStructValue(new Point({X: 1, Y: 1}))))
+}
+
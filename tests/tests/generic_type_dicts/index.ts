@@ -0,0 +1,3 @@
+export { Celsius_String, Count, Describe, DescribeAll, Equal, Filled, Index, Lookup, NewOf, Zero, ZeroPair } from "./generic_type_dicts.gs.js"
+export { Point } from "./generic_type_dicts.gs.js"
+export type { Celsius, Stringer } from "./generic_type_dicts.gs.js"
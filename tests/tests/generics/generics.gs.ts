@@ -60,8 +60,8 @@ export function printVal<T extends any>(val: T): void {
 }
 
 // Generic function with comparable constraint
-export function equal<T extends $.Comparable>(a: T, b: T): boolean {
-	return a == b
+export function equal<T extends $.Comparable>($T: $.TypeDict<T>, a: T, b: T): boolean {
+	return $.dictEquals($T, a, b)
 }
 
 // Generic function with union constraint
@@ -88,10 +88,10 @@ export async function main(): Promise<void> {
 
 	// Test comparable constraint
 	$.println("=== Comparable Constraint ===")
-	$.println(equal(1, 1))
-	$.println(equal(1, 2))
-	$.println(equal("hello", "hello"))
-	$.println(equal("hello", "world"))
+	$.println(equal<number>($.typeDict<number>(() => 0), 1, 1))
+	$.println(equal<number>($.typeDict<number>(() => 0), 1, 2))
+	$.println(equal<string>($.typeDict<string>(() => ""), "hello", "hello"))
+	$.println(equal<string>($.typeDict<string>(() => ""), "hello", "world"))
 
 	// Test union constraint with string
 	$.println("=== Union Constraint ===")
@@ -4,9 +4,9 @@
 import * as $ from "@goscript/builtin/index.js"
 
 // leadingInt consumes the leading [0-9]* from s.
-export function leadingInt<bytes extends $.Bytes | string>(s: bytes): [number, bytes, boolean] {
+export function leadingInt<bytes extends $.Bytes | string>($bytes: $.TypeDict<bytes>, s: bytes): [number, bytes, boolean] {
 	let x: number = 0
-	let rem: bytes = null as any
+	let rem: bytes = $bytes.zero()
 	let err: boolean = false
 	{
 		let i = 0
@@ -38,20 +38,20 @@ export function leadingInt<bytes extends $.Bytes | string>(s: bytes): [number, b
 }
 
 export async function main(): Promise<void> {
-	let [x1, rem1, err1] = leadingInt($.stringToBytes("123abc456"))
+	let [x1, rem1, err1] = leadingInt<$.Bytes>($.typeDict<$.Bytes>(() => new Uint8Array(0)), $.stringToBytes("123abc456"))
 	$.println(x1, $.bytesToString(rem1), err1)
 
-	let [x2, rem2, err2] = leadingInt("456def123")
+	let [x2, rem2, err2] = leadingInt<string>($.typeDict<string>(() => ""), "456def123")
 	$.println(x2, rem2, err2)
 
-	let [x3, rem3, err3] = leadingInt("abc")
+	let [x3, rem3, err3] = leadingInt<string>($.typeDict<string>(() => ""), "abc")
 	$.println(x3, rem3, err3)
 
 	// Test overflow
-	let [x4, rem4, err4] = leadingInt("999999999999999999999999999999")
+	let [x4, rem4, err4] = leadingInt<string>($.typeDict<string>(() => ""), "999999999999999999999999999999")
 	$.println(x4, rem4, err4)
 
-	let [x5, rem5, err5] = leadingInt<string>("123")
+	let [x5, rem5, err5] = leadingInt<string>($.typeDict<string>(() => ""), "123")
 	$.println(x5, rem5, err5)
 }
 
@@ -7,7 +7,7 @@ export async function main(): Promise<void> {
 	let x: null | any = (): void => {
 		$.println("goroutine executed")
 	}
-	queueMicrotask(() => {
+	$.go(() => {
 		$.mustTypeAssert<(() => void) | null>(x, {kind: $.TypeKind.Function})!()
 	})
 	$.println("main finished")
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+func main() {
+	println("goroutines at start:", runtime.NumGoroutine())
+
+	// Goroutines blocked on a channel still exist.
+	start := make(chan struct{})
+	results := make(chan int)
+	for i := 1; i <= 3; i++ {
+		go func() {
+			<-start
+			results <- i * i
+		}()
+	}
+	println("goroutines started:", runtime.NumGoroutine())
+	close(start)
+	sum := 0
+	for i := 0; i < 3; i++ {
+		sum += <-results
+	}
+	println("sum of squares:", sum)
+
+	// Contended mutex and a WaitGroup.
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	counter := 0
+	mu.Lock()
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			counter++
+			mu.Unlock()
+		}()
+	}
+	mu.Unlock()
+	wg.Wait()
+	println("counter:", counter)
+
+	// A condition variable signalled by another goroutine.
+	cmu := new(sync.Mutex)
+	cond := sync.NewCond(cmu)
+	ready := false
+	go func() {
+		cmu.Lock()
+		ready = true
+		cond.Signal()
+		cmu.Unlock()
+	}()
+	cmu.Lock()
+	for !ready {
+		cond.Wait()
+	}
+	cmu.Unlock()
+	println("ready:", ready)
+
+	// Only a timer can wake the main goroutine.
+	select {
+	case <-time.After(10 * time.Millisecond):
+		println("timer fired")
+	}
+	time.Sleep(time.Millisecond)
+	println("slept")
+
+	// Goroutines still blocked when main returns are not a deadlock.
+	never := make(chan int)
+	go func() {
+		<-never
+	}()
+	println("main done")
+}
@@ -0,0 +1,97 @@
+// Generated file based on goroutine_scheduler.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as runtime from "@goscript/runtime/index.js"
+
+import * as sync from "@goscript/sync/index.js"
+
+import * as time from "@goscript/time/index.js"
+
+export async function main(): Promise<void> {
+	using __defer = new $.DisposableStack();
+	$.println("goroutines at start:", runtime.NumGoroutine())
+
+	// Goroutines blocked on a channel still exist.
+	let start = $.makeChannel<{  }>(0, {}, 'both')
+	let results = $.makeChannel<number>(0, 0, 'both')
+	for (let i = 1; i <= 3; i++) {
+		$.go(async () => {
+			await $.chanRecv(start)
+			await $.chanSend(results, i * i)
+		})
+	}
+	$.println("goroutines started:", runtime.NumGoroutine())
+	start.close()
+	let sum = 0
+	for (let i = 0; i < 3; i++) {
+		sum += await $.chanRecv(results)
+	}
+	$.println("sum of squares:", sum)
+
+	// Contended mutex and a WaitGroup.
+	let mu: $.VarRef<sync.Mutex> = $.varRef(new sync.Mutex())
+	let wg: $.VarRef<sync.WaitGroup> = $.varRef(new sync.WaitGroup())
+	let counter = 0
+	await mu!.value.Lock()
+	for (let i = 0; i < 5; i++) {
+		using __defer = new $.DisposableStack();
+		wg!.value.Add(1)
+		$.go(async () => {
+			using __defer = new $.DisposableStack();
+			__defer.defer(() => {
+				wg!.value.Done()
+			});
+			await mu!.value.Lock()
+			counter++
+			mu!.value.Unlock()
+		})
+	}
+	mu!.value.Unlock()
+	await wg!.value.Wait()
+	$.println("counter:", counter)
+
+	// A condition variable signalled by another goroutine.
+	let cmu = new sync.Mutex()
+	let cond = sync.NewCond(cmu)
+	let ready = false
+	$.go(async () => {
+		await cmu!.Lock()
+		ready = true
+		cond!.Signal()
+		cmu!.Unlock()
+	})
+	await cmu!.Lock()
+	for (; !ready; ) {
+		await cond!.Wait()
+	}
+	cmu!.Unlock()
+	$.println("ready:", ready)
+
+	// Only a timer can wake the main goroutine.
+	const [_select_has_return_412d, _select_value_412d] = await $.selectStatement([
+		{
+			id: 0,
+			isSend: false,
+			channel: time.After(10 * time.Millisecond),
+			onSelected: async (result) => {
+				$.println("timer fired")
+			}
+		},
+	], false)
+	if (_select_has_return_412d) {
+		return _select_value_412d!
+	}
+	// If _select_has_return_412d is false, continue execution
+	await time.Sleep(time.Millisecond)
+	$.println("slept")
+
+	// Goroutines still blocked when main returns are not a deadlock.
+	let _never = $.makeChannel<number>(0, 0, 'both')
+	$.go(async () => {
+		await $.chanRecv(_never)
+	})
+	$.println("main done")
+}
+
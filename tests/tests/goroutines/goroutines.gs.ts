@@ -78,19 +78,19 @@ export async function main(): Promise<void> {
 
 	// This will trigger a past error with *ast.Ident
 	for (let i = 0; i < 3; i++) {{
-		queueMicrotask(async () => {
+		$.go(async () => {
 			await worker(i)
 		})
 	}
 }
 
 // Start another worker goroutine
-queueMicrotask(async () => {
+$.go(async () => {
 	await anotherWorker("test")
 })
 
 // Start an anonymous function worker
-queueMicrotask(async () => {
+$.go(async () => {
 	await $.chanSend(messages, $.markAsStructValue(new Message({priority: 50, text: "Anonymous function worker"})))
 })
 
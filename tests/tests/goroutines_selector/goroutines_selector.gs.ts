@@ -51,7 +51,7 @@ export function NewFoo(): Foo | null {
 
 export async function main(): Promise<void> {
 	let f = NewFoo()
-	queueMicrotask(async () => {
+	$.go(async () => {
 		await f!.Bar()
 	})
 	await $.chanRecv(f!.done)
@@ -0,0 +1,112 @@
+package main
+
+// forward jumps over statements to a label later in the same block.
+func forward(n int) string {
+	if n < 0 {
+		goto negative
+	}
+	println("forward: non-negative", n)
+	return "ok"
+
+negative:
+	println("forward: negative", n)
+	return "negative"
+}
+
+// backward implements a retry loop with a backward goto.
+func backward() int {
+	attempts := 0
+retry:
+	attempts++
+	if attempts < 3 {
+		println("backward: retrying, attempt", attempts)
+		goto retry
+	}
+	result := attempts * 10
+	println("backward: done after", attempts)
+	return result
+}
+
+// crossBlock jumps out of nested blocks and loops to a label in the function body.
+func crossBlock(grid [][]int, target int) (int, int) {
+	var row, col int
+	for i, r := range grid {
+		for j, v := range r {
+			if v == target {
+				row, col = i, j
+				goto found
+			}
+		}
+	}
+	println("crossBlock: not found", target)
+	return -1, -1
+
+found:
+	println("crossBlock: found", target, "at", row, col)
+	return row, col
+}
+
+// escape uses break and continue of an enclosing loop inside a goto loop.
+func escape() {
+	for i := 0; i < 5; i++ {
+		n := 0
+	again:
+		n++
+		if i == 1 {
+			continue
+		}
+		if i == 3 {
+			break
+		}
+		if n < 2 {
+			goto again
+		}
+		println("escape: i", i, "n", n)
+	}
+	println("escape: done")
+}
+
+// dispatch jumps forward over a label which is also targeted from below.
+func dispatch(skip bool) {
+	i := 0
+	if skip {
+		goto second
+	}
+first:
+	println("dispatch: first", i)
+	i++
+second:
+	println("dispatch: second", i)
+	if i < 2 {
+		goto first
+	}
+	println("dispatch: done", i)
+}
+
+// nestedLabel jumps to a label inside a nested block.
+func nestedLabel(x int) {
+	if x > 0 {
+	loop:
+		println("nestedLabel:", x)
+		x--
+		if x > 0 {
+			goto loop
+		}
+	}
+	println("nestedLabel: done")
+}
+
+func main() {
+	println(forward(1))
+	println(forward(-1))
+	println(backward())
+	grid := [][]int{{1, 2}, {3, 4}}
+	r, c := crossBlock(grid, 4)
+	println(r, c)
+	r, c = crossBlock(grid, 5)
+	println(r, c)
+	escape()
+	dispatch(false)
+	dispatch(true)
+	nestedLabel(3)
+}
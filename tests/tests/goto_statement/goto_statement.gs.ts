@@ -0,0 +1,149 @@
+// Generated file based on goto_statement.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+// forward jumps over statements to a label later in the same block.
+export function forward(n: number): string {
+	__goto_fwd_negative: {
+		if (n < 0) {
+			break __goto_fwd_negative
+		}
+		$.println("forward: non-negative", n)
+		return "ok"
+	}
+
+	$.println("forward: negative", n)
+	return "negative"
+}
+
+// backward implements a retry loop with a backward goto.
+export function backward(): number {
+	let attempts = 0
+	__goto_back_retry: for (;;) {
+		attempts++
+		if (attempts < 3) {
+			$.println("backward: retrying, attempt", attempts)
+			continue __goto_back_retry
+		}
+		break
+	}
+	let result = attempts * 10
+	$.println("backward: done after", attempts)
+	return result
+}
+
+// crossBlock jumps out of nested blocks and loops to a label in the function body.
+export function crossBlock(grid: $.Slice<$.Slice<number>>, target: number): [number, number] {
+	let row: number = 0
+	let col: number = 0
+	__goto_fwd_found: {
+		for (let i = 0; i < $.len(grid); i++) {
+			let r = grid![i]
+			{
+				for (let j = 0; j < $.len(r); j++) {
+					let v = r![j]
+					{
+						if (v == target) {
+							;[row, col] = [i, j]
+							break __goto_fwd_found
+						}
+					}
+				}
+			}
+		}
+		$.println("crossBlock: not found", target)
+		return [-1, -1]
+	}
+
+	$.println("crossBlock: found", target, "at", row, col)
+	return [row, col]
+}
+
+// escape uses break and continue of an enclosing loop inside a goto loop.
+export function escape(): void {
+	for (let i = 0; i < 5; i++) {
+		let n = 0
+		let __goto_back_again_esc = 0
+		__goto_back_again: for (;;) {
+			n++
+			if (i == 1) {
+				__goto_back_again_esc = 2
+				break __goto_back_again
+			}
+			if (i == 3) {
+				__goto_back_again_esc = 1
+				break __goto_back_again
+			}
+			if (n < 2) {
+				continue __goto_back_again
+			}
+			break
+		}
+		if (__goto_back_again_esc === 1) {
+			break
+		}
+		if (__goto_back_again_esc === 2) {
+			continue
+		}
+		$.println("escape: i", i, "n", n)
+	}
+	$.println("escape: done")
+}
+
+// dispatch jumps forward over a label which is also targeted from below.
+export function dispatch(skip: boolean): void {
+	let i = 0
+	let __goto_sm_first_state: number = 0
+	__goto_sm_first: for (;;) {
+		switch (__goto_sm_first_state) {
+			case 0:
+				if (skip) {
+					__goto_sm_first_state = 2
+					continue __goto_sm_first
+				}
+			case 1:
+				$.println("dispatch: first", i)
+				i++
+			case 2:
+				$.println("dispatch: second", i)
+				if (i < 2) {
+					__goto_sm_first_state = 1
+					continue __goto_sm_first
+				}
+				$.println("dispatch: done", i)
+		}
+		break
+	}
+}
+
+// nestedLabel jumps to a label inside a nested block.
+export function nestedLabel(x: number): void {
+	if (x > 0) {
+		__goto_back_loop: for (;;) {
+			$.println("nestedLabel:", x)
+			x--
+			if (x > 0) {
+				continue __goto_back_loop
+			}
+			break
+		}
+	}
+	$.println("nestedLabel: done")
+}
+
+export async function main(): Promise<void> {
+	$.println(forward(1))
+	$.println(forward(-1))
+	$.println(backward())
+	let grid = $.arrayToSlice<$.Slice<number>>([[ 1, 2 ], [ 3, 4 ]], 2)
+	let [r, c] = crossBlock(grid, 4)
+	$.println(r, c)
+	;[r, c] = crossBlock(grid, 5)
+	$.println(r, c)
+	escape()
+	dispatch(false)
+	dispatch(true)
+	nestedLabel(3)
+}
+
@@ -0,0 +1,3 @@
+export { ID_String } from "./int64_bigint.gs.js"
+export { Range } from "./int64_bigint.gs.js"
+export type { ID } from "./int64_bigint.gs.js"
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+type ID uint64
+
+type Range struct {
+	Lo, Hi int64
+}
+
+func (id ID) String() string {
+	return "id-" + strconv.FormatUint(uint64(id), 16)
+}
+
+// fnv64a computes the 64-bit FNV-1a hash of s.
+func fnv64a(s string) uint64 {
+	var h uint64 = 14695981039346656037
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+func main() {
+	// Values above 2^53 stay exact
+	var big int64 = 1<<62 + 1
+	println("big:", big)
+	println("big+1:", big+1)
+
+	// Signed overflow wraps around
+	maxInt := int64(9223372036854775807)
+	maxInt++
+	println("wrapped:", maxInt)
+	minInt := maxInt
+	println("negated min:", -minInt)
+
+	// Unsigned arithmetic wraps around
+	var u uint64 = 0
+	u--
+	println("u:", u)
+	println("u+2:", u+2)
+	println("^uint64(0):", ^uint64(0))
+
+	// Shifts and masks
+	var one uint64 = 1
+	n := 63
+	println("1<<63:", one<<n)
+	println("1<<64:", one<<(n+1))
+	println("mask:", (u>>32)&0xffff)
+	println("and not:", u&^0xff)
+
+	// Conversions between integer kinds
+	i := 42
+	x := int64(i) * 3
+	println("x:", x)
+	println("int(x):", int(x)+1)
+	println("float64:", float64(x)/4)
+	neg := int64(-1)
+	println("uint64(neg):", uint64(neg))
+	println("int64(u):", int64(u))
+
+	// Division truncates towards zero
+	println("div:", int64(-7)/2, int64(-7)%2)
+
+	// Hashing and IDs
+	h := fnv64a("hello")
+	println("fnv64a:", h)
+	fmt.Println(ID(h).String())
+	fmt.Printf("%d %x\n", h, h)
+
+	// Indexing with int64 values
+	s := []string{"a", "b", "c"}
+	var idx int64 = 2
+	println("s[idx]:", s[idx])
+	arr := make([]int64, idx)
+	arr[1] += 5
+	println("arr:", arr[0], arr[1])
+
+	// Handwritten package results are converted to bigint
+	v, err := strconv.ParseInt("123456", 10, 64)
+	println("parsed:", v+1, err == nil)
+	println("formatted:", strconv.FormatInt(big, 10))
+
+	// Composite literal elements, keys and fields are bigint
+	const limit = 1 << 60
+	ints := []int64{1, -2, limit}
+	println("slice:", ints[0]+ints[1], ints[2]+1)
+	words := [3]uint64{3, 2: 1<<64 - 1}
+	println("array:", words[0], words[1], words[2])
+	squares := map[int64]int64{2: 4, limit: 1}
+	println("map:", squares[2], squares[limit], len(squares))
+	r := Range{-1, limit}
+	println("struct:", r.Lo, r.Hi+1)
+	keyed := &Range{Hi: 7}
+	println("keyed:", keyed.Lo, keyed.Hi*2)
+	ranges := []Range{{1, 2}, {Hi: 3}}
+	println("nested:", ranges[0].Lo+ranges[0].Hi, ranges[1].Hi)
+	anon := struct{ N uint64 }{42}
+	println("anonymous:", anon.N+1)
+
+	// sync/atomic takes and returns int64 and uint64 values as bigint
+	var counter atomic.Int64
+	counter.Add(1)
+	println("atomic.Int64:", counter.Load()+2)
+	var total int64 = limit
+	atomic.AddInt64(&total, 5)
+	println("AddInt64:", atomic.LoadInt64(&total))
+	println("CompareAndSwapInt64:", atomic.CompareAndSwapInt64(&total, limit+5, -1), total)
+	var flags uint64
+	atomic.StoreUint64(&flags, 1<<63)
+	println("AddUint64:", atomic.AddUint64(&flags, 1<<63), atomic.SwapUint64(&flags, 7), flags)
+	var ptr uintptr = 10
+	println("AddUintptr:", atomic.AddUintptr(&ptr, 5), atomic.LoadUintptr(&ptr))
+
+	// Interfaces keep the int64 type
+	var anyVal any = big
+	if got, ok := anyVal.(int64); ok {
+		println("asserted:", got)
+	}
+	if _, ok := anyVal.(int); !ok {
+		println("not an int")
+	}
+}
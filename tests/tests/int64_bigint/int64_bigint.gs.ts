@@ -0,0 +1,181 @@
+// Generated file based on int64_bigint.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+import * as strconv from "@goscript/strconv/index.js"
+
+import * as atomic from "@goscript/sync/atomic/index.js"
+
+export type ID = bigint;
+
+export function ID_String(id: ID): string {
+	return "id-" + strconv.FormatUint(id, 16)
+}
+
+
+export class Range {
+	public get Lo(): bigint {
+		return this._fields.Lo.value
+	}
+	public set Lo(value: bigint) {
+		this._fields.Lo.value = value
+	}
+
+	public get Hi(): bigint {
+		return this._fields.Hi.value
+	}
+	public set Hi(value: bigint) {
+		this._fields.Hi.value = value
+	}
+
+	public _fields: {
+		Lo: $.VarRef<bigint>;
+		Hi: $.VarRef<bigint>;
+	}
+
+	constructor(init?: Partial<{Hi?: bigint, Lo?: bigint}>) {
+		this._fields = {
+			Lo: $.varRef(init?.Lo ?? 0n),
+			Hi: $.varRef(init?.Hi ?? 0n)
+		}
+	}
+
+	public clone(): Range {
+		const cloned = new Range()
+		cloned._fields = {
+			Lo: $.varRef(this._fields.Lo.value),
+			Hi: $.varRef(this._fields.Hi.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Range',
+	  new Range(),
+	  [],
+	  Range,
+	  {"Lo": { kind: $.TypeKind.Basic, name: "int64" }, "Hi": { kind: $.TypeKind.Basic, name: "int64" }}
+	);
+}
+
+// fnv64a computes the 64-bit FNV-1a hash of s.
+export function fnv64a(s: string): bigint {
+	let h: bigint = 14695981039346656037n
+	for (let i = 0; i < $.len(s); i++) {
+		h = (h ^ $.uint64($.indexString(s, i)))
+		h = BigInt.asUintN(64, h * 1099511628211n)
+	}
+	return h
+}
+
+export async function main(): Promise<void> {
+	// Values above 2^53 stay exact
+	let big: bigint = 4611686018427387905n
+	$.println("big:", big)
+	$.println("big+1:", BigInt.asIntN(64, big + 1n))
+
+	// Signed overflow wraps around
+	let maxInt = 9223372036854775807n
+	maxInt = BigInt.asIntN(64, maxInt + 1n)
+	$.println("wrapped:", maxInt)
+	let minInt = maxInt
+	$.println("negated min:", BigInt.asIntN(64, -(minInt)))
+
+	// Unsigned arithmetic wraps around
+	let u: bigint = 0n
+	u = BigInt.asUintN(64, u - 1n)
+	$.println("u:", u)
+	$.println("u+2:", BigInt.asUintN(64, u + 2n))
+	$.println("^uint64(0):", 18446744073709551615n)
+
+	// Shifts and masks
+	let one: bigint = 1n
+	let n = 63
+	$.println("1<<63:", BigInt.asUintN(64, one << BigInt(n)))
+	$.println("1<<64:", BigInt.asUintN(64, one << BigInt((n + 1))))
+	$.println("mask:", (((u >> 32n)) & 65535n))
+	$.println("and not:", (u & ~(255n)))
+
+	// Conversions between integer kinds
+	let i = 42
+	let x = BigInt.asIntN(64, $.int64(i) * 3n)
+	$.println("x:", x)
+	$.println("int(x):", Number(x) + 1)
+	$.println("float64:", Number(x) / 4)
+	let neg = -1n
+	$.println("uint64(neg):", BigInt.asUintN(64, neg))
+	$.println("int64(u):", BigInt.asIntN(64, u))
+
+	// Division truncates towards zero
+	$.println("div:", -3n, -1n)
+
+	// Hashing and IDs
+	let h = fnv64a("hello")
+	$.println("fnv64a:", h)
+	fmt.Println(ID_String(h))
+	fmt.Printf("%d %x\n", h, h)
+
+	// Indexing with int64 values
+	let s = $.arrayToSlice<string>(["a", "b", "c"])
+	let idx: bigint = 2n
+	$.println("s[idx]:", s![Number(idx)])
+	let arr = $.makeSlice<bigint>(Number(idx), undefined, 'bigint')
+	arr![1] = BigInt.asIntN(64, arr![1] + 5n)
+	$.println("arr:", arr![0], arr![1])
+
+	// Handwritten package results are converted to bigint
+	let [v, err] = $.bigintResults(strconv.ParseInt("123456", 10, 64), ['int64', null])
+	$.println("parsed:", BigInt.asIntN(64, v + 1n), err == null)
+	$.println("formatted:", strconv.FormatInt(big, 10))
+
+	// Composite literal elements, keys and fields are bigint
+	let limit: number = 1152921504606846976
+	let ints = $.arrayToSlice<bigint>([1n, -2n, 1152921504606846976n])
+	$.println("slice:", BigInt.asIntN(64, ints![0] + ints![1]), BigInt.asIntN(64, ints![2] + 1n))
+	let words = $.arrayToSlice<bigint>([3n, 0n, 18446744073709551615n])
+	$.println("array:", words![0], words![1], words![2])
+	let squares = new Map([[2n, 4n], [1152921504606846976n, 1n]])
+	$.println("map:", $.mapGet(squares, 2n, 0n)[0], $.mapGet(squares, 1152921504606846976n, 0n)[0], $.len(squares))
+	let r = $.markAsStructValue(new Range({Hi: 1152921504606846976n, Lo: -1n}))
+	$.println("struct:", r.Lo, BigInt.asIntN(64, r.Hi + 1n))
+	let keyed = new Range({Hi: 7n})
+	$.println("keyed:", keyed!.Lo, BigInt.asIntN(64, keyed!.Hi * 2n))
+	let ranges = $.arrayToSlice<Range>([$.markAsStructValue(new Range({Hi: 2n, Lo: 1n})), $.markAsStructValue(new Range({Hi: 3n}))])
+	$.println("nested:", BigInt.asIntN(64, ranges![0].Lo + ranges![0].Hi), ranges![1].Hi)
+	let anon = {N: 42n}
+	$.println("anonymous:", BigInt.asUintN(64, anon.N + 1n))
+
+	// sync/atomic takes and returns int64 and uint64 values as bigint
+	let counter: $.VarRef<atomic.Int64> = $.varRef(new atomic.Int64())
+	$.int64(counter!.value.Add(Number(1n)))
+	$.println("atomic.Int64:", BigInt.asIntN(64, $.int64(counter!.value.Load()) + 2n))
+	let total: $.VarRef<bigint> = $.varRef(1152921504606846976n)
+	$.int64(atomic.AddInt64(total, 5n))
+	$.println("AddInt64:", $.int64(atomic.LoadInt64(total)))
+	$.println("CompareAndSwapInt64:", atomic.CompareAndSwapInt64(total, 1152921504606846981n, -1n), total!.value)
+	let flags: $.VarRef<bigint> = $.varRef(0n)
+	atomic.StoreUint64(flags, 9223372036854775808n)
+	$.println("AddUint64:", $.uint64(atomic.AddUint64(flags, 9223372036854775808n)), $.uint64(atomic.SwapUint64(flags, 7n)), flags!.value)
+	let ptr: $.VarRef<bigint> = $.varRef(10n)
+	$.println("AddUintptr:", $.uint64(atomic.AddUintptr(ptr, 5n)), $.uint64(atomic.LoadUintptr(ptr)))
+
+	// Interfaces keep the int64 type
+	let anyVal: null | any = big
+	{
+		let { value: got, ok: ok } = $.typeAssert<bigint>(anyVal, {kind: $.TypeKind.Basic, name: 'int64'})
+		if (ok) {
+			$.println("asserted:", got)
+		}
+	}
+	{
+		let { ok: ok } = $.typeAssert<number>(anyVal, {kind: $.TypeKind.Basic, name: 'number'})
+		if (!ok) {
+			$.println("not an int")
+		}
+	}
+}
+
@@ -0,0 +1,119 @@
+package main
+
+func crc32(data []byte) uint32 {
+	crc := ^uint32(0)
+	for _, b := range data {
+		crc ^= uint32(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = (crc >> 1) ^ 0xedb88320
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return ^crc
+}
+
+func fnv1a(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+
+func xorshift(x uint32) uint32 {
+	x ^= x << 13
+	x ^= x >> 17
+	x ^= x << 5
+	return x
+}
+
+func main() {
+	// uint8 overflow
+	var b uint8 = 250
+	b += 10
+	println("uint8 250+10:", b)
+	b = 0
+	b--
+	println("uint8 0-1:", b)
+	b = 200
+	println("uint8 200*2:", b*2)
+
+	// int8 overflow
+	var i8 int8 = 127
+	i8++
+	println("int8 127+1:", i8)
+	i8 = -128
+	println("int8 -(-128):", -i8)
+
+	// uint16 and int16
+	var u16 uint16 = 65535
+	u16 += 2
+	println("uint16 65535+2:", u16)
+	var i16 int16 = 32767
+	i16 += 1
+	println("int16 32767+1:", i16)
+
+	// int32 overflow
+	var i32 int32 = 2147483647
+	i32++
+	println("int32 max+1:", i32)
+	var m int32 = 123456789
+	println("int32 mul:", m*m)
+
+	// uint32 overflow
+	var u32 uint32 = 4294967295
+	u32++
+	println("uint32 max+1:", u32)
+	u32 = 0
+	println("uint32 ^0:", ^u32)
+	var big uint32 = 0xdeadbeef
+	println("uint32 mul:", big*big)
+	println("uint32 and:", big&0xffff0000)
+	println("uint32 andnot:", big&^0xff)
+
+	// shifts
+	var n uint = 40
+	var one uint32 = 1
+	println("uint32 1<<31:", one<<31)
+	println("uint32 1<<40:", one<<n)
+	println("int32 -8>>40:", int32(-8)>>n)
+	var u8 uint8 = 0x81
+	println("uint8 0x81<<1:", u8<<1)
+
+	// conversions
+	x := 300
+	println("uint8(300):", uint8(x))
+	y := -1
+	println("uint32(-1):", uint32(y))
+	println("int8(200):", int8(uint8(200+x-300)))
+	f := 3.9
+	println("int32(3.9):", int32(f))
+
+	// integer division and modulo
+	a, d := -7, 2
+	println("-7/2:", a/d, "-7%2:", a%d)
+	var p int8 = -128
+	var q int8 = -1
+	println("int8 -128/-1:", p/q)
+
+	// hashes
+	println("crc32:", crc32([]byte("hello world")))
+	println("fnv1a:", fnv1a("hello world"))
+	s := uint32(2463534242)
+	for i := 0; i < 3; i++ {
+		s = xorshift(s)
+		println("xorshift:", s)
+	}
+
+	// division by zero panics
+	defer func() {
+		r := recover()
+		println("recovered:", r != nil)
+	}()
+	zero := 0
+	println(10 / zero)
+}
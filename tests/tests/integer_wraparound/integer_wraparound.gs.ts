@@ -0,0 +1,127 @@
+// Generated file based on integer_wraparound.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+export function crc32(data: $.Bytes): number {
+	let crc = 4294967295
+	for (let _i = 0; _i < $.len(data); _i++) {
+		let b = data![_i]
+		{
+			crc = ((crc ^ (b as number)) >>> 0)
+			for (let i = 0; i < 8; i++) {
+				if (((crc & 1) >>> 0) != 0) {
+					crc = ((((crc >>> 1)) ^ 0xedb88320) >>> 0)
+				} else {
+					crc = (crc >>> 1)
+				}
+			}
+		}
+	}
+	return ((~(crc)) >>> 0)
+}
+
+export function fnv1a(s: string): number {
+	let h = (2166136261 as number)
+	for (let i = 0; i < $.len(s); i++) {
+		h = ((h ^ ($.indexString(s, i) as number)) >>> 0)
+		h = ((Math.imul(h, 16777619)) >>> 0)
+	}
+	return h
+}
+
+export function xorshift(x: number): number {
+	x = ((x ^ ((x << 13) >>> 0)) >>> 0)
+	x = ((x ^ (x >>> 17)) >>> 0)
+	x = ((x ^ ((x << 5) >>> 0)) >>> 0)
+	return x
+}
+
+export async function main(): Promise<void> {
+	using __defer = new $.DisposableStack();
+	// uint8 overflow
+	let b: number = 250
+	b = ((b + 10) & 0xff)
+	$.println("uint8 250+10:", b)
+	b = 0
+	b = ((b - 1) & 0xff)
+	$.println("uint8 0-1:", b)
+	b = 200
+	$.println("uint8 200*2:", ((b * 2) & 0xff))
+
+	// int8 overflow
+	let i8: number = 127
+	i8 = ((i8 + 1) << 24 >> 24)
+	$.println("int8 127+1:", i8)
+	i8 = -128
+	$.println("int8 -(-128):", ((-(i8)) << 24 >> 24))
+
+	// uint16 and int16
+	let u16: number = 65535
+	u16 = ((u16 + 2) & 0xffff)
+	$.println("uint16 65535+2:", u16)
+	let i16: number = 32767
+	i16 = ((i16 + 1) << 16 >> 16)
+	$.println("int16 32767+1:", i16)
+
+	// int32 overflow
+	let i32: number = 2147483647
+	i32 = ((i32 + 1) | 0)
+	$.println("int32 max+1:", i32)
+	let m: number = 123456789
+	$.println("int32 mul:", Math.imul(m, m))
+
+	// uint32 overflow
+	let u32: number = 4294967295
+	u32 = ((u32 + 1) >>> 0)
+	$.println("uint32 max+1:", u32)
+	u32 = 0
+	$.println("uint32 ^0:", ((~(u32)) >>> 0))
+	let big: number = 0xdeadbeef
+	$.println("uint32 mul:", ((Math.imul(big, big)) >>> 0))
+	$.println("uint32 and:", ((big & 0xffff0000) >>> 0))
+	$.println("uint32 andnot:", ((big & ~(0xff)) >>> 0))
+
+	// shifts
+	let n: number = 40
+	let one: number = 1
+	$.println("uint32 1<<31:", ((one << 31) >>> 0))
+	$.println("uint32 1<<40:", $.shlUint(one, n, 32))
+	$.println("int32 -8>>40:", $.shrInt((-8 as number), n))
+	let u8: number = 0x81
+	$.println("uint8 0x81<<1:", ((u8 << 1) & 0xff))
+
+	// conversions
+	let x = 300
+	$.println("uint8(300):", ((x) & 0xff))
+	let y = -1
+	$.println("uint32(-1):", ((y) >>> 0))
+	$.println("int8(200):", ((((200 + x - 300) & 0xff)) << 24 >> 24))
+	let f = 3.9
+	$.println("int32(3.9):", ((f) | 0))
+
+	// integer division and modulo
+	let [a, d] = [-7, 2]
+	$.println("-7/2:", $.idiv(a, d), "-7%2:", $.imod(a, d))
+	let p: number = -128
+	let q: number = -1
+	$.println("int8 -128/-1:", (($.idiv(p, q)) << 24 >> 24))
+
+	// hashes
+	$.println("crc32:", crc32($.stringToBytes("hello world")))
+	$.println("fnv1a:", fnv1a("hello world"))
+	let s = (2463534242 as number)
+	for (let i = 0; i < 3; i++) {
+		s = xorshift(s)
+		$.println("xorshift:", s)
+	}
+
+	// division by zero panics
+	__defer.defer(() => {
+		let r = $.recover()
+		$.println("recovered:", r != null)
+	});
+	let zero = 0
+	$.println($.idiv(10, zero))
+}
+
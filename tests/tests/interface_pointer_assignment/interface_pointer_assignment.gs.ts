@@ -80,7 +80,7 @@ export async function main(): Promise<void> {
 	let s5 = $.varRef($.markAsStructValue(new MyStruct({Value: 80})))
 	let p5a = s5
 	let p5b = p5a // p5b points to same varref as p5a
-	let i5: null | any = p5b
+	let i5: null | any = $.typedNilOf(p5b, "*main.MyStruct")
 	let { ok: ok5 } = $.typeAssert<MyStruct | null>(i5, {kind: $.TypeKind.Pointer, elemType: 'main.MyStruct'})
 	$.println("Scenario 5 - Nested pointer assignment assertion:", ok5)
 
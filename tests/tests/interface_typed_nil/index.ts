@@ -0,0 +1,2 @@
+export { Cat, Dog, MyError, Pen } from "./interface_typed_nil.gs.js"
+export type { Animal } from "./interface_typed_nil.gs.js"
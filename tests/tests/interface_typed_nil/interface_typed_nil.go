@@ -0,0 +1,143 @@
+package main
+
+import "fmt"
+
+type Animal interface {
+	Name() string
+}
+
+type Dog struct{}
+
+type Cat struct{}
+
+func (d *Dog) Name() string {
+	if d == nil {
+		return "unknown dog"
+	}
+	return "Fido"
+}
+
+func (c *Cat) Name() string {
+	if c == nil {
+		return "unknown cat"
+	}
+	return "Whiskers"
+}
+
+type MyError struct {
+	msg string
+}
+
+func (e *MyError) Error() string {
+	if e == nil {
+		return "nil MyError"
+	}
+	return e.msg
+}
+
+// mayFail returns a typed nil *MyError as an error, the classic Go gotcha.
+func mayFail(fail bool) error {
+	var err *MyError
+	if fail {
+		err = &MyError{msg: "failed"}
+	}
+	return err
+}
+
+type Pen struct {
+	Pet Animal
+}
+
+func describe(a Animal) string {
+	if a == nil {
+		return "nil interface"
+	}
+	return "animal " + a.Name()
+}
+
+func kind(v any) string {
+	k := "other"
+	switch v.(type) {
+	case nil:
+		k = "nil"
+	case *Dog:
+		k = "*Dog"
+	case *Cat, string:
+		k = "*Cat or string"
+	}
+	return k
+}
+
+func main() {
+	// A nil pointer in an interface makes a non-nil interface
+	var d *Dog
+	var b Animal = d
+	println("b != nil:", b != nil)
+	println("b.Name():", b.Name())
+
+	var nilAnimal Animal
+	println("nilAnimal == nil:", nilAnimal == nil)
+
+	// Assigning a typed nil later
+	var c Animal
+	c = d
+	println("c != nil:", c != nil)
+
+	// Passing a typed nil as an interface argument
+	println(describe(d))
+	println(describe(nil))
+	println(describe(&Dog{}))
+
+	// Type assertions keep the dynamic type
+	if dog, ok := b.(*Dog); ok {
+		println("b.(*Dog) ok, dog == nil:", dog == nil)
+	}
+	_, isCat := b.(*Cat)
+	println("b.(*Cat) ok:", isCat)
+	named, ok := b.(interface{ Name() string })
+	println("b.(interface) ok:", ok, named.Name())
+
+	// Type switches see the dynamic type
+	println("kind(d):", kind(d))
+	println("kind(nil):", kind(nil))
+	var cat *Cat
+	println("kind(cat):", kind(cat))
+
+	// Typed nils of the same type are equal
+	var d2 *Dog
+	var b2 Animal = d2
+	println("b == b2:", b == b2)
+	println("b == Animal(cat):", b == Animal(cat))
+
+	// Composite literal elements, map values and struct fields
+	animals := []Animal{d, cat, &Dog{}}
+	for _, a := range animals {
+		println("slice:", a != nil, a.Name())
+	}
+	byName := map[string]Animal{"dog": d}
+	println("map:", byName["dog"] != nil, kind(byName["dog"]))
+	pen := Pen{Pet: cat}
+	println("field:", pen.Pet != nil, pen.Pet.Name())
+	pens := []Pen{{d}}
+	println("nested:", pens[0].Pet != nil, kind(pens[0].Pet))
+
+	// Channel sends and appends
+	ch := make(chan Animal, 1)
+	ch <- d
+	received := <-ch
+	println("received:", received != nil, kind(received))
+	animals = append(animals[:0], cat)
+	println("appended:", animals[0] != nil, kind(animals[0]))
+
+	// The classic typed nil error
+	err := mayFail(false)
+	println("err != nil:", err != nil)
+	println("err.Error():", err.Error())
+	fmt.Println("fmt:", err)
+	fmt.Printf("%T %v\n", err, err)
+	if myErr, ok := err.(*MyError); ok {
+		println("err.(*MyError) ok, nil:", myErr == nil)
+	}
+	err = mayFail(true)
+	println("failed:", err.Error())
+}
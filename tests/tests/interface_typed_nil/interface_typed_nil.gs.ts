@@ -0,0 +1,278 @@
+// Generated file based on interface_typed_nil.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+export type Animal = null | {
+	Name(): string
+}
+
+$.registerInterfaceType(
+  'main.Animal',
+  null, // Zero value for interface is null
+  [{ name: "Name", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }]
+);
+
+export class Cat {
+	public _fields: {
+	}
+
+	constructor(init?: Partial<{}>) {
+		this._fields = {}
+	}
+
+	public clone(): Cat {
+		const cloned = new Cat()
+		cloned._fields = {
+		}
+		return cloned
+	}
+
+	public Name(): string {
+		const c = this
+		if (c == null) {
+			return "unknown cat"
+		}
+		return "Whiskers"
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Cat',
+	  new Cat(),
+	  [{ name: "Name", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }],
+	  Cat,
+	  {}
+	);
+}
+
+export class Dog {
+	public _fields: {
+	}
+
+	constructor(init?: Partial<{}>) {
+		this._fields = {}
+	}
+
+	public clone(): Dog {
+		const cloned = new Dog()
+		cloned._fields = {
+		}
+		return cloned
+	}
+
+	public Name(): string {
+		const d = this
+		if (d == null) {
+			return "unknown dog"
+		}
+		return "Fido"
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Dog',
+	  new Dog(),
+	  [{ name: "Name", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }],
+	  Dog,
+	  {}
+	);
+}
+
+export class MyError {
+	public get msg(): string {
+		return this._fields.msg.value
+	}
+	public set msg(value: string) {
+		this._fields.msg.value = value
+	}
+
+	public _fields: {
+		msg: $.VarRef<string>;
+	}
+
+	constructor(init?: Partial<{msg?: string}>) {
+		this._fields = {
+			msg: $.varRef(init?.msg ?? "")
+		}
+	}
+
+	public clone(): MyError {
+		const cloned = new MyError()
+		cloned._fields = {
+			msg: $.varRef(this._fields.msg.value)
+		}
+		return cloned
+	}
+
+	public Error(): string {
+		const e = this
+		if (e == null) {
+			return "nil MyError"
+		}
+		return e.msg
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.MyError',
+	  new MyError(),
+	  [{ name: "Error", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }],
+	  MyError,
+	  {"msg": { kind: $.TypeKind.Basic, name: "string" }}
+	);
+}
+
+export class Pen {
+	public get Pet(): Animal {
+		return this._fields.Pet.value
+	}
+	public set Pet(value: Animal) {
+		this._fields.Pet.value = value
+	}
+
+	public _fields: {
+		Pet: $.VarRef<Animal>;
+	}
+
+	constructor(init?: Partial<{Pet?: Animal}>) {
+		this._fields = {
+			Pet: $.varRef(init?.Pet ?? null)
+		}
+	}
+
+	public clone(): Pen {
+		const cloned = new Pen()
+		cloned._fields = {
+			Pet: $.varRef(this._fields.Pet.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Pen',
+	  new Pen(),
+	  [],
+	  Pen,
+	  {"Pet": "Animal"}
+	);
+}
+
+// mayFail returns a typed nil *MyError as an error, the classic Go gotcha.
+export function mayFail(fail: boolean): $.GoError {
+	let err: MyError | null = null
+	if (fail) {
+		err = new MyError({msg: "failed"})
+	}
+	return $.typedNilOf(err, "*main.MyError")
+}
+
+export function describe(a: Animal): string {
+	if (a == null) {
+		return "nil interface"
+	}
+	return "animal " + a!.Name()
+}
+
+export function kind(v: null | any): string {
+	let k = "other"
+	$.typeSwitch(v, [{ types: ['nil'], body: () => {
+		k = "nil"
+	}},
+	{ types: [{kind: $.TypeKind.Pointer, elemType: 'main.Dog'}], body: () => {
+		k = "*Dog"
+	}},
+	{ types: [{kind: $.TypeKind.Pointer, elemType: 'main.Cat'}, {kind: $.TypeKind.Basic, name: 'string'}], body: () => {
+		k = "*Cat or string"
+	}}])
+	return k
+}
+
+export async function main(): Promise<void> {
+	// A nil pointer in an interface makes a non-nil interface
+	let d: Dog | null = null
+	let b: Animal = $.typedNilOf(d, "*main.Dog")
+	$.println("b != nil:", b != null)
+	$.println("b.Name():", b!.Name())
+
+	let nilAnimal: Animal = null
+	$.println("nilAnimal == nil:", nilAnimal == null)
+
+	// Assigning a typed nil later
+	let c: Animal = null
+	c = $.typedNilOf(d, "*main.Dog")
+	$.println("c != nil:", c != null)
+
+	// Passing a typed nil as an interface argument
+	$.println(describe($.typedNilOf(d, "*main.Dog")))
+	$.println(describe(null))
+	$.println(describe(new Dog({})))
+
+	// Type assertions keep the dynamic type
+	{
+		let { value: dog, ok: ok } = $.typeAssert<Dog | null>(b, {kind: $.TypeKind.Pointer, elemType: 'main.Dog'})
+		if (ok) {
+			$.println("b.(*Dog) ok, dog == nil:", dog == null)
+		}
+	}
+	let { ok: isCat } = $.typeAssert<Cat | null>(b, {kind: $.TypeKind.Pointer, elemType: 'main.Cat'})
+	$.println("b.(*Cat) ok:", isCat)
+	let { value: named, ok: ok } = $.typeAssert<null | {
+		Name(): string
+	}>(b, {kind: $.TypeKind.Interface, methods: [{ name: 'Name', args: [], returns: [{ type: {kind: $.TypeKind.Basic, name: 'string'} }] }]})
+	$.println("b.(interface) ok:", ok, named!.Name())
+
+	// Type switches see the dynamic type
+	$.println("kind(d):", kind($.typedNilOf(d, "*main.Dog")))
+	$.println("kind(nil):", kind(null))
+	let cat: Cat | null = null
+	$.println("kind(cat):", kind($.typedNilOf(cat, "*main.Cat")))
+
+	// Typed nils of the same type are equal
+	let d2: Dog | null = null
+	let b2: Animal = $.typedNilOf(d2, "*main.Dog")
+	$.println("b == b2:", b == b2)
+	$.println("b == Animal(cat):", b == $.typedNilOf(cat, "*main.Cat"))
+
+	// Composite literal elements, map values and struct fields
+	let animals = $.arrayToSlice<Animal>([$.typedNilOf(d, "*main.Dog"), $.typedNilOf(cat, "*main.Cat"), new Dog({})])
+	for (let _i = 0; _i < $.len(animals); _i++) {
+		let a = animals![_i]
+		{
+			$.println("slice:", a != null, a!.Name())
+		}
+	}
+	let byName = new Map([["dog", $.typedNilOf(d, "*main.Dog")]])
+	$.println("map:", $.mapGet(byName, "dog", null)[0] != null, kind($.mapGet(byName, "dog", null)[0]))
+	let pen = $.markAsStructValue(new Pen({Pet: $.typedNilOf(cat, "*main.Cat")}))
+	$.println("field:", pen.Pet != null, pen.Pet!.Name())
+	let pens = $.arrayToSlice<Pen>([$.markAsStructValue(new Pen({Pet: $.typedNilOf(d, "*main.Dog")}))])
+	$.println("nested:", pens![0].Pet != null, kind(pens![0].Pet))
+
+	// Channel sends and appends
+	let ch = $.makeChannel<Animal>(1, null, 'both')
+	await $.chanSend(ch, $.typedNilOf(d, "*main.Dog"))
+	let received = await $.chanRecv(ch)
+	$.println("received:", received != null, kind(received))
+	animals = $.append($.goSlice(animals, undefined, 0), $.typedNilOf(cat, "*main.Cat"))
+	$.println("appended:", animals![0] != null, kind(animals![0]))
+
+	// The classic typed nil error
+	let err = mayFail(false)
+	$.println("err != nil:", err != null)
+	$.println("err.Error():", err!.Error())
+	fmt.Println("fmt:", err)
+	fmt.Printf("%T %v\n", err, err)
+	{
+		let { value: myErr, ok: ok } = $.typeAssert<MyError | null>(err, {kind: $.TypeKind.Pointer, elemType: 'main.MyError'})
+		if (ok) {
+			$.println("err.(*MyError) ok, nil:", myErr == null)
+		}
+	}
+	err = mayFail(true)
+	$.println("failed:", err!.Error())
+}
+
@@ -20,7 +20,7 @@ export async function main(): Promise<void> {
 
 	// Simulate CPU-bound work with a tight loop
 	// In real code this might be a computation without I/O
-	queueMicrotask(async () => {
+	$.go(async () => {
 		using __defer = new $.DisposableStack();
 		__defer.defer(() => {
 			wg!.value.Done()
@@ -38,7 +38,7 @@ export async function main(): Promise<void> {
 	// In Go: Will run concurrently with worker1
 	// In GoScript: Would never run if worker1 starves the event loop
 	wg!.value.Add(1)
-	queueMicrotask(async () => {
+	$.go(async () => {
 		using __defer = new $.DisposableStack();
 		__defer.defer(() => {
 			wg!.value.Done()
@@ -47,7 +47,7 @@ export async function main(): Promise<void> {
 	})
 
 	// Wait for both workers with a timeout
-	queueMicrotask(async () => {
+	$.go(async () => {
 		await wg!.value.Wait()
 		done.close()
 	})
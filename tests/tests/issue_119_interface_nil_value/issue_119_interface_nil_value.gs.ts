@@ -118,14 +118,14 @@ export function FindAnimal(): Animal {
 	// In Go, this branch IS taken because dog != nil
 	// The interface has type=*Dog, value=nil
 	{
-		let dog = (FindDog() as Animal)
+		let dog = $.typedNilOf(FindDog(), "*main.Dog")
 		if (dog != null) {
 			// In Go, this branch IS taken because dog != nil
 			// The interface has type=*Dog, value=nil
 			return dog
 		}
 	}
-	return FindCat()
+	return $.typedNilOf(FindCat(), "*main.Cat")
 }
 
 export async function main(): Promise<void> {
@@ -145,7 +145,7 @@ export async function main(): Promise<void> {
 
 	// Test 3: Direct nil pointer to interface assignment
 	let dog: Dog | null = null
-	let a: Animal = dog
+	let a: Animal = $.typedNilOf(dog, "*main.Dog")
 
 	if (a == null) {
 		$.println("a is nil")
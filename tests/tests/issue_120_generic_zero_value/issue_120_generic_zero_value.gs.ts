@@ -30,20 +30,20 @@ $.registerInterfaceType(
 );
 
 // ZeroValue returns the zero value of type T
-export function ZeroValue<T extends Stringer>(): T {
-	let zero: T = null as any
+export function ZeroValue<T extends Stringer>($T: $.TypeDict<T>): T {
+	let zero: T = $T.zero()
 	return zero
 }
 
 // CallString calls the String method on a value of type T
-export function CallString<T extends Stringer>(v: T): string {
-	return v!.String()
+export function CallString<T extends Stringer>($T: $.TypeDict<T>, v: T): string {
+	return $.callMethod($T, v, "String")
 }
 
 // Sum demonstrates zero value + method call in a generic context
-export function Sum<T extends Stringer>(...vals: T[]): T {
+export function Sum<T extends Stringer>($T: $.TypeDict<T>, ...vals: T[]): T {
 	// Should be 0 for IntVal, "" for StringVal
-	let sum: T = null as any
+	let sum: T = $T.zero()
 	// Note: We can't actually add T values in Go without more constraints
 	// This just tests that sum has the right zero value and String() works
 	return sum
@@ -51,22 +51,22 @@ export function Sum<T extends Stringer>(...vals: T[]): T {
 
 export async function main(): Promise<void> {
 	// Test 1: Zero value of IntVal should be 0
-	let zeroInt = ZeroValue<IntVal>()
+	let zeroInt = ZeroValue<any>($.typeDict<IntVal>(() => 0, 'value', { String: IntVal_String }))
 	$.println("ZeroValue[IntVal]:", IntVal_String(zeroInt))
 
 	// Test 2: Zero value of StringVal should be ""
-	let zeroStr = ZeroValue<StringVal>()
+	let zeroStr = ZeroValue<any>($.typeDict<StringVal>(() => "", 'value', { String: StringVal_String }))
 	$.println("ZeroValue[StringVal]:", StringVal_String(zeroStr))
 
 	// Test 3: CallString on zero value
-	$.println("CallString on zero IntVal:", CallString(zeroInt))
-	$.println("CallString on zero StringVal:", CallString(zeroStr))
+	$.println("CallString on zero IntVal:", CallString<any>($.typeDict<IntVal>(() => 0, 'value', { String: IntVal_String }), zeroInt))
+	$.println("CallString on zero StringVal:", CallString<any>($.typeDict<StringVal>(() => "", 'value', { String: StringVal_String }), zeroStr))
 
 	// Test 4: Sum returns zero value
-	let sumInt = Sum<IntVal>()
+	let sumInt = Sum<any>($.typeDict<IntVal>(() => 0, 'value', { String: IntVal_String }))
 	$.println("Sum[IntVal]():", IntVal_String(sumInt))
 
-	let sumStr = Sum<StringVal>()
+	let sumStr = Sum<any>($.typeDict<StringVal>(() => "", 'value', { String: StringVal_String }))
 	$.println("Sum[StringVal]():", StringVal_String(sumStr))
 
 	// Test 5: Verify the actual values
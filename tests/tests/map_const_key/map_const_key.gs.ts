@@ -5,9 +5,9 @@ import * as $ from "@goscript/builtin/index.js"
 
 export let Add: Operation = 0
 
-export let Sub: Operation = 0
+export let Sub: Operation = 1
 
-export let Mul: Operation = 0
+export let Mul: Operation = 2
 
 export type OpNames = Map<Operation, string> | null;
 
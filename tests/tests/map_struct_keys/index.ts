@@ -0,0 +1,2 @@
+export { Labeled, Node, Point } from "./map_struct_keys.gs.js"
+export type { Key } from "./map_struct_keys.gs.js"
@@ -0,0 +1,96 @@
+package main
+
+import "math"
+
+type Point struct {
+	X, Y int
+}
+
+type Labeled struct {
+	Name string
+	Pos  Point
+}
+
+type Node struct {
+	ID int
+}
+
+type Key interface {
+	String() string
+}
+
+func (p Point) String() string {
+	return "point"
+}
+
+func main() {
+	// Struct keys are compared by value
+	visits := make(map[Point]int)
+	visits[Point{1, 2}] = visits[Point{1, 2}] + 1
+	visits[Point{1, 2}] = visits[Point{1, 2}] + 1
+	visits[Point{3, 4}] = 10
+	println("len:", len(visits))
+	println("visits[{1,2}]:", visits[Point{1, 2}])
+	_, ok := visits[Point{5, 6}]
+	println("has {5,6}:", ok)
+
+	// Mutating the original value does not change the stored key
+	p := Point{7, 8}
+	visits[p] = 1
+	p.X = 100
+	println("visits[{7,8}]:", visits[Point{7, 8}])
+	_, ok = visits[p]
+	println("has {100,8}:", ok)
+
+	delete(visits, Point{3, 4})
+	println("len after delete:", len(visits))
+
+	// Map literals with nested struct keys
+	labels := map[Labeled]string{
+		{"a", Point{0, 0}}: "origin",
+		{"b", Point{1, 1}}: "diagonal",
+	}
+	println("labels:", labels[Labeled{"a", Point{0, 0}}], labels[Labeled{"b", Point{1, 1}}])
+
+	// Array keys
+	grid := map[[2]int]bool{}
+	grid[[2]int{1, 2}] = true
+	println("grid[{1,2}]:", grid[[2]int{1, 2}], "grid[{2,1}]:", grid[[2]int{2, 1}])
+
+	// Interface keys compare the dynamic value
+	anyKeys := make(map[any]string)
+	anyKeys[1] = "int"
+	anyKeys["1"] = "string"
+	anyKeys[Point{1, 1}] = "point"
+	println("any:", anyKeys[1], anyKeys["1"], anyKeys[Point{1, 1}], len(anyKeys))
+
+	named := map[Key]int{Point{2, 2}: 4}
+	named[Point{2, 2}] = named[Point{2, 2}] + 1
+	println("named:", named[Point{2, 2}], len(named))
+
+	// Pointer keys are compared by identity
+	n1 := &Node{ID: 1}
+	n2 := &Node{ID: 1}
+	nodes := map[*Node]string{n1: "first"}
+	nodes[n2] = "second"
+	println("nodes:", len(nodes), nodes[n1], nodes[n2])
+
+	// NaN keys never match and +0 equals -0
+	floats := make(map[float64]int)
+	nan := math.NaN()
+	floats[nan] = 1
+	floats[nan] = 2
+	_, ok = floats[nan]
+	println("NaN entries:", len(floats), "found:", ok)
+	negZero := math.Copysign(0, -1)
+	floats[negZero] = 3
+	floats[0] = 4
+	println("zero:", floats[negZero], len(floats))
+	sum := 0
+	for _, v := range floats {
+		sum += v
+	}
+	println("sum:", sum)
+	delete(floats, nan)
+	println("len after deleting NaN:", len(floats))
+}
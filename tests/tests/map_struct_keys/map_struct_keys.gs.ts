@@ -0,0 +1,220 @@
+// Generated file based on map_struct_keys.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as math from "@goscript/math/index.js"
+
+export type Key = null | {
+	String(): string
+}
+
+$.registerInterfaceType(
+  'main.Key',
+  null, // Zero value for interface is null
+  [{ name: "String", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }]
+);
+
+export class Node {
+	public get ID(): number {
+		return this._fields.ID.value
+	}
+	public set ID(value: number) {
+		this._fields.ID.value = value
+	}
+
+	public _fields: {
+		ID: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{ID?: number}>) {
+		this._fields = {
+			ID: $.varRef(init?.ID ?? 0)
+		}
+	}
+
+	public clone(): Node {
+		const cloned = new Node()
+		cloned._fields = {
+			ID: $.varRef(this._fields.ID.value)
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Node',
+	  new Node(),
+	  [],
+	  Node,
+	  {"ID": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export class Point {
+	public get X(): number {
+		return this._fields.X.value
+	}
+	public set X(value: number) {
+		this._fields.X.value = value
+	}
+
+	public get Y(): number {
+		return this._fields.Y.value
+	}
+	public set Y(value: number) {
+		this._fields.Y.value = value
+	}
+
+	public _fields: {
+		X: $.VarRef<number>;
+		Y: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{X?: number, Y?: number}>) {
+		this._fields = {
+			X: $.varRef(init?.X ?? 0),
+			Y: $.varRef(init?.Y ?? 0)
+		}
+	}
+
+	public clone(): Point {
+		const cloned = new Point()
+		cloned._fields = {
+			X: $.varRef(this._fields.X.value),
+			Y: $.varRef(this._fields.Y.value)
+		}
+		return cloned
+	}
+
+	public String(): string {
+		return "point"
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Point',
+	  new Point(),
+	  [{ name: "String", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "string" } }] }],
+	  Point,
+	  {"X": { kind: $.TypeKind.Basic, name: "int" }, "Y": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export class Labeled {
+	public get Name(): string {
+		return this._fields.Name.value
+	}
+	public set Name(value: string) {
+		this._fields.Name.value = value
+	}
+
+	public get Pos(): Point {
+		return this._fields.Pos.value
+	}
+	public set Pos(value: Point) {
+		this._fields.Pos.value = value
+	}
+
+	public _fields: {
+		Name: $.VarRef<string>;
+		Pos: $.VarRef<Point>;
+	}
+
+	constructor(init?: Partial<{Name?: string, Pos?: Point}>) {
+		this._fields = {
+			Name: $.varRef(init?.Name ?? ""),
+			Pos: $.varRef(init?.Pos ? $.markAsStructValue(init.Pos.clone()) : new Point())
+		}
+	}
+
+	public clone(): Labeled {
+		const cloned = new Labeled()
+		cloned._fields = {
+			Name: $.varRef(this._fields.Name.value),
+			Pos: $.varRef($.markAsStructValue(this._fields.Pos.value.clone()))
+		}
+		return cloned
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Labeled',
+	  new Labeled(),
+	  [],
+	  Labeled,
+	  {"Name": { kind: $.TypeKind.Basic, name: "string" }, "Pos": "Point"}
+	);
+}
+
+export async function main(): Promise<void> {
+	// Struct keys are compared by value
+	let visits = $.makeMap<Point, number>({ fields: { "X": 'value', "Y": 'value' } })
+	$.mapSet(visits, $.markAsStructValue(new Point({X: 1, Y: 2})), $.mapGet(visits, $.markAsStructValue(new Point({X: 1, Y: 2})), 0)[0] + 1)
+	$.mapSet(visits, $.markAsStructValue(new Point({X: 1, Y: 2})), $.mapGet(visits, $.markAsStructValue(new Point({X: 1, Y: 2})), 0)[0] + 1)
+	$.mapSet(visits, $.markAsStructValue(new Point({X: 3, Y: 4})), 10)
+	$.println("len:", $.len(visits))
+	$.println("visits[{1,2}]:", $.mapGet(visits, $.markAsStructValue(new Point({X: 1, Y: 2})), 0)[0])
+	let [, ok] = $.mapGet(visits, $.markAsStructValue(new Point({X: 5, Y: 6})), 0)
+	$.println("has {5,6}:", ok)
+
+	// Mutating the original value does not change the stored key
+	let p = $.markAsStructValue(new Point({X: 7, Y: 8}))
+	$.mapSet(visits, p, 1)
+	p.X = 100
+	$.println("visits[{7,8}]:", $.mapGet(visits, $.markAsStructValue(new Point({X: 7, Y: 8})), 0)[0])
+	;[, ok] = $.mapGet(visits, p, 0)
+	$.println("has {100,8}:", ok)
+
+	$.deleteMapEntry(visits, $.markAsStructValue(new Point({X: 3, Y: 4})))
+	$.println("len after delete:", $.len(visits))
+
+	// Map literals with nested struct keys
+	let labels = new $.HashMap<Labeled, string>({ fields: { "Name": 'value', "Pos": { fields: { "X": 'value', "Y": 'value' } } } }, [[$.markAsStructValue(new Labeled({Name: "a", Pos: $.markAsStructValue(new Point({X: 0, Y: 0}))})), "origin"], [$.markAsStructValue(new Labeled({Name: "b", Pos: $.markAsStructValue(new Point({X: 1, Y: 1}))})), "diagonal"]])
+	$.println("labels:", $.mapGet(labels, $.markAsStructValue(new Labeled({Name: "a", Pos: $.markAsStructValue(new Point({X: 0, Y: 0}))})), "")[0], $.mapGet(labels, $.markAsStructValue(new Labeled({Name: "b", Pos: $.markAsStructValue(new Point({X: 1, Y: 1}))})), "")[0])
+
+	// Array keys
+	let grid = new $.HashMap<number[], boolean>({ elem: 'value' }, [])
+	$.mapSet(grid, $.arrayToSlice<number>([1, 2]), true)
+	$.println("grid[{1,2}]:", $.mapGet(grid, $.arrayToSlice<number>([1, 2]), false)[0], "grid[{2,1}]:", $.mapGet(grid, $.arrayToSlice<number>([2, 1]), false)[0])
+
+	// Interface keys compare the dynamic value
+	let anyKeys = $.makeMap<null | any, string>('interface')
+	$.mapSet(anyKeys, 1, "int")
+	$.mapSet(anyKeys, "1", "string")
+	$.mapSet(anyKeys, $.markAsStructValue(new Point({X: 1, Y: 1})), "point")
+	$.println("any:", $.mapGet(anyKeys, 1, "")[0], $.mapGet(anyKeys, "1", "")[0], $.mapGet(anyKeys, $.markAsStructValue(new Point({X: 1, Y: 1})), "")[0], $.len(anyKeys))
+
+	let named = new $.HashMap<Key, number>('interface', [[$.markAsStructValue(new Point({X: 2, Y: 2})), 4]])
+	$.mapSet(named, $.markAsStructValue(new Point({X: 2, Y: 2})), $.mapGet(named, $.markAsStructValue(new Point({X: 2, Y: 2})), 0)[0] + 1)
+	$.println("named:", $.mapGet(named, $.markAsStructValue(new Point({X: 2, Y: 2})), 0)[0], $.len(named))
+
+	// Pointer keys are compared by identity
+	let n1 = new Node({ID: 1})
+	let n2 = new Node({ID: 1})
+	let nodes = new Map([[n1, "first"]])
+	$.mapSet(nodes, n2, "second")
+	$.println("nodes:", $.len(nodes), $.mapGet(nodes, n1, "")[0], $.mapGet(nodes, n2, "")[0])
+
+	// NaN keys never match and +0 equals -0
+	let floats = $.makeMap<number, number>('float')
+	let nan = math.NaN()
+	$.mapSet(floats, nan, 1)
+	$.mapSet(floats, nan, 2)
+	;[, ok] = $.mapGet(floats, nan, 0)
+	$.println("NaN entries:", $.len(floats), "found:", ok)
+	let negZero = math.Copysign(0, -1)
+	$.mapSet(floats, negZero, 3)
+	$.mapSet(floats, 0, 4)
+	$.println("zero:", $.mapGet(floats, negZero, 0)[0], $.len(floats))
+	let sum = 0
+	for (const [_k, v] of floats?.entries() ?? []) {
+		{
+			sum += v
+		}
+	}
+	$.println("sum:", sum)
+	$.deleteMapEntry(floats, nan)
+	$.println("len after deleting NaN:", $.len(floats))
+}
+
@@ -33,7 +33,7 @@ export class Thing {
 	public async callIt(x: number): Promise<void> {
 		const t = this
 		let done = $.makeChannel<{  }>(0, {}, 'both')
-		queueMicrotask(() => {
+		$.go(() => {
 			;getFunc()!(t, x)
 			done.close()
 		})
@@ -32,7 +32,7 @@ export class Thing {
 	public async callIt(x: number): Promise<void> {
 		const t = this
 		let done = $.makeChannel<((p0: Thing | null, p1: number) => void) | null>(0, null, 'both')
-		queueMicrotask(async () => {
+		$.go(async () => {
 			await $.chanSend(done, getFunc())
 			done.close()
 		})
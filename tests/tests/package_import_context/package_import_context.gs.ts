@@ -14,7 +14,7 @@ export async function run(ctx: null | context.Context): Promise<void> {
 
 	let myCh = $.makeChannel<{  }>(0, {}, 'both')
 
-	queueMicrotask(async () => {
+	$.go(async () => {
 		await $.chanRecv(sctx!.Done())
 		await $.chanSend(myCh, {})
 	})
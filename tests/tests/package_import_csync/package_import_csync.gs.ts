@@ -64,14 +64,14 @@ export async function main(): Promise<void> {
 
 	// Start worker goroutines
 	for (let i = 0; i < numWorkers; i++) {
-		queueMicrotask(() => {
+		$.go(() => {
 			worker(i)
 		})
 	}
 
 	// Wait for all workers to complete or context timeout
 	let done = $.makeChannel<{  }>(0, {}, 'both')
-	queueMicrotask(async () => {
+	$.go(async () => {
 		await wg!.value.Wait()
 		done.close()
 	})
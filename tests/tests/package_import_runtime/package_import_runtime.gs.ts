@@ -22,7 +22,7 @@ export async function main(): Promise<void> {
 	$.println("NumGoroutine:", runtime.NumGoroutine())
 
 	// Test GC (should be no-op)
-	await runtime.GC()
+	runtime.GC()
 	$.println("GC called successfully")
 }
 
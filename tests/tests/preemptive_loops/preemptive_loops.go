@@ -0,0 +1,52 @@
+package main
+
+import "sync/atomic"
+
+// count busy-waits until stop is set and sends the number of iterations.
+// It is async because of the channel send, so its loop is preempted.
+func count(stop *atomic.Bool, result chan<- int) {
+	n := 0
+	for !stop.Load() {
+		n++
+	}
+	result <- n
+}
+
+func main() {
+	// A goroutine busy-waiting for another goroutine, which only gets to run
+	// when the loop yields.
+	var stop atomic.Bool
+	done := make(chan bool)
+	go func() {
+		for !stop.Load() {
+		}
+		println("worker stopped")
+		done <- true
+	}()
+	go func() {
+		println("stopping worker")
+		stop.Store(true)
+	}()
+	<-done
+
+	var stopCount atomic.Bool
+	result := make(chan int)
+	go count(&stopCount, result)
+	go func() {
+		println("stopping counter")
+		stopCount.Store(true)
+	}()
+	println("counted:", <-result >= 0)
+
+	// Loops over channels block at each iteration and are left unchanged.
+	ch := make(chan int, 3)
+	for i := range 3 {
+		ch <- i
+	}
+	close(ch)
+	sum := 0
+	for v := range ch {
+		sum += v
+	}
+	println("sum:", sum)
+}
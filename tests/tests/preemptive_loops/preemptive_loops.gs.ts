@@ -0,0 +1,66 @@
+// Generated file based on preemptive_loops.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as atomic from "@goscript/sync/atomic/index.js"
+
+// count busy-waits until stop is set and sends the number of iterations.
+// It is async because of the channel send, so its loop is preempted.
+export async function count(stop: atomic.Bool | null, result: $.Channel<number> | null): Promise<void> {
+	let n = 0
+	for (; !stop!.Load(); ) {
+		if ($.shouldYield()) await $.yieldSlice()
+		n++
+	}
+	await $.chanSend(result, n)
+}
+
+export async function main(): Promise<void> {
+	// A goroutine busy-waiting for another goroutine, which only gets to run
+	// when the loop yields.
+	let stop: $.VarRef<atomic.Bool> = $.varRef(new atomic.Bool())
+	let done = $.makeChannel<boolean>(0, false, 'both')
+	$.go(async () => {
+		for (; !stop!.value.Load(); ) {
+			if ($.shouldYield()) await $.yieldSlice()
+		}
+		$.println("worker stopped")
+		await $.chanSend(done, true)
+	})
+	$.go(() => {
+		$.println("stopping worker")
+		stop!.value.Store(true)
+	})
+	await $.chanRecv(done)
+
+	let stopCount: $.VarRef<atomic.Bool> = $.varRef(new atomic.Bool())
+	let result = $.makeChannel<number>(0, 0, 'both')
+	$.go(async () => {
+		await count(stopCount, result)
+	})
+	$.go(() => {
+		$.println("stopping counter")
+		stopCount!.value.Store(true)
+	})
+	$.println("counted:", await $.chanRecv(result) >= 0)
+
+	// Loops over channels block at each iteration and are left unchanged.
+	let ch = $.makeChannel<number>(3, 0, 'both')
+	for (let i = 0; i < 3; i++) {{
+		if ($.shouldYield()) await $.yieldSlice()
+		await $.chanSend(ch, i)
+	}
+}
+ch.close()
+let sum = 0
+for (;;) {
+	const { value: v, ok: _ok } = await $.chanRecvWithOk(ch)
+	if (!_ok) break
+	{
+		sum += v
+	}
+}
+$.println("sum:", sum)
+}
+
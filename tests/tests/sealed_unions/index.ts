@@ -0,0 +1 @@
+export { Big } from "./sealed_unions.gs.js"
@@ -0,0 +1,31 @@
+package main
+
+import "github.com/aperturerobotics/goscript/tests/tests/sealed_unions/shape"
+
+// Big embeds a circle, so it implements shape.Shape outside of its package.
+type Big struct {
+	*shape.Circle
+}
+
+func main() {
+	var circle *shape.Circle
+	shapes := []shape.Shape{
+		nil,
+		&shape.Circle{R: 2},
+		&shape.Circle{},
+		circle,
+		&shape.Square{S: 3},
+		Big{Circle: &shape.Circle{R: 1}},
+	}
+
+	// Embedded in a type declared in a function
+	type local struct {
+		*shape.Square
+	}
+	shapes = append(shapes, local{Square: &shape.Square{S: 1}})
+
+	for i, s := range shapes {
+		println(i, shape.Describe(s), shape.Kind(s))
+	}
+	println("areas:", shapes[1].Area(), shapes[4].Area(), shapes[5].Area(), shapes[6].Area())
+}
@@ -0,0 +1,124 @@
+// Generated file based on sealed_unions.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as shape from "@goscript/github.com/aperturerobotics/goscript/tests/tests/sealed_unions/shape/index.js"
+
+export class Big {
+	public get Circle(): shape.Circle | null {
+		return this._fields.Circle.value
+	}
+	public set Circle(value: shape.Circle | null) {
+		this._fields.Circle.value = value
+	}
+
+	public _fields: {
+		Circle: $.VarRef<shape.Circle | null>;
+	}
+
+	constructor(init?: Partial<{Circle?: Partial<ConstructorParameters<typeof shape.Circle>[0]>}>) {
+		this._fields = {
+			Circle: $.varRef(init?.Circle ?? null)
+		}
+	}
+
+	public clone(): Big {
+		const cloned = new Big()
+		cloned._fields = {
+			Circle: $.varRef(this._fields.Circle.value)
+		}
+		return cloned
+	}
+
+	public get R(): number {
+		return this.Circle.R
+	}
+	public set R(value: number) {
+		this.Circle.R = value
+	}
+
+	public Area(): number {
+		return this.Circle.Area()
+	}
+
+	public isShape(): void {
+		this.Circle.isShape()
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Big',
+	  new Big(),
+	  [],
+	  Big,
+	  {"Circle": { kind: $.TypeKind.Pointer, elemType: "Circle" }}
+	);
+}
+
+export async function main(): Promise<void> {
+	let circle: shape.Circle | null = null
+	let shapes = $.arrayToSlice<null | shape.Shape>([null, new shape.Circle({R: 2}), new shape.Circle({}), $.typedNilOf(circle, "*shape.Circle"), new shape.Square({S: 3}), $.markAsStructValue(new Big({Circle: new shape.Circle({R: 1})}))])
+
+	// Embedded in a type declared in a function
+	class local {
+		public get Square(): shape.Square | null {
+			return this._fields.Square.value
+		}
+		public set Square(value: shape.Square | null) {
+			this._fields.Square.value = value
+		}
+
+		public _fields: {
+			Square: $.VarRef<shape.Square | null>;
+		}
+
+		constructor(init?: Partial<{Square?: Partial<ConstructorParameters<typeof shape.Square>[0]>}>) {
+			this._fields = {
+				Square: $.varRef(init?.Square ?? null)
+			}
+		}
+
+		public clone(): local {
+			const cloned = new local()
+			cloned._fields = {
+				Square: $.varRef(this._fields.Square.value)
+			}
+			return cloned
+		}
+
+		public get S(): number {
+			return this.Square.S
+		}
+		public set S(value: number) {
+			this.Square.S = value
+		}
+
+		public Area(): number {
+			return this.Square.Area()
+		}
+
+		public isShape(): void {
+			this.Square.isShape()
+		}
+
+		// Register this type with the runtime type system
+		static __typeInfo = $.registerStructType(
+		  'main.local',
+		  new local(),
+		  [],
+		  local,
+		  {"Square": { kind: $.TypeKind.Pointer, elemType: "Square" }}
+		);
+	}
+	shapes = $.append(shapes, $.markAsStructValue(new local({Square: new shape.Square({S: 1})})))
+
+	for (let i = 0; i < $.len(shapes); i++) {
+		let s = shapes![i]
+		{
+			$.println(i, shape.Describe(s), shape.Kind(s))
+		}
+	}
+	$.println("areas:", shapes![1]!.Area(), shapes![4]!.Area(), shapes![5]!.Area(), shapes![6]!.Area())
+}
+
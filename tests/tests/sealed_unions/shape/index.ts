@@ -0,0 +1,3 @@
+export { Describe, Kind } from "./shape.gs.js"
+export { Circle, Square } from "./shape.gs.js"
+export type { Shape } from "./shape.gs.js"
@@ -0,0 +1,64 @@
+package shape
+
+// Shape is a shape. Its unexported method seals it to this package.
+type Shape interface {
+	Area() int
+	isShape()
+}
+
+// Circle is a circle.
+type Circle struct{ R int }
+
+// Area returns the area of the circle, with pi rounded to 3.
+func (c *Circle) Area() int {
+	if c == nil {
+		return 0
+	}
+	return 3 * c.R * c.R
+}
+
+func (*Circle) isShape() {}
+
+// Square is a square.
+type Square struct{ S int }
+
+// Area returns the area of the square.
+func (s *Square) Area() int { return s.S * s.S }
+
+func (*Square) isShape() {}
+
+// Describe describes s.
+func Describe(s Shape) string {
+	switch v := s.(type) {
+	case *Circle:
+		if v == nil {
+			return "nil circle"
+		}
+		if v.R == 0 {
+			break
+		}
+		return "circle"
+	case *Square:
+		return "square"
+	case nil:
+		return "nil"
+	default:
+		return "other"
+	}
+	return "dot"
+}
+
+// Kind returns the kind of s, nil interfaces included in the default case.
+func Kind(s Shape) string {
+	kind := "unknown"
+	switch s.(type) {
+	case *Square:
+		kind = "square"
+	default:
+		if s == nil {
+			break
+		}
+		kind = "not a square"
+	}
+	return kind
+}
@@ -0,0 +1,163 @@
+// Generated file based on shape/shape.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+export class Circle {
+	public get R(): number {
+		return this._fields.R.value
+	}
+	public set R(value: number) {
+		this._fields.R.value = value
+	}
+
+	public _fields: {
+		R: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{R?: number}>) {
+		this._fields = {
+			R: $.varRef(init?.R ?? 0)
+		}
+	}
+
+	public clone(): Circle {
+		const cloned = new Circle()
+		cloned._fields = {
+			R: $.varRef(this._fields.R.value)
+		}
+		return cloned
+	}
+
+	public get __type(): "Circle" {
+		return "Circle"
+	}
+
+	// Area returns the area of the circle, with pi rounded to 3.
+	public Area(): number {
+		const c = this
+		if (c == null) {
+			return 0
+		}
+		return 3 * c.R * c.R
+	}
+
+	public isShape(): void {
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'github.com/aperturerobotics/goscript/tests/tests/sealed_unions/shape.Circle',
+	  new Circle(),
+	  [{ name: "Area", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "int" } }] }, { name: "isShape", args: [], returns: [] }],
+	  Circle,
+	  {"R": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export type Shape = null | Circle | Square
+
+$.registerInterfaceType(
+  'github.com/aperturerobotics/goscript/tests/tests/sealed_unions/shape.Shape',
+  null, // Zero value for interface is null
+  [{ name: "Area", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "int" } }] }, { name: "isShape", args: [], returns: [] }]
+);
+
+export class Square {
+	public get S(): number {
+		return this._fields.S.value
+	}
+	public set S(value: number) {
+		this._fields.S.value = value
+	}
+
+	public _fields: {
+		S: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{S?: number}>) {
+		this._fields = {
+			S: $.varRef(init?.S ?? 0)
+		}
+	}
+
+	public clone(): Square {
+		const cloned = new Square()
+		cloned._fields = {
+			S: $.varRef(this._fields.S.value)
+		}
+		return cloned
+	}
+
+	public get __type(): "Square" {
+		return "Square"
+	}
+
+	// Area returns the area of the square.
+	public Area(): number {
+		const s = this
+		return s.S * s.S
+	}
+
+	public isShape(): void {
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'github.com/aperturerobotics/goscript/tests/tests/sealed_unions/shape.Square',
+	  new Square(),
+	  [{ name: "Area", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "int" } }] }, { name: "isShape", args: [], returns: [] }],
+	  Square,
+	  {"S": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+// Describe describes s.
+export function Describe(s: Shape): string {
+	switch (s?.__type) {
+		case "Circle": {
+			let v: Circle | null = $.isTypedNil(s) ? null : s
+			if (v == null) {
+				return "nil circle"
+			}
+			if (Number(v!.R) == 0) {
+				break
+			}
+			return "circle"
+			break
+		}
+		case "Square": {
+			return "square"
+			break
+		}
+		default: {
+			if (s == null) {
+				return "nil"
+			} else {
+				return "other"
+			}
+			break
+		}
+	}
+	return "dot"
+}
+
+// Kind returns the kind of s, nil interfaces included in the default case.
+export function Kind(s: Shape): string {
+	let kind = "unknown"
+	switch (s?.__type) {
+		case "Square": {
+			kind = "square"
+			break
+		}
+		default: {
+			if (s == null) {
+				break
+			}
+			kind = "not a square"
+			break
+		}
+	}
+	return kind
+}
+
@@ -0,0 +1,25 @@
+// Implementations of the //goscript:extern functions of struct_json_js.go,
+// with the JSON methods of Entry.
+
+import { Entry } from './struct_json.gs.js'
+
+export function encode(e: Entry | null): string {
+  return JSON.stringify(e)
+}
+
+export function decode(data: string): Entry | null {
+  return Entry.fromJSON(JSON.parse(data))
+}
+
+export function decodeFails(data: string): boolean {
+  try {
+    Entry.fromJSON(JSON.parse(data))
+    return false
+  } catch {
+    return true
+  }
+}
+
+export function roundTrip(e: Entry | null): Entry | null {
+  return Entry.fromJSON(e!.toPlain())
+}
@@ -0,0 +1 @@
+export { Entry, Meta, Point, Window } from "./struct_json.gs.js"
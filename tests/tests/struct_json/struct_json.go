@@ -0,0 +1,97 @@
+package main
+
+import "fmt"
+
+// Point is a struct without an IsZero method: omitzero omits it if all its
+// fields are zero.
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Window has an IsZero method, used by omitzero.
+type Window struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// IsZero reports whether the window is empty.
+func (w Window) IsZero() bool {
+	return w.End <= w.Start
+}
+
+// Meta is embedded in Entry: its fields are promoted.
+type Meta struct {
+	Owner string `json:"owner"`
+}
+
+// Entry is encoded by encoding/json natively and by its toJSON method when
+// compiled by goscript.
+type Entry struct {
+	*Meta
+	ID       int64            `json:"id"`
+	Big      uint64           `json:"big,string"`
+	Name     string           `json:"name,omitempty"`
+	Data     []byte           `json:"data"`
+	Tags     map[string]int   `json:"tags"`
+	Scores   map[int64]string `json:"scores,omitempty"`
+	Origin   Point            `json:"origin,omitzero"`
+	Window   Window           `json:"window,omitzero"`
+	Corners  [2]Point         `json:"corners,omitzero"`
+	Children []*Entry         `json:"children,omitempty"`
+	Note     string
+	hidden   int
+}
+
+func main() {
+	// Zero values: omitempty and omitzero fields are left out. The zero value
+	// of a []byte field is not nil when compiled by goscript, so it is set.
+	fmt.Println(encode(&Entry{Data: []byte{}}))
+	fmt.Println(encode(&Entry{Data: []byte{}, Window: Window{Start: 5, End: 5}}))
+	fmt.Println(encode(nil))
+
+	// Map keys are sorted as strings, []byte is base64.
+	e := &Entry{
+		Meta:    &Meta{Owner: "gopher"},
+		ID:      42,
+		Big:     18446744073709551615,
+		Name:    "first",
+		Data:    []byte("hello, world"),
+		Tags:    map[string]int{"zeta": 26, "alpha": 1, "Mu": 12},
+		Scores:  map[int64]string{9: "nine", 3: "three", 7: "seven"},
+		Origin:  Point{X: 1},
+		Window:  Window{Start: 1, End: 3},
+		Corners: [2]Point{{}, {X: 2, Y: 2}},
+		Children: []*Entry{
+			{ID: 1, Data: []byte{}},
+			nil,
+		},
+		Note:   "promoted by name",
+		hidden: 7,
+	}
+	fmt.Println(encode(e))
+
+	// Decoding matches names ignoring case and decodes base64.
+	d := decode(`{"OWNER":"folded","Id":7,"BIG":"9007199254740993","Data":"AAEC/w==","tags":{"b":2,"a":1},"Scores":{"3":"three"},"note":"lower","corners":[{"X":1}]}`)
+	fmt.Println(d.Owner, d.ID, d.Big, len(d.Data), d.Data[3], d.Tags["a"], d.Tags["b"], d.Scores[3], d.Note, d.Corners[0].X)
+	fmt.Println(encode(d))
+
+	// Values missing from the object are zero.
+	d = decode(`{"name":"partial","data":""}`)
+	fmt.Println(d.Meta == nil, d.Name, len(d.Data), d.Tags == nil, d.Children == nil)
+	fmt.Println(encode(d))
+
+	// 64-bit integers keep their exact value through toPlain and fromJSON.
+	r := roundTrip(&Entry{ID: -9223372036854775808, Big: 9007199254740993, Data: []byte("round trip")})
+	fmt.Println(r.ID, r.Big, string(r.Data))
+	r = roundTrip(&Entry{ID: 9223372036854775807, Scores: map[int64]string{9007199254740993: "big key"}})
+	fmt.Println(r.ID, r.Scores[9007199254740993])
+
+	// Values out of the range of the type fail to decode.
+	fmt.Println(decodeFails(`{"id":1.5}`))
+	fmt.Println(decodeFails(`{"id":9223372036854775808}`))
+	fmt.Println(decodeFails(`{"big":"-1"}`))
+	fmt.Println(decodeFails(`{"big":"18446744073709551616"}`))
+	fmt.Println(decodeFails(`{"scores":{"x":"not a number"}}`))
+	fmt.Println(decodeFails(`{"id":-9007199254740991}`))
+}
@@ -0,0 +1,486 @@
+// Generated file based on struct_json.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+import { decode, decodeFails, encode, roundTrip } from "./struct_json_js.gs.js";
+
+import * as fmt from "@goscript/fmt/index.js"
+
+export class Meta {
+	public get Owner(): string {
+		return this._fields.Owner.value
+	}
+	public set Owner(value: string) {
+		this._fields.Owner.value = value
+	}
+
+	public _fields: {
+		Owner: $.VarRef<string>;
+	}
+
+	constructor(init?: Partial<{Owner?: string}>) {
+		this._fields = {
+			Owner: $.varRef(init?.Owner ?? "")
+		}
+	}
+
+	public clone(): Meta {
+		const cloned = new Meta()
+		cloned._fields = {
+			Owner: $.varRef(this._fields.Owner.value)
+		}
+		return cloned
+	}
+
+	public toJSON(): Record<string, unknown> {
+		return $.plainToJSON(this.toPlain()) as Record<string, unknown>
+	}
+
+	public toPlain(): Record<string, unknown> {
+		const obj: Record<string, unknown> = {}
+		obj["owner"] = this.Owner
+		return obj
+	}
+
+	public static fromJSON(obj: unknown): Meta {
+		const value = new Meta()
+		const props = $.plainObject(obj, ["owner"])
+		if (props["owner"] != null) {
+			value.Owner = props["owner"]
+		}
+		return value
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Meta',
+	  new Meta(),
+	  [],
+	  Meta,
+	  {"Owner": { type: { kind: $.TypeKind.Basic, name: "string" }, tag: "json:\"owner\"" }}
+	);
+}
+
+export class Point {
+	public get X(): number {
+		return this._fields.X.value
+	}
+	public set X(value: number) {
+		this._fields.X.value = value
+	}
+
+	public get Y(): number {
+		return this._fields.Y.value
+	}
+	public set Y(value: number) {
+		this._fields.Y.value = value
+	}
+
+	public _fields: {
+		X: $.VarRef<number>;
+		Y: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{X?: number, Y?: number}>) {
+		this._fields = {
+			X: $.varRef(init?.X ?? 0),
+			Y: $.varRef(init?.Y ?? 0)
+		}
+	}
+
+	public clone(): Point {
+		const cloned = new Point()
+		cloned._fields = {
+			X: $.varRef(this._fields.X.value),
+			Y: $.varRef(this._fields.Y.value)
+		}
+		return cloned
+	}
+
+	public toJSON(): Record<string, unknown> {
+		return $.plainToJSON(this.toPlain()) as Record<string, unknown>
+	}
+
+	public toPlain(): Record<string, unknown> {
+		const obj: Record<string, unknown> = {}
+		obj["x"] = this.X
+		obj["y"] = this.Y
+		return obj
+	}
+
+	public static fromJSON(obj: unknown): Point {
+		const value = new Point()
+		const props = $.plainObject(obj, ["x", "y"])
+		if (props["x"] != null) {
+			value.X = props["x"]
+		}
+		if (props["y"] != null) {
+			value.Y = props["y"]
+		}
+		return value
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Point',
+	  new Point(),
+	  [],
+	  Point,
+	  {"X": { type: { kind: $.TypeKind.Basic, name: "int" }, tag: "json:\"x\"" }, "Y": { type: { kind: $.TypeKind.Basic, name: "int" }, tag: "json:\"y\"" }}
+	);
+}
+
+export class Window {
+	public get Start(): number {
+		return this._fields.Start.value
+	}
+	public set Start(value: number) {
+		this._fields.Start.value = value
+	}
+
+	public get End(): number {
+		return this._fields.End.value
+	}
+	public set End(value: number) {
+		this._fields.End.value = value
+	}
+
+	public _fields: {
+		Start: $.VarRef<number>;
+		End: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{End?: number, Start?: number}>) {
+		this._fields = {
+			Start: $.varRef(init?.Start ?? 0),
+			End: $.varRef(init?.End ?? 0)
+		}
+	}
+
+	public clone(): Window {
+		const cloned = new Window()
+		cloned._fields = {
+			Start: $.varRef(this._fields.Start.value),
+			End: $.varRef(this._fields.End.value)
+		}
+		return cloned
+	}
+
+	public toJSON(): Record<string, unknown> {
+		return $.plainToJSON(this.toPlain()) as Record<string, unknown>
+	}
+
+	public toPlain(): Record<string, unknown> {
+		const obj: Record<string, unknown> = {}
+		obj["start"] = this.Start
+		obj["end"] = this.End
+		return obj
+	}
+
+	public static fromJSON(obj: unknown): Window {
+		const value = new Window()
+		const props = $.plainObject(obj, ["start", "end"])
+		if (props["start"] != null) {
+			value.Start = props["start"]
+		}
+		if (props["end"] != null) {
+			value.End = props["end"]
+		}
+		return value
+	}
+
+	// IsZero reports whether the window is empty.
+	public IsZero(): boolean {
+		const w = this
+		return w.End <= w.Start
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Window',
+	  new Window(),
+	  [{ name: "IsZero", args: [], returns: [{ type: { kind: $.TypeKind.Basic, name: "bool" } }] }],
+	  Window,
+	  {"Start": { type: { kind: $.TypeKind.Basic, name: "int" }, tag: "json:\"start\"" }, "End": { type: { kind: $.TypeKind.Basic, name: "int" }, tag: "json:\"end\"" }}
+	);
+}
+
+export class Entry {
+	public get ID(): bigint {
+		return this._fields.ID.value
+	}
+	public set ID(value: bigint) {
+		this._fields.ID.value = value
+	}
+
+	public get Big(): bigint {
+		return this._fields.Big.value
+	}
+	public set Big(value: bigint) {
+		this._fields.Big.value = value
+	}
+
+	public get Name(): string {
+		return this._fields.Name.value
+	}
+	public set Name(value: string) {
+		this._fields.Name.value = value
+	}
+
+	public get Data(): $.Bytes {
+		return this._fields.Data.value
+	}
+	public set Data(value: $.Bytes) {
+		this._fields.Data.value = value
+	}
+
+	public get Tags(): Map<string, number> | null {
+		return this._fields.Tags.value
+	}
+	public set Tags(value: Map<string, number> | null) {
+		this._fields.Tags.value = value
+	}
+
+	public get Scores(): Map<bigint, string> | null {
+		return this._fields.Scores.value
+	}
+	public set Scores(value: Map<bigint, string> | null) {
+		this._fields.Scores.value = value
+	}
+
+	public get Origin(): Point {
+		return this._fields.Origin.value
+	}
+	public set Origin(value: Point) {
+		this._fields.Origin.value = value
+	}
+
+	public get Window(): Window {
+		return this._fields.Window.value
+	}
+	public set Window(value: Window) {
+		this._fields.Window.value = value
+	}
+
+	public get Corners(): Point[] {
+		return this._fields.Corners.value
+	}
+	public set Corners(value: Point[]) {
+		this._fields.Corners.value = value
+	}
+
+	public get Children(): $.Slice<Entry | null> {
+		return this._fields.Children.value
+	}
+	public set Children(value: $.Slice<Entry | null>) {
+		this._fields.Children.value = value
+	}
+
+	public get Note(): string {
+		return this._fields.Note.value
+	}
+	public set Note(value: string) {
+		this._fields.Note.value = value
+	}
+
+	public get hidden(): number {
+		return this._fields.hidden.value
+	}
+	public set hidden(value: number) {
+		this._fields.hidden.value = value
+	}
+
+	public get Meta(): Meta | null {
+		return this._fields.Meta.value
+	}
+	public set Meta(value: Meta | null) {
+		this._fields.Meta.value = value
+	}
+
+	public _fields: {
+		Meta: $.VarRef<Meta | null>;
+		ID: $.VarRef<bigint>;
+		Big: $.VarRef<bigint>;
+		Name: $.VarRef<string>;
+		Data: $.VarRef<$.Bytes>;
+		Tags: $.VarRef<Map<string, number> | null>;
+		Scores: $.VarRef<Map<bigint, string> | null>;
+		Origin: $.VarRef<Point>;
+		Window: $.VarRef<Window>;
+		Corners: $.VarRef<Point[]>;
+		Children: $.VarRef<$.Slice<Entry | null>>;
+		Note: $.VarRef<string>;
+		hidden: $.VarRef<number>;
+	}
+
+	constructor(init?: Partial<{Big?: bigint, Children?: $.Slice<Entry | null>, Corners?: Point[], Data?: $.Bytes, ID?: bigint, Meta?: Partial<ConstructorParameters<typeof Meta>[0]>, Name?: string, Note?: string, Origin?: Point, Scores?: Map<bigint, string> | null, Tags?: Map<string, number> | null, Window?: Window, hidden?: number}>) {
+		this._fields = {
+			Meta: $.varRef(init?.Meta ?? null),
+			ID: $.varRef(init?.ID ?? 0n),
+			Big: $.varRef(init?.Big ?? 0n),
+			Name: $.varRef(init?.Name ?? ""),
+			Data: $.varRef(init?.Data ?? new Uint8Array(0)),
+			Tags: $.varRef(init?.Tags ?? null),
+			Scores: $.varRef(init?.Scores ?? null),
+			Origin: $.varRef(init?.Origin ? $.markAsStructValue(init.Origin.clone()) : new Point()),
+			Window: $.varRef(init?.Window ? $.markAsStructValue(init.Window.clone()) : new Window()),
+			Corners: $.varRef(init?.Corners ?? [new Point(), new Point()]),
+			Children: $.varRef(init?.Children ?? null),
+			Note: $.varRef(init?.Note ?? ""),
+			hidden: $.varRef(init?.hidden ?? 0)
+		}
+	}
+
+	public clone(): Entry {
+		const cloned = new Entry()
+		cloned._fields = {
+			Meta: $.varRef(this._fields.Meta.value),
+			ID: $.varRef(this._fields.ID.value),
+			Big: $.varRef(this._fields.Big.value),
+			Name: $.varRef(this._fields.Name.value),
+			Data: $.varRef(this._fields.Data.value),
+			Tags: $.varRef(this._fields.Tags.value),
+			Scores: $.varRef(this._fields.Scores.value),
+			Origin: $.varRef($.markAsStructValue(this._fields.Origin.value.clone())),
+			Window: $.varRef($.markAsStructValue(this._fields.Window.value.clone())),
+			Corners: $.varRef(this._fields.Corners.value),
+			Children: $.varRef(this._fields.Children.value),
+			Note: $.varRef(this._fields.Note.value),
+			hidden: $.varRef(this._fields.hidden.value)
+		}
+		return cloned
+	}
+
+	public toJSON(): Record<string, unknown> {
+		return $.plainToJSON(this.toPlain()) as Record<string, unknown>
+	}
+
+	public toPlain(): Record<string, unknown> {
+		const obj: Record<string, unknown> = {}
+		if (this.Meta !== null) {
+			obj["owner"] = this.Meta.Owner
+		}
+		obj["id"] = this.ID
+		obj["big"] = String(this.Big)
+		if (this.Name !== "") {
+			obj["name"] = this.Name
+		}
+		obj["data"] = $.bytesToBase64(this.Data)
+		obj["tags"] = $.mapToPlain(this.Tags)
+		if ($.len(this.Scores) !== 0) {
+			obj["scores"] = $.mapToPlain(this.Scores)
+		}
+		if (!$.isZero(this.Origin)) {
+			obj["origin"] = $.toPlain(this.Origin)
+		}
+		if (!$.isZero(this.Window)) {
+			obj["window"] = $.toPlain(this.Window)
+		}
+		if (!$.isZero(this.Corners)) {
+			obj["corners"] = $.sliceToPlain(this.Corners, (e) => $.toPlain(e))
+		}
+		if ($.len(this.Children) !== 0) {
+			obj["children"] = $.sliceToPlain(this.Children, (e) => $.toPlain(e))
+		}
+		obj["Note"] = this.Note
+		return obj
+	}
+
+	public static fromJSON(obj: unknown): Entry {
+		const value = new Entry()
+		const props = $.plainObject(obj, ["owner", "id", "big", "name", "data", "tags", "scores", "origin", "window", "corners", "children", "Note"])
+		if (props["owner"] != null) {
+			if (value.Meta === null) {
+				value.Meta = new Meta()
+			}
+			value.Meta.Owner = props["owner"]
+		}
+		if (props["id"] != null) {
+			value.ID = $.bigIntFromPlain(props["id"], false)
+		}
+		if (props["big"] != null) {
+			value.Big = $.bigIntFromPlain(props["big"], true)
+		}
+		if (props["name"] != null) {
+			value.Name = props["name"]
+		}
+		if (props["data"] !== undefined) {
+			value.Data = $.base64ToBytes(props["data"])
+		}
+		if (props["tags"] !== undefined) {
+			value.Tags = $.mapFromPlain(props["tags"])
+		}
+		if (props["scores"] !== undefined) {
+			value.Scores = $.mapFromPlain(props["scores"], (k) => $.bigIntFromPlain(k, false))
+		}
+		if (props["origin"] != null) {
+			value.Origin = $.structFromPlain(Point, props["origin"])
+		}
+		if (props["window"] != null) {
+			value.Window = $.structFromPlain(Window, props["window"])
+		}
+		if (props["corners"] != null) {
+			value.Corners = $.arrayFromPlain(props["corners"], [new Point(), new Point()], (e) => $.structFromPlain(Point, e))
+		}
+		if (props["children"] !== undefined) {
+			value.Children = $.sliceFromPlain(props["children"], (e) => e === null ? null : $.structFromPlain(Entry, e))
+		}
+		if (props["Note"] != null) {
+			value.Note = props["Note"]
+		}
+		return value
+	}
+
+	public get Owner(): string {
+		return this.Meta.Owner
+	}
+	public set Owner(value: string) {
+		this.Meta.Owner = value
+	}
+
+	// Register this type with the runtime type system
+	static __typeInfo = $.registerStructType(
+	  'main.Entry',
+	  new Entry(),
+	  [],
+	  Entry,
+	  {"Meta": { kind: $.TypeKind.Pointer, elemType: "Meta" }, "ID": { type: { kind: $.TypeKind.Basic, name: "int64" }, tag: "json:\"id\"" }, "Big": { type: { kind: $.TypeKind.Basic, name: "uint64" }, tag: "json:\"big,string\"" }, "Name": { type: { kind: $.TypeKind.Basic, name: "string" }, tag: "json:\"name,omitempty\"" }, "Data": { type: { kind: $.TypeKind.Slice, elemType: { kind: $.TypeKind.Basic, name: "byte" } }, tag: "json:\"data\"" }, "Tags": { type: { kind: $.TypeKind.Map, keyType: { kind: $.TypeKind.Basic, name: "string" }, elemType: { kind: $.TypeKind.Basic, name: "int" } }, tag: "json:\"tags\"" }, "Scores": { type: { kind: $.TypeKind.Map, keyType: { kind: $.TypeKind.Basic, name: "int64" }, elemType: { kind: $.TypeKind.Basic, name: "string" } }, tag: "json:\"scores,omitempty\"" }, "Origin": { type: "Point", tag: "json:\"origin,omitzero\"" }, "Window": { type: "Window", tag: "json:\"window,omitzero\"" }, "Corners": { type: { kind: $.TypeKind.Array, length: 2, elemType: "Point" }, tag: "json:\"corners,omitzero\"" }, "Children": { type: { kind: $.TypeKind.Slice, elemType: { kind: $.TypeKind.Pointer, elemType: "Entry" } }, tag: "json:\"children,omitempty\"" }, "Note": { kind: $.TypeKind.Basic, name: "string" }, "hidden": { kind: $.TypeKind.Basic, name: "int" }}
+	);
+}
+
+export async function main(): Promise<void> {
+	// Zero values: omitempty and omitzero fields are left out. The zero value
+	// of a []byte field is not nil when compiled by goscript, so it is set.
+	fmt.Println(encode(new Entry({Data: new Uint8Array([])})))
+	fmt.Println(encode(new Entry({Data: new Uint8Array([]), Window: new Window({End: 5, Start: 5})})))
+	fmt.Println(encode(null))
+
+	// Map keys are sorted as strings, []byte is base64.
+	let e = new Entry({Big: 18446744073709551615n, Children: $.arrayToSlice<Entry | null>([$.markAsStructValue(new Entry({Data: new Uint8Array([]), ID: 1n})), null]), Corners: $.arrayToSlice<Point>([$.markAsStructValue(new Point({})), $.markAsStructValue(new Point({X: 2, Y: 2}))]), Data: $.stringToBytes("hello, world"), ID: 42n, Name: "first", Note: "promoted by name", Origin: new Point({X: 1}), Scores: new Map([[9n, "nine"], [3n, "three"], [7n, "seven"]]), Tags: new Map([["zeta", 26], ["alpha", 1], ["Mu", 12]]), Window: new Window({End: 3, Start: 1}), hidden: 7, Meta: new Meta({Owner: "gopher"})})
+	fmt.Println(encode(e))
+
+	// Decoding matches names ignoring case and decodes base64.
+	let d = decode(`{"OWNER":"folded","Id":7,"BIG":"9007199254740993","Data":"AAEC/w==","tags":{"b":2,"a":1},"Scores":{"3":"three"},"note":"lower","corners":[{"X":1}]}`)
+	fmt.Println(d!.Owner, d!.ID, d!.Big, $.len(d!.Data), d!.Data![3], $.mapGet(d!.Tags, "a", 0)[0], $.mapGet(d!.Tags, "b", 0)[0], $.mapGet(d!.Scores, 3n, "")[0], d!.Note, d!.Corners![0].X)
+	fmt.Println(encode(d))
+
+	// Values missing from the object are zero.
+	d = decode(`{"name":"partial","data":""}`)
+	fmt.Println(d!.Meta == null, d!.Name, $.len(d!.Data), d!.Tags == null, d!.Children == null)
+	fmt.Println(encode(d))
+
+	// 64-bit integers keep their exact value through toPlain and fromJSON.
+	let r = roundTrip(new Entry({Big: 9007199254740993n, Data: $.stringToBytes("round trip"), ID: -9223372036854775808n}))
+	fmt.Println(r!.ID, r!.Big, $.bytesToString(r!.Data))
+	r = roundTrip(new Entry({ID: 9223372036854775807n, Scores: new Map([[9007199254740993n, "big key"]])}))
+	fmt.Println(r!.ID, $.mapGet(r!.Scores, 9007199254740993n, "")[0])
+
+	// Values out of the range of the type fail to decode.
+	fmt.Println(decodeFails(`{"id":1.5}`))
+	fmt.Println(decodeFails(`{"id":9223372036854775808}`))
+	fmt.Println(decodeFails(`{"big":"-1"}`))
+	fmt.Println(decodeFails(`{"big":"18446744073709551616"}`))
+	fmt.Println(decodeFails(`{"scores":{"x":"not a number"}}`))
+	fmt.Println(decodeFails(`{"id":-9007199254740991}`))
+}
+
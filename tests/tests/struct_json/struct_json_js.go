@@ -0,0 +1,24 @@
+package main
+
+// The functions below are implemented in host.ts with the JSON methods of
+// Entry. struct_json_native.go implements them with encoding/json.
+
+// encode encodes the entry with JSON.stringify.
+//
+//goscript:extern "./host.ts"
+func encode(e *Entry) string
+
+// decode decodes the entry with Entry.fromJSON.
+//
+//goscript:extern "./host.ts"
+func decode(data string) *Entry
+
+// decodeFails reports whether Entry.fromJSON throws.
+//
+//goscript:extern "./host.ts"
+func decodeFails(data string) bool
+
+// roundTrip converts the entry to a plain value and back.
+//
+//goscript:extern "./host.ts"
+func roundTrip(e *Entry) *Entry
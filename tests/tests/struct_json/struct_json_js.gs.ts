@@ -0,0 +1,35 @@
+// Generated file based on struct_json_js.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+import * as __extern_host from "./host.js"
+import { Entry } from "./struct_json.gs.js";
+
+// encode encodes the entry with JSON.stringify.
+//
+//goscript:extern "./host.ts"
+export function encode(e: Entry | null): string {
+	return __extern_host.encode(e)
+}
+
+// decode decodes the entry with Entry.fromJSON.
+//
+//goscript:extern "./host.ts"
+export function decode(data: string): Entry | null {
+	return __extern_host.decode(data)
+}
+
+// decodeFails reports whether Entry.fromJSON throws.
+//
+//goscript:extern "./host.ts"
+export function decodeFails(data: string): boolean {
+	return __extern_host.decodeFails(data)
+}
+
+// roundTrip converts the entry to a plain value and back.
+//
+//goscript:extern "./host.ts"
+export function roundTrip(e: Entry | null): Entry | null {
+	return __extern_host.roundTrip(e)
+}
+
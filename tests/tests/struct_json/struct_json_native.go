@@ -0,0 +1,30 @@
+//go:build !js
+
+package main
+
+import "encoding/json"
+
+func encode(e *Entry) string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func decode(data string) *Entry {
+	var e Entry
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		panic(err)
+	}
+	return &e
+}
+
+func decodeFails(data string) bool {
+	var e Entry
+	return json.Unmarshal([]byte(data), &e) != nil
+}
+
+func roundTrip(e *Entry) *Entry {
+	return decode(encode(e))
+}
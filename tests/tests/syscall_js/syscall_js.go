@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"github.com/aperturerobotics/goscript/jspromise"
+)
+
+func main() {
+	global := js.Global()
+
+	// Property access and method calls
+	math := global.Get("Math")
+	fmt.Println("max:", math.Call("max", 3, 7, 5).Int())
+	fmt.Println("pi:", math.Get("PI").Float() > 3.14)
+
+	json := global.Get("JSON")
+	fmt.Println("stringify:", json.Call("stringify", map[string]any{"b": []any{true, "x", nil}}).String())
+
+	obj := json.Call("parse", `{"name":"gopher","tags":["go","ts"]}`)
+	fmt.Println("name:", obj.Get("name").String())
+	tags := obj.Get("tags")
+	for i := 0; i < tags.Length(); i++ {
+		fmt.Println("tag:", i, tags.Index(i).String())
+	}
+
+	// Setting and deleting properties
+	obj.Set("count", 42)
+	obj.SetIndex(0, "zero")
+	fmt.Println("keys:", global.Get("Object").Call("keys", obj).Call("join", ",").String())
+	obj.Delete("count")
+	fmt.Println("deleted:", obj.Get("count").IsUndefined())
+
+	// Types and conversions
+	values := []js.Value{js.Undefined(), js.Null(), js.ValueOf(true), js.ValueOf(1.5), js.ValueOf("s"), obj, math.Get("max")}
+	for _, v := range values {
+		fmt.Println("type:", v.Type().String(), v.String(), v.Truthy())
+	}
+	fmt.Println("nan:", global.Call("parseInt", "x").IsNaN())
+	fmt.Println("equal:", math.Equal(global.Get("Math")), math.Equal(json))
+	fmt.Println("null:", js.Null().IsNull(), js.Value{}.IsUndefined())
+
+	// Constructors and instanceof
+	arr := global.Get("Array").New(3)
+	fmt.Println("array:", arr.Length(), arr.InstanceOf(global.Get("Array")))
+	date := global.Get("Date").New(0)
+	fmt.Println("year:", date.Call("getUTCFullYear").Int())
+
+	// Byte copies
+	u8 := global.Get("Uint8Array").New(4)
+	fmt.Println("copied to js:", js.CopyBytesToJS(u8, []byte{1, 2, 3}))
+	buf := make([]byte, 8)
+	n := js.CopyBytesToGo(buf, u8)
+	fmt.Println("copied to go:", n, buf[0], buf[2], buf[3])
+
+	// Go functions called by JavaScript
+	double := js.FuncOf(func(this js.Value, args []js.Value) any {
+		return args[0].Int() * 2
+	})
+	fmt.Println("doubled:", double.Invoke(21).Int())
+	mapped := json.Call("parse", "[1,2,3]").Call("map", double)
+	fmt.Println("mapped:", mapped.Call("join", " ").String())
+	double.Release()
+
+	// Awaiting a promise by blocking on a channel until it settles
+	done := make(chan string)
+	then := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- args[0].String()
+		return nil
+	})
+	defer then.Release()
+	global.Get("Promise").Call("resolve", "resolved").Call("then", then)
+	fmt.Println("promise:", <-done)
+
+	// Awaiting promises with jspromise
+	value, awaitErr := jspromise.Await(global.Get("Promise").Call("resolve", "awaited"))
+	fmt.Println("await:", value.String(), awaitErr)
+	_, awaitErr = jspromise.Await(global.Get("Promise").Call("reject", global.Get("Error").New("rejected")))
+	fmt.Println("await rejected:", awaitErr)
+	value, _ = jspromise.Await(js.ValueOf(7))
+	fmt.Println("await value:", value.Int())
+
+	// Errors
+	err := &js.ValueError{Method: "Value.Int", Type: js.ValueOf("text").Type()}
+	fmt.Println("value error:", err.Error())
+	jsErr := js.Error{Value: global.Get("Error").New("boom")}
+	fmt.Println("js error:", jsErr.Error())
+}
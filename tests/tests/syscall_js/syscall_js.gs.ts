@@ -0,0 +1,98 @@
+// Generated file based on syscall_js.go
+// Updated when compliance tests are re-run, DO NOT EDIT!
+
+import * as $ from "@goscript/builtin/index.js"
+
+import * as fmt from "@goscript/fmt/index.js"
+
+import * as js from "@goscript/syscall/js/index.js"
+
+import * as jspromise from "@goscript/github.com/aperturerobotics/goscript/jspromise/index.js"
+
+export async function main(): Promise<void> {
+	using __defer = new $.DisposableStack();
+	let global = $.markAsStructValue(js.Global().clone())
+
+	// Property access and method calls
+	let math = $.markAsStructValue(js.wrap(global.ref.Math).clone())
+	fmt.Println("max:", js.wrap(math.ref.max(3, 7, 5))!.Int())
+	fmt.Println("pi:", js.wrap(math.ref.PI)!.Float() > 3.14)
+
+	let json = $.markAsStructValue(js.wrap(global.ref.JSON).clone())
+	fmt.Println("stringify:", js.wrap(json.ref.stringify(js.unwrap(new Map([["b", $.arrayToSlice<null | any>([true, "x", null])]]))))!.String())
+
+	let obj = $.markAsStructValue(js.wrap(json.ref.parse(`{"name":"gopher","tags":["go","ts"]}`)).clone())
+	fmt.Println("name:", js.wrap(obj.ref.name)!.String())
+	let tags = $.markAsStructValue(js.wrap(obj.ref.tags).clone())
+	for (let i = 0; i < tags.ref.length; i++) {
+		fmt.Println("tag:", i, js.wrap(tags.ref[i])!.String())
+	}
+
+	// Setting and deleting properties
+	obj.ref.count = 42
+	obj.ref[0] = "zero"
+	fmt.Println("keys:", js.wrap(global.ref.Object.keys(obj.ref).join(","))!.String())
+	delete obj.ref.count
+	fmt.Println("deleted:", (obj.ref.count === undefined))
+
+	// Types and conversions
+	let values = $.arrayToSlice<js.Value>([js.Undefined(), js.Null(), js.ValueOf(true), js.ValueOf(1.5), js.ValueOf("s"), obj, js.wrap(math.ref.max)])
+	for (let _i = 0; _i < $.len(values); _i++) {
+		let v = values![_i]
+		{
+			fmt.Println("type:", js.Type_String(v.Type()), v.String(), !!v.ref)
+		}
+	}
+	fmt.Println("nan:", js.wrap(global.ref.parseInt("x"))!.IsNaN())
+	fmt.Println("equal:", (math.ref === global.ref.Math), (math.ref === json.ref))
+	fmt.Println("null:", (null === null), (($.markAsStructValue(new js.Value({}))).ref === undefined))
+
+	// Constructors and instanceof
+	let arr = $.markAsStructValue(js.wrap(new (global.ref.Array)(3)).clone())
+	fmt.Println("array:", arr.ref.length, arr.InstanceOf(js.wrap(global.ref.Array)))
+	let date = $.markAsStructValue(js.wrap(new (global.ref.Date)(0)).clone())
+	fmt.Println("year:", js.wrap(date.ref.getUTCFullYear())!.Int())
+
+	// Byte copies
+	let u8 = $.markAsStructValue(js.wrap(new (global.ref.Uint8Array)(4)).clone())
+	fmt.Println("copied to js:", js.CopyBytesToJS(u8, new Uint8Array([1, 2, 3])))
+	let buf = new Uint8Array(8)
+	let n = js.CopyBytesToGo(buf, u8)
+	fmt.Println("copied to go:", n, buf![0], buf![2], buf![3])
+
+	// Go functions called by JavaScript
+	let double = $.markAsStructValue(js.FuncOf((_this: js.Value, args: $.Slice<js.Value>): null | any => {
+		return args![0].Int() * 2
+	}).clone())
+	fmt.Println("doubled:", js.wrap(double.ref(21))!.Int())
+	let mapped = $.markAsStructValue(js.wrap(json.ref.parse("[1,2,3]").map(double.ref)).clone())
+	fmt.Println("mapped:", js.wrap(mapped.ref.join(" "))!.String())
+	double.Release()
+
+	// Awaiting a promise by blocking on a channel until it settles
+	let done = $.makeChannel<string>(0, "", 'both')
+	let then = $.markAsStructValue(js.FuncOf(async (_this: js.Value, args: $.Slice<js.Value>): Promise<null | any> => {
+		await $.chanSend(done, args![0].String())
+		return null
+	}).clone())
+	__defer.defer(() => {
+		then.Release()
+	});
+	js.wrap(global.ref.Promise.resolve("resolved").then(then.ref))
+	fmt.Println("promise:", await $.chanRecv(done))
+
+	// Awaiting promises with jspromise
+	let [value, awaitErr] = await jspromise.Await(js.wrap(global.ref.Promise.resolve("awaited")))
+	fmt.Println("await:", value.String(), awaitErr)
+	;[, awaitErr] = await jspromise.Await(js.wrap(global.ref.Promise.reject(new (global.ref.Error)("rejected"))))
+	fmt.Println("await rejected:", awaitErr)
+	;[value] = await jspromise.Await(js.ValueOf(7))
+	fmt.Println("await value:", value.Int())
+
+	// Errors
+	let err = new js.ValueError({Method: "Value.Int", Type: js.ValueOf("text")!.Type()})
+	fmt.Println("value error:", err!.Error())
+	let jsErr = $.markAsStructValue(new js.Error({Value: js.wrap(new (global.ref.Error)("boom"))}))
+	fmt.Println("js error:", jsErr.Error())
+}
+
@@ -142,7 +142,7 @@ export async function main(): Promise<void> {
 	let p1 = NewPromise<string>()
 
 	// Set result in goroutine
-	queueMicrotask(() => {
+	$.go(() => {
 		p1!.SetResult("hello world", null)
 	})
 